@@ -15,6 +15,7 @@ import (
 	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
 	"github.com/minhmannh2001/authconnecthub/pkg/httpserver"
 	"github.com/minhmannh2001/authconnecthub/pkg/logger"
+	"github.com/minhmannh2001/authconnecthub/pkg/mailer"
 	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
 	"github.com/minhmannh2001/authconnecthub/pkg/redis"
 )
@@ -43,6 +44,7 @@ func main() {
 			config.NewConfig,
 			postgres.New,
 			redis.New,
+			mailer.New,
 			fx.Annotate(
 				repos.NewAuthRepo,
 				fx.As(new(repos.IAuthRepo)),
@@ -55,21 +57,57 @@ func main() {
 				repos.NewUserRepo,
 				fx.As(new(repos.IUserRepo)),
 			),
+			fx.Annotate(
+				repos.NewRateLimitRepo,
+				fx.As(new(repos.IRateLimitRepo)),
+			),
+			fx.Annotate(
+				repos.NewServiceAccountRepo,
+				fx.As(new(repos.IServiceAccountRepo)),
+			),
+			fx.Annotate(
+				repos.NewRecoveryCodeRepo,
+				fx.As(new(repos.IRecoveryCodeRepo)),
+			),
+			fx.Annotate(
+				repos.NewWebAuthnCredentialRepo,
+				fx.As(new(repos.IWebAuthnCredentialRepo)),
+			),
+			fx.Annotate(
+				repos.NewLinkedIdentityRepo,
+				fx.As(new(repos.ILinkedIdentityRepo)),
+			),
+			fx.Annotate(
+				repos.NewApplicationRepo,
+				fx.As(new(repos.IApplicationRepo)),
+			),
 			fx.Annotate(
 				usecases.NewRoleUseCase,
 				fx.As(new(usecases.IRoleUC)),
 			),
+			fx.Annotate(
+				usecases.NewCredentialVerifiers,
+				fx.ResultTags(`group:"credential_verifiers,flatten"`),
+			),
 			fx.Annotate(
 				usecases.NewAuthUseCase,
+				fx.ParamTags(``, ``, ``, ``, ``, ``, ``, ``, ``, `group:"credential_verifiers"`),
 				fx.As(new(usecases.IAuthUC)),
 			),
 			fx.Annotate(
 				usecases.NewUserUseCase,
 				fx.As(new(usecases.IUserUC)),
 			),
-			func(cfg *config.Config, authUseCase usecases.IAuthUC) *gin.Engine {
+			fx.Annotate(
+				usecases.NewRateLimitUseCase,
+				fx.As(new(usecases.IRateLimitUC)),
+			),
+			func(cfg *config.Config, authUseCase usecases.IAuthUC, rateLimitUseCase usecases.IRateLimitUC) *gin.Engine {
 				e := gin.New()
 				// Middlewares
+				// a panic in any of the middlewares below is recovered and logged too
+				e.Use(gin.Logger())
+				e.Use(gin.Recovery())
 				e.Use(func(c *gin.Context) {
 					c.Set("config", cfg)
 					c.Next()
@@ -77,8 +115,7 @@ func main() {
 				e.Use(middlewares.IsHtmxRequest)
 				e.Use(middlewares.IsLoggedIn(authUseCase))
 				e.Use(middlewares.IsAuthorized(authUseCase))
-				e.Use(gin.Logger())
-				e.Use(gin.Recovery())
+				e.Use(middlewares.RateLimit(rateLimitUseCase))
 
 				return e
 			},
@@ -1,25 +1,31 @@
 package config
 
 import (
-	"crypto/rsa"
-	"crypto/x509"
-	"encoding/pem"
+	"crypto"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"sync"
 
 	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/minhmannh2001/authconnecthub/pkg/keyring"
 )
 
 type (
 	// Config contains app config.
 	Config struct {
-		App    `yaml:"app"`
-		Log    `yaml:"logger"`
-		PG     `yaml:"postgres"`
-		Redis  `yaml:"redis"`
-		Authen `yaml:"authen"`
+		App         `yaml:"app"`
+		Log         `yaml:"logger"`
+		PG          `yaml:"postgres"`
+		Redis       `yaml:"redis"`
+		Authen      `yaml:"authen"`
+		Mail        `yaml:"mail"`
+		OIDC        `yaml:"oidc"`
+		Federation  `yaml:"federation"`
+		LDAP        `yaml:"ldap"`
+		ForwardAuth `yaml:"forward_auth"`
+		SSO         `yaml:"sso"`
 	}
 
 	// App contains app config.
@@ -29,6 +35,7 @@ type (
 		Host        string `env-required:"true" yaml:"host"         env:"APP_HOST"`
 		Port        string `env-required:"true" yaml:"port"         env:"APP_PORT"`
 		SwaggerPath string `env-required:"true" yaml:"swagger_path" env:"SWAGGER_PATH"`
+		BaseURL     string `env-required:"true" yaml:"base_url"     env:"APP_BASE_URL"`
 	}
 
 	// Log contains logger config.
@@ -55,14 +62,127 @@ type (
 
 	// Authen contains authen config.
 	Authen struct {
-		AdminUsername     string `env-required:"true" yaml:"admin_username"       env:"ADMIN_USERNAME"`
-		AdminEmail        string `env-required:"true" yaml:"admin_email"          env:"ADMIN_EMAIL"`
-		AdminPassword     string `env-required:"true" yaml:"admin_password"       env:"ADMIN_PASSWORD"`
-		AccessTokenTTL    int    `env-required:"true" yaml:"access_token_ttl"     env:"ACCESS_TOKEN_TTL"`
-		RefreshTokenTTL   int    `env-required:"true" yaml:"refresh_token_ttl"    env:"REFRESH_TOKEN_TTL"`
-		JwtPrivateKeyPath string `env-required:"true" yaml:"jwt_private_key_path" env:"JWT_PRIVATE_KEY_PATH"`
-		JwtPrivateKey     *rsa.PrivateKey
-		SecretKey         string `env-required:"true" yaml:"secret_key"           env:"SECRET_KEY"`
+		AdminUsername             string   `env-required:"true" yaml:"admin_username"               env:"ADMIN_USERNAME"`
+		AdminEmail                string   `env-required:"true" yaml:"admin_email"                  env:"ADMIN_EMAIL"`
+		AdminPassword             string   `env-required:"true" yaml:"admin_password"               env:"ADMIN_PASSWORD"`
+		AccessTokenTTL            int      `env-required:"true" yaml:"access_token_ttl"             env:"ACCESS_TOKEN_TTL"`
+		RefreshTokenTTL           int      `env-required:"true" yaml:"refresh_token_ttl"            env:"REFRESH_TOKEN_TTL"`
+		ResetPasswordTokenTTL     int      `env-required:"true" yaml:"reset_password_token_ttl"     env:"RESET_PASSWORD_TOKEN_TTL"`
+		EmailVerificationTokenTTL int      `env-required:"true" yaml:"email_verification_token_ttl" env:"EMAIL_VERIFICATION_TOKEN_TTL"`
+		RequireEmailVerification  bool     `yaml:"require_email_verification" env:"REQUIRE_EMAIL_VERIFICATION"`
+		MFAPendingTokenTTL        int      `env-required:"true" yaml:"mfa_pending_token_ttl"        env:"MFA_PENDING_TOKEN_TTL"`
+		WebAuthnSessionTTL        int      `env-required:"true" yaml:"webauthn_session_ttl"         env:"WEBAUTHN_SESSION_TTL"`
+		LoginFailureWindow        int      `env-required:"true" yaml:"login_failure_window"         env:"LOGIN_FAILURE_WINDOW"`
+		LoginMaxFailures          int      `env-required:"true" yaml:"login_max_failures"           env:"LOGIN_MAX_FAILURES"`
+		LoginMaxFailuresPerIP     int      `env-required:"true" yaml:"login_max_failures_per_ip"    env:"LOGIN_MAX_FAILURES_PER_IP"`
+		LoginLockoutDuration      int      `env-required:"true" yaml:"login_lockout_duration"       env:"LOGIN_LOCKOUT_DURATION"`
+		LoginFailureDelay         int      `yaml:"login_failure_delay" env:"LOGIN_FAILURE_DELAY"`
+		ServiceTokenTTL           int      `env-required:"true" yaml:"service_token_ttl"            env:"SERVICE_TOKEN_TTL"`
+		JwtPrivateKeyPath         string   `yaml:"jwt_private_key_path" env:"JWT_PRIVATE_KEY_PATH"`
+		JwtAlgorithm              string   `yaml:"jwt_algorithm"        env:"JWT_ALGORITHM" env-default:"RS256"`
+		JwtKeys                   []JwtKey `yaml:"jwt_keys"`
+		JwtPrivateKey             crypto.Signer
+		JwtKeyring                *keyring.Keyring
+		SecretKey                 string `env-required:"true" yaml:"secret_key"                   env:"SECRET_KEY"`
+	}
+
+	// JwtKey is a signing key of the keyring, see keyring.StatusActive for the statuses.
+	// Its algorithm defaults to jwt_algorithm.
+	JwtKey struct {
+		ID        string `yaml:"kid"`
+		Path      string `yaml:"path"`
+		Status    string `yaml:"status"`
+		Algorithm string `yaml:"alg"`
+	}
+
+	// OIDC contains the config of the OpenID Connect provider, its issuer is the base url of the app.
+	OIDC struct {
+		AuthorizationCodeTTL int          `env-required:"true" yaml:"authorization_code_ttl" env:"OIDC_AUTHORIZATION_CODE_TTL"`
+		AccessTokenTTL       int          `env-required:"true" yaml:"access_token_ttl"       env:"OIDC_ACCESS_TOKEN_TTL"`
+		IDTokenTTL           int          `env-required:"true" yaml:"id_token_ttl"           env:"OIDC_ID_TOKEN_TTL"`
+		Clients              []OIDCClient `yaml:"clients"`
+	}
+
+	// OIDCClient is an application which logs its users in with the hub. Clients without
+	// a secret, such as single page apps, are public and have to use PKCE. The client ids
+	// users and services are reserved for the audiences of the hub tokens, clients with them are ignored.
+	OIDCClient struct {
+		ID           string   `yaml:"client_id"`
+		Secret       string   `yaml:"client_secret"`
+		Name         string   `yaml:"name"`
+		RedirectURIs []string `yaml:"redirect_uris"`
+	}
+
+	// Federation contains the upstream OpenID Connect providers users can sign in with. The redirect uri
+	// to register at a provider is the base url of the app followed by /v1/auth/federation/callback.
+	Federation struct {
+		StateTTL  int                `env-required:"true" yaml:"state_ttl" env:"FEDERATION_STATE_TTL"`
+		Providers []UpstreamProvider `yaml:"providers"`
+	}
+
+	// UpstreamProvider is an OpenID Connect provider, its ID names it in the urls of the hub
+	UpstreamProvider struct {
+		ID           string   `yaml:"id"`
+		Name         string   `yaml:"name"`
+		Issuer       string   `yaml:"issuer"`
+		ClientID     string   `yaml:"client_id"`
+		ClientSecret string   `yaml:"client_secret"`
+		Scopes       []string `yaml:"scopes"`
+	}
+
+	// LDAP contains the directory users can log in with their directory password, it is off without a url.
+	// Users are created on their first login and their email and role follow the directory afterwards.
+	LDAP struct {
+		URL               string          `yaml:"url"                env:"LDAP_URL"`
+		StartTLS          bool            `yaml:"start_tls"          env:"LDAP_START_TLS"`
+		BindDN            string          `yaml:"bind_dn"            env:"LDAP_BIND_DN"`
+		BindPassword      string          `yaml:"bind_password"      env:"LDAP_BIND_PASSWORD"`
+		BaseDN            string          `yaml:"base_dn"            env:"LDAP_BASE_DN"`
+		UserFilter        string          `yaml:"user_filter"        env:"LDAP_USER_FILTER"`
+		IDAttribute       string          `yaml:"id_attribute"       env:"LDAP_ID_ATTRIBUTE"`
+		UsernameAttribute string          `yaml:"username_attribute" env:"LDAP_USERNAME_ATTRIBUTE"`
+		EmailAttribute    string          `yaml:"email_attribute"    env:"LDAP_EMAIL_ATTRIBUTE"`
+		GroupBaseDN       string          `yaml:"group_base_dn"      env:"LDAP_GROUP_BASE_DN"`
+		GroupFilter       string          `yaml:"group_filter"       env:"LDAP_GROUP_FILTER"`
+		GroupRoles        []LDAPGroupRole `yaml:"group_roles"`
+		DefaultRole       string          `yaml:"default_role"       env:"LDAP_DEFAULT_ROLE"`
+		Timeout           int             `yaml:"timeout"            env:"LDAP_TIMEOUT"`
+	}
+
+	// LDAPGroupRole gives the members of a group of the directory a role. The first group
+	// of the list a user is a member of decides.
+	LDAPGroupRole struct {
+		Group string `yaml:"group"`
+		Role  string `yaml:"role"`
+	}
+
+	// ForwardAuth contains the rules reverse proxies are answered with at /v1/auth/verify. The first
+	// rule matching the host and path of a request decides, requests no rule matches are denied.
+	ForwardAuth struct {
+		Rules []ForwardAuthRule `yaml:"rules"`
+	}
+
+	// ForwardAuthRule lets requests through, asks for a login or denies them, see entity.ForwardAuthPolicyBypass
+	// for the policies. The host may start with a wildcard label, the path is a prefix. Logged in users
+	// need one of the roles, when there are any.
+	ForwardAuthRule struct {
+		Host   string   `yaml:"host"`
+		Path   string   `yaml:"path"`
+		Policy string   `yaml:"policy"`
+		Roles  []string `yaml:"roles"`
+	}
+
+	// SSO shares the login with the apps on the subdomains of Domain, it is off without a domain.
+	// The tokens are kept in cookies of the domain instead of the storage of the browser.
+	SSO struct {
+		Domain string `yaml:"domain" env:"SSO_DOMAIN"`
+	}
+
+	// Mail contains mailer config.
+	Mail struct {
+		Driver string `env-required:"true" yaml:"driver" env:"MAIL_DRIVER"`
+		From   string `env-required:"true" yaml:"from"   env:"MAIL_FROM"`
+		Dir    string `yaml:"dir"    env:"MAIL_DIR"`
 	}
 )
 
@@ -90,10 +210,11 @@ func NewConfig() (*Config, error) {
 			return nil, err
 		}
 
-		cfg.Authen.JwtPrivateKey, err = readPrivateKeyFromFile(cfg.Authen.JwtPrivateKeyPath)
+		cfg.Authen.JwtKeyring, err = loadJwtKeyring(cfg.Authen)
 		if err != nil {
 			return nil, fmt.Errorf("error while reading private key: %w", err)
 		}
+		cfg.Authen.JwtPrivateKey = cfg.Authen.JwtKeyring.Active().PrivateKey
 
 		log.Println("config loaded")
 
@@ -103,27 +224,48 @@ func NewConfig() (*Config, error) {
 	return instance, nil
 }
 
-func readPrivateKeyFromFile(filename string) (*rsa.PrivateKey, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, err
+// loadJwtKeyring reads the keys of the keyring. A single jwt_private_key_path is
+// the only and active key, its kid is the thumbprint of the key.
+func loadJwtKeyring(authen Authen) (*keyring.Keyring, error) {
+	if len(authen.JwtKeys) == 0 {
+		if authen.JwtPrivateKeyPath == "" {
+			return nil, errors.New("either jwt_keys or jwt_private_key_path is required")
+		}
+
+		privateKey, err := readPrivateKeyFromFile(authen.JwtPrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		return keyring.New(keyring.Key{
+			ID:         keyring.Thumbprint(privateKey.Public()),
+			Status:     keyring.StatusActive,
+			Algorithm:  authen.JwtAlgorithm,
+			PrivateKey: privateKey,
+		})
 	}
-	defer file.Close()
 
-	fileInfo, _ := file.Stat()
-	fileSize := fileInfo.Size()
-	buffer := make([]byte, fileSize)
+	keys := make([]keyring.Key, 0, len(authen.JwtKeys))
+	for _, k := range authen.JwtKeys {
+		privateKey, err := readPrivateKeyFromFile(k.Path)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", k.ID, err)
+		}
 
-	_, err = file.Read(buffer)
-	if err != nil {
-		return nil, err
+		algorithm := k.Algorithm
+		if algorithm == "" {
+			algorithm = authen.JwtAlgorithm
+		}
+		keys = append(keys, keyring.Key{ID: k.ID, Status: k.Status, Algorithm: algorithm, PrivateKey: privateKey})
 	}
 
-	data, _ := pem.Decode(buffer)
-	privateKey, err := x509.ParsePKCS1PrivateKey(data.Bytes)
+	return keyring.New(keys...)
+}
+
+func readPrivateKeyFromFile(filename string) (crypto.Signer, error) {
+	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	return privateKey, nil
+	return keyring.ParsePrivateKey(data)
 }
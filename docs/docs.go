@@ -24,38 +24,298 @@ const docTemplate = `{
     "paths": {
         "/": {
             "get": {
-                "description": "This endpoint renders the index.html page with potential toast notification settings based on query parameters and validation.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "This endpoint renders the home page of the application.",
                 "produces": [
                     "text/html"
                 ],
                 "tags": [
-                    "index"
+                    "home"
                 ],
-                "summary": "Get Index Page",
+                "summary": "Home Page",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Message to display in the toast notification",
+                        "description": "Toast message to display",
                         "name": "toast-message",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Type of the toast notification (e.g., success, error)",
+                        "description": "Type of toast notification (e.g., success, error)",
                         "name": "toast-type",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Hash value used for validation (optional)",
+                        "description": "Hash value for validation",
                         "name": "hash-value",
                         "in": "query"
                     }
                 ],
-                "responses": {}
+                "responses": {
+                    "200": {
+                        "description": "Response object containing HTML data",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "This endpoint publishes the public keys tokens are signed with, see RFC 7517.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "well-known"
+                ],
+                "summary": "JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "JSON Web Key Set",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/.well-known/openid-configuration": {
+            "get": {
+                "description": "This endpoint tells applications where the endpoints of the OpenID Connect provider are and what it supports, see OpenID Connect Discovery section 4.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "well-known"
+                ],
+                "summary": "OpenID Connect Discovery",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OpenIDConfiguration"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/introspect": {
+            "post": {
+                "description": "Tells a resource server whether a token is active and what it grants, see RFC 7662.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "OAuth 2.0 Token Introspection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The access or refresh token.",
+                        "name": "token",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "access_token or refresh_token, tokens are recognized without it.",
+                        "name": "token_type_hint",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The client id, unless sent with HTTP basic authentication.",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The client secret, unless sent with HTTP basic authentication.",
+                        "name": "client_secret",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TokenIntrospection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    }
+                },
+                "x-rate-limit": {
+                    "key": "ip",
+                    "limit": 600,
+                    "period": 60
+                }
+            }
+        },
+        "/oauth/revoke": {
+            "post": {
+                "description": "Revokes an access or refresh token, see RFC 7009. Unknown, invalid and expired tokens are answered with 200 as well.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "OAuth 2.0 Token Revocation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The access or refresh token.",
+                        "name": "token",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "access_token or refresh_token, tokens are recognized without it.",
+                        "name": "token_type_hint",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The client id, unless sent with HTTP basic authentication.",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The client secret, unless sent with HTTP basic authentication.",
+                        "name": "client_secret",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    }
+                },
+                "x-rate-limit": {
+                    "key": "ip",
+                    "limit": 60,
+                    "period": 60
+                }
+            }
+        },
+        "/oauth/token": {
+            "post": {
+                "description": "Issues an access token to a service account with the client credentials grant, see RFC 6749 section 4.4.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OAuth"
+                ],
+                "summary": "OAuth 2.0 Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Has to be client_credentials.",
+                        "name": "grant_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Space separated scopes, defaults to all scopes of the service account.",
+                        "name": "scope",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The client id of the service account, unless sent with HTTP basic authentication.",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The client secret of the service account, unless sent with HTTP basic authentication.",
+                        "name": "client_secret",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ServiceToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    }
+                },
+                "x-rate-limit": {
+                    "key": "ip",
+                    "limit": 60,
+                    "period": 60
+                }
             }
         },
         "/private": {
@@ -83,56 +343,4310 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/auth/login": {
+        "/v1/admin/grants": {
             "get": {
-                "description": "This endpoint renders the login page and displays a toast notification if provided query parameters are valid.",
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "This endpoint renders the grant matrix of the applications, which role each granted user or role has within each application.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Grants Section",
+                "responses": {},
+                "x-permissions": [
+                    "application-grants:read"
+                ]
+            }
+        },
+        "/v1/admin/roles": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "This endpoint renders the roles with the forms to change them and to create new ones.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Roles Section",
+                "responses": {},
+                "x-permissions": [
+                    "roles:read"
+                ]
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Creates a role which inherits the permissions of its parents. It renders the roles section.",
                 "consumes": [
-                    "application/json"
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
                     "text/html"
                 ],
                 "tags": [
-                    "Authen"
+                    "Admin"
                 ],
-                "summary": "Login Page",
+                "summary": "Create Role",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The message to display in the toast notification.",
-                        "name": "toast-message",
-                        "in": "query"
+                        "description": "The name of the role.",
+                        "name": "name",
+                        "in": "formData",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "The type of the toast notification (e.g., success, error).",
-                        "name": "toast-type",
-                        "in": "query"
+                        "description": "What the role is for.",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "The names of the parent roles.",
+                        "name": "parents",
+                        "in": "formData"
                     },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "The names of the permissions.",
+                        "name": "permissions",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {},
+                "x-permissions": [
+                    "roles:write"
+                ]
+            }
+        },
+        "/v1/admin/roles/delete": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Deletes a role which isn't a system role and which nobody has anymore. It renders the roles section.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete Role",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "A hash value used for validation.",
-                        "name": "hash-value",
-                        "in": "query"
+                        "description": "The name of the role.",
+                        "name": "name",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
-                "responses": {}
+                "responses": {},
+                "x-permissions": [
+                    "roles:write"
+                ]
             }
         },
-        "/v1/auth/logout": {
+        "/v1/admin/roles/update": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Replaces the description, the parents and the permissions of a role, and renames it when a new name is given. It renders the roles section.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the role.",
+                        "name": "name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The new name of the role.",
+                        "name": "new_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "What the role is for.",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "The names of the parent roles.",
+                        "name": "parents",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "The names of the permissions.",
+                        "name": "permissions",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {},
+                "x-permissions": [
+                    "roles:write"
+                ]
+            }
+        },
+        "/v1/admin/users": {
             "get": {
                 "security": [
                     {
                         "JWT": []
                     }
                 ],
-                "description": "Logs out the currently authenticated user and redirects to the home page with a success toast notification.",
+                "description": "This endpoint renders a page of the users with the filters above it. The next cursor of a page",
+                "produces": [
+                    "text/html"
+                ],
                 "tags": [
-                    "Authen"
+                    "Admin"
+                ],
+                "summary": "Users Section",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "A part of the username or email.",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The name of the role.",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "active, disabled or deleted. The users which aren't deleted when empty.",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "username, email or created_at, descending with a leading -. By username when empty.",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The next cursor of the page before.",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {},
+                "x-permissions": [
+                    "users:read"
+                ]
+            }
+        },
+        "/v1/admin/users/delete": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "This endpoint renders the dialog which asks to confirm the deletion of a user.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete User Dialog",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The id of the user.",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "x-permissions": [
+                    "users:write"
+                ]
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Deletes a user, who is logged out everywhere. The user is kept, so it can be restored. It renders the row of the user and closes the dialog.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The id of the user.",
+                        "name": "id",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "x-permissions": [
+                    "users:write"
+                ]
+            }
+        },
+        "/v1/admin/users/disable": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Disables a user, who is logged out everywhere and can't log in until enabled again. It renders the row of the user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Disable User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The id of the user.",
+                        "name": "id",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "x-permissions": [
+                    "users:write"
+                ]
+            }
+        },
+        "/v1/admin/users/enable": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Enables a disabled user, who can log in again. It renders the row of the user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Enable User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The id of the user.",
+                        "name": "id",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "x-permissions": [
+                    "users:write"
+                ]
+            }
+        },
+        "/v1/admin/users/reset-password": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Sends a user the link to choose a new password, as if they had asked for it themselves.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Send Password Reset",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The id of the user.",
+                        "name": "id",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "x-permissions": [
+                    "users:write"
+                ]
+            }
+        },
+        "/v1/admin/users/restore": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Restores a deleted user, who can log in again. It renders the row of the user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Restore User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The id of the user.",
+                        "name": "id",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "x-permissions": [
+                    "users:write"
+                ]
+            }
+        },
+        "/v1/admin/users/update": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Gives a user another role, it applies once the tokens of the user are refreshed. It renders the row of the user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Change User Role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The id of the user.",
+                        "name": "id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The name of the role.",
+                        "name": "role",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "x-permissions": [
+                    "users:write"
+                ]
+            }
+        },
+        "/v1/auth/apps": {
+            "get": {
+                "description": "This endpoint renders the applications the user may open as a section of the launcher page. It is empty for anonymous users.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Apps Section",
+                "responses": {}
+            }
+        },
+        "/v1/auth/apps/menu": {
+            "get": {
+                "description": "This endpoint renders the applications the user may open in the apps dropdown of the navbar. It is empty for anonymous users.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Apps Menu",
+                "responses": {}
+            }
+        },
+        "/v1/auth/federation": {
+            "get": {
+                "description": "This endpoint renders the accounts of upstream providers linked to the user as a section of the profile page. It is empty for anonymous users.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Linked Accounts Section",
+                "responses": {}
+            }
+        },
+        "/v1/auth/federation/callback": {
+            "get": {
+                "description": "The upstream provider sends the browser back here. The user is logged in, signed up with the customer role when the identity is new, or the identity is linked to the logged in user.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Federated Login Callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The authorization code.",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The state handed out when the sign in began.",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set when the provider turned the sign in down.",
+                        "name": "error",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Why the provider turned the sign in down.",
+                        "name": "error_description",
+                        "in": "query"
+                    }
+                ],
+                "responses": {},
+                "x-rate-limit": {
+                    "key": "ip",
+                    "limit": 20,
+                    "period": 60
+                }
+            }
+        },
+        "/v1/auth/federation/link": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Starts linking an account of an upstream provider to the logged in user, the browser is sent to the login page of the provider with the HX-Redirect header.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Link Account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The id of the provider.",
+                        "name": "provider",
+                        "in": "formData",
+                        "required": true
+                    }
                 ],
-                "summary": "Logout User",
                 "responses": {}
             }
+        },
+        "/v1/auth/federation/login": {
+            "post": {
+                "description": "Starts a sign in at an upstream OpenID Connect provider, the browser is sent to its login page with the HX-Redirect header.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Begin Federated Login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The id of the provider.",
+                        "name": "provider",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keep the user logged in.",
+                        "name": "remember_me",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {},
+                "x-rate-limit": {
+                    "key": "ip",
+                    "limit": 20,
+                    "period": 60
+                }
+            }
+        },
+        "/v1/auth/federation/unlink": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Unlinks an account of an upstream provider from the logged in user, it can't be used to sign in anymore.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Unlink Account",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The id of the linked account.",
+                        "name": "id",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/v1/auth/forget-password": {
+            "get": {
+                "description": "This endpoint renders the page where users can ask for a password reset link.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Forget Password Page",
+                "responses": {}
+            }
+        },
+        "/v1/auth/login": {
+            "get": {
+                "description": "This endpoint renders the login page and displays a toast notification if provided query parameters are valid.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Login Page",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The message to display in the toast notification.",
+                        "name": "toast-message",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The type of the toast notification (e.g., success, error).",
+                        "name": "toast-type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "A hash value used for validation.",
+                        "name": "hash-value",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The url of an app behind forward auth to go back to after the login.",
+                        "name": "rd",
+                        "in": "query"
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/v1/auth/login/mfa": {
+            "post": {
+                "description": "Finishes a login of a user with two-factor authentication by checking the code of their authenticator app.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Login Second Step",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The pending login token returned by the first step.",
+                        "name": "token",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The 6 digit code of the authenticator app or a recovery code.",
+                        "name": "code",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keep the user logged in.",
+                        "name": "remember_me",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {},
+                "x-rate-limit": {
+                    "key": "ip",
+                    "limit": 10,
+                    "period": 60
+                }
+            }
+        },
+        "/v1/auth/logout": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Logs out the currently authenticated user and redirects to the home page with a success toast notification.",
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Logout User",
+                "responses": {}
+            }
+        },
+        "/v1/auth/passkey": {
+            "get": {
+                "description": "This endpoint renders the passkeys section of the profile page. It is empty for anonymous users.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Passkeys Section",
+                "responses": {}
+            }
+        },
+        "/v1/auth/passkey/delete": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Removes a passkey of the logged in user, it can't be used to sign in anymore.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Delete Passkey",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The id of the passkey.",
+                        "name": "id",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/v1/auth/passkey/login/begin": {
+            "post": {
+                "description": "Creates the options to sign in with a passkey. They are sent in the passkeyLogin event of the HX-Trigger header.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Begin Passkey Login",
+                "responses": {},
+                "x-rate-limit": {
+                    "key": "ip",
+                    "limit": 20,
+                    "period": 60
+                }
+            }
+        },
+        "/v1/auth/passkey/login/finish": {
+            "post": {
+                "description": "Signs the user in with the answer of their authenticator and redirects to the home page.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Finish Passkey Login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The session returned when the login began.",
+                        "name": "session",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The JSON encoded assertion of the authenticator.",
+                        "name": "credential",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keep the user logged in.",
+                        "name": "remember_me",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {},
+                "x-rate-limit": {
+                    "key": "ip",
+                    "limit": 20,
+                    "period": 60
+                }
+            }
+        },
+        "/v1/auth/passkey/register/begin": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Creates the options for a new passkey of the logged in user. They are sent in the passkeyRegistration event of the HX-Trigger header.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Begin Passkey Registration",
+                "responses": {}
+            }
+        },
+        "/v1/auth/passkey/register/finish": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Stores the passkey created by the authenticator of the logged in user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Finish Passkey Registration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The session returned when the registration began.",
+                        "name": "session",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The JSON encoded credential created by the authenticator.",
+                        "name": "credential",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "A name to recognize the passkey.",
+                        "name": "name",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/v1/auth/password": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "This endpoint renders the page where logged in users can change their password.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Change Password Page",
+                "responses": {}
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Changes the password of the logged in user and logs out every other session of the user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Change Password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The current password.",
+                        "name": "current_password",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The new password.",
+                        "name": "password",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The new password again.",
+                        "name": "confirm_password",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 5,
+                    "period": 60
+                }
+            }
+        },
+        "/v1/auth/reset-password": {
+            "get": {
+                "description": "This endpoint renders the page where users choose a new password using the link sent to their email.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Reset Password Page",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The reset password token sent by email.",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/v1/auth/sessions": {
+            "get": {
+                "description": "This endpoint renders the devices the user is logged in on as a section of the profile page. It is empty for anonymous users.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Sessions Section",
+                "responses": {}
+            }
+        },
+        "/v1/auth/sessions/logout-everywhere": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Logs the user out on every device, including this one, and redirects to the login page.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Logout Everywhere",
+                "responses": {}
+            }
+        },
+        "/v1/auth/sessions/revoke": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Logs the user out on one of their devices.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Revoke Session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The id of the session.",
+                        "name": "id",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/v1/auth/totp": {
+            "get": {
+                "description": "This endpoint renders the two-factor authentication section of the profile page. It is empty for anonymous users.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Two-Factor Authentication Section",
+                "responses": {}
+            }
+        },
+        "/v1/auth/totp/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Turns two-factor authentication on after checking a first code of the authenticator app.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Confirm Two-Factor Authentication",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The 6 digit code of the authenticator app.",
+                        "name": "code",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 5,
+                    "period": 60
+                }
+            }
+        },
+        "/v1/auth/totp/disable": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Turns two-factor authentication off after checking a code of the authenticator app.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Disable Two-Factor Authentication",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The 6 digit code of the authenticator app.",
+                        "name": "code",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 5,
+                    "period": 60
+                }
+            }
+        },
+        "/v1/auth/totp/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Generates a new TOTP secret for the logged in user and renders its QR code.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Enroll Two-Factor Authentication",
+                "responses": {}
+            }
+        },
+        "/v1/auth/totp/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Replaces the recovery codes of the logged in user after checking a code of the authenticator app. The new codes are shown once.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Regenerate Recovery Codes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The 6 digit code of the authenticator app.",
+                        "name": "code",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {},
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 5,
+                    "period": 60
+                }
+            }
+        },
+        "/v1/auth/verify": {
+            "get": {
+                "description": "Reverse proxies ask here whether a request may reach the app behind them: Traefik with forwardAuth, Caddy with forward_auth and nginx with auth_request.",
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Forward Auth",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The method of the request.",
+                        "name": "X-Forwarded-Method",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The scheme of the request.",
+                        "name": "X-Forwarded-Proto",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The host of the request.",
+                        "name": "X-Forwarded-Host",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The path and query of the request.",
+                        "name": "X-Forwarded-Uri",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The url of the request, set by nginx.",
+                        "name": "X-Original-URL",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The request may pass"
+                    },
+                    "302": {
+                        "description": "The browser has to log in"
+                    },
+                    "401": {
+                        "description": "The request needs a logged in user"
+                    },
+                    "403": {
+                        "description": "The request is denied"
+                    }
+                }
+            }
+        },
+        "/v1/auth/verify-email": {
+            "get": {
+                "description": "This endpoint verifies the email address of a user with the link sent on registration and redirects to the login page with a toast notification.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Verify Email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The verification token sent by email.",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/v1/oidc/authorize": {
+            "get": {
+                "description": "This endpoint logs the user in to an application which uses the hub as its identity provider, see OpenID Connect Core section 3.1.2.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "OIDC"
+                ],
+                "summary": "OpenID Connect Authorization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Has to be code.",
+                        "name": "response_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The id of the application.",
+                        "name": "client_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "One of the redirect uris registered for the application.",
+                        "name": "redirect_uri",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Space separated scopes, openid is required. profile and email add claims about the user.",
+                        "name": "scope",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Returned to the application unchanged.",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Copied into the ID token.",
+                        "name": "nonce",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "none fails with login_required instead of showing the login page.",
+                        "name": "prompt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The PKCE challenge, required for applications without a secret.",
+                        "name": "code_challenge",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Has to be S256.",
+                        "name": "code_challenge_method",
+                        "in": "query"
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/v2/admin/applications": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Lists the applications of the registry, which log their users in with the hub and show in the launcher.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Applications",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "applications:read"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 60,
+                    "period": 60
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Registers an application. Its client id is the audience of the ID tokens it gets, the client secret is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Create Application",
+                "parameters": [
+                    {
+                        "description": "The settings of the application.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApplicationCreateRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ApplicationCredentials"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "applications:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 10,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/applications/delete": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Deletes an application, it can't log its users in anymore.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete Application",
+                "parameters": [
+                    {
+                        "description": "The client id of the application.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApplicationDeleteRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "applications:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/applications/grants": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Shows which role each granted user or role has within each application. The app roles of a row follow the order of the applications, they are empty where there is no grant.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Application Grants",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ApplicationGrantMatrix"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "application-grants:read"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 60,
+                    "period": 60
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Gives a user, or the users with a role, a viewer, editor or admin role within an application. A subject granted already gets the new role instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Grant Application Access",
+                "parameters": [
+                    {
+                        "description": "The application, the subject and its role within the application.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApplicationGrantRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "application-grants:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/applications/grants/delete": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Takes the grant of a user or a role away, the application is closed to them unless another grant covers them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Revoke Application Access",
+                "parameters": [
+                    {
+                        "description": "The application and the subject.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApplicationGrantDeleteRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "application-grants:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/applications/update": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Replaces the settings of an application, its client id and secret stay.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update Application",
+                "parameters": [
+                    {
+                        "description": "The client id and the new settings of the application.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApplicationUpdateRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "applications:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/login-locks": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Lists the usernames and ips which can't log in after too many failed attempts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Login Locks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "login-locks:read"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 60,
+                    "period": 60
+                },
+                "x-scopes": [
+                    "login-locks:read"
+                ]
+            }
+        },
+        "/v2/admin/login-locks/clear": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Lets a locked username or ip log in again right away and forgets its failed attempts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Clear Login Lock",
+                "parameters": [
+                    {
+                        "description": "The locked username or ip.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginLockClearRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "login-locks:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                },
+                "x-scopes": [
+                    "login-locks:write"
+                ]
+            }
+        },
+        "/v2/admin/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Lists the permissions roles can be given.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Permissions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.AdminPermission"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "roles:read"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 60,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/roles": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Lists the roles with their parents, their own permissions and the ones they inherit from their parents.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Roles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.AdminRole"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "roles:read"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 60,
+                    "period": 60
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Creates a role. It gets the permissions of its parents too, and the ones of their parents.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Create Role",
+                "parameters": [
+                    {
+                        "description": "The role.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleCreateRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AdminRole"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "roles:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 10,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/roles/delete": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Deletes a role which isn't a system role. Roles still assigned to users, deleted ones too, or named by applications can't be deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete Role",
+                "parameters": [
+                    {
+                        "description": "The name of the role.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleDeleteRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "roles:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/roles/update": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Replaces the description, the parents and the permissions of a role, and renames it when a new name is given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update Role",
+                "parameters": [
+                    {
+                        "description": "The name of the role and its settings.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleUpdateRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AdminRole"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "roles:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/service-accounts": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Lists the service accounts which get tokens with the client credentials grant of /oauth/token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Service Accounts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "service-accounts:read"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 60,
+                    "period": 60
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Creates a service account for a script or a cron job. The client secret is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Create Service Account",
+                "parameters": [
+                    {
+                        "description": "The name, scopes and token ttl of the service account.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ServiceAccountCreateRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ServiceAccountCredentials"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "service-accounts:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 10,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/service-accounts/delete": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Deletes a service account, the tokens it got stop working right away.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete Service Account",
+                "parameters": [
+                    {
+                        "description": "The client id of the service account.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ServiceAccountDeleteRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "service-accounts:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Lists a page of the users, the deleted ones only when asked for by their status. The next cursor of a page",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "A part of the username or email.",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The name of the role.",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "active, disabled or deleted. The users which aren't deleted when empty.",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "username, email or created_at, descending with a leading -. By username when empty.",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The number of users of the page, 20 when empty.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The next cursor of the page before.",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserPage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "users:read"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 60,
+                    "period": 60
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Creates a user, with the customer role when none is given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Create User",
+                "parameters": [
+                    {
+                        "description": "The user.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserCreateRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AdminUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "users:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 10,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/users/delete": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Deletes a user, who is logged out everywhere. The user is kept, so it can be restored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete User",
+                "parameters": [
+                    {
+                        "description": "The id of the user.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserIDRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AdminUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "users:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/users/detail": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Returns a user, a deleted one too.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The id of the user.",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AdminUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "users:read"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 60,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/users/disable": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Disables a user, who is logged out everywhere and can't log in until enabled again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Disable User",
+                "parameters": [
+                    {
+                        "description": "The id of the user.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserIDRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AdminUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "users:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/users/enable": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Enables a disabled user, who can log in again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Enable User",
+                "parameters": [
+                    {
+                        "description": "The id of the user.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserIDRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AdminUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "users:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/users/restore": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Restores a deleted user, who can log in again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Restore User",
+                "parameters": [
+                    {
+                        "description": "The id of the user.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserIDRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AdminUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "users:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/admin/users/update": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Changes the email or the role of a user, empty fields stay as they are. A changed email has to be verified again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update User",
+                "parameters": [
+                    {
+                        "description": "The id of the user and the fields to change.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserUpdateRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AdminUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-permissions": [
+                    "users:write"
+                ],
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 30,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/auth/password": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Changes the password of the logged in user and logs out every other session of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authen"
+                ],
+                "summary": "Change Password",
+                "parameters": [
+                    {
+                        "description": "The current and the new password.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangePasswordRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ValidationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                },
+                "x-rate-limit": {
+                    "key": "user",
+                    "limit": 5,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/oidc/token": {
+            "post": {
+                "description": "Redeems an authorization code for an access token and an ID token, see OpenID Connect Core section 3.1.3.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OIDC"
+                ],
+                "summary": "OpenID Connect Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Has to be authorization_code.",
+                        "name": "grant_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The authorization code.",
+                        "name": "code",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The redirect uri of the authorization request.",
+                        "name": "redirect_uri",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The id of the application, unless sent with HTTP basic authentication.",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The secret of the application, unless sent with HTTP basic authentication.",
+                        "name": "client_secret",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The PKCE verifier of the code challenge.",
+                        "name": "code_verifier",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OIDCTokens"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    }
+                },
+                "x-rate-limit": {
+                    "key": "ip",
+                    "limit": 60,
+                    "period": 60
+                }
+            }
+        },
+        "/v2/oidc/userinfo": {
+            "get": {
+                "description": "Returns the claims about the user an access token was issued for, as far as its scope allows, see OpenID Connect Core section 5.3.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OIDC"
+                ],
+                "summary": "OpenID Connect UserInfo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer followed by the access token of the token endpoint.",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The claims about the user.",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Returns the claims about the user an access token was issued for, as far as its scope allows, see OpenID Connect Core section 5.3.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "OIDC"
+                ],
+                "summary": "OpenID Connect UserInfo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer followed by the access token of the token endpoint.",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The claims about the user.",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OAuthErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AdminPermission": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AdminRole": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "inherited_permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parents": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "permissions_fixed": {
+                    "type": "boolean"
+                },
+                "system": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.AdminUser": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "totp_enabled": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ApplicationCreateRequestBody": {
+            "type": "object",
+            "required": [
+                "name",
+                "url"
+            ],
+            "properties": {
+                "access_token_ttl": {
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 60
+                },
+                "allowed_roles": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "client_id": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "icon": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "id_token_ttl": {
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 60
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "public": {
+                    "type": "boolean"
+                },
+                "redirect_uris": {
+                    "type": "string",
+                    "maxLength": 4096
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 1024
+                }
+            }
+        },
+        "dto.ApplicationCredentials": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ApplicationDeleteRequestBody": {
+            "type": "object",
+            "required": [
+                "client_id"
+            ],
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ApplicationGrantColumn": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ApplicationGrantDeleteRequestBody": {
+            "type": "object",
+            "required": [
+                "client_id",
+                "subject",
+                "subject_type"
+            ],
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "subject_type": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "role"
+                    ]
+                }
+            }
+        },
+        "dto.ApplicationGrantMatrix": {
+            "type": "object",
+            "properties": {
+                "applications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ApplicationGrantColumn"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ApplicationGrantRow"
+                    }
+                }
+            }
+        },
+        "dto.ApplicationGrantRequestBody": {
+            "type": "object",
+            "required": [
+                "app_role",
+                "client_id",
+                "subject",
+                "subject_type"
+            ],
+            "properties": {
+                "app_role": {
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor",
+                        "admin"
+                    ]
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "subject_type": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "role"
+                    ]
+                }
+            }
+        },
+        "dto.ApplicationGrantRow": {
+            "type": "object",
+            "properties": {
+                "app_roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "subject_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ApplicationUpdateRequestBody": {
+            "type": "object",
+            "required": [
+                "client_id",
+                "name",
+                "url"
+            ],
+            "properties": {
+                "access_token_ttl": {
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 60
+                },
+                "allowed_roles": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "id_token_ttl": {
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 60
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "redirect_uris": {
+                    "type": "string",
+                    "maxLength": 4096
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 1024
+                }
+            }
+        },
+        "dto.ChangePasswordRequestBody": {
+            "type": "object",
+            "required": [
+                "confirm_password",
+                "current_password",
+                "password"
+            ],
+            "properties": {
+                "confirm_password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "current_password": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "dto.LoginLockClearRequestBody": {
+            "type": "object",
+            "required": [
+                "kind",
+                "value"
+            ],
+            "properties": {
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "username",
+                        "ip"
+                    ]
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OAuthErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "error_description": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OIDCTokens": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "id_token": {
+                    "type": "string"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OpenIDConfiguration": {
+            "type": "object",
+            "properties": {
+                "authorization_endpoint": {
+                    "type": "string"
+                },
+                "claims_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code_challenge_methods_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "grant_types_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id_token_signing_alg_values_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "introspection_endpoint": {
+                    "type": "string"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "jwks_uri": {
+                    "type": "string"
+                },
+                "response_types_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "revocation_endpoint": {
+                    "type": "string"
+                },
+                "scopes_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subject_types_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token_endpoint": {
+                    "type": "string"
+                },
+                "token_endpoint_auth_methods_supported": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "userinfo_endpoint": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.RoleCreateRequestBody": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "parents": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "permissions": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.RoleDeleteRequestBody": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RoleUpdateRequestBody": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "name": {
+                    "type": "string"
+                },
+                "new_name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "parents": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "permissions": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.ServiceAccountCreateRequestBody": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "scopes": {
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "token_ttl": {
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 60
+                }
+            }
+        },
+        "dto.ServiceAccountCredentials": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "string"
+                },
+                "token_ttl": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ServiceAccountDeleteRequestBody": {
+            "type": "object",
+            "required": [
+                "client_id"
+            ],
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ServiceToken": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TokenIntrospection": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "aud": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "exp": {
+                    "type": "integer"
+                },
+                "iat": {
+                    "type": "integer"
+                },
+                "iss": {
+                    "type": "string"
+                },
+                "jti": {
+                    "type": "string"
+                },
+                "nbf": {
+                    "type": "integer"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "sub": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserCreateRequestBody": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "role": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "dto.UserIDRequestBody": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.UserPage": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AdminUser"
+                    }
+                }
+            }
+        },
+        "dto.UserUpdateRequestBody": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "dto.Validation": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ValidationResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean"
+                },
+                "validations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Validation"
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// @Summary OAuth 2.0 Token
+// @Description Issues an access token to a service account with the client credentials grant, see RFC 6749 section 4.4.
+// The client authenticates with HTTP basic authentication or the client_id and client_secret fields.
+// The token is accepted on the routes whose x-scopes extension it holds all the scopes of.
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "Has to be client_credentials."
+// @Param scope formData string false "Space separated scopes, defaults to all scopes of the service account."
+// @Param client_id formData string false "The client id of the service account, unless sent with HTTP basic authentication."
+// @Param client_secret formData string false "The client secret of the service account, unless sent with HTTP basic authentication."
+// @Success 200 {object} dto.ServiceToken
+// @Failure 400 {object} dto.OAuthErrorResponse
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
+// @x-rate-limit {"limit": 60, "period": 60, "key": "ip"}
+// @router /oauth/token [POST]
+func (h *HTTP) oauthTokenHandler(c *gin.Context) {
+	// the response contains a token, so it mustn't be cached
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var tokenRequestBody dto.TokenRequestBody
+	_ = c.ShouldBind(&tokenRequestBody)
+
+	usesBasicAuth := bindBasicClientCredentials(c, &tokenRequestBody.ClientID, &tokenRequestBody.ClientSecret)
+
+	token, err := h.authUC.IssueServiceToken(tokenRequestBody, helper.GetConfig(c))
+	if err != nil {
+		h.handleOAuthError(c, err, tokenRequestBody.ClientID, usesBasicAuth, "Token request")
+		return
+	}
+
+	h.logger.Info("Service account token issued", slog.String("client_id", tokenRequestBody.ClientID), slog.String("scope", token.Scope))
+	c.JSON(http.StatusOK, token)
+}
+
+// @Summary OAuth 2.0 Token Introspection
+// @Description Tells a resource server whether a token is active and what it grants, see RFC 7662.
+// Revoked tokens, tokens of ended sessions and tokens of deleted service accounts are inactive, inactive tokens only have the active field.
+// Applications with a secret and service accounts authenticate with HTTP basic authentication or the client_id and client_secret fields.
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "The access or refresh token."
+// @Param token_type_hint formData string false "access_token or refresh_token, tokens are recognized without it."
+// @Param client_id formData string false "The client id, unless sent with HTTP basic authentication."
+// @Param client_secret formData string false "The client secret, unless sent with HTTP basic authentication."
+// @Success 200 {object} dto.TokenIntrospection
+// @Failure 400 {object} dto.OAuthErrorResponse
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
+// @x-rate-limit {"limit": 600, "period": 60, "key": "ip"}
+// @router /oauth/introspect [POST]
+func (h *HTTP) oauthIntrospectHandler(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var introspectionRequestBody dto.IntrospectionRequestBody
+	_ = c.ShouldBind(&introspectionRequestBody)
+
+	usesBasicAuth := bindBasicClientCredentials(c, &introspectionRequestBody.ClientID, &introspectionRequestBody.ClientSecret)
+
+	introspection, err := h.authUC.IntrospectToken(introspectionRequestBody, helper.GetConfig(c))
+	if err != nil {
+		h.handleOAuthError(c, err, introspectionRequestBody.ClientID, usesBasicAuth, "Introspection request")
+		return
+	}
+
+	c.JSON(http.StatusOK, introspection)
+}
+
+// @Summary OAuth 2.0 Token Revocation
+// @Description Revokes an access or refresh token, see RFC 7009. Unknown, invalid and expired tokens are answered with 200 as well.
+// Tokens issued to an application or a service account can only be revoked by it, the tokens of users by any client.
+// Clients authenticate with HTTP basic authentication or the client_id and client_secret fields, applications without a secret only send their client_id.
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "The access or refresh token."
+// @Param token_type_hint formData string false "access_token or refresh_token, tokens are recognized without it."
+// @Param client_id formData string false "The client id, unless sent with HTTP basic authentication."
+// @Param client_secret formData string false "The client secret, unless sent with HTTP basic authentication."
+// @Success 200
+// @Failure 400 {object} dto.OAuthErrorResponse
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
+// @x-rate-limit {"limit": 60, "period": 60, "key": "ip"}
+// @router /oauth/revoke [POST]
+func (h *HTTP) oauthRevokeHandler(c *gin.Context) {
+	var revocationRequestBody dto.RevocationRequestBody
+	_ = c.ShouldBind(&revocationRequestBody)
+
+	usesBasicAuth := bindBasicClientCredentials(c, &revocationRequestBody.ClientID, &revocationRequestBody.ClientSecret)
+
+	err := h.authUC.RevokeToken(revocationRequestBody, helper.GetConfig(c))
+	if err != nil {
+		h.handleOAuthError(c, err, revocationRequestBody.ClientID, usesBasicAuth, "Revocation request")
+		return
+	}
+
+	h.logger.Info("Token revoked", slog.String("client_id", revocationRequestBody.ClientID))
+	c.Status(http.StatusOK)
+}
+
+// bindBasicClientCredentials replaces the client credentials of the form with the ones of HTTP basic
+// authentication, if sent. It returns whether they were.
+func bindBasicClientCredentials(c *gin.Context, clientID *string, clientSecret *string) bool {
+	basicClientID, basicClientSecret, ok := c.Request.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	// the credentials are form encoded before they are put in the header, see RFC 6749 section 2.3.1
+	*clientID, _ = url.QueryUnescape(basicClientID)
+	*clientSecret, _ = url.QueryUnescape(basicClientSecret)
+	return true
+}
+
+// handleOAuthError answers with the OAuth error code of the error, see RFC 6749 section 5.2
+func (h *HTTP) handleOAuthError(c *gin.Context, err error, clientID string, usesBasicAuth bool, request string) {
+	var oauthErr *entity.OAuthError
+	if !errors.As(err, &oauthErr) {
+		h.logger.Error(request+" failed", slog.String("client_id", clientID), slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.OAuthErrorResponse{Error: "server_error"})
+		return
+	}
+
+	status := http.StatusBadRequest
+	if oauthErr.Code == entity.OAuthErrorInvalidClient {
+		status = http.StatusUnauthorized
+		if usesBasicAuth {
+			c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+	}
+	h.logger.Warn(request+" rejected", slog.String("client_id", clientID), slog.String("error", oauthErr.Code))
+	c.JSON(status, dto.OAuthErrorResponse{Error: oauthErr.Code, ErrorDescription: oauthErr.Description})
+}
@@ -7,7 +7,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	v1 "github.com/minhmannh2001/authconnecthub/internal/controller/http/v1"
+	v2 "github.com/minhmannh2001/authconnecthub/internal/controller/http/v2"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
 	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/middlewares"
 	"github.com/minhmannh2001/authconnecthub/internal/usecases"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
@@ -20,15 +23,17 @@ type HTTP struct {
 	authUC usecases.IAuthUC
 	userUC usecases.IUserUC
 	roleUC usecases.IRoleUC
+	rateUC usecases.IRateLimitUC
 }
 
 // New returns a new HTTP controller
-func New(l *slog.Logger, a usecases.IAuthUC, u usecases.IUserUC, r usecases.IRoleUC) *HTTP {
+func New(l *slog.Logger, a usecases.IAuthUC, u usecases.IUserUC, r usecases.IRoleUC, rl usecases.IRateLimitUC) *HTTP {
 	return &HTTP{
 		logger: l,
 		authUC: a,
 		userUC: u,
 		roleUC: r,
+		rateUC: rl,
 	}
 }
 
@@ -51,7 +56,7 @@ func (h *HTTP) registerRoutes(e *gin.Engine) {
 
 	e.GET("/", homeHandler)
 
-	e.PUT("/show-toast", func(c *gin.Context) {
+	e.PUT("/show-toast", middlewares.RouteRateLimit(h.rateUC, entity.RateLimit{Limit: 30, Period: 60, Key: entity.RateLimitKeyIP}), func(c *gin.Context) {
 		c.HTML(http.StatusOK, "toast-section", gin.H{
 			"hidden": false,
 		})
@@ -67,11 +72,30 @@ func (h *HTTP) registerRoutes(e *gin.Engine) {
 
 	e.GET("/private", privateHandler)
 
+	e.GET("/.well-known/jwks.json", jwksHandler)
+	e.GET("/.well-known/openid-configuration", h.openIDConfigurationHandler)
+
+	e.POST("/oauth/token", h.oauthTokenHandler)
+	e.POST("/oauth/introspect", h.oauthIntrospectHandler)
+	e.POST("/oauth/revoke", h.oauthRevokeHandler)
+
 	// Routers
 	groupRouter := e.Group("/v1")
 	{
-		v1.NewAuthenRoutes(groupRouter, h.logger, h.authUC, h.userUC, h.roleUC)
+		v1.NewAuthenRoutes(groupRouter, h.logger, h.authUC, h.userUC, h.roleUC, h.rateUC)
+		v1.NewAdminRoutes(groupRouter, h.logger, h.authUC, h.userUC, h.roleUC)
 		e.GET("/dashboard", dashboardHandler)
+		e.GET("/apps", launcherHandler)
+		e.GET("/admin/grants", grantsHandler)
+		e.GET("/admin/users", usersHandler)
+		e.GET("/admin/roles", rolesHandler)
+	}
+
+	// JSON API
+	apiRouter := e.Group("/v2")
+	{
+		v2.NewAuthenRoutes(apiRouter, h.logger, h.authUC, h.userUC, h.roleUC, h.rateUC)
+		v2.NewAdminRoutes(apiRouter, h.logger, h.authUC, h.userUC, h.roleUC)
 	}
 }
 
@@ -83,7 +107,7 @@ func (h *HTTP) registerRoutes(e *gin.Engine) {
 // @Param toast-message query string false "Toast message to display"
 // @Param toast-type query string false "Type of toast notification (e.g., success, error)"
 // @Param hash-value query string false "Hash value for validation"
-// @Success 200 {object} object Response object containing HTML data
+// @Success 200 {string} string "Response object containing HTML data"
 // @Router / [GET]
 func homeHandler(c *gin.Context) {
 	queryParams := c.Request.URL.Query()
@@ -129,6 +153,33 @@ func privateHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, "Hello. You are in private path")
 }
 
+// @Summary JSON Web Key Set
+// @Description This endpoint publishes the public keys tokens are signed with, see RFC 7517.
+// The kid header of a token names the key it was signed with. Keys about to become active are published ahead of time,
+// retired keys stay until the tokens they signed have expired.
+// @Tags well-known
+// @Produce json
+// @Success 200 {object} object "JSON Web Key Set"
+// @Router /.well-known/jwks.json [GET]
+func jwksHandler(c *gin.Context) {
+	cfg := helper.GetConfig(c)
+
+	// relying parties refetch the set when they meet an unknown kid
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, cfg.Authen.JwtKeyring.JSONWebKeySet())
+}
+
+// @Summary OpenID Connect Discovery
+// @Description This endpoint tells applications where the endpoints of the OpenID Connect provider are and what it supports, see OpenID Connect Discovery section 4.
+// @Tags well-known
+// @Produce json
+// @Success 200 {object} dto.OpenIDConfiguration
+// @Router /.well-known/openid-configuration [GET]
+func (h *HTTP) openIDConfigurationHandler(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.authUC.OpenIDConfiguration(helper.GetConfig(c)))
+}
+
 func handleInternalServerError(c *gin.Context) {
 	c.HTML(http.StatusInternalServerError, "500.html", gin.H{
 		"title": "Personal Hub",
@@ -157,7 +208,65 @@ func handleNoMethod(c *gin.Context) {
 }
 
 func dashboardHandler(c *gin.Context) {
+	queryParams := c.Request.URL.Query()
+
+	toastMessage := helper.ExtractQueryParam(queryParams, "toast-message", "")
+	toastType := helper.ExtractQueryParam(queryParams, "toast-type", "")
+	hashValue := helper.ExtractQueryParam(queryParams, "hash-value", "")
+
+	// linking an account of an upstream provider comes back here with a toast
+	isValid := helper.IsMapValid(map[string]interface{}{
+		"toast-message": toastMessage,
+		"toast-type":    toastType,
+	}, hashValue)
+
 	c.HTML(http.StatusOK, "dashboard.html", gin.H{
+		"title": "Personal Hub",
+		"toastSettings": map[string]interface{}{
+			"hidden":  !isValid,
+			"type":    toastType,
+			"message": helper.FormatToastMessage(toastMessage),
+		},
+		"reload": c.GetHeader("HX-Reload"),
+	})
+}
+
+// launcherHandler renders the page of the applications the user may open, the section lists them
+func launcherHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "launcher.html", gin.H{
+		"title": "Personal Hub",
+		"toastSettings": map[string]interface{}{
+			"hidden": true,
+		},
+		"reload": c.GetHeader("HX-Reload"),
+	})
+}
+
+// grantsHandler renders the page of the grant matrix, the section is only filled in for administrators
+func grantsHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "grants.html", gin.H{
+		"title": "Personal Hub",
+		"toastSettings": map[string]interface{}{
+			"hidden": true,
+		},
+		"reload": c.GetHeader("HX-Reload"),
+	})
+}
+
+// rolesHandler renders the page of the roles, the section is only filled in for administrators
+func rolesHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "roles.html", gin.H{
+		"title": "Personal Hub",
+		"toastSettings": map[string]interface{}{
+			"hidden": true,
+		},
+		"reload": c.GetHeader("HX-Reload"),
+	})
+}
+
+// usersHandler renders the page of the user list, the section is only filled in for administrators
+func usersHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "users.html", gin.H{
 		"title": "Personal Hub",
 		"toastSettings": map[string]interface{}{
 			"hidden": true,
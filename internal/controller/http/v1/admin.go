@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+)
+
+type adminRoutes struct {
+	logger *slog.Logger
+	authUC usecases.IAuthUC
+	userUC usecases.IUserUC
+	roleUC usecases.IRoleUC
+}
+
+// NewAdminRoutes creates the sections of the pages only administrators can use
+func NewAdminRoutes(handler *gin.RouterGroup,
+	l *slog.Logger,
+	a usecases.IAuthUC,
+	u usecases.IUserUC,
+	r usecases.IRoleUC,
+) {
+	ar := &adminRoutes{l, a, u, r}
+
+	h := handler.Group("/admin")
+	{
+		h.GET("/grants", ar.getGrants)
+		h.GET("/users", ar.getUsers)
+		h.GET("/users/delete", ar.getDeleteUser)
+		h.POST("/users/update", ar.postUpdateUser)
+		h.POST("/users/disable", ar.postDisableUser)
+		h.POST("/users/enable", ar.postEnableUser)
+		h.POST("/users/reset-password", ar.postResetUserPassword)
+		h.POST("/users/delete", ar.postDeleteUser)
+		h.POST("/users/restore", ar.postRestoreUser)
+		h.GET("/roles", ar.getRoles)
+		h.POST("/roles", ar.postCreateRole)
+		h.POST("/roles/update", ar.postUpdateRole)
+		h.POST("/roles/delete", ar.postDeleteRole)
+	}
+}
+
+// @Summary Grants Section
+// @Description This endpoint renders the grant matrix of the applications, which role each granted user or role has within each application.
+// @Tags Admin
+// @Security JWT
+// @Produce html
+// @x-permissions ["application-grants:read"]
+// @router /v1/admin/grants [GET]
+func (ar *adminRoutes) getGrants(c *gin.Context) {
+	matrix, err := ar.authUC.ApplicationGrantMatrix()
+	if err != nil {
+		ar.logger.Error("Failed to list application grants", slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	c.HTML(http.StatusOK, "grant-section", gin.H{
+		"matrix": matrix,
+	})
+}
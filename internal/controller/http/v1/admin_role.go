@@ -0,0 +1,215 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// roleOption is a checkbox of the role form, for a parent or a permission
+type roleOption struct {
+	Name        string
+	Description string
+	Checked     bool
+}
+
+// roleCard is a role with the checkboxes of its form, a role can't be its own parent
+type roleCard struct {
+	Role        dto.AdminRole
+	Parents     []roleOption
+	Permissions []roleOption
+}
+
+// @Summary Roles Section
+// @Description This endpoint renders the roles with the forms to change them and to create new ones.
+// @Tags Admin
+// @Security JWT
+// @Produce html
+// @x-permissions ["roles:read"]
+// @router /v1/admin/roles [GET]
+func (ar *adminRoutes) getRoles(c *gin.Context) {
+	ar.renderRoleSection(c)
+}
+
+// @Summary Create Role
+// @Description Creates a role which inherits the permissions of its parents. It renders the roles section.
+// @Tags Admin
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param name formData string true "The name of the role."
+// @Param description formData string false "What the role is for."
+// @Param parents formData []string false "The names of the parent roles." collectionFormat(multi)
+// @Param permissions formData []string false "The names of the permissions." collectionFormat(multi)
+// @x-permissions ["roles:write"]
+// @router /v1/admin/roles [POST]
+func (ar *adminRoutes) postCreateRole(c *gin.Context) {
+	var roleCreateRequestBody dto.RoleCreateRequestBody
+	if err := c.ShouldBind(&roleCreateRequestBody); err != nil {
+		ar.roleFormInvalid(c)
+		return
+	}
+
+	role, err := ar.roleUC.CreateRole(roleCreateRequestBody)
+	if err != nil {
+		ar.roleActionFailed(c, err, "Failed to create role")
+		return
+	}
+
+	ar.logger.Info("Admin created role",
+		slog.String("admin", c.GetString("username")),
+		slog.String("role", role.Name),
+	)
+	ar.roleActionDone(c, "The role "+role.Name+" has been created.")
+}
+
+// @Summary Update Role
+// @Description Replaces the description, the parents and the permissions of a role, and renames it when a new name is given. It renders the roles section.
+// @Tags Admin
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param name formData string true "The name of the role."
+// @Param new_name formData string false "The new name of the role."
+// @Param description formData string false "What the role is for."
+// @Param parents formData []string false "The names of the parent roles." collectionFormat(multi)
+// @Param permissions formData []string false "The names of the permissions." collectionFormat(multi)
+// @x-permissions ["roles:write"]
+// @router /v1/admin/roles/update [POST]
+func (ar *adminRoutes) postUpdateRole(c *gin.Context) {
+	var roleUpdateRequestBody dto.RoleUpdateRequestBody
+	if err := c.ShouldBind(&roleUpdateRequestBody); err != nil {
+		ar.roleFormInvalid(c)
+		return
+	}
+
+	role, err := ar.roleUC.UpdateRole(roleUpdateRequestBody)
+	if err != nil {
+		ar.roleActionFailed(c, err, "Failed to update role")
+		return
+	}
+
+	ar.logger.Info("Admin updated role",
+		slog.String("admin", c.GetString("username")),
+		slog.String("role", roleUpdateRequestBody.Name),
+		slog.String("new_name", role.Name),
+	)
+	ar.roleActionDone(c, "The role "+role.Name+" has been saved.")
+}
+
+// @Summary Delete Role
+// @Description Deletes a role which isn't a system role and which nobody has anymore. It renders the roles section.
+// @Tags Admin
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param name formData string true "The name of the role."
+// @x-permissions ["roles:write"]
+// @router /v1/admin/roles/delete [POST]
+func (ar *adminRoutes) postDeleteRole(c *gin.Context) {
+	var roleDeleteRequestBody dto.RoleDeleteRequestBody
+	if err := c.ShouldBind(&roleDeleteRequestBody); err != nil {
+		ar.roleFormInvalid(c)
+		return
+	}
+
+	err := ar.roleUC.DeleteRole(roleDeleteRequestBody.Name)
+	if err != nil {
+		ar.roleActionFailed(c, err, "Failed to delete role")
+		return
+	}
+
+	ar.logger.Info("Admin deleted role",
+		slog.String("admin", c.GetString("username")),
+		slog.String("role", roleDeleteRequestBody.Name),
+	)
+	ar.roleActionDone(c, "The role "+roleDeleteRequestBody.Name+" has been deleted.")
+}
+
+func (ar *adminRoutes) roleActionDone(c *gin.Context, message string) {
+	c.HTML(http.StatusOK, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeSuccess,
+		"message": message,
+	})
+
+	ar.renderRoleSection(c)
+}
+
+func (ar *adminRoutes) roleActionFailed(c *gin.Context, err error, message string) {
+	toastMessage := "An unexpected error occurred. Please try again later."
+	switch {
+	case helper.IsErrOfType(err, &entity.RoleNotFoundError{}),
+		helper.IsErrOfType(err, &entity.RoleConflictError{}),
+		helper.IsErrOfType(err, &entity.RoleInUseError{}),
+		helper.IsErrOfType(err, &entity.RoleCycleError{}),
+		helper.IsErrOfType(err, &entity.InvalidRoleNameError{}),
+		helper.IsErrOfType(err, &entity.SystemRoleError{}),
+		helper.IsErrOfType(err, &entity.PermissionNotFoundError{}):
+		toastMessage = err.Error()
+	default:
+		ar.logger.Error(message, slog.Any("err", err))
+	}
+
+	// the forms keep what was typed into them
+	c.Header("HX-Reswap", "none")
+	c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeDanger,
+		"message": toastMessage,
+	})
+}
+
+// roleFormInvalid answers a form which didn't bind, names are at most 50 characters and descriptions 255
+func (ar *adminRoutes) roleFormInvalid(c *gin.Context) {
+	c.Header("HX-Reswap", "none")
+	c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeDanger,
+		"message": "Please give the role a name of at most 50 characters and a description of at most 255.",
+	})
+}
+
+func (ar *adminRoutes) renderRoleSection(c *gin.Context) {
+	roles, err := ar.roleUC.ListRoles()
+	if err != nil {
+		ar.logger.Error("Failed to list roles", slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+	permissions, err := ar.roleUC.ListPermissions()
+	if err != nil {
+		ar.logger.Error("Failed to list permissions", slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	cards := make([]roleCard, 0, len(roles))
+	for _, role := range roles {
+		card := roleCard{Role: role}
+		for _, parent := range roles {
+			if parent.Name != role.Name {
+				card.Parents = append(card.Parents, roleOption{Name: parent.Name, Checked: slices.Contains(role.Parents, parent.Name)})
+			}
+		}
+		for _, permission := range permissions {
+			card.Permissions = append(card.Permissions, roleOption{
+				Name:        permission.Name,
+				Description: permission.Description,
+				Checked:     slices.Contains(role.Permissions, permission.Name),
+			})
+		}
+		cards = append(cards, card)
+	}
+
+	c.HTML(http.StatusOK, "role-section", gin.H{
+		"roles":       cards,
+		"parents":     roles,
+		"permissions": permissions,
+	})
+}
@@ -0,0 +1,348 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// userRow is a row of the user list, the own row of the administrator has no actions
+type userRow struct {
+	User  *dto.AdminUser
+	Roles []dto.AdminRole
+	Self  bool
+}
+
+// @Summary Users Section
+// @Description This endpoint renders a page of the users with the filters above it. The next cursor of a page
+// is passed on to get the page after it, together with the same filters and sort.
+// @Tags Admin
+// @Security JWT
+// @Produce html
+// @Param q query string false "A part of the username or email."
+// @Param role query string false "The name of the role."
+// @Param status query string false "active, disabled or deleted. The users which aren't deleted when empty."
+// @Param sort query string false "username, email or created_at, descending with a leading -. By username when empty."
+// @Param cursor query string false "The next cursor of the page before."
+// @x-permissions ["users:read"]
+// @router /v1/admin/users [GET]
+func (ar *adminRoutes) getUsers(c *gin.Context) {
+	roles, err := ar.roleUC.ListRoles()
+	if err != nil {
+		ar.logger.Error("Failed to list roles", slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	var userListQuery dto.UserListQuery
+	page := &dto.UserPage{}
+	message := "Please check the filters."
+	err = c.ShouldBindQuery(&userListQuery)
+	if err == nil {
+		page, err = ar.userUC.ListUsers(userListQuery)
+		switch {
+		case helper.IsErrOfType(err, &entity.InvalidCursorError{}):
+			message = err.Error()
+		case err != nil:
+			ar.logger.Error("Failed to list users", slog.Any("err", err))
+			message = "An unexpected error occurred. Please try again later."
+		}
+	}
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": message,
+		})
+		page = &dto.UserPage{}
+	}
+
+	admin := c.GetString("username")
+	rows := make([]userRow, 0, len(page.Users))
+	for i := range page.Users {
+		rows = append(rows, userRow{User: &page.Users[i], Roles: roles, Self: page.Users[i].Username == admin})
+	}
+
+	c.HTML(http.StatusOK, "user-section", gin.H{
+		"query":      userListQuery,
+		"roles":      roles,
+		"rows":       rows,
+		"nextCursor": page.NextCursor,
+	})
+}
+
+// @Summary Delete User Dialog
+// @Description This endpoint renders the dialog which asks to confirm the deletion of a user.
+// @Tags Admin
+// @Security JWT
+// @Produce html
+// @Param id query int true "The id of the user."
+// @x-permissions ["users:write"]
+// @router /v1/admin/users/delete [GET]
+func (ar *adminRoutes) getDeleteUser(c *gin.Context) {
+	var userIDQuery dto.UserIDQuery
+	if err := c.ShouldBindQuery(&userIDQuery); err != nil {
+		ar.userActionFailed(c, &entity.UserNotFoundError{}, "", 0)
+		return
+	}
+
+	user, err := ar.userUC.GetUser(userIDQuery.ID)
+	if err != nil {
+		ar.userActionFailed(c, err, "Failed to get user", 0)
+		return
+	}
+
+	c.HTML(http.StatusOK, "user-modal", gin.H{
+		"user": user,
+	})
+}
+
+// @Summary Change User Role
+// @Description Gives a user another role, it applies once the tokens of the user are refreshed. It renders the row of the user.
+// @Tags Admin
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param id formData int true "The id of the user."
+// @Param role formData string true "The name of the role."
+// @x-permissions ["users:write"]
+// @router /v1/admin/users/update [POST]
+func (ar *adminRoutes) postUpdateUser(c *gin.Context) {
+	var userUpdateRequestBody dto.UserUpdateRequestBody
+	if err := c.ShouldBind(&userUpdateRequestBody); err != nil {
+		ar.userActionFailed(c, &entity.UserNotFoundError{}, "", userUpdateRequestBody.ID)
+		return
+	}
+
+	user, err := ar.userUC.UpdateUser(dto.UserUpdateRequestBody{
+		ID:   userUpdateRequestBody.ID,
+		Role: userUpdateRequestBody.Role,
+	}, c.GetString("username"))
+	if err != nil {
+		// the row goes back to the role the user has
+		ar.userActionFailed(c, err, "Failed to update user", userUpdateRequestBody.ID)
+		return
+	}
+
+	ar.logger.Info("Admin changed the role of user",
+		slog.String("admin", c.GetString("username")),
+		slog.String("username", user.Username),
+		slog.String("role", user.Role),
+	)
+	ar.renderUserRow(c, user, "The role of "+user.Username+" has been changed.")
+}
+
+// @Summary Disable User
+// @Description Disables a user, who is logged out everywhere and can't log in until enabled again. It renders the row of the user.
+// @Tags Admin
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param id formData int true "The id of the user."
+// @x-permissions ["users:write"]
+// @router /v1/admin/users/disable [POST]
+func (ar *adminRoutes) postDisableUser(c *gin.Context) {
+	ar.setUserDisabled(c, true)
+}
+
+// @Summary Enable User
+// @Description Enables a disabled user, who can log in again. It renders the row of the user.
+// @Tags Admin
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param id formData int true "The id of the user."
+// @x-permissions ["users:write"]
+// @router /v1/admin/users/enable [POST]
+func (ar *adminRoutes) postEnableUser(c *gin.Context) {
+	ar.setUserDisabled(c, false)
+}
+
+func (ar *adminRoutes) setUserDisabled(c *gin.Context, disabled bool) {
+	var userIDRequestBody dto.UserIDRequestBody
+	if err := c.ShouldBind(&userIDRequestBody); err != nil {
+		ar.userActionFailed(c, &entity.UserNotFoundError{}, "", 0)
+		return
+	}
+
+	user, err := ar.userUC.SetUserDisabled(userIDRequestBody.ID, disabled, c.GetString("username"), helper.GetConfig(c))
+	if err != nil {
+		ar.userActionFailed(c, err, "Failed to update user", userIDRequestBody.ID)
+		return
+	}
+
+	ar.logger.Info("Admin changed whether user is disabled",
+		slog.String("admin", c.GetString("username")),
+		slog.String("username", user.Username),
+		slog.Bool("disabled", disabled),
+	)
+
+	message := user.Username + " has been enabled."
+	if disabled {
+		message = user.Username + " has been disabled and logged out everywhere."
+	}
+	ar.renderUserRow(c, user, message)
+}
+
+// @Summary Send Password Reset
+// @Description Sends a user the link to choose a new password, as if they had asked for it themselves.
+// @Tags Admin
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param id formData int true "The id of the user."
+// @x-permissions ["users:write"]
+// @router /v1/admin/users/reset-password [POST]
+func (ar *adminRoutes) postResetUserPassword(c *gin.Context) {
+	var userIDRequestBody dto.UserIDRequestBody
+	if err := c.ShouldBind(&userIDRequestBody); err != nil {
+		ar.userActionFailed(c, &entity.UserNotFoundError{}, "", 0)
+		return
+	}
+
+	user, err := ar.userUC.GetUser(userIDRequestBody.ID)
+	if err == nil && user.DeletedAt != nil {
+		err = &entity.UserNotFoundError{}
+	}
+	if err == nil {
+		err = ar.authUC.RequestPasswordReset(user.Email, helper.GetConfig(c))
+	}
+	if err != nil {
+		ar.userActionFailed(c, err, "Failed to send password reset", 0)
+		return
+	}
+
+	ar.logger.Info("Admin sent a password reset to user",
+		slog.String("admin", c.GetString("username")),
+		slog.String("username", user.Username),
+	)
+	c.HTML(http.StatusOK, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeSuccess,
+		"message": "A reset link has been sent to " + user.Email + ".",
+	})
+}
+
+// @Summary Delete User
+// @Description Deletes a user, who is logged out everywhere. The user is kept, so it can be restored. It renders the row of the user and closes the dialog.
+// @Tags Admin
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param id formData int true "The id of the user."
+// @x-permissions ["users:write"]
+// @router /v1/admin/users/delete [POST]
+func (ar *adminRoutes) postDeleteUser(c *gin.Context) {
+	var userIDRequestBody dto.UserIDRequestBody
+	if err := c.ShouldBind(&userIDRequestBody); err != nil {
+		ar.userActionFailed(c, &entity.UserNotFoundError{}, "", 0)
+		return
+	}
+
+	user, err := ar.userUC.DeleteUser(userIDRequestBody.ID, c.GetString("username"), helper.GetConfig(c))
+	if err != nil {
+		ar.userActionFailed(c, err, "Failed to delete user", 0)
+		return
+	}
+
+	ar.logger.Info("Admin deleted user",
+		slog.String("admin", c.GetString("username")),
+		slog.String("username", user.Username),
+	)
+	c.HTML(http.StatusOK, "user-modal", gin.H{
+		"oob": true,
+	})
+	ar.renderUserRow(c, user, user.Username+" has been deleted.")
+}
+
+// @Summary Restore User
+// @Description Restores a deleted user, who can log in again. It renders the row of the user.
+// @Tags Admin
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param id formData int true "The id of the user."
+// @x-permissions ["users:write"]
+// @router /v1/admin/users/restore [POST]
+func (ar *adminRoutes) postRestoreUser(c *gin.Context) {
+	var userIDRequestBody dto.UserIDRequestBody
+	if err := c.ShouldBind(&userIDRequestBody); err != nil {
+		ar.userActionFailed(c, &entity.UserNotFoundError{}, "", 0)
+		return
+	}
+
+	user, err := ar.userUC.RestoreUser(userIDRequestBody.ID)
+	if err != nil {
+		ar.userActionFailed(c, err, "Failed to restore user", 0)
+		return
+	}
+
+	ar.logger.Info("Admin restored user",
+		slog.String("admin", c.GetString("username")),
+		slog.String("username", user.Username),
+	)
+	ar.renderUserRow(c, user, user.Username+" has been restored.")
+}
+
+// renderUserRow answers an action on a user with a toast and the new row of the user
+func (ar *adminRoutes) renderUserRow(c *gin.Context, user *dto.AdminUser, message string) {
+	roles, err := ar.roleUC.ListRoles()
+	if err != nil {
+		ar.logger.Error("Failed to list roles", slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	c.HTML(http.StatusOK, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeSuccess,
+		"message": message,
+	})
+
+	c.HTML(http.StatusOK, "user-row", userRow{
+		User:  user,
+		Roles: roles,
+		Self:  user.Username == c.GetString("username"),
+	})
+}
+
+// userActionFailed shows why an action on a user failed. Unless the id is 0 the row of the user is
+// rendered again, so it shows the user as it is, otherwise nothing is swapped.
+func (ar *adminRoutes) userActionFailed(c *gin.Context, err error, message string, id uint) {
+	toastMessage := "An unexpected error occurred. Please try again later."
+	switch {
+	case helper.IsErrOfType(err, &entity.UserNotFoundError{}),
+		helper.IsErrOfType(err, &entity.ErrDuplicateUser{}),
+		helper.IsErrOfType(err, &entity.RoleNotFoundError{}),
+		helper.IsErrOfType(err, &entity.OwnAccountError{}):
+		toastMessage = err.Error()
+	default:
+		ar.logger.Error(message, slog.Any("err", err))
+	}
+
+	var row *userRow
+	if id != 0 {
+		user, err := ar.userUC.GetUser(id)
+		roles, rolesErr := ar.roleUC.ListRoles()
+		if err == nil && rolesErr == nil {
+			row = &userRow{User: user, Roles: roles, Self: user.Username == c.GetString("username")}
+		}
+	}
+	if row == nil {
+		c.Header("HX-Reswap", "none")
+	}
+
+	c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeDanger,
+		"message": toastMessage,
+	})
+
+	if row != nil {
+		c.HTML(http.StatusOK, "user-row", row)
+	}
+}
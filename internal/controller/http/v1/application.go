@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// @Summary Apps Section
+// @Description This endpoint renders the applications the user may open as a section of the launcher page. It is empty for anonymous users.
+// @Tags Authen
+// @Produce html
+// @router /v1/auth/apps [GET]
+func (ar *authRoutes) getApps(c *gin.Context) {
+	ar.renderApps(c, "apps-section")
+}
+
+// @Summary Apps Menu
+// @Description This endpoint renders the applications the user may open in the apps dropdown of the navbar. It is empty for anonymous users.
+// @Tags Authen
+// @Produce html
+// @router /v1/auth/apps/menu [GET]
+func (ar *authRoutes) getAppsMenu(c *gin.Context) {
+	ar.renderApps(c, "apps-menu")
+}
+
+func (ar *authRoutes) renderApps(c *gin.Context, name string) {
+	username := c.GetString("username")
+	if username == "" {
+		c.HTML(http.StatusOK, name, gin.H{})
+		return
+	}
+
+	applications, err := ar.authUC.ListLauncherApplications(username)
+	if err != nil {
+		ar.logger.Error("Failed to list applications", slog.String("username", username), slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	c.HTML(http.StatusOK, name, gin.H{
+		"username":     username,
+		"applications": applications,
+	})
+}
@@ -1,14 +1,20 @@
 package v1
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/minhmannh2001/authconnecthub/internal/dto"
 	"github.com/minhmannh2001/authconnecthub/internal/entity"
 	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/middlewares"
 	"github.com/minhmannh2001/authconnecthub/internal/usecases"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -26,13 +32,18 @@ func NewAuthenRoutes(handler *gin.RouterGroup,
 	a usecases.IAuthUC,
 	u usecases.IUserUC,
 	r usecases.IRoleUC,
+	rl usecases.IRateLimitUC,
 ) {
 	ar := &authRoutes{l, a, u, r}
 
+	// these routes aren't documented in the swagger file, so they declare their limits here
+	registerLimit := middlewares.RouteRateLimit(rl, entity.RateLimit{Limit: 5, Period: 3600, Key: entity.RateLimitKeyIP})
+
 	h := handler.Group("/auth")
 	{
 		h.GET("/login", ar.getLogin)
-		h.POST("/login", ar.postLogin)
+		h.POST("/login", middlewares.RouteRateLimit(rl, entity.RateLimit{Limit: 10, Period: 60, Key: entity.RateLimitKeyIP}), ar.postLogin)
+		h.POST("/login/mfa", ar.postLoginMFA)
 
 		h.GET("/register", func(c *gin.Context) {
 			c.HTML(http.StatusOK, "register.html", gin.H{
@@ -42,10 +53,53 @@ func NewAuthenRoutes(handler *gin.RouterGroup,
 				},
 			})
 		})
-		h.POST("/register", ar.register)
+		h.POST("/register", registerLimit, ar.register)
+
+		h.GET("/verify-email", ar.verifyEmail)
+		h.GET("/verify", ar.getForwardAuth)
+
+		h.GET("/forget-password", ar.getForgetPassword)
+		h.POST("/forget-password", middlewares.RouteRateLimit(rl, entity.RateLimit{Limit: 5, Period: 900, Key: entity.RateLimitKeyIP}), ar.postForgetPassword)
+
+		h.GET("/reset-password", ar.getResetPassword)
+		h.POST("/reset-password", ar.postResetPassword)
+
+		h.GET("/password", ar.getChangePassword)
+		h.POST("/password", ar.postChangePassword)
+
+		h.GET("/totp", ar.getTOTP)
+		h.POST("/totp/enroll", ar.postEnrollTOTP)
+		h.POST("/totp/confirm", ar.postConfirmTOTP)
+		h.POST("/totp/disable", ar.postDisableTOTP)
+		h.POST("/totp/recovery-codes", ar.postRegenerateRecoveryCodes)
+
+		h.GET("/passkey", ar.getPasskeys)
+		h.POST("/passkey/register/begin", ar.postBeginPasskeyRegistration)
+		h.POST("/passkey/register/finish", ar.postFinishPasskeyRegistration)
+		h.POST("/passkey/delete", ar.postDeletePasskey)
+		h.POST("/passkey/login/begin", ar.postBeginPasskeyLogin)
+		h.POST("/passkey/login/finish", ar.postFinishPasskeyLogin)
+
+		h.GET("/federation", ar.getFederation)
+		h.POST("/federation/login", ar.postFederationLogin)
+		h.GET("/federation/callback", ar.getFederationCallback)
+		h.POST("/federation/link", ar.postFederationLink)
+		h.POST("/federation/unlink", ar.postFederationUnlink)
+
+		h.GET("/apps", ar.getApps)
+		h.GET("/apps/menu", ar.getAppsMenu)
+
+		h.GET("/sessions", ar.getSessions)
+		h.POST("/sessions/revoke", ar.postRevokeSession)
+		h.POST("/sessions/logout-everywhere", ar.postLogoutEverywhere)
 
 		h.GET("/logout", ar.LogoutHandler)
 	}
+
+	o := handler.Group("/oidc")
+	{
+		o.GET("/authorize", ar.getAuthorize)
+	}
 }
 
 // @Summary Login Page
@@ -56,10 +110,16 @@ func NewAuthenRoutes(handler *gin.RouterGroup,
 // @Param toast-message query string false "The message to display in the toast notification.""
 // @Param toast-type query string false "The type of the toast notification (e.g., success, error).""
 // @Param hash-value query string false "A hash value used for validation."
+// @Param rd query string false "The url of an app behind forward auth to go back to after the login."
 // @router /v1/auth/login [GET]
 func (ar *authRoutes) getLogin(c *gin.Context) {
 	queryParams := c.Request.URL.Query()
 
+	// forward auth sent the user here from an app, go back to it afterwards
+	if rd := queryParams.Get("rd"); rd != "" && isAllowedLoginRedirect(c, rd) {
+		setLoginRedirect(c, rd)
+	}
+
 	toastMessage := helper.ExtractQueryParam(queryParams, "toast-message", "")
 	toastType := helper.ExtractQueryParam(queryParams, "toast-type", "")
 	hashValue := helper.ExtractQueryParam(queryParams, "hash-value", "")
@@ -79,9 +139,32 @@ func (ar *authRoutes) getLogin(c *gin.Context) {
 		"title":         "Personal Hub",
 		"toastSettings": toastSettings,
 		"reload":        c.GetHeader("HX-Reload"),
+		"providers":     helper.GetConfig(c).Federation.Providers,
+		"mfa":           takeLoginMFA(c),
 	})
 }
 
+// takeLoginMFA returns the pending login an upstream provider started, if any, and forgets it.
+// The login page asks for the second factor of it right away.
+func takeLoginMFA(c *gin.Context) gin.H {
+	value, err := c.Cookie(loginMFACookie)
+	if err != nil {
+		return nil
+	}
+
+	c.SetCookie(loginMFACookie, "", -1, "/v1/auth/login", "", isSecure(c), true)
+	pending, err := url.ParseQuery(value)
+	if err != nil || pending.Get("token") == "" {
+		return nil
+	}
+	return gin.H{
+		"inputData": map[string]string{
+			"token":       pending.Get("token"),
+			"remember_me": pending.Get("remember_me"),
+		},
+	}
+}
+
 func (ar *authRoutes) register(c *gin.Context) {
 	var registerRequestBody dto.RegisterRequestBody
 
@@ -215,9 +298,36 @@ func (ar *authRoutes) register(c *gin.Context) {
 		return
 	}
 
+	ar.logger.Info("User created", slog.String("username", newUser.Username), slog.Any("roleID", newUser.RoleID), slog.String("email", newUser.Email))
+
 	cfg := helper.GetConfig(c)
-	accessToken, _ := ar.authUC.CreateAccessToken(user, cfg.Authen.AccessTokenTTL)
-	refreshToken, _ := ar.authUC.CreateRefreshToken(user, accessToken, cfg.Authen.RefreshTokenTTL)
+	// the account exists already, a lost mail is sent again on a login attempt once its link expired
+	if err := ar.authUC.SendVerificationEmail(newUser, cfg); err != nil {
+		ar.logger.Error("Failed to send verification email", slog.String("username", newUser.Username), slog.Any("err", err))
+	}
+
+	if cfg.Authen.RequireEmailVerification {
+		toastMessage := "account-created.-check-your-email-to-verify-your-address-before-logging-in."
+		hashValue, err := helper.HashMap(map[string]interface{}{
+			"toast-message": toastMessage,
+			"toast-type":    dto.ToastTypeSuccess,
+		})
+		if err != nil {
+			ar.logger.Error("Failed to generate toast message hash", slog.Any("err", err))
+			helper.HandleInternalError(c, err)
+			return
+		}
+
+		c.Header("HX-Redirect", fmt.Sprintf("/v1/auth/login?toast-message=%s&toast-type=%s&hash-value=%s", toastMessage, dto.ToastTypeSuccess, hashValue))
+		return
+	}
+
+	jwtTokens, err := ar.authUC.GenerateTokens(newUser, cfg)
+	if err != nil {
+		ar.logger.Error("Failed to generate tokens", slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
 
 	hashValue, err := helper.HashMap(map[string]interface{}{
 		"toast-message": "user-registered-successfully",
@@ -227,14 +337,7 @@ func (ar *authRoutes) register(c *gin.Context) {
 		ar.logger.Error("Failed to generate toast message hash", slog.Any("err", err))
 	}
 
-	ar.logger.Info("User created", slog.String("username", newUser.Username), slog.Any("roleID", newUser.RoleID), slog.String("email", newUser.Email))
-	HXTriggerEvents, _ := helper.MapToJSONString(map[string]interface{}{
-		"saveToken": map[string]interface{}{
-			"accessToken":  accessToken,
-			"refreshToken": refreshToken,
-		},
-	})
-	c.Header("HX-Trigger", HXTriggerEvents)
+	_ = helper.SaveTokens(c, jwtTokens, false)
 	c.Header("HX-Redirect", fmt.Sprintf("/?toast-message=user-registered-successfully&toast-type=%s&hash-value=%s", dto.ToastTypeSuccess, hashValue))
 }
 
@@ -275,7 +378,42 @@ func (ar *authRoutes) postLogin(c *gin.Context) {
 			"remember_me": loginRequestBody.RememberMe,
 		}
 
-		if helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+		var mfaRequiredErr *entity.MFARequiredError
+		if errors.As(err, &mfaRequiredErr) {
+			c.HTML(http.StatusOK, "toast-section", gin.H{
+				"hidden":  false,
+				"type":    dto.ToastTypeWarning,
+				"message": err.Error(),
+			})
+
+			c.HTML(http.StatusOK, "login-mfa-form", gin.H{
+				"inputData": map[string]string{
+					"token":       mfaRequiredErr.Token,
+					"remember_me": loginRequestBody.RememberMe,
+				},
+			})
+			return
+		}
+
+		if setLoginRetryAfter(c, err) {
+			locked := helper.IsErrOfType(err, &entity.LoginLockedError{})
+			if locked {
+				ar.logger.Warn("Login locked", slog.String("username", loginRequestBody.Username), slog.String("ip", c.ClientIP()))
+			}
+			c.HTML(http.StatusTooManyRequests, "toast-section", gin.H{
+				"hidden":  false,
+				"type":    dto.ToastTypeWarning,
+				"message": err.Error(),
+			})
+
+			c.HTML(http.StatusOK, "login-form", gin.H{
+				"inputData":      inputData,
+				"validationFail": true,
+				"validationMap":  map[string]string{},
+				"locked":         locked,
+			})
+		} else if helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) || helper.IsErrOfType(err, &entity.EmailNotVerifiedError{}) ||
+			helper.IsErrOfType(err, &entity.DirectoryUserConflictError{}) || helper.IsErrOfType(err, &entity.UserDisabledError{}) {
 			c.HTML(http.StatusBadRequest, "toast-section", gin.H{
 				"hidden":  false,
 				"type":    dto.ToastTypeDanger,
@@ -288,6 +426,7 @@ func (ar *authRoutes) postLogin(c *gin.Context) {
 				"validationMap":  map[string]string{},
 			})
 		} else {
+			ar.logger.Error("Failed to log in", slog.String("username", loginRequestBody.Username), slog.Any("err", err))
 			c.HTML(http.StatusBadRequest, "toast-section", gin.H{
 				"hidden":  false,
 				"type":    dto.ToastTypeDanger,
@@ -303,19 +442,33 @@ func (ar *authRoutes) postLogin(c *gin.Context) {
 		return
 	}
 
-	saveTo := "session"
-	if loginRequestBody.RememberMe == "on" {
-		saveTo = "local"
+	ar.logger.Info("User logged in", slog.String("username", loginRequestBody.Username))
+	ar.finishLogin(c, jwtTokens, loginRequestBody.RememberMe)
+}
+
+// setLoginRetryAfter tells the client when to try again after a login was rejected because of failed
+// ones before. It reports whether that was the reason.
+func setLoginRetryAfter(c *gin.Context, err error) bool {
+	var until time.Time
+	var loginLockedErr *entity.LoginLockedError
+	var loginThrottledErr *entity.LoginThrottledError
+	switch {
+	case errors.As(err, &loginLockedErr):
+		until = loginLockedErr.Until
+	case errors.As(err, &loginThrottledErr):
+		until = loginThrottledErr.Until
+	default:
+		return false
 	}
 
-	HXTriggerEvents, err := helper.MapToJSONString(map[string]interface{}{
-		"saveToken": map[string]interface{}{
-			"saveTo":       saveTo,
-			"accessToken":  jwtTokens.AccessToken,
-			"refreshToken": jwtTokens.RefreshToken,
-		},
-	})
-	if err != nil {
+	c.Header("Retry-After", strconv.Itoa(max(1, int(math.Ceil(time.Until(until).Seconds())))))
+	return true
+}
+
+// finishLogin hands the tokens over to the browser and redirects to the home page,
+// or to where the user was sent to the login page from
+func (ar *authRoutes) finishLogin(c *gin.Context, jwtTokens *dto.JwtTokens, rememberMe string) {
+	if err := helper.SaveTokens(c, jwtTokens, rememberMe == "on"); err != nil {
 		ar.logger.Error("Failed to create HX-Trigger events", slog.Any("err", err))
 	}
 
@@ -329,9 +482,97 @@ func (ar *authRoutes) postLogin(c *gin.Context) {
 		return
 	}
 
-	ar.logger.Info("User logged in", slog.String("username", loginRequestBody.Username))
-	c.Header("HX-Trigger", HXTriggerEvents)
-	c.Header("HX-Redirect", fmt.Sprintf("/?toast-message=login-successfully&toast-type=%s&hash-value=%s", dto.ToastTypeSuccess, hashValue))
+	redirectURL := fmt.Sprintf("/?toast-message=login-successfully&toast-type=%s&hash-value=%s", dto.ToastTypeSuccess, hashValue)
+	// an application sent the user to log in, go back to it
+	if path := takeLoginRedirect(c); path != "" {
+		redirectURL = path
+	}
+
+	c.Header("HX-Redirect", redirectURL)
+}
+
+// @Summary Login Second Step
+// @Description Finishes a login of a user with two-factor authentication by checking the code of their authenticator app.
+// @Tags Authen
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param token formData string true "The pending login token returned by the first step."
+// @Param code formData string true "The 6 digit code of the authenticator app or a recovery code."
+// @Param remember_me formData string false "Keep the user logged in."
+// @x-rate-limit {"limit": 10, "period": 60, "key": "ip"}
+// @router /v1/auth/login/mfa [POST]
+func (ar *authRoutes) postLoginMFA(c *gin.Context) {
+	var mfaLoginRequestBody dto.MFALoginRequestBody
+
+	err := c.ShouldBind(&mfaLoginRequestBody)
+	inputData := map[string]string{
+		"token":       mfaLoginRequestBody.Token,
+		"remember_me": mfaLoginRequestBody.RememberMe,
+	}
+
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": "Please enter a code of your authenticator app or a recovery code.",
+		})
+
+		c.HTML(http.StatusOK, "login-mfa-form", gin.H{
+			"inputData":      inputData,
+			"validationFail": true,
+			"validationMap":  helper.GenerateValidationMap(err),
+		})
+		return
+	}
+
+	jwtTokens, err := ar.authUC.VerifyMFALogin(mfaLoginRequestBody, c.ClientIP(), helper.GetConfig(c))
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.InvalidMFATokenError{}) {
+			toastMessage := "your-sign-in-attempt-has-expired.-please-log-in-again."
+			hashValue, err := helper.HashMap(map[string]interface{}{
+				"toast-message": toastMessage,
+				"toast-type":    dto.ToastTypeDanger,
+			})
+			if err != nil {
+				ar.logger.Error("Failed to generate toast message hash", slog.Any("err", err))
+				helper.HandleInternalError(c, err)
+				return
+			}
+
+			c.Header("HX-Redirect", fmt.Sprintf("/v1/auth/login?toast-message=%s&toast-type=%s&hash-value=%s", toastMessage, dto.ToastTypeDanger, hashValue))
+			return
+		}
+
+		status := http.StatusBadRequest
+		message := "An unexpected error occurred. Please try again later."
+		if setLoginRetryAfter(c, err) {
+			if helper.IsErrOfType(err, &entity.LoginLockedError{}) {
+				ar.logger.Warn("Login locked at second factor", slog.String("ip", c.ClientIP()))
+			}
+			status = http.StatusTooManyRequests
+			message = err.Error()
+		} else if helper.IsErrOfType(err, &entity.InvalidTOTPCodeError{}) || helper.IsErrOfType(err, &entity.UserDisabledError{}) {
+			message = err.Error()
+		} else {
+			ar.logger.Error("Failed to verify second factor", slog.Any("err", err))
+		}
+
+		c.HTML(status, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": message,
+		})
+
+		c.HTML(http.StatusOK, "login-mfa-form", gin.H{
+			"inputData":      inputData,
+			"validationFail": true,
+			"validationMap":  map[string]string{},
+		})
+		return
+	}
+
+	ar.logger.Info("User logged in with second factor")
+	ar.finishLogin(c, jwtTokens, mfaLoginRequestBody.RememberMe)
 }
 
 // @Summary Logout User
@@ -359,3 +600,274 @@ func (ar *authRoutes) LogoutHandler(c *gin.Context) {
 	ar.logger.Error("User logged out", slog.Any("username", c.MustGet("username")))
 	c.Header("HX-Redirect", fmt.Sprintf("/?toast-message=logout-successfully&toast-type=%s&hash-value=%s", dto.ToastTypeSuccess, hashValue))
 }
+
+// @Summary Verify Email
+// @Description This endpoint verifies the email address of a user with the link sent on registration and redirects to the login page with a toast notification.
+// @Tags Authen
+// @Produce html
+// @Param token query string true "The verification token sent by email."
+// @router /v1/auth/verify-email [GET]
+func (ar *authRoutes) verifyEmail(c *gin.Context) {
+	token := helper.ExtractQueryParam(c.Request.URL.Query(), "token", "")
+
+	toastMessage := "your-email-has-been-verified.-you-can-now-log-in."
+	toastType := dto.ToastTypeSuccess
+
+	err := ar.authUC.VerifyEmail(token, helper.GetConfig(c))
+	if err != nil {
+		toastType = dto.ToastTypeDanger
+		if helper.IsErrOfType(err, &entity.InvalidEmailVerificationTokenError{}) {
+			toastMessage = "your-verification-link-is-invalid-or-has-expired."
+		} else {
+			ar.logger.Error("Failed to verify email", slog.Any("err", err))
+			toastMessage = "an-unexpected-error-occurred.-please-try-again-later."
+		}
+	}
+
+	hashValue, err := helper.HashMap(map[string]interface{}{
+		"toast-message": toastMessage,
+		"toast-type":    toastType,
+	})
+	if err != nil {
+		ar.logger.Error("Failed to generate toast message hash", slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	c.Header("HX-Redirect", fmt.Sprintf("/v1/auth/login?toast-message=%s&toast-type=%s&hash-value=%s", toastMessage, toastType, hashValue))
+}
+
+// @Summary Forget Password Page
+// @Description This endpoint renders the page where users can ask for a password reset link.
+// @Tags Authen
+// @Produce html
+// @router /v1/auth/forget-password [GET]
+func (ar *authRoutes) getForgetPassword(c *gin.Context) {
+	c.HTML(http.StatusOK, "forget_password.html", gin.H{
+		"title": "Personal Hub",
+		"toastSettings": map[string]interface{}{
+			"hidden": true,
+		},
+		"reload": c.GetHeader("HX-Reload"),
+	})
+}
+
+func (ar *authRoutes) postForgetPassword(c *gin.Context) {
+	var forgetPasswordRequestBody dto.ForgetPasswordRequestBody
+
+	if err := c.ShouldBind(&forgetPasswordRequestBody); err != nil {
+		validationMap := helper.GenerateValidationMap(err)
+
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": "Please enter a valid email address.",
+		})
+
+		c.HTML(http.StatusOK, "forget-password-form", gin.H{
+			"inputData": map[string]string{
+				"email": forgetPasswordRequestBody.Email,
+			},
+			"validationFail": true,
+			"validationMap":  validationMap,
+		})
+		return
+	}
+
+	err := ar.authUC.RequestPasswordReset(forgetPasswordRequestBody.Email, helper.GetConfig(c))
+	if err != nil {
+		ar.logger.Error("Failed to request password reset", slog.Any("err", err))
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": "An unexpected error occurred. Please try again later.",
+		})
+
+		c.HTML(http.StatusOK, "forget-password-form", gin.H{
+			"inputData": map[string]string{
+				"email": forgetPasswordRequestBody.Email,
+			},
+			"validationFail": true,
+			"validationMap":  map[string]string{},
+		})
+		return
+	}
+
+	toastMessage := "if-an-account-exists-for-this-email,-you-will-receive-a-reset-link-shortly."
+	hashValue, err := helper.HashMap(map[string]interface{}{
+		"toast-message": toastMessage,
+		"toast-type":    dto.ToastTypeSuccess,
+	})
+	if err != nil {
+		ar.logger.Error("Failed to generate toast message hash", slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	c.Header("HX-Redirect", fmt.Sprintf("/v1/auth/login?toast-message=%s&toast-type=%s&hash-value=%s", toastMessage, dto.ToastTypeSuccess, hashValue))
+}
+
+// @Summary Reset Password Page
+// @Description This endpoint renders the page where users choose a new password using the link sent to their email.
+// @Tags Authen
+// @Produce html
+// @Param token query string true "The reset password token sent by email."
+// @router /v1/auth/reset-password [GET]
+func (ar *authRoutes) getResetPassword(c *gin.Context) {
+	token := helper.ExtractQueryParam(c.Request.URL.Query(), "token", "")
+
+	c.HTML(http.StatusOK, "reset_password.html", gin.H{
+		"title": "Personal Hub",
+		"toastSettings": map[string]interface{}{
+			"hidden": true,
+		},
+		"reload": c.GetHeader("HX-Reload"),
+		"inputData": map[string]string{
+			"token": token,
+		},
+	})
+}
+
+func (ar *authRoutes) postResetPassword(c *gin.Context) {
+	var resetPasswordRequestBody dto.ResetPasswordRequestBody
+
+	err := c.ShouldBind(&resetPasswordRequestBody)
+	inputData := map[string]string{
+		"token": resetPasswordRequestBody.Token,
+	}
+
+	if err != nil {
+		validationMap := helper.GenerateValidationMap(err)
+
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": "Failed to reset password.",
+		})
+
+		c.HTML(http.StatusOK, "reset-password-form", gin.H{
+			"inputData":      inputData,
+			"validationFail": true,
+			"validationMap":  validationMap,
+		})
+		return
+	}
+
+	err = ar.authUC.ResetPassword(resetPasswordRequestBody.Token, resetPasswordRequestBody.Password, helper.GetConfig(c))
+	if err != nil {
+		message := "An unexpected error occurred. Please try again later."
+		if helper.IsErrOfType(err, &entity.InvalidResetPasswordTokenError{}) {
+			message = err.Error()
+		} else {
+			ar.logger.Error("Failed to reset password", slog.Any("err", err))
+		}
+
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": message,
+		})
+
+		c.HTML(http.StatusOK, "reset-password-form", gin.H{
+			"inputData":      inputData,
+			"validationFail": false,
+			"validationMap":  map[string]string{},
+		})
+		return
+	}
+
+	toastMessage := "your-password-has-been-reset.-please-log-in-with-your-new-password."
+	hashValue, err := helper.HashMap(map[string]interface{}{
+		"toast-message": toastMessage,
+		"toast-type":    dto.ToastTypeSuccess,
+	})
+	if err != nil {
+		ar.logger.Error("Failed to generate toast message hash", slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	// tokens of this browser are blacklisted as well, so drop them
+	helper.DeleteTokens(c, true, true)
+	c.Header("HX-Redirect", fmt.Sprintf("/v1/auth/login?toast-message=%s&toast-type=%s&hash-value=%s", toastMessage, dto.ToastTypeSuccess, hashValue))
+}
+
+// @Summary Change Password Page
+// @Description This endpoint renders the page where logged in users can change their password.
+// @Tags Authen
+// @Security JWT
+// @Produce html
+// @router /v1/auth/password [GET]
+func (ar *authRoutes) getChangePassword(c *gin.Context) {
+	c.HTML(http.StatusOK, "change_password.html", gin.H{
+		"title": "Personal Hub",
+		"toastSettings": map[string]interface{}{
+			"hidden": true,
+		},
+		"reload": c.GetHeader("HX-Reload"),
+	})
+}
+
+// @Summary Change Password
+// @Description Changes the password of the logged in user and logs out every other session of the user.
+// @Tags Authen
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param current_password formData string true "The current password."
+// @Param password formData string true "The new password."
+// @Param confirm_password formData string true "The new password again."
+// @x-rate-limit {"limit": 5, "period": 60, "key": "user"}
+// @router /v1/auth/password [POST]
+func (ar *authRoutes) postChangePassword(c *gin.Context) {
+	var changePasswordRequestBody dto.ChangePasswordRequestBody
+
+	if err := c.ShouldBind(&changePasswordRequestBody); err != nil {
+		validationMap := helper.GenerateValidationMap(err)
+
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": "Failed to change password.",
+		})
+
+		c.HTML(http.StatusOK, "change-password-form", gin.H{
+			"validationFail": true,
+			"validationMap":  validationMap,
+		})
+		return
+	}
+
+	err := ar.authUC.ChangePassword(c, changePasswordRequestBody)
+	if err != nil {
+		validationMap := map[string]string{}
+		message := "An unexpected error occurred. Please try again later."
+		if helper.IsErrOfType(err, &entity.IncorrectPasswordError{}) {
+			message = err.Error()
+			validationMap["currentPassword"] = err.Error()
+		} else {
+			ar.logger.Error("Failed to change password", slog.Any("username", c.GetString("username")), slog.Any("err", err))
+		}
+
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": message,
+		})
+
+		c.HTML(http.StatusOK, "change-password-form", gin.H{
+			"validationFail": true,
+			"validationMap":  validationMap,
+		})
+		return
+	}
+
+	ar.logger.Info("User changed password", slog.String("username", c.GetString("username")))
+	c.HTML(http.StatusOK, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeSuccess,
+		"message": "Your password has been changed. Other sessions have been logged out.",
+	})
+
+	c.HTML(http.StatusOK, "change-password-form", gin.H{})
+}
@@ -0,0 +1,256 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// federationStateCookie binds a sign in at an upstream provider to the browser it was started in,
+// loginMFACookie hands the pending login over to the login page when the second factor is still needed
+const (
+	federationStateCookie = "federation_state"
+	loginMFACookie        = "login_mfa"
+)
+
+// @Summary Begin Federated Login
+// @Description Starts a sign in at an upstream OpenID Connect provider, the browser is sent to its login page with the HX-Redirect header.
+// @Tags Authen
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param provider formData string true "The id of the provider."
+// @Param remember_me formData string false "Keep the user logged in."
+// @x-rate-limit {"limit": 20, "period": 60, "key": "ip"}
+// @router /v1/auth/federation/login [POST]
+func (ar *authRoutes) postFederationLogin(c *gin.Context) {
+	var federationLoginRequestBody dto.FederationLoginRequestBody
+	if err := c.ShouldBind(&federationLoginRequestBody); err != nil {
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": (&entity.UpstreamProviderNotFoundError{}).Error(),
+		})
+		return
+	}
+
+	ar.beginFederation(c, federationLoginRequestBody.Provider, federationLoginRequestBody.RememberMe == "on", "")
+}
+
+// @Summary Federated Login Callback
+// @Description The upstream provider sends the browser back here. The user is logged in, signed up with the customer role when the identity is new, or the identity is linked to the logged in user.
+// A new identity whose email belongs to an existing user isn't linked automatically, the user has to log in and link it from their profile.
+// @Tags Authen
+// @Produce html
+// @Param code query string false "The authorization code."
+// @Param state query string true "The state handed out when the sign in began."
+// @Param error query string false "Set when the provider turned the sign in down."
+// @Param error_description query string false "Why the provider turned the sign in down."
+// @x-rate-limit {"limit": 20, "period": 60, "key": "ip"}
+// @router /v1/auth/federation/callback [GET]
+func (ar *authRoutes) getFederationCallback(c *gin.Context) {
+	var federationCallbackQuery dto.FederationCallbackQuery
+	_ = c.ShouldBindQuery(&federationCallbackQuery)
+
+	cfg := helper.GetConfig(c)
+	browserState, _ := c.Cookie(federationStateCookie)
+	c.SetCookie(federationStateCookie, "", -1, "/v1/auth/federation", "", isSecure(c), true)
+
+	roleID, err := ar.roleUC.GetRoleIDByName("customer")
+	if err != nil {
+		ar.logger.Error("Error getting role ID", slog.Any("err", err))
+		redirectWithToast(c, "/v1/auth/login", "an-unexpected-error-occurred.-please-try-again-later.", dto.ToastTypeDanger)
+		return
+	}
+
+	username := c.GetString("username")
+	result, err := ar.authUC.FinishFederation(c.Request.Context(), federationCallbackQuery, browserState, username, roleID, cfg)
+	if err != nil {
+		ar.handleFederationError(c, err, username)
+		return
+	}
+
+	if result.Linked {
+		ar.logger.Info("User linked an identity", slog.String("username", username), slog.String("provider", result.Provider))
+		redirectWithToast(c, "/dashboard", "your-account-has-been-linked.", dto.ToastTypeSuccess)
+		return
+	}
+
+	rememberMe := ""
+	if result.RememberMe {
+		rememberMe = "on"
+	}
+
+	if result.MFAToken != "" {
+		pending := url.Values{"token": {result.MFAToken}, "remember_me": {rememberMe}}
+		c.SetCookie(loginMFACookie, pending.Encode(), cfg.Authen.MFAPendingTokenTTL, "/v1/auth/login", "", isSecure(c), true)
+		redirectWithToast(c, "/v1/auth/login", "enter-the-code-from-your-authenticator-app-to-finish-signing-in.", dto.ToastTypeWarning)
+		return
+	}
+
+	ar.logger.Info("User logged in with an upstream provider", slog.String("provider", result.Provider))
+	ar.finishLogin(c, result.Tokens, rememberMe)
+}
+
+// @Summary Linked Accounts Section
+// @Description This endpoint renders the accounts of upstream providers linked to the user as a section of the profile page. It is empty for anonymous users.
+// @Tags Authen
+// @Produce html
+// @router /v1/auth/federation [GET]
+func (ar *authRoutes) getFederation(c *gin.Context) {
+	username := c.GetString("username")
+	if username == "" {
+		c.HTML(http.StatusOK, "federation-section", gin.H{})
+		return
+	}
+
+	ar.renderFederationSection(c, username)
+}
+
+// @Summary Link Account
+// @Description Starts linking an account of an upstream provider to the logged in user, the browser is sent to the login page of the provider with the HX-Redirect header.
+// @Tags Authen
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param provider formData string true "The id of the provider."
+// @router /v1/auth/federation/link [POST]
+func (ar *authRoutes) postFederationLink(c *gin.Context) {
+	var federationLinkRequestBody dto.FederationLinkRequestBody
+	if err := c.ShouldBind(&federationLinkRequestBody); err != nil {
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": (&entity.UpstreamProviderNotFoundError{}).Error(),
+		})
+		return
+	}
+
+	ar.beginFederation(c, federationLinkRequestBody.Provider, false, c.GetString("username"))
+}
+
+// @Summary Unlink Account
+// @Description Unlinks an account of an upstream provider from the logged in user, it can't be used to sign in anymore.
+// @Tags Authen
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param id formData integer true "The id of the linked account."
+// @router /v1/auth/federation/unlink [POST]
+func (ar *authRoutes) postFederationUnlink(c *gin.Context) {
+	username := c.GetString("username")
+
+	var federationUnlinkRequestBody dto.FederationUnlinkRequestBody
+	err := c.ShouldBind(&federationUnlinkRequestBody)
+	if err == nil {
+		err = ar.authUC.UnlinkIdentity(username, federationUnlinkRequestBody.ID)
+	}
+	if err != nil {
+		message := "An unexpected error occurred. Please try again later."
+		if helper.IsErrOfType(err, &entity.LinkedIdentityNotFoundError{}) {
+			message = err.Error()
+		} else {
+			ar.logger.Error("Failed to unlink identity", slog.String("username", username), slog.Any("err", err))
+		}
+
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": message,
+		})
+
+		ar.renderFederationSection(c, username)
+		return
+	}
+
+	ar.logger.Info("User unlinked an identity", slog.String("username", username))
+	c.HTML(http.StatusOK, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeSuccess,
+		"message": "The account has been unlinked.",
+	})
+
+	ar.renderFederationSection(c, username)
+}
+
+// beginFederation sends the browser to the login page of the provider. The state is kept in a cookie
+// as well, so the answer of the provider is only accepted from this browser.
+func (ar *authRoutes) beginFederation(c *gin.Context, provider string, rememberMe bool, linkUsername string) {
+	cfg := helper.GetConfig(c)
+
+	authURL, state, err := ar.authUC.BeginFederation(c.Request.Context(), provider, rememberMe, linkUsername, cfg)
+	if err != nil {
+		message := "Signing in with the provider is not possible right now. Please try again later."
+		if helper.IsErrOfType(err, &entity.UpstreamProviderNotFoundError{}) {
+			message = err.Error()
+		} else {
+			ar.logger.Error("Failed to begin federated login", slog.String("provider", provider), slog.Any("err", err))
+		}
+
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": message,
+		})
+		return
+	}
+
+	// lax, so the cookie comes along when the provider sends the browser back
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(federationStateCookie, state, cfg.Federation.StateTTL, "/v1/auth/federation", "", isSecure(c), true)
+	c.Header("HX-Redirect", authURL)
+}
+
+func (ar *authRoutes) handleFederationError(c *gin.Context, err error, username string) {
+	message := "an-unexpected-error-occurred.-please-try-again-later."
+
+	var federationFailedErr *entity.FederationFailedError
+	switch {
+	case errors.As(err, &federationFailedErr):
+		ar.logger.Warn("Federated login failed", slog.String("reason", federationFailedErr.Reason))
+		message = "signing-in-with-the-provider-failed.-please-try-again."
+	case helper.IsErrOfType(err, &entity.InvalidFederationStateError{}):
+		message = "your-sign-in-attempt-has-expired.-please-try-again."
+	case helper.IsErrOfType(err, &entity.FederatedEmailRequiredError{}):
+		message = "the-provider-did-not-share-a-verified-email-address,-so-no-account-could-be-created."
+	case helper.IsErrOfType(err, &entity.FederatedEmailTakenError{}):
+		message = "an-account-with-this-email-already-exists.-log-in-and-link-the-provider-from-your-profile."
+	case helper.IsErrOfType(err, &entity.IdentityAlreadyLinkedError{}):
+		message = "this-account-of-the-provider-is-already-linked-to-a-user."
+	case helper.IsErrOfType(err, &entity.EmailNotVerifiedError{}):
+		message = "please-verify-your-email-address-before-logging-in.-check-your-inbox-for-the-verification-link."
+	case helper.IsErrOfType(err, &entity.UserDisabledError{}):
+		message = "your-account-has-been-disabled.-please-contact-an-administrator."
+	case helper.IsErrOfType(err, &entity.InvalidCredentialsError{}):
+		message = "the-account-linked-to-this-provider-has-been-deleted."
+	default:
+		ar.logger.Error("Failed to finish federated login", slog.Any("err", err))
+	}
+
+	// linking started from the profile page, so that is where the user goes back to
+	path := "/v1/auth/login"
+	if username != "" {
+		path = "/dashboard"
+	}
+	redirectWithToast(c, path, message, dto.ToastTypeDanger)
+}
+
+func (ar *authRoutes) renderFederationSection(c *gin.Context, username string) {
+	identities, err := ar.authUC.ListLinkedIdentities(username)
+	if err != nil {
+		ar.logger.Error("Failed to list linked identities", slog.String("username", username), slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	c.HTML(http.StatusOK, "federation-section", gin.H{
+		"username":   username,
+		"providers":  helper.GetConfig(c).Federation.Providers,
+		"identities": identities,
+	})
+}
@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// @Summary Forward Auth
+// @Description Reverse proxies ask here whether a request may reach the app behind them: Traefik with forwardAuth, Caddy with forward_auth and nginx with auth_request.
+// The request is read from the X-Forwarded-Method, X-Forwarded-Proto, X-Forwarded-Host and X-Forwarded-Uri headers, nginx has to set X-Original-URL instead. The forward_auth rules of the config decide.
+// The token is taken from the Authorization header, or from the access_token cookie. Expired tokens of the cookies are refreshed, the new ones are set as cookies which the proxy has to pass on to the browser.
+// Allowed requests get the user in the Remote-User, Remote-Email and Remote-Groups headers. Browsers which have to log in are redirected to the login page and come back afterwards, nginx and other clients get 401 instead.
+// Hosts of a registered application with grants get the role of the user within the application in the Remote-App-Role header.
+// Requests the rules deny, or whose user lacks the role a rule asks for or a grant of the application, get 403.
+// @Tags Authen
+// @Param X-Forwarded-Method header string false "The method of the request."
+// @Param X-Forwarded-Proto header string false "The scheme of the request."
+// @Param X-Forwarded-Host header string false "The host of the request."
+// @Param X-Forwarded-Uri header string false "The path and query of the request."
+// @Param X-Original-URL header string false "The url of the request, set by nginx."
+// @Success 200 "The request may pass"
+// @Failure 302 "The browser has to log in"
+// @Failure 401 "The request needs a logged in user"
+// @Failure 403 "The request is denied"
+// @router /v1/auth/verify [GET]
+func (ar *authRoutes) getForwardAuth(c *gin.Context) {
+	cfg := helper.GetConfig(c)
+	originalURL, fromNginx := forwardedURL(c)
+
+	forwardAuthRequest := dto.ForwardAuthRequest{
+		Host:         originalURL.Host,
+		Path:         originalURL.Path,
+		AccessToken:  helper.ExtractHeaderToken(c, helper.AccessTokenHeader),
+		RefreshToken: helper.ExtractHeaderToken(c, helper.RefreshTokenHeader),
+	}
+	if forwardAuthRequest.AccessToken == "" {
+		forwardAuthRequest.AccessToken, _ = c.Cookie(helper.AccessTokenCookie)
+		forwardAuthRequest.RefreshToken, _ = c.Cookie(helper.RefreshTokenCookie)
+		forwardAuthRequest.FromCookie = true
+	}
+
+	result, err := ar.authUC.ForwardAuth(forwardAuthRequest, cfg)
+	if err != nil {
+		switch {
+		case helper.IsErrOfType(err, &entity.LoginRequiredError{}):
+			// nginx can't pass a redirect on, it sends the browser to the login page with error_page 401
+			method := c.GetHeader("X-Forwarded-Method")
+			browser := strings.Contains(c.GetHeader("Accept"), "text/html") && (method == "" || method == http.MethodGet)
+			if fromNginx || !browser {
+				c.Status(http.StatusUnauthorized)
+				return
+			}
+			loginURL := strings.TrimSuffix(cfg.App.BaseURL, "/") + "/v1/auth/login?rd=" + url.QueryEscape(originalURL.String())
+			c.Redirect(http.StatusFound, loginURL)
+		case helper.IsErrOfType(err, &entity.AccessDeniedError{}):
+			c.Status(http.StatusForbidden)
+		default:
+			ar.logger.Error("Failed to check forward auth request", slog.String("host", originalURL.Host), slog.Any("err", err))
+			c.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if result.Tokens != nil {
+		helper.SetTokenCookies(c, result.Tokens, result.RememberMe)
+	}
+	if result.Username != "" {
+		c.Header("Remote-User", result.Username)
+		c.Header("Remote-Email", result.Email)
+		c.Header("Remote-Groups", strings.Join(result.Groups, ","))
+	}
+	if result.AppRole != "" {
+		c.Header("Remote-App-Role", result.AppRole)
+	}
+	c.Status(http.StatusOK)
+}
+
+// forwardedURL rebuilds the url of the request the proxy asks about. nginx names it in X-Original-URL,
+// Traefik and Caddy split it up into X-Forwarded headers.
+func forwardedURL(c *gin.Context) (*url.URL, bool) {
+	if original := c.GetHeader("X-Original-URL"); original != "" {
+		if u, err := url.Parse(original); err == nil {
+			return u, true
+		}
+	}
+
+	u, err := url.ParseRequestURI(c.GetHeader("X-Forwarded-Uri"))
+	if err != nil {
+		u = &url.URL{Path: "/"}
+	}
+	u.Scheme = c.GetHeader("X-Forwarded-Proto")
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+	u.Host = c.GetHeader("X-Forwarded-Host")
+	return u, false
+}
@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// loginRedirectCookie remembers where to go once the user has logged in, so an application
+// which sent the user to the login page gets them back
+const (
+	loginRedirectCookie    = "login_redirect"
+	loginRedirectCookieTTL = 600
+)
+
+// @Summary OpenID Connect Authorization
+// @Description This endpoint logs the user in to an application which uses the hub as its identity provider, see OpenID Connect Core section 3.1.2.
+// Users who aren't logged in are sent to the login page first and come back here afterwards.
+// The browser is then redirected to the application with an authorization code, or with an error the application has to handle.
+// @Tags OIDC
+// @Produce html
+// @Param response_type query string true "Has to be code."
+// @Param client_id query string true "The id of the application."
+// @Param redirect_uri query string true "One of the redirect uris registered for the application."
+// @Param scope query string true "Space separated scopes, openid is required. profile and email add claims about the user."
+// @Param state query string false "Returned to the application unchanged."
+// @Param nonce query string false "Copied into the ID token."
+// @Param prompt query string false "none fails with login_required instead of showing the login page."
+// @Param code_challenge query string false "The PKCE challenge, required for applications without a secret."
+// @Param code_challenge_method query string false "Has to be S256."
+// @router /v1/oidc/authorize [GET]
+func (ar *authRoutes) getAuthorize(c *gin.Context) {
+	var authorizeRequest dto.AuthorizeRequest
+	_ = c.ShouldBindQuery(&authorizeRequest)
+
+	redirectURL, err := ar.authUC.Authorize(authorizeRequest, c.GetString("username"), helper.GetConfig(c))
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.OIDCLoginRequiredError{}) {
+			setLoginRedirect(c, c.Request.URL.RequestURI())
+			redirectWithToast(c, "/v1/auth/login", "please-log-in-to-continue-to-the-application.", dto.ToastTypeWarning)
+			return
+		}
+
+		message := "an-unexpected-error-occurred.-please-try-again-later."
+		if helper.IsErrOfType(err, &entity.InvalidOIDCClientError{}) {
+			ar.logger.Warn("Authorization request of an unknown client", slog.String("client_id", authorizeRequest.ClientID), slog.String("redirect_uri", authorizeRequest.RedirectURI))
+			message = "the-application-you-came-from-is-not-registered-with-the-hub."
+		} else {
+			ar.logger.Error("Failed to authorize client", slog.String("client_id", authorizeRequest.ClientID), slog.Any("err", err))
+		}
+
+		redirectWithToast(c, "/", message, dto.ToastTypeDanger)
+		return
+	}
+
+	ar.logger.Info("User authorized client", slog.String("username", c.GetString("username")), slog.String("client_id", authorizeRequest.ClientID))
+	c.Header("HX-Redirect", redirectURL)
+}
+
+func redirectWithToast(c *gin.Context, path string, message string, toastType string) {
+	hashValue, err := helper.HashMap(map[string]interface{}{
+		"toast-message": message,
+		"toast-type":    toastType,
+	})
+	if err != nil {
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	c.Header("HX-Redirect", fmt.Sprintf("%s?toast-message=%s&toast-type=%s&hash-value=%s", path, message, toastType, hashValue))
+}
+
+func setLoginRedirect(c *gin.Context, path string) {
+	c.SetCookie(loginRedirectCookie, path, loginRedirectCookieTTL, "/", "", isSecure(c), true)
+}
+
+// takeLoginRedirect returns where to go after the login, if anywhere, and forgets it.
+// Only paths of the hub and apps behind forward auth are followed, so the cookie can't send the user elsewhere.
+func takeLoginRedirect(c *gin.Context) string {
+	path, err := c.Cookie(loginRedirectCookie)
+	if err != nil {
+		return ""
+	}
+
+	c.SetCookie(loginRedirectCookie, "", -1, "/", "", isSecure(c), true)
+	if !isAllowedLoginRedirect(c, path) {
+		return ""
+	}
+	return path
+}
+
+// isAllowedLoginRedirect accepts paths of the hub and urls of the hosts the forward auth rules cover
+func isAllowedLoginRedirect(c *gin.Context, target string) bool {
+	if strings.HasPrefix(target, "/") {
+		return !strings.HasPrefix(target, "//") && !strings.HasPrefix(target, "/\\")
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.User != nil {
+		return false
+	}
+	return helper.IsForwardAuthHost(helper.GetConfig(c).ForwardAuth.Rules, u.Host)
+}
+
+func isSecure(c *gin.Context) bool {
+	return strings.HasPrefix(helper.GetConfig(c).App.BaseURL, "https://")
+}
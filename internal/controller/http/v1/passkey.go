@@ -0,0 +1,233 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// @Summary Passkeys Section
+// @Description This endpoint renders the passkeys section of the profile page. It is empty for anonymous users.
+// @Tags Authen
+// @Produce html
+// @router /v1/auth/passkey [GET]
+func (ar *authRoutes) getPasskeys(c *gin.Context) {
+	username := c.GetString("username")
+	if username == "" {
+		c.HTML(http.StatusOK, "passkey-section", gin.H{})
+		return
+	}
+
+	ar.renderPasskeySection(c, username)
+}
+
+// @Summary Begin Passkey Registration
+// @Description Creates the options for a new passkey of the logged in user. They are sent in the passkeyRegistration event of the HX-Trigger header.
+// @Tags Authen
+// @Security JWT
+// @Produce html
+// @router /v1/auth/passkey/register/begin [POST]
+func (ar *authRoutes) postBeginPasskeyRegistration(c *gin.Context) {
+	username := c.GetString("username")
+
+	ceremony, err := ar.authUC.BeginPasskeyRegistration(username, helper.GetConfig(c))
+	if err != nil {
+		ar.logger.Error("Failed to begin passkey registration", slog.String("username", username), slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	ar.triggerPasskeyCeremony(c, "passkeyRegistration", ceremony)
+}
+
+// @Summary Finish Passkey Registration
+// @Description Stores the passkey created by the authenticator of the logged in user.
+// @Tags Authen
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param session formData string true "The session returned when the registration began."
+// @Param credential formData string true "The JSON encoded credential created by the authenticator."
+// @Param name formData string false "A name to recognize the passkey."
+// @router /v1/auth/passkey/register/finish [POST]
+func (ar *authRoutes) postFinishPasskeyRegistration(c *gin.Context) {
+	username := c.GetString("username")
+
+	var passkeyRegistrationRequestBody dto.PasskeyRegistrationRequestBody
+	if err := c.ShouldBind(&passkeyRegistrationRequestBody); err != nil {
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": "The passkey could not be verified. Please try again.",
+		})
+
+		ar.renderPasskeySection(c, username)
+		return
+	}
+
+	err := ar.authUC.FinishPasskeyRegistration(username, passkeyRegistrationRequestBody, helper.GetConfig(c))
+	if err != nil {
+		message := "An unexpected error occurred. Please try again later."
+		if helper.IsErrOfType(err, &entity.InvalidPasskeyError{}) {
+			message = err.Error()
+		} else {
+			ar.logger.Error("Failed to finish passkey registration", slog.String("username", username), slog.Any("err", err))
+		}
+
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": message,
+		})
+
+		ar.renderPasskeySection(c, username)
+		return
+	}
+
+	ar.logger.Info("User registered a passkey", slog.String("username", username))
+	c.HTML(http.StatusOK, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeSuccess,
+		"message": "Your passkey has been added.",
+	})
+
+	ar.renderPasskeySection(c, username)
+}
+
+// @Summary Delete Passkey
+// @Description Removes a passkey of the logged in user, it can't be used to sign in anymore.
+// @Tags Authen
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param id formData integer true "The id of the passkey."
+// @router /v1/auth/passkey/delete [POST]
+func (ar *authRoutes) postDeletePasskey(c *gin.Context) {
+	username := c.GetString("username")
+
+	var passkeyDeleteRequestBody dto.PasskeyDeleteRequestBody
+	err := c.ShouldBind(&passkeyDeleteRequestBody)
+	if err == nil {
+		err = ar.authUC.DeletePasskey(username, passkeyDeleteRequestBody.ID)
+	}
+	if err != nil {
+		message := "An unexpected error occurred. Please try again later."
+		if helper.IsErrOfType(err, &entity.PasskeyNotFoundError{}) {
+			message = err.Error()
+		} else {
+			ar.logger.Error("Failed to delete passkey", slog.String("username", username), slog.Any("err", err))
+		}
+
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": message,
+		})
+
+		ar.renderPasskeySection(c, username)
+		return
+	}
+
+	ar.logger.Info("User deleted a passkey", slog.String("username", username))
+	c.HTML(http.StatusOK, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeSuccess,
+		"message": "Your passkey has been removed.",
+	})
+
+	ar.renderPasskeySection(c, username)
+}
+
+// @Summary Begin Passkey Login
+// @Description Creates the options to sign in with a passkey. They are sent in the passkeyLogin event of the HX-Trigger header.
+// @Tags Authen
+// @Produce html
+// @x-rate-limit {"limit": 20, "period": 60, "key": "ip"}
+// @router /v1/auth/passkey/login/begin [POST]
+func (ar *authRoutes) postBeginPasskeyLogin(c *gin.Context) {
+	ceremony, err := ar.authUC.BeginPasskeyLogin(helper.GetConfig(c))
+	if err != nil {
+		ar.logger.Error("Failed to begin passkey login", slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	ar.triggerPasskeyCeremony(c, "passkeyLogin", ceremony)
+}
+
+// @Summary Finish Passkey Login
+// @Description Signs the user in with the answer of their authenticator and redirects to the home page.
+// @Tags Authen
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param session formData string true "The session returned when the login began."
+// @Param credential formData string true "The JSON encoded assertion of the authenticator."
+// @Param remember_me formData string false "Keep the user logged in."
+// @x-rate-limit {"limit": 20, "period": 60, "key": "ip"}
+// @router /v1/auth/passkey/login/finish [POST]
+func (ar *authRoutes) postFinishPasskeyLogin(c *gin.Context) {
+	var passkeyLoginRequestBody dto.PasskeyLoginRequestBody
+	if err := c.ShouldBind(&passkeyLoginRequestBody); err != nil {
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": "The passkey could not be verified. Please try again.",
+		})
+		return
+	}
+
+	jwtTokens, err := ar.authUC.FinishPasskeyLogin(passkeyLoginRequestBody, helper.GetConfig(c))
+	if err != nil {
+		message := "An unexpected error occurred. Please try again later."
+		if helper.IsErrOfType(err, &entity.InvalidPasskeyError{}) || helper.IsErrOfType(err, &entity.EmailNotVerifiedError{}) ||
+			helper.IsErrOfType(err, &entity.UserDisabledError{}) {
+			message = err.Error()
+		} else {
+			ar.logger.Error("Failed to finish passkey login", slog.Any("err", err))
+		}
+
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": message,
+		})
+		return
+	}
+
+	ar.logger.Info("User logged in with a passkey")
+	ar.finishLogin(c, jwtTokens, passkeyLoginRequestBody.RememberMe)
+}
+
+// triggerPasskeyCeremony hands the options over to the browser, which asks the authenticator
+// and posts its answer back to the matching finish endpoint
+func (ar *authRoutes) triggerPasskeyCeremony(c *gin.Context, event string, ceremony *dto.PasskeyCeremony) {
+	HXTriggerEvents, err := helper.MapToJSONString(map[string]interface{}{
+		event: ceremony,
+	})
+	if err != nil {
+		ar.logger.Error("Failed to create HX-Trigger events", slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	c.Header("HX-Trigger", HXTriggerEvents)
+	c.Status(http.StatusNoContent)
+}
+
+func (ar *authRoutes) renderPasskeySection(c *gin.Context, username string) {
+	passkeys, err := ar.authUC.ListPasskeys(username)
+	if err != nil {
+		ar.logger.Error("Failed to list passkeys", slog.String("username", username), slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	c.HTML(http.StatusOK, "passkey-section", gin.H{
+		"username": username,
+		"passkeys": passkeys,
+	})
+}
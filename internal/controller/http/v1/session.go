@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// @Summary Sessions Section
+// @Description This endpoint renders the devices the user is logged in on as a section of the profile page. It is empty for anonymous users.
+// @Tags Authen
+// @Produce html
+// @router /v1/auth/sessions [GET]
+func (ar *authRoutes) getSessions(c *gin.Context) {
+	username := c.GetString("username")
+	if username == "" {
+		c.HTML(http.StatusOK, "session-section", gin.H{})
+		return
+	}
+
+	ar.renderSessionSection(c, username)
+}
+
+// @Summary Revoke Session
+// @Description Logs the user out on one of their devices.
+// @Tags Authen
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param id formData string true "The id of the session."
+// @router /v1/auth/sessions/revoke [POST]
+func (ar *authRoutes) postRevokeSession(c *gin.Context) {
+	username := c.GetString("username")
+
+	var sessionRevokeRequestBody dto.SessionRevokeRequestBody
+	err := c.ShouldBind(&sessionRevokeRequestBody)
+	if err == nil {
+		err = ar.authUC.RevokeSession(username, sessionRevokeRequestBody.ID, helper.GetConfig(c))
+	}
+	if err != nil {
+		message := "An unexpected error occurred. Please try again later."
+		if helper.IsErrOfType(err, &entity.SessionNotFoundError{}) {
+			message = err.Error()
+		} else {
+			ar.logger.Error("Failed to revoke session", slog.String("username", username), slog.Any("err", err))
+		}
+
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": message,
+		})
+
+		ar.renderSessionSection(c, username)
+		return
+	}
+
+	ar.logger.Info("User revoked a session", slog.String("username", username))
+	c.HTML(http.StatusOK, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeSuccess,
+		"message": "The device has been logged out.",
+	})
+
+	ar.renderSessionSection(c, username)
+}
+
+// @Summary Logout Everywhere
+// @Description Logs the user out on every device, including this one, and redirects to the login page.
+// @Tags Authen
+// @Security JWT
+// @Produce html
+// @router /v1/auth/sessions/logout-everywhere [POST]
+func (ar *authRoutes) postLogoutEverywhere(c *gin.Context) {
+	username := c.GetString("username")
+
+	err := ar.authUC.LogoutEverywhere(username, helper.GetConfig(c))
+	if err != nil {
+		ar.logger.Error("Failed to log out everywhere", slog.String("username", username), slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	toastMessage := "you-have-been-logged-out-on-every-device."
+	hashValue, err := helper.HashMap(map[string]interface{}{
+		"toast-message": toastMessage,
+		"toast-type":    dto.ToastTypeSuccess,
+	})
+	if err != nil {
+		ar.logger.Error("Failed to generate toast message hash", slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	ar.logger.Info("User logged out everywhere", slog.String("username", username))
+	helper.DeleteTokens(c, true, true)
+	c.Header("HX-Redirect", fmt.Sprintf("/v1/auth/login?toast-message=%s&toast-type=%s&hash-value=%s", toastMessage, dto.ToastTypeSuccess, hashValue))
+}
+
+func (ar *authRoutes) renderSessionSection(c *gin.Context, username string) {
+	sessions, err := ar.authUC.ListSessions(username)
+	if err != nil {
+		ar.logger.Error("Failed to list sessions", slog.String("username", username), slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	// the session of this browser can't be revoked on its own, it is ended by logging out
+	currentSid, _ := ar.authUC.RetrieveFieldFromJwtToken(helper.ExtractHeaderToken(c, helper.AccessTokenHeader), "sid", false)
+
+	c.HTML(http.StatusOK, "session-section", gin.H{
+		"username":   username,
+		"sessions":   sessions,
+		"currentSid": currentSid,
+	})
+}
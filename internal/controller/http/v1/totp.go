@@ -0,0 +1,190 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// @Summary Two-Factor Authentication Section
+// @Description This endpoint renders the two-factor authentication section of the profile page. It is empty for anonymous users.
+// @Tags Authen
+// @Produce html
+// @router /v1/auth/totp [GET]
+func (ar *authRoutes) getTOTP(c *gin.Context) {
+	username := c.GetString("username")
+	if username == "" {
+		c.HTML(http.StatusOK, "totp-section", gin.H{})
+		return
+	}
+
+	user, err := ar.userUC.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		ar.logger.Error("Failed to find user", slog.String("username", username), slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	ar.renderTOTPSection(c, username, user.TOTPEnabled, nil)
+}
+
+// @Summary Enroll Two-Factor Authentication
+// @Description Generates a new TOTP secret for the logged in user and renders its QR code.
+// @Tags Authen
+// @Security JWT
+// @Produce html
+// @router /v1/auth/totp/enroll [POST]
+func (ar *authRoutes) postEnrollTOTP(c *gin.Context) {
+	username := c.GetString("username")
+
+	enrollment, err := ar.authUC.EnrollTOTP(username, helper.GetConfig(c))
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.TOTPAlreadyEnabledError{}) {
+			c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+				"hidden":  false,
+				"type":    dto.ToastTypeDanger,
+				"message": err.Error(),
+			})
+
+			ar.renderTOTPSection(c, username, true, nil)
+			return
+		}
+
+		ar.logger.Error("Failed to enroll totp", slog.String("username", username), slog.Any("err", err))
+		helper.HandleInternalError(c, err)
+		return
+	}
+
+	c.HTML(http.StatusOK, "totp-section", gin.H{
+		"username":   username,
+		"enrollment": enrollment,
+	})
+}
+
+// @Summary Confirm Two-Factor Authentication
+// @Description Turns two-factor authentication on after checking a first code of the authenticator app.
+// @Tags Authen
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param code formData string true "The 6 digit code of the authenticator app."
+// @x-rate-limit {"limit": 5, "period": 60, "key": "user"}
+// @router /v1/auth/totp/confirm [POST]
+func (ar *authRoutes) postConfirmTOTP(c *gin.Context) {
+	ar.handleTOTPCode(c, "totp-confirm-form", ar.authUC.ConfirmTOTP, true,
+		"Two-factor authentication is now enabled.")
+}
+
+// @Summary Disable Two-Factor Authentication
+// @Description Turns two-factor authentication off after checking a code of the authenticator app.
+// @Tags Authen
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param code formData string true "The 6 digit code of the authenticator app."
+// @x-rate-limit {"limit": 5, "period": 60, "key": "user"}
+// @router /v1/auth/totp/disable [POST]
+func (ar *authRoutes) postDisableTOTP(c *gin.Context) {
+	disable := func(username string, code string) ([]string, error) {
+		return nil, ar.authUC.DisableTOTP(username, code)
+	}
+	ar.handleTOTPCode(c, "totp-disable-form", disable, false,
+		"Two-factor authentication is now disabled.")
+}
+
+// @Summary Regenerate Recovery Codes
+// @Description Replaces the recovery codes of the logged in user after checking a code of the authenticator app. The new codes are shown once.
+// @Tags Authen
+// @Security JWT
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param code formData string true "The 6 digit code of the authenticator app."
+// @x-rate-limit {"limit": 5, "period": 60, "key": "user"}
+// @router /v1/auth/totp/recovery-codes [POST]
+func (ar *authRoutes) postRegenerateRecoveryCodes(c *gin.Context) {
+	ar.handleTOTPCode(c, "totp-recovery-codes-form", ar.authUC.RegenerateRecoveryCodes, true,
+		"New recovery codes have been generated. The old ones no longer work.")
+}
+
+// handleTOTPCode checks the submitted code with the given action. Failures re-render the form,
+// a success replaces the whole two-factor authentication section.
+func (ar *authRoutes) handleTOTPCode(c *gin.Context, form string, action func(string, string) ([]string, error), enabled bool, successMessage string) {
+	username := c.GetString("username")
+
+	var totpCodeRequestBody dto.TOTPCodeRequestBody
+	if err := c.ShouldBind(&totpCodeRequestBody); err != nil {
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": "Please enter the 6 digit code of your authenticator app.",
+		})
+
+		c.HTML(http.StatusOK, form, gin.H{
+			"validationFail": true,
+			"validationMap":  helper.GenerateValidationMap(err),
+		})
+		return
+	}
+
+	recoveryCodes, err := action(username, totpCodeRequestBody.Code)
+	if err != nil {
+		message := "An unexpected error occurred. Please try again later."
+		if helper.IsErrOfType(err, &entity.InvalidTOTPCodeError{}) ||
+			helper.IsErrOfType(err, &entity.TOTPAlreadyEnabledError{}) ||
+			helper.IsErrOfType(err, &entity.TOTPNotEnrolledError{}) {
+			message = err.Error()
+		} else {
+			ar.logger.Error("Failed to check totp code", slog.String("username", username), slog.Any("err", err))
+		}
+
+		c.HTML(http.StatusBadRequest, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeDanger,
+			"message": message,
+		})
+
+		c.HTML(http.StatusOK, form, gin.H{
+			"validationFail": true,
+			"validationMap":  map[string]string{},
+		})
+		return
+	}
+
+	ar.logger.Info("User changed two-factor authentication", slog.String("username", username), slog.Bool("enabled", enabled))
+	// headers have to be set before the first fragment is written
+	c.Header("HX-Retarget", "#totp-section")
+	c.HTML(http.StatusOK, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeSuccess,
+		"message": successMessage,
+	})
+
+	ar.renderTOTPSection(c, username, enabled, recoveryCodes)
+}
+
+// renderTOTPSection renders the two-factor authentication section. Recovery codes
+// are only passed right after they were generated, this is the only time they are shown.
+func (ar *authRoutes) renderTOTPSection(c *gin.Context, username string, enabled bool, recoveryCodes []string) {
+	data := gin.H{
+		"username":      username,
+		"enabled":       enabled,
+		"recoveryCodes": recoveryCodes,
+	}
+
+	if enabled {
+		// the count is informative only, so the section is still rendered without it
+		remaining, err := ar.authUC.CountRecoveryCodes(username)
+		if err != nil {
+			ar.logger.Error("Failed to count recovery codes", slog.String("username", username), slog.Any("err", err))
+		} else {
+			data["recoveryCodesCounted"] = true
+			data["remainingRecoveryCodes"] = remaining
+		}
+	}
+
+	c.HTML(http.StatusOK, "totp-section", data)
+}
@@ -0,0 +1,537 @@
+package v2
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+)
+
+type adminRoutes struct {
+	logger *slog.Logger
+	authUC usecases.IAuthUC
+	userUC usecases.IUserUC
+	roleUC usecases.IRoleUC
+}
+
+// NewAdminRoutes creates new routes only administrators can use
+func NewAdminRoutes(handler *gin.RouterGroup,
+	l *slog.Logger,
+	a usecases.IAuthUC,
+	u usecases.IUserUC,
+	r usecases.IRoleUC,
+) {
+	ar := &adminRoutes{l, a, u, r}
+
+	h := handler.Group("/admin")
+	{
+		h.GET("/login-locks", ar.listLoginLocks)
+		h.POST("/login-locks/clear", ar.clearLoginLock)
+		h.GET("/service-accounts", ar.listServiceAccounts)
+		h.POST("/service-accounts", ar.createServiceAccount)
+		h.POST("/service-accounts/delete", ar.deleteServiceAccount)
+		h.GET("/applications", ar.listApplications)
+		h.POST("/applications", ar.createApplication)
+		h.POST("/applications/update", ar.updateApplication)
+		h.POST("/applications/delete", ar.deleteApplication)
+		h.GET("/applications/grants", ar.listApplicationGrants)
+		h.POST("/applications/grants", ar.grantApplicationAccess)
+		h.POST("/applications/grants/delete", ar.revokeApplicationAccess)
+		h.GET("/users", ar.listUsers)
+		h.GET("/users/detail", ar.getUser)
+		h.POST("/users", ar.createUser)
+		h.POST("/users/update", ar.updateUser)
+		h.POST("/users/disable", ar.disableUser)
+		h.POST("/users/enable", ar.enableUser)
+		h.POST("/users/delete", ar.deleteUser)
+		h.POST("/users/restore", ar.restoreUser)
+		h.GET("/roles", ar.listRoles)
+		h.POST("/roles", ar.createRole)
+		h.POST("/roles/update", ar.updateRole)
+		h.POST("/roles/delete", ar.deleteRole)
+		h.GET("/permissions", ar.listPermissions)
+	}
+}
+
+// @Summary List Login Locks
+// @Description Lists the usernames and ips which can't log in after too many failed attempts.
+// @Tags Admin
+// @Security JWT
+// @Produce json
+// @Success 200 {object} dto.Response
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 60, "period": 60, "key": "user"}
+// @x-scopes ["login-locks:read"]
+// @x-permissions ["login-locks:read"]
+// @router /v2/admin/login-locks [GET]
+func (ar *adminRoutes) listLoginLocks(c *gin.Context) {
+	locks, err := ar.authUC.ListLoginLocks()
+	if err != nil {
+		ar.logger.Error("Failed to list login locks", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to list login locks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: locks})
+}
+
+// @Summary Clear Login Lock
+// @Description Lets a locked username or ip log in again right away and forgets its failed attempts.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.LoginLockClearRequestBody true "The locked username or ip."
+// @Success 200 {object} dto.Response
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 404 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-scopes ["login-locks:write"]
+// @x-permissions ["login-locks:write"]
+// @router /v2/admin/login-locks/clear [POST]
+func (ar *adminRoutes) clearLoginLock(c *gin.Context) {
+	var loginLockClearRequestBody dto.LoginLockClearRequestBody
+
+	err := c.ShouldBind(&loginLockClearRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err = ar.authUC.ClearLoginLock(loginLockClearRequestBody.Kind, loginLockClearRequestBody.Value)
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.LoginLockNotFoundError{}) {
+			c.JSON(http.StatusNotFound, dto.Response{Success: false, Message: err.Error()})
+			return
+		}
+
+		ar.logger.Error("Failed to clear login lock", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to clear login lock"})
+		return
+	}
+
+	ar.logger.Info("Admin cleared login lock",
+		slog.String("admin", adminName(c)),
+		slog.String("kind", loginLockClearRequestBody.Kind),
+		slog.String("value", loginLockClearRequestBody.Value),
+	)
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Login lock cleared"})
+}
+
+// @Summary List Service Accounts
+// @Description Lists the service accounts which get tokens with the client credentials grant of /oauth/token.
+// @Tags Admin
+// @Security JWT
+// @Produce json
+// @Success 200 {object} dto.Response
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 60, "period": 60, "key": "user"}
+// @x-permissions ["service-accounts:read"]
+// @router /v2/admin/service-accounts [GET]
+func (ar *adminRoutes) listServiceAccounts(c *gin.Context) {
+	serviceAccounts, err := ar.authUC.ListServiceAccounts()
+	if err != nil {
+		ar.logger.Error("Failed to list service accounts", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to list service accounts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: serviceAccounts})
+}
+
+// @Summary Create Service Account
+// @Description Creates a service account for a script or a cron job. The client secret is only returned in this response.
+// Scopes are space separated, a route lets service accounts in when their token holds all the scopes in its x-scopes extension.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.ServiceAccountCreateRequestBody true "The name, scopes and token ttl of the service account."
+// @Success 201 {object} dto.Response{data=dto.ServiceAccountCredentials}
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 10, "period": 60, "key": "user"}
+// @x-permissions ["service-accounts:write"]
+// @router /v2/admin/service-accounts [POST]
+func (ar *adminRoutes) createServiceAccount(c *gin.Context) {
+	var serviceAccountCreateRequestBody dto.ServiceAccountCreateRequestBody
+
+	err := c.ShouldBind(&serviceAccountCreateRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	credentials, err := ar.authUC.CreateServiceAccount(serviceAccountCreateRequestBody)
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.InvalidScopeError{}) {
+			c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+			return
+		}
+
+		ar.logger.Error("Failed to create service account", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to create service account"})
+		return
+	}
+
+	ar.logger.Info("Admin created service account",
+		slog.String("admin", adminName(c)),
+		slog.String("client_id", credentials.ClientID),
+		slog.String("scopes", credentials.Scopes),
+	)
+	c.JSON(http.StatusCreated, dto.Response{Success: true, Message: "Service account created", Data: credentials})
+}
+
+// @Summary Delete Service Account
+// @Description Deletes a service account, the tokens it got stop working right away.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.ServiceAccountDeleteRequestBody true "The client id of the service account."
+// @Success 200 {object} dto.Response
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 404 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-permissions ["service-accounts:write"]
+// @router /v2/admin/service-accounts/delete [POST]
+func (ar *adminRoutes) deleteServiceAccount(c *gin.Context) {
+	var serviceAccountDeleteRequestBody dto.ServiceAccountDeleteRequestBody
+
+	err := c.ShouldBind(&serviceAccountDeleteRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err = ar.authUC.DeleteServiceAccount(serviceAccountDeleteRequestBody.ClientID)
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.ServiceAccountNotFoundError{}) {
+			c.JSON(http.StatusNotFound, dto.Response{Success: false, Message: err.Error()})
+			return
+		}
+
+		ar.logger.Error("Failed to delete service account", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to delete service account"})
+		return
+	}
+
+	ar.logger.Info("Admin deleted service account",
+		slog.String("admin", adminName(c)),
+		slog.String("client_id", serviceAccountDeleteRequestBody.ClientID),
+	)
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Service account deleted"})
+}
+
+// @Summary List Applications
+// @Description Lists the applications of the registry, which log their users in with the hub and show in the launcher.
+// @Tags Admin
+// @Security JWT
+// @Produce json
+// @Success 200 {object} dto.Response
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 60, "period": 60, "key": "user"}
+// @x-permissions ["applications:read"]
+// @router /v2/admin/applications [GET]
+func (ar *adminRoutes) listApplications(c *gin.Context) {
+	applications, err := ar.authUC.ListApplications()
+	if err != nil {
+		ar.logger.Error("Failed to list applications", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to list applications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: applications})
+}
+
+// @Summary Create Application
+// @Description Registers an application. Its client id is the audience of the ID tokens it gets, the client secret is only returned in this response.
+// Public applications get no secret and have to use PKCE. Redirect uris and allowed roles are space separated, any role may open the application when none are listed.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.ApplicationCreateRequestBody true "The settings of the application."
+// @Success 201 {object} dto.Response{data=dto.ApplicationCredentials}
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 409 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 10, "period": 60, "key": "user"}
+// @x-permissions ["applications:write"]
+// @router /v2/admin/applications [POST]
+func (ar *adminRoutes) createApplication(c *gin.Context) {
+	var applicationCreateRequestBody dto.ApplicationCreateRequestBody
+
+	err := c.ShouldBind(&applicationCreateRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	credentials, err := ar.authUC.CreateApplication(applicationCreateRequestBody, helper.GetConfig(c))
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.InvalidClientIDError{}) || helper.IsErrOfType(err, &entity.InvalidRedirectURIError{}) {
+			c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+			return
+		}
+		if helper.IsErrOfType(err, &entity.ApplicationConflictError{}) {
+			c.JSON(http.StatusConflict, dto.Response{Success: false, Message: err.Error()})
+			return
+		}
+
+		ar.logger.Error("Failed to create application", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to create application"})
+		return
+	}
+
+	ar.logger.Info("Admin created application",
+		slog.String("admin", adminName(c)),
+		slog.String("client_id", credentials.ClientID),
+	)
+	c.JSON(http.StatusCreated, dto.Response{Success: true, Message: "Application created", Data: credentials})
+}
+
+// @Summary Update Application
+// @Description Replaces the settings of an application, its client id and secret stay.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.ApplicationUpdateRequestBody true "The client id and the new settings of the application."
+// @Success 200 {object} dto.Response
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 404 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-permissions ["applications:write"]
+// @router /v2/admin/applications/update [POST]
+func (ar *adminRoutes) updateApplication(c *gin.Context) {
+	var applicationUpdateRequestBody dto.ApplicationUpdateRequestBody
+
+	err := c.ShouldBind(&applicationUpdateRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	application, err := ar.authUC.UpdateApplication(applicationUpdateRequestBody)
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.InvalidRedirectURIError{}) {
+			c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+			return
+		}
+		if helper.IsErrOfType(err, &entity.ApplicationNotFoundError{}) {
+			c.JSON(http.StatusNotFound, dto.Response{Success: false, Message: err.Error()})
+			return
+		}
+
+		ar.logger.Error("Failed to update application", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to update application"})
+		return
+	}
+
+	ar.logger.Info("Admin updated application",
+		slog.String("admin", adminName(c)),
+		slog.String("client_id", application.ClientID),
+	)
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Application updated", Data: application})
+}
+
+// @Summary Delete Application
+// @Description Deletes an application, it can't log its users in anymore.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.ApplicationDeleteRequestBody true "The client id of the application."
+// @Success 200 {object} dto.Response
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 404 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-permissions ["applications:write"]
+// @router /v2/admin/applications/delete [POST]
+func (ar *adminRoutes) deleteApplication(c *gin.Context) {
+	var applicationDeleteRequestBody dto.ApplicationDeleteRequestBody
+
+	err := c.ShouldBind(&applicationDeleteRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err = ar.authUC.DeleteApplication(applicationDeleteRequestBody.ClientID)
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.ApplicationNotFoundError{}) {
+			c.JSON(http.StatusNotFound, dto.Response{Success: false, Message: err.Error()})
+			return
+		}
+
+		ar.logger.Error("Failed to delete application", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to delete application"})
+		return
+	}
+
+	ar.logger.Info("Admin deleted application",
+		slog.String("admin", adminName(c)),
+		slog.String("client_id", applicationDeleteRequestBody.ClientID),
+	)
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Application deleted"})
+}
+
+// @Summary List Application Grants
+// @Description Shows which role each granted user or role has within each application. The app roles of a row follow the order of the applications, they are empty where there is no grant.
+// @Tags Admin
+// @Security JWT
+// @Produce json
+// @Success 200 {object} dto.Response{data=dto.ApplicationGrantMatrix}
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 60, "period": 60, "key": "user"}
+// @x-permissions ["application-grants:read"]
+// @router /v2/admin/applications/grants [GET]
+func (ar *adminRoutes) listApplicationGrants(c *gin.Context) {
+	matrix, err := ar.authUC.ApplicationGrantMatrix()
+	if err != nil {
+		ar.logger.Error("Failed to list application grants", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to list application grants"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: matrix})
+}
+
+// @Summary Grant Application Access
+// @Description Gives a user, or the users with a role, a viewer, editor or admin role within an application. A subject granted already gets the new role instead.
+// Once an application has grants only the users they cover may log in to it, open it in the launcher or pass forward auth on its host. The role is in the app_role claim of the ID token and in the Remote-App-Role header of forward auth.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.ApplicationGrantRequestBody true "The application, the subject and its role within the application."
+// @Success 200 {object} dto.Response
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 404 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-permissions ["application-grants:write"]
+// @router /v2/admin/applications/grants [POST]
+func (ar *adminRoutes) grantApplicationAccess(c *gin.Context) {
+	var applicationGrantRequestBody dto.ApplicationGrantRequestBody
+
+	err := c.ShouldBind(&applicationGrantRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	grant, err := ar.authUC.GrantApplicationAccess(applicationGrantRequestBody)
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.ApplicationNotFoundError{}) || helper.IsErrOfType(err, &entity.GrantSubjectNotFoundError{}) {
+			c.JSON(http.StatusNotFound, dto.Response{Success: false, Message: err.Error()})
+			return
+		}
+
+		ar.logger.Error("Failed to grant application access", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to grant application access"})
+		return
+	}
+
+	ar.logger.Info("Admin granted application access",
+		slog.String("admin", adminName(c)),
+		slog.String("client_id", applicationGrantRequestBody.ClientID),
+		slog.String("subject_type", grant.SubjectType),
+		slog.String("subject", grant.Subject),
+		slog.String("app_role", grant.AppRole),
+	)
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Application access granted", Data: grant})
+}
+
+// @Summary Revoke Application Access
+// @Description Takes the grant of a user or a role away, the application is closed to them unless another grant covers them.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.ApplicationGrantDeleteRequestBody true "The application and the subject."
+// @Success 200 {object} dto.Response
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 404 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-permissions ["application-grants:write"]
+// @router /v2/admin/applications/grants/delete [POST]
+func (ar *adminRoutes) revokeApplicationAccess(c *gin.Context) {
+	var applicationGrantDeleteRequestBody dto.ApplicationGrantDeleteRequestBody
+
+	err := c.ShouldBind(&applicationGrantDeleteRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err = ar.authUC.RevokeApplicationAccess(applicationGrantDeleteRequestBody)
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.ApplicationNotFoundError{}) || helper.IsErrOfType(err, &entity.ApplicationGrantNotFoundError{}) {
+			c.JSON(http.StatusNotFound, dto.Response{Success: false, Message: err.Error()})
+			return
+		}
+
+		ar.logger.Error("Failed to revoke application access", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to revoke application access"})
+		return
+	}
+
+	ar.logger.Info("Admin revoked application access",
+		slog.String("admin", adminName(c)),
+		slog.String("client_id", applicationGrantDeleteRequestBody.ClientID),
+		slog.String("subject_type", applicationGrantDeleteRequestBody.SubjectType),
+		slog.String("subject", applicationGrantDeleteRequestBody.Subject),
+	)
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Application access revoked"})
+}
+
+// adminName names who made the request in the logs, a user or a service account
+func adminName(c *gin.Context) string {
+	if clientID := c.GetString("service_account"); clientID != "" {
+		return clientID
+	}
+	return c.GetString("username")
+}
@@ -0,0 +1,194 @@
+package v2
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// @Summary List Roles
+// @Description Lists the roles with their parents, their own permissions and the ones they inherit from their parents.
+// @Tags Admin
+// @Security JWT
+// @Produce json
+// @Success 200 {object} dto.Response{data=[]dto.AdminRole}
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 60, "period": 60, "key": "user"}
+// @x-permissions ["roles:read"]
+// @router /v2/admin/roles [GET]
+func (ar *adminRoutes) listRoles(c *gin.Context) {
+	roles, err := ar.roleUC.ListRoles()
+	if err != nil {
+		ar.logger.Error("Failed to list roles", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to list roles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: roles})
+}
+
+// @Summary Create Role
+// @Description Creates a role. It gets the permissions of its parents too, and the ones of their parents.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.RoleCreateRequestBody true "The role."
+// @Success 201 {object} dto.Response{data=dto.AdminRole}
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 409 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 10, "period": 60, "key": "user"}
+// @x-permissions ["roles:write"]
+// @router /v2/admin/roles [POST]
+func (ar *adminRoutes) createRole(c *gin.Context) {
+	var roleCreateRequestBody dto.RoleCreateRequestBody
+
+	err := c.ShouldBind(&roleCreateRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	role, err := ar.roleUC.CreateRole(roleCreateRequestBody)
+	if err != nil {
+		ar.handleRoleError(c, err, "Failed to create role")
+		return
+	}
+
+	ar.logger.Info("Admin created role",
+		slog.String("admin", adminName(c)),
+		slog.String("role", role.Name),
+	)
+	c.JSON(http.StatusCreated, dto.Response{Success: true, Message: "Role created", Data: role})
+}
+
+// @Summary Update Role
+// @Description Replaces the description, the parents and the permissions of a role, and renames it when a new name is given.
+// The grants and the allowed roles of the applications follow a renamed role, roles named in the config have to be renamed there.
+// System roles can't be renamed, the admin role keeps every permission. Changes apply once the tokens of the users are refreshed.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.RoleUpdateRequestBody true "The name of the role and its settings."
+// @Success 200 {object} dto.Response{data=dto.AdminRole}
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 409 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-permissions ["roles:write"]
+// @router /v2/admin/roles/update [POST]
+func (ar *adminRoutes) updateRole(c *gin.Context) {
+	var roleUpdateRequestBody dto.RoleUpdateRequestBody
+
+	err := c.ShouldBind(&roleUpdateRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	role, err := ar.roleUC.UpdateRole(roleUpdateRequestBody)
+	if err != nil {
+		ar.handleRoleError(c, err, "Failed to update role")
+		return
+	}
+
+	ar.logger.Info("Admin updated role",
+		slog.String("admin", adminName(c)),
+		slog.String("role", roleUpdateRequestBody.Name),
+		slog.String("new_name", role.Name),
+	)
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Role updated", Data: role})
+}
+
+// @Summary Delete Role
+// @Description Deletes a role which isn't a system role. Roles still assigned to users, deleted ones too, or named by applications can't be deleted.
+// The roles which inherited from it don't anymore.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.RoleDeleteRequestBody true "The name of the role."
+// @Success 200 {object} dto.Response
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 409 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-permissions ["roles:write"]
+// @router /v2/admin/roles/delete [POST]
+func (ar *adminRoutes) deleteRole(c *gin.Context) {
+	var roleDeleteRequestBody dto.RoleDeleteRequestBody
+
+	err := c.ShouldBind(&roleDeleteRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err = ar.roleUC.DeleteRole(roleDeleteRequestBody.Name)
+	if err != nil {
+		ar.handleRoleError(c, err, "Failed to delete role")
+		return
+	}
+
+	ar.logger.Info("Admin deleted role",
+		slog.String("admin", adminName(c)),
+		slog.String("role", roleDeleteRequestBody.Name),
+	)
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "Role deleted"})
+}
+
+// @Summary List Permissions
+// @Description Lists the permissions roles can be given.
+// @Tags Admin
+// @Security JWT
+// @Produce json
+// @Success 200 {object} dto.Response{data=[]dto.AdminPermission}
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 60, "period": 60, "key": "user"}
+// @x-permissions ["roles:read"]
+// @router /v2/admin/permissions [GET]
+func (ar *adminRoutes) listPermissions(c *gin.Context) {
+	permissions, err := ar.roleUC.ListPermissions()
+	if err != nil {
+		ar.logger.Error("Failed to list permissions", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to list permissions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: permissions})
+}
+
+func (ar *adminRoutes) handleRoleError(c *gin.Context, err error, message string) {
+	switch {
+	case helper.IsErrOfType(err, &entity.RoleConflictError{}) || helper.IsErrOfType(err, &entity.RoleInUseError{}):
+		c.JSON(http.StatusConflict, dto.Response{Success: false, Message: err.Error()})
+	case helper.IsErrOfType(err, &entity.RoleNotFoundError{}),
+		helper.IsErrOfType(err, &entity.PermissionNotFoundError{}),
+		helper.IsErrOfType(err, &entity.InvalidRoleNameError{}),
+		helper.IsErrOfType(err, &entity.SystemRoleError{}),
+		helper.IsErrOfType(err, &entity.RoleCycleError{}):
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+	default:
+		ar.logger.Error(message, slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: message})
+	}
+}
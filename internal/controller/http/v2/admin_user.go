@@ -0,0 +1,333 @@
+package v2
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// @Summary List Users
+// @Description Lists a page of the users, the deleted ones only when asked for by their status. The next cursor of a page
+// is passed on to get the page after it, together with the same filters and sort.
+// @Tags Admin
+// @Security JWT
+// @Produce json
+// @Param q query string false "A part of the username or email."
+// @Param role query string false "The name of the role."
+// @Param status query string false "active, disabled or deleted. The users which aren't deleted when empty."
+// @Param sort query string false "username, email or created_at, descending with a leading -. By username when empty."
+// @Param limit query int false "The number of users of the page, 20 when empty."
+// @Param cursor query string false "The next cursor of the page before."
+// @Success 200 {object} dto.Response{data=dto.UserPage}
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 60, "period": 60, "key": "user"}
+// @x-permissions ["users:read"]
+// @router /v2/admin/users [GET]
+func (ar *adminRoutes) listUsers(c *gin.Context) {
+	var userListQuery dto.UserListQuery
+
+	err := c.ShouldBindQuery(&userListQuery)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	page, err := ar.userUC.ListUsers(userListQuery)
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.InvalidCursorError{}) {
+			c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+			return
+		}
+
+		ar.logger.Error("Failed to list users", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: "Failed to list users"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: page})
+}
+
+// @Summary Get User
+// @Description Returns a user, a deleted one too.
+// @Tags Admin
+// @Security JWT
+// @Produce json
+// @Param id query int true "The id of the user."
+// @Success 200 {object} dto.Response{data=dto.AdminUser}
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 404 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 60, "period": 60, "key": "user"}
+// @x-permissions ["users:read"]
+// @router /v2/admin/users/detail [GET]
+func (ar *adminRoutes) getUser(c *gin.Context) {
+	var userIDQuery dto.UserIDQuery
+
+	err := c.ShouldBindQuery(&userIDQuery)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, err := ar.userUC.GetUser(userIDQuery.ID)
+	if err != nil {
+		ar.handleUserError(c, err, "Failed to get user")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Success: true, Data: user})
+}
+
+// @Summary Create User
+// @Description Creates a user, with the customer role when none is given.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.UserCreateRequestBody true "The user."
+// @Success 201 {object} dto.Response{data=dto.AdminUser}
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 409 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 10, "period": 60, "key": "user"}
+// @x-permissions ["users:write"]
+// @router /v2/admin/users [POST]
+func (ar *adminRoutes) createUser(c *gin.Context) {
+	var userCreateRequestBody dto.UserCreateRequestBody
+
+	err := c.ShouldBind(&userCreateRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, err := ar.userUC.CreateUser(userCreateRequestBody)
+	if err != nil {
+		ar.handleUserError(c, err, "Failed to create user")
+		return
+	}
+
+	ar.logger.Info("Admin created user",
+		slog.String("admin", adminName(c)),
+		slog.String("username", user.Username),
+		slog.String("role", user.Role),
+	)
+	c.JSON(http.StatusCreated, dto.Response{Success: true, Message: "User created", Data: user})
+}
+
+// @Summary Update User
+// @Description Changes the email or the role of a user, empty fields stay as they are. A changed email has to be verified again.
+// A changed role applies once the tokens of the user are refreshed.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.UserUpdateRequestBody true "The id of the user and the fields to change."
+// @Success 200 {object} dto.Response{data=dto.AdminUser}
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 404 {object} dto.Response
+// @Failure 409 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-permissions ["users:write"]
+// @router /v2/admin/users/update [POST]
+func (ar *adminRoutes) updateUser(c *gin.Context) {
+	var userUpdateRequestBody dto.UserUpdateRequestBody
+
+	err := c.ShouldBind(&userUpdateRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, err := ar.userUC.UpdateUser(userUpdateRequestBody, c.GetString("username"))
+	if err != nil {
+		ar.handleUserError(c, err, "Failed to update user")
+		return
+	}
+
+	ar.logger.Info("Admin updated user",
+		slog.String("admin", adminName(c)),
+		slog.String("username", user.Username),
+		slog.String("role", user.Role),
+	)
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "User updated", Data: user})
+}
+
+// @Summary Disable User
+// @Description Disables a user, who is logged out everywhere and can't log in until enabled again.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.UserIDRequestBody true "The id of the user."
+// @Success 200 {object} dto.Response{data=dto.AdminUser}
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 404 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-permissions ["users:write"]
+// @router /v2/admin/users/disable [POST]
+func (ar *adminRoutes) disableUser(c *gin.Context) {
+	ar.setUserDisabled(c, true)
+}
+
+// @Summary Enable User
+// @Description Enables a disabled user, who can log in again.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.UserIDRequestBody true "The id of the user."
+// @Success 200 {object} dto.Response{data=dto.AdminUser}
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 404 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-permissions ["users:write"]
+// @router /v2/admin/users/enable [POST]
+func (ar *adminRoutes) enableUser(c *gin.Context) {
+	ar.setUserDisabled(c, false)
+}
+
+func (ar *adminRoutes) setUserDisabled(c *gin.Context, disabled bool) {
+	var userIDRequestBody dto.UserIDRequestBody
+
+	err := c.ShouldBind(&userIDRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, err := ar.userUC.SetUserDisabled(userIDRequestBody.ID, disabled, c.GetString("username"), helper.GetConfig(c))
+	if err != nil {
+		ar.handleUserError(c, err, "Failed to update user")
+		return
+	}
+
+	message := "User enabled"
+	if disabled {
+		message = "User disabled"
+	}
+
+	ar.logger.Info("Admin changed whether user is disabled",
+		slog.String("admin", adminName(c)),
+		slog.String("username", user.Username),
+		slog.Bool("disabled", disabled),
+	)
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: message, Data: user})
+}
+
+// @Summary Delete User
+// @Description Deletes a user, who is logged out everywhere. The user is kept, so it can be restored.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.UserIDRequestBody true "The id of the user."
+// @Success 200 {object} dto.Response{data=dto.AdminUser}
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 404 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-permissions ["users:write"]
+// @router /v2/admin/users/delete [POST]
+func (ar *adminRoutes) deleteUser(c *gin.Context) {
+	var userIDRequestBody dto.UserIDRequestBody
+
+	err := c.ShouldBind(&userIDRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, err := ar.userUC.DeleteUser(userIDRequestBody.ID, c.GetString("username"), helper.GetConfig(c))
+	if err != nil {
+		ar.handleUserError(c, err, "Failed to delete user")
+		return
+	}
+
+	ar.logger.Info("Admin deleted user",
+		slog.String("admin", adminName(c)),
+		slog.String("username", user.Username),
+	)
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "User deleted", Data: user})
+}
+
+// @Summary Restore User
+// @Description Restores a deleted user, who can log in again.
+// @Tags Admin
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.UserIDRequestBody true "The id of the user."
+// @Success 200 {object} dto.Response{data=dto.AdminUser}
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 403 {object} dto.Response
+// @Failure 404 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 30, "period": 60, "key": "user"}
+// @x-permissions ["users:write"]
+// @router /v2/admin/users/restore [POST]
+func (ar *adminRoutes) restoreUser(c *gin.Context) {
+	var userIDRequestBody dto.UserIDRequestBody
+
+	err := c.ShouldBind(&userIDRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, err := ar.userUC.RestoreUser(userIDRequestBody.ID)
+	if err != nil {
+		ar.handleUserError(c, err, "Failed to restore user")
+		return
+	}
+
+	ar.logger.Info("Admin restored user",
+		slog.String("admin", adminName(c)),
+		slog.String("username", user.Username),
+	)
+	c.JSON(http.StatusOK, dto.Response{Success: true, Message: "User restored", Data: user})
+}
+
+func (ar *adminRoutes) handleUserError(c *gin.Context, err error, message string) {
+	switch {
+	case helper.IsErrOfType(err, &entity.UserNotFoundError{}):
+		c.JSON(http.StatusNotFound, dto.Response{Success: false, Message: err.Error()})
+	case helper.IsErrOfType(err, &entity.ErrDuplicateUser{}):
+		c.JSON(http.StatusConflict, dto.Response{Success: false, Message: err.Error()})
+	case helper.IsErrOfType(err, &entity.RoleNotFoundError{}) || helper.IsErrOfType(err, &entity.OwnAccountError{}):
+		c.JSON(http.StatusBadRequest, dto.Response{Success: false, Message: err.Error()})
+	default:
+		ar.logger.Error(message, slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.Response{Success: false, Message: message})
+	}
+}
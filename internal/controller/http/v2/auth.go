@@ -8,6 +8,7 @@ import (
 	"github.com/minhmannh2001/authconnecthub/internal/dto"
 	"github.com/minhmannh2001/authconnecthub/internal/entity"
 	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/middlewares"
 	"github.com/minhmannh2001/authconnecthub/internal/usecases"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -25,9 +26,13 @@ func NewAuthenRoutes(handler *gin.RouterGroup,
 	a usecases.IAuthUC,
 	u usecases.IUserUC,
 	r usecases.IRoleUC,
+	rl usecases.IRateLimitUC,
 ) {
 	ar := &authRoutes{l, a, u, r}
 
+	// these routes aren't documented in the swagger file, so they declare their limits here
+	registerLimit := middlewares.RouteRateLimit(rl, entity.RateLimit{Limit: 5, Period: 3600, Key: entity.RateLimitKeyIP})
+
 	h := handler.Group("/auth")
 	{
 		h.GET("/register", func(c *gin.Context) {
@@ -35,7 +40,16 @@ func NewAuthenRoutes(handler *gin.RouterGroup,
 				"title": "Personal Hub",
 			})
 		})
-		h.POST("/register", ar.register)
+		h.POST("/register", registerLimit, ar.register)
+
+		h.POST("/password", ar.changePassword)
+	}
+
+	o := handler.Group("/oidc")
+	{
+		o.POST("/token", ar.postToken)
+		o.GET("/userinfo", ar.getUserInfo)
+		o.POST("/userinfo", ar.getUserInfo)
 	}
 }
 
@@ -101,6 +115,10 @@ func (ar *authRoutes) register(c *gin.Context) {
 	}
 
 	ar.logger.Info("User created", slog.String("username", newUser.Username), slog.Any("roleID", newUser.RoleID), slog.String("email", newUser.Email))
+	if err := ar.authUC.SendVerificationEmail(newUser, helper.GetConfig(c)); err != nil {
+		ar.logger.Error("Failed to send verification email", slog.String("username", newUser.Username), slog.Any("err", err))
+	}
+
 	response := dto.Response{
 		Success: true,
 		Data:    newUser,
@@ -108,3 +126,51 @@ func (ar *authRoutes) register(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, response)
 }
+
+// @Summary Change Password
+// @Description Changes the password of the logged in user and logs out every other session of the user.
+// @Tags Authen
+// @Security JWT
+// @Accept json
+// @Produce json
+// @Param body body dto.ChangePasswordRequestBody true "The current and the new password."
+// @Success 200 {object} dto.Response
+// @Failure 400 {object} dto.ValidationResponse
+// @Failure 401 {object} dto.Response
+// @Failure 500 {object} dto.Response
+// @x-rate-limit {"limit": 5, "period": 60, "key": "user"}
+// @router /v2/auth/password [POST]
+func (ar *authRoutes) changePassword(c *gin.Context) {
+	var changePasswordRequestBody dto.ChangePasswordRequestBody
+
+	err := c.ShouldBind(&changePasswordRequestBody)
+	if err != nil {
+		response := helper.GenerateValidationResponse(err)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err = ar.authUC.ChangePassword(c, changePasswordRequestBody)
+	if err != nil {
+		response := dto.Response{Success: false, Data: nil}
+
+		if helper.IsErrOfType(err, &entity.IncorrectPasswordError{}) {
+			response.Message = err.Error()
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+
+		ar.logger.Error("Failed to change password", slog.Any("username", c.GetString("username")), slog.Any("err", err))
+		response.Message = "Failed to change password"
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	ar.logger.Info("User changed password", slog.String("username", c.GetString("username")))
+	response := dto.Response{
+		Success: true,
+		Data:    nil,
+		Message: "Password changed successfully",
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,106 @@
+package v2
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// @Summary OpenID Connect Token
+// @Description Redeems an authorization code for an access token and an ID token, see OpenID Connect Core section 3.1.3.
+// Applications with a secret authenticate with HTTP basic authentication or the client_secret field, applications without one send the PKCE code_verifier.
+// @Tags OIDC
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "Has to be authorization_code."
+// @Param code formData string true "The authorization code."
+// @Param redirect_uri formData string true "The redirect uri of the authorization request."
+// @Param client_id formData string false "The id of the application, unless sent with HTTP basic authentication."
+// @Param client_secret formData string false "The secret of the application, unless sent with HTTP basic authentication."
+// @Param code_verifier formData string false "The PKCE verifier of the code challenge."
+// @Success 200 {object} dto.OIDCTokens
+// @Failure 400 {object} dto.OAuthErrorResponse
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
+// @x-rate-limit {"limit": 60, "period": 60, "key": "ip"}
+// @router /v2/oidc/token [POST]
+func (ar *authRoutes) postToken(c *gin.Context) {
+	// the response contains tokens, so it mustn't be cached
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var tokenRequestBody dto.TokenRequestBody
+	_ = c.ShouldBind(&tokenRequestBody)
+
+	usesBasicAuth := false
+	if clientID, clientSecret, ok := c.Request.BasicAuth(); ok {
+		// the credentials are form encoded before they are put in the header, see RFC 6749 section 2.3.1
+		tokenRequestBody.ClientID, _ = url.QueryUnescape(clientID)
+		tokenRequestBody.ClientSecret, _ = url.QueryUnescape(clientSecret)
+		usesBasicAuth = true
+	}
+
+	tokens, err := ar.authUC.ExchangeAuthorizationCode(tokenRequestBody, helper.GetConfig(c))
+	if err != nil {
+		var oauthErr *entity.OAuthError
+		if !errors.As(err, &oauthErr) {
+			ar.logger.Error("Failed to exchange authorization code", slog.String("client_id", tokenRequestBody.ClientID), slog.Any("err", err))
+			c.JSON(http.StatusInternalServerError, dto.OAuthErrorResponse{Error: "server_error"})
+			return
+		}
+
+		status := http.StatusBadRequest
+		if oauthErr.Code == entity.OAuthErrorInvalidClient {
+			status = http.StatusUnauthorized
+			if usesBasicAuth {
+				c.Header("WWW-Authenticate", `Basic realm="oidc"`)
+			}
+		}
+		ar.logger.Warn("Token request rejected", slog.String("client_id", tokenRequestBody.ClientID), slog.String("error", oauthErr.Code))
+		c.JSON(status, dto.OAuthErrorResponse{Error: oauthErr.Code, ErrorDescription: oauthErr.Description})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary OpenID Connect UserInfo
+// @Description Returns the claims about the user an access token was issued for, as far as its scope allows, see OpenID Connect Core section 5.3.
+// @Tags OIDC
+// @Produce json
+// @Param Authorization header string true "Bearer followed by the access token of the token endpoint."
+// @Success 200 {object} object "The claims about the user."
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
+// @router /v2/oidc/userinfo [GET]
+// @router /v2/oidc/userinfo [POST]
+func (ar *authRoutes) getUserInfo(c *gin.Context) {
+	accessToken := helper.ExtractHeaderToken(c, helper.AccessTokenHeader)
+	if accessToken == "" {
+		c.Header("WWW-Authenticate", `Bearer realm="oidc"`)
+		c.JSON(http.StatusUnauthorized, dto.OAuthErrorResponse{Error: entity.OAuthErrorInvalidToken, ErrorDescription: "The access token is missing."})
+		return
+	}
+
+	claims, err := ar.authUC.UserInfo(accessToken)
+	if err != nil {
+		var oauthErr *entity.OAuthError
+		if errors.As(err, &oauthErr) {
+			c.Header("WWW-Authenticate", `Bearer realm="oidc", error="`+oauthErr.Code+`"`)
+			c.JSON(http.StatusUnauthorized, dto.OAuthErrorResponse{Error: oauthErr.Code, ErrorDescription: oauthErr.Description})
+			return
+		}
+
+		ar.logger.Error("Failed to return user info", slog.Any("err", err))
+		c.JSON(http.StatusInternalServerError, dto.OAuthErrorResponse{Error: "server_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, claims)
+}
@@ -14,3 +14,217 @@ type RegisterRequestBody struct {
 	Password        string `json:"password"         form:"password"         binding:"required,min=8"`
 	ConfirmPassword string `json:"confirm_password" form:"confirm_password" binding:"required,min=8,eqfield=Password"`
 }
+
+// ForgetPasswordRequestBody
+type ForgetPasswordRequestBody struct {
+	Email string `json:"email" form:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequestBody
+type ResetPasswordRequestBody struct {
+	Token           string `json:"token"            form:"token"            binding:"required"`
+	Password        string `json:"password"         form:"password"         binding:"required,min=8"`
+	ConfirmPassword string `json:"confirm_password" form:"confirm_password" binding:"required,min=8,eqfield=Password"`
+}
+
+// ChangePasswordRequestBody
+type ChangePasswordRequestBody struct {
+	CurrentPassword string `json:"current_password" form:"current_password" binding:"required"`
+	Password        string `json:"password"         form:"password"         binding:"required,min=8,nefield=CurrentPassword"`
+	ConfirmPassword string `json:"confirm_password" form:"confirm_password" binding:"required,min=8,eqfield=Password"`
+}
+
+// MFALoginRequestBody
+type MFALoginRequestBody struct {
+	Token      string `json:"token"       form:"token"       binding:"required"`
+	Code       string `json:"code"        form:"code"        binding:"required,max=32"`
+	RememberMe string `json:"remember_me" form:"remember_me"`
+}
+
+// TOTPCodeRequestBody
+type TOTPCodeRequestBody struct {
+	Code string `json:"code" form:"code" binding:"required,len=6,numeric"`
+}
+
+// PasskeyRegistrationRequestBody
+type PasskeyRegistrationRequestBody struct {
+	Session    string `json:"session"    form:"session"    binding:"required"`
+	Credential string `json:"credential" form:"credential" binding:"required"`
+	Name       string `json:"name"       form:"name"       binding:"max=64"`
+}
+
+// PasskeyLoginRequestBody
+type PasskeyLoginRequestBody struct {
+	Session    string `json:"session"     form:"session"     binding:"required"`
+	Credential string `json:"credential"  form:"credential"  binding:"required"`
+	RememberMe string `json:"remember_me" form:"remember_me"`
+}
+
+// PasskeyDeleteRequestBody
+type PasskeyDeleteRequestBody struct {
+	ID uint `json:"id" form:"id" binding:"required"`
+}
+
+// SessionRevokeRequestBody
+type SessionRevokeRequestBody struct {
+	ID string `json:"id" form:"id" binding:"required"`
+}
+
+// LoginLockClearRequestBody
+type LoginLockClearRequestBody struct {
+	Kind  string `json:"kind"  form:"kind"  binding:"required,oneof=username ip"`
+	Value string `json:"value" form:"value" binding:"required"`
+}
+
+// ServiceAccountCreateRequestBody, a token ttl of 0 uses service_token_ttl of the config
+type ServiceAccountCreateRequestBody struct {
+	Name     string `json:"name"      form:"name"      binding:"required,max=255"`
+	Scopes   string `json:"scopes"    form:"scopes"    binding:"max=1024"`
+	TokenTTL int    `json:"token_ttl" form:"token_ttl" binding:"omitempty,min=60,max=86400"`
+}
+
+// ServiceAccountDeleteRequestBody
+type ServiceAccountDeleteRequestBody struct {
+	ClientID string `json:"client_id" form:"client_id" binding:"required"`
+}
+
+// ApplicationCreateRequestBody, redirect uris and allowed roles are space separated. A random client id is
+// picked when none is given, public applications get no secret. A token ttl of 0 uses the one of the oidc config.
+type ApplicationCreateRequestBody struct {
+	ClientID       string `json:"client_id"        form:"client_id"        binding:"max=64"`
+	Name           string `json:"name"             form:"name"             binding:"required,max=255"`
+	URL            string `json:"url"              form:"url"              binding:"required,url,max=1024"`
+	Icon           string `json:"icon"             form:"icon"             binding:"max=1024"`
+	RedirectURIs   string `json:"redirect_uris"    form:"redirect_uris"    binding:"max=4096"`
+	AllowedRoles   string `json:"allowed_roles"    form:"allowed_roles"    binding:"max=1024"`
+	Public         bool   `json:"public"           form:"public"`
+	AccessTokenTTL int    `json:"access_token_ttl" form:"access_token_ttl" binding:"omitempty,min=60,max=86400"`
+	IDTokenTTL     int    `json:"id_token_ttl"     form:"id_token_ttl"     binding:"omitempty,min=60,max=86400"`
+}
+
+// ApplicationUpdateRequestBody replaces the settings of the application, its client id and secret stay
+type ApplicationUpdateRequestBody struct {
+	ClientID       string `json:"client_id"        form:"client_id"        binding:"required"`
+	Name           string `json:"name"             form:"name"             binding:"required,max=255"`
+	URL            string `json:"url"              form:"url"              binding:"required,url,max=1024"`
+	Icon           string `json:"icon"             form:"icon"             binding:"max=1024"`
+	RedirectURIs   string `json:"redirect_uris"    form:"redirect_uris"    binding:"max=4096"`
+	AllowedRoles   string `json:"allowed_roles"    form:"allowed_roles"    binding:"max=1024"`
+	AccessTokenTTL int    `json:"access_token_ttl" form:"access_token_ttl" binding:"omitempty,min=60,max=86400"`
+	IDTokenTTL     int    `json:"id_token_ttl"     form:"id_token_ttl"     binding:"omitempty,min=60,max=86400"`
+}
+
+// ApplicationDeleteRequestBody
+type ApplicationDeleteRequestBody struct {
+	ClientID string `json:"client_id" form:"client_id" binding:"required"`
+}
+
+// ApplicationGrantRequestBody gives a user, or the users with a role, a role within the application
+type ApplicationGrantRequestBody struct {
+	ClientID    string `json:"client_id"    form:"client_id"    binding:"required"`
+	SubjectType string `json:"subject_type" form:"subject_type" binding:"required,oneof=user role"`
+	Subject     string `json:"subject"      form:"subject"      binding:"required,max=255"`
+	AppRole     string `json:"app_role"     form:"app_role"     binding:"required,oneof=viewer editor admin"`
+}
+
+// ApplicationGrantDeleteRequestBody
+type ApplicationGrantDeleteRequestBody struct {
+	ClientID    string `json:"client_id"    form:"client_id"    binding:"required"`
+	SubjectType string `json:"subject_type" form:"subject_type" binding:"required,oneof=user role"`
+	Subject     string `json:"subject"      form:"subject"      binding:"required"`
+}
+
+// UserListQuery filters and sorts the users. The search matches parts of usernames and emails, the
+// cursor is the next cursor of the page before. A sort by a column with a leading - is descending.
+type UserListQuery struct {
+	Search string `form:"q"      binding:"max=255"`
+	Role   string `form:"role"   binding:"max=50"`
+	Status string `form:"status" binding:"omitempty,oneof=active disabled deleted"`
+	Sort   string `form:"sort"   binding:"omitempty,oneof=username -username email -email created_at -created_at"`
+	Limit  int    `form:"limit"  binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor" binding:"max=1024"`
+}
+
+// UserIDQuery
+type UserIDQuery struct {
+	ID uint `form:"id" binding:"required"`
+}
+
+// UserCreateRequestBody, users get the customer role when none is given
+type UserCreateRequestBody struct {
+	Username      string `json:"username"       form:"username"       binding:"required,max=255"`
+	Email         string `json:"email"          form:"email"          binding:"required,email,max=255"`
+	Password      string `json:"password"       form:"password"       binding:"required,min=8"`
+	Role          string `json:"role"           form:"role"           binding:"max=50"`
+	EmailVerified bool   `json:"email_verified" form:"email_verified"`
+}
+
+// UserUpdateRequestBody changes the email or the role of the user, empty fields stay as they are.
+// A changed email has to be verified again.
+type UserUpdateRequestBody struct {
+	ID    uint   `json:"id"    form:"id"    binding:"required"`
+	Email string `json:"email" form:"email" binding:"omitempty,email,max=255"`
+	Role  string `json:"role"  form:"role"  binding:"max=50"`
+}
+
+// UserIDRequestBody
+type UserIDRequestBody struct {
+	ID uint `json:"id" form:"id" binding:"required"`
+}
+
+// RoleCreateRequestBody, the parents and permissions are names of roles and permissions which exist.
+// The role gets the permissions of its parents too.
+type RoleCreateRequestBody struct {
+	Name        string   `json:"name"        form:"name"        binding:"required,max=50"`
+	Description string   `json:"description" form:"description" binding:"max=255"`
+	Parents     []string `json:"parents"     form:"parents"     binding:"max=50"`
+	Permissions []string `json:"permissions" form:"permissions" binding:"max=100"`
+}
+
+// RoleUpdateRequestBody replaces the settings of the role, it is renamed when a new name is given
+type RoleUpdateRequestBody struct {
+	Name        string   `json:"name"        form:"name"        binding:"required"`
+	NewName     string   `json:"new_name"    form:"new_name"    binding:"max=50"`
+	Description string   `json:"description" form:"description" binding:"max=255"`
+	Parents     []string `json:"parents"     form:"parents"     binding:"max=50"`
+	Permissions []string `json:"permissions" form:"permissions" binding:"max=100"`
+}
+
+// RoleDeleteRequestBody
+type RoleDeleteRequestBody struct {
+	Name string `json:"name" form:"name" binding:"required"`
+}
+
+// FederationLoginRequestBody starts a sign in at an upstream provider
+type FederationLoginRequestBody struct {
+	Provider   string `json:"provider"    form:"provider"    binding:"required"`
+	RememberMe string `json:"remember_me" form:"remember_me"`
+}
+
+// FederationLinkRequestBody
+type FederationLinkRequestBody struct {
+	Provider string `json:"provider" form:"provider" binding:"required"`
+}
+
+// FederationUnlinkRequestBody
+type FederationUnlinkRequestBody struct {
+	ID uint `json:"id" form:"id" binding:"required"`
+}
+
+// FederationCallbackQuery is how an upstream provider sends the browser back, see OpenID Connect Core section 3.1.2.5 and 3.1.2.6
+type FederationCallbackQuery struct {
+	Code             string `form:"code"`
+	State            string `form:"state"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// ForwardAuthRequest is the request a reverse proxy asks about. The tokens come from the
+// Authorization and Refresh headers, or from the cookies when FromCookie is set.
+type ForwardAuthRequest struct {
+	Host         string
+	Path         string
+	AccessToken  string
+	RefreshToken string
+	FromCookie   bool
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type LoginRespData struct {
 }
 
@@ -10,3 +12,114 @@ type JwtTokens struct {
 	AccessToken  string
 	RefreshToken string
 }
+
+// TOTPEnrollment holds what an authenticator app needs to be set up
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+	// QRCode is a PNG data URI of the URI
+	QRCode string `json:"qr_code"`
+}
+
+// PasskeyCeremony is handed to the browser to start a passkey prompt.
+// The session has to be sent back together with the answer of the authenticator.
+type PasskeyCeremony struct {
+	Session string      `json:"session"`
+	Options interface{} `json:"options"`
+}
+
+// ServiceAccountCredentials is returned once when a service account is created, only the hash of the secret is kept
+type ServiceAccountCredentials struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	Name         string `json:"name"`
+	Scopes       string `json:"scopes"`
+	TokenTTL     int    `json:"token_ttl"`
+}
+
+// ApplicationCredentials is returned once when an application is created, only the hash of the secret is kept.
+// Public applications have no secret.
+type ApplicationCredentials struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	Name         string `json:"name"`
+}
+
+// ApplicationGrantMatrix shows which role each subject has within each application. The app roles of
+// a row follow the order of the applications, they are empty where the subject has no grant.
+type ApplicationGrantMatrix struct {
+	Applications []ApplicationGrantColumn `json:"applications"`
+	Rows         []ApplicationGrantRow    `json:"rows"`
+}
+
+type ApplicationGrantColumn struct {
+	ClientID string `json:"client_id"`
+	Name     string `json:"name"`
+}
+
+type ApplicationGrantRow struct {
+	SubjectType string   `json:"subject_type"`
+	Subject     string   `json:"subject"`
+	AppRoles    []string `json:"app_roles"`
+}
+
+// AdminUser is how administrators see a user
+type AdminUser struct {
+	ID            uint       `json:"id"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	Role          string     `json:"role"`
+	EmailVerified bool       `json:"email_verified"`
+	TOTPEnabled   bool       `json:"totp_enabled"`
+	Disabled      bool       `json:"disabled"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeletedAt     *time.Time `json:"deleted_at"`
+}
+
+// UserPage is a page of users, the next cursor is empty on the last page
+type UserPage struct {
+	Users      []AdminUser `json:"users"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// AdminRole is a role as administrators see it. The inherited permissions come from the parents of the
+// role and their parents, the ones the role has itself aren't repeated. The permissions of the admin
+// role are fixed, it has every one of them.
+type AdminRole struct {
+	Name                 string   `json:"name"`
+	Description          string   `json:"description"`
+	System               bool     `json:"system"`
+	PermissionsFixed     bool     `json:"permissions_fixed"`
+	Parents              []string `json:"parents"`
+	Permissions          []string `json:"permissions"`
+	InheritedPermissions []string `json:"inherited_permissions"`
+}
+
+// AdminPermission is a permission roles can be given
+type AdminPermission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// FederationResult tells what a sign in at an upstream provider came to. Linked is set when the identity
+// was linked to the logged in user, MFAToken when the user still has to prove the second factor.
+// Otherwise the user is logged in with the tokens.
+type FederationResult struct {
+	Provider   string
+	Linked     bool
+	Tokens     *JwtTokens
+	MFAToken   string
+	RememberMe bool
+}
+
+// ForwardAuthResult lets the request through. The user is empty when the rule bypasses the login,
+// AppRole is set when the host belongs to an application with grants. Tokens are set when the tokens
+// of the cookies were refreshed.
+type ForwardAuthResult struct {
+	Username   string
+	Email      string
+	Groups     []string
+	AppRole    string
+	Tokens     *JwtTokens
+	RememberMe bool
+}
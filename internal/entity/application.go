@@ -0,0 +1,45 @@
+package entity
+
+import "gorm.io/gorm"
+
+// Application is a home app registered with the hub. It logs its users in with OpenID Connect under its
+// client id, which is the audience of the ID tokens it gets. Applications without a secret are public
+// and have to use PKCE. Redirect uris and allowed roles are space separated, users need one of the
+// allowed roles to open the application, any role will do when there are none. Grants take over from
+// the allowed roles, see ApplicationGrant. A token ttl of 0 uses the one of the oidc config.
+type Application struct {
+	gorm.Model
+	ClientID       string `gorm:"size:255;not null;uniqueIndex" json:"client_id"`
+	SecretHash     string `gorm:"size:255;not null;default:''"  json:"-"`
+	Name           string `gorm:"size:255;not null"             json:"name"`
+	URL            string `gorm:"size:1024;not null"            json:"url"`
+	Icon           string `gorm:"size:1024;not null;default:''" json:"icon"`
+	RedirectURIs   string `gorm:"size:4096;not null;default:''" json:"redirect_uris"`
+	AllowedRoles   string `gorm:"size:1024;not null;default:''" json:"allowed_roles"`
+	AccessTokenTTL int    `gorm:"not null;default:0"            json:"access_token_ttl"`
+	IDTokenTTL     int    `gorm:"not null;default:0"            json:"id_token_ttl"`
+}
+
+// Roles a grant gives within an application, each one includes the ones before it
+const (
+	AppRoleViewer = "viewer"
+	AppRoleEditor = "editor"
+	AppRoleAdmin  = "admin"
+)
+
+// Who an application grant is for. Groups of the directory are granted through the role they map to.
+const (
+	GrantSubjectUser = "user"
+	GrantSubjectRole = "role"
+)
+
+// ApplicationGrant lets a user, or the users with a role, use an application with a role of the application.
+// Once an application has grants only the users they cover may use it, its allowed roles aren't looked at anymore.
+type ApplicationGrant struct {
+	gorm.Model
+	ApplicationID uint        `gorm:"not null;uniqueIndex:idx_application_grant_subject"          json:"application_id"`
+	SubjectType   string      `gorm:"size:16;not null;uniqueIndex:idx_application_grant_subject"  json:"subject_type"`
+	Subject       string      `gorm:"size:255;not null;uniqueIndex:idx_application_grant_subject" json:"subject"`
+	AppRole       string      `gorm:"size:16;not null"                                            json:"app_role"`
+	Application   Application `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"               json:"-"`
+}
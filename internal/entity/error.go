@@ -1,6 +1,10 @@
 package entity
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 type CustomErrorType interface {
 	Error() string
@@ -38,6 +42,375 @@ func (e *InvalidCredentialsError) Error() string {
 	return "Invalid credentials. Please try again."
 }
 
+type InvalidResetPasswordTokenError struct{}
+
+func (e *InvalidResetPasswordTokenError) Error() string {
+	return "Your password reset link is invalid or has expired."
+}
+
+type IncorrectPasswordError struct{}
+
+func (e *IncorrectPasswordError) Error() string {
+	return "Your current password is incorrect."
+}
+
+type InvalidEmailVerificationTokenError struct{}
+
+func (e *InvalidEmailVerificationTokenError) Error() string {
+	return "Your verification link is invalid or has expired."
+}
+
+type EmailNotVerifiedError struct{}
+
+func (e *EmailNotVerifiedError) Error() string {
+	return "Please verify your email address before logging in. Check your inbox for the verification link."
+}
+
+// MFARequiredError is returned when the password is correct but the user still has to
+// prove the second factor. Token identifies the pending login.
+type MFARequiredError struct {
+	Token string
+}
+
+func (e *MFARequiredError) Error() string {
+	return "Enter the code from your authenticator app to finish signing in."
+}
+
+// LoginLockedError is returned when too many logins failed for the username or the
+// client ip. Until is when the lock is lifted.
+type LoginLockedError struct {
+	Until time.Time
+}
+
+func (e *LoginLockedError) Error() string {
+	minutes := int(math.Ceil(time.Until(e.Until).Minutes()))
+	if minutes <= 1 {
+		return "Too many failed login attempts. Please try again in a minute."
+	}
+	return fmt.Sprintf("Too many failed login attempts. Please try again in %d minutes.", minutes)
+}
+
+// LoginThrottledError is returned when a login is attempted too soon after a failed one.
+// Until is when the next attempt is allowed.
+type LoginThrottledError struct {
+	Until time.Time
+}
+
+func (e *LoginThrottledError) Error() string {
+	return "Please wait a moment before trying to log in again."
+}
+
+type LoginLockNotFoundError struct{}
+
+func (e *LoginLockNotFoundError) Error() string {
+	return "The lock does not exist or has already expired."
+}
+
+type InvalidMFATokenError struct{}
+
+func (e *InvalidMFATokenError) Error() string {
+	return "Your sign in attempt has expired. Please log in again."
+}
+
+type InvalidTOTPCodeError struct{}
+
+func (e *InvalidTOTPCodeError) Error() string {
+	return "The verification code is invalid."
+}
+
+type TOTPAlreadyEnabledError struct{}
+
+func (e *TOTPAlreadyEnabledError) Error() string {
+	return "Two-factor authentication is already enabled."
+}
+
+type TOTPNotEnrolledError struct{}
+
+func (e *TOTPNotEnrolledError) Error() string {
+	return "Two-factor authentication has not been set up yet."
+}
+
+type InvalidPasskeyError struct{}
+
+func (e *InvalidPasskeyError) Error() string {
+	return "The passkey could not be verified. Please try again."
+}
+
+type PasskeyNotFoundError struct{}
+
+func (e *PasskeyNotFoundError) Error() string {
+	return "The passkey does not exist."
+}
+
+// RefreshTokenReusedError means a refresh token was presented after it had been exchanged already,
+// so it has likely been stolen
+type RefreshTokenReusedError struct {
+	Username string
+	Family   string
+}
+
+func (e *RefreshTokenReusedError) Error() string {
+	return "The refresh token has already been used."
+}
+
+type SessionNotFoundError struct{}
+
+func (e *SessionNotFoundError) Error() string {
+	return "The session does not exist or has already ended."
+}
+
+// OAuthError is an error of the OpenID Connect endpoints which is reported to the client, Code is one
+// of the OAuth 2.0 error codes
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Description
+}
+
+// InvalidOIDCClientError is returned when the client or its redirect uri isn't registered,
+// so the user can't be sent back to the application with an error
+type InvalidOIDCClientError struct{}
+
+func (e *InvalidOIDCClientError) Error() string {
+	return "The application you came from isn't registered with the hub."
+}
+
+// OIDCLoginRequiredError is returned when an application asks to log in a user who isn't logged in to the hub yet
+type OIDCLoginRequiredError struct{}
+
+func (e *OIDCLoginRequiredError) Error() string {
+	return "Please log in to continue to the application."
+}
+
+type ServiceAccountNotFoundError struct{}
+
+func (e *ServiceAccountNotFoundError) Error() string {
+	return "The service account does not exist."
+}
+
+// InvalidScopeError is returned when a service account is given a scope which isn't made of
+// lowercase letters, digits and the characters : . _ -
+type InvalidScopeError struct {
+	Scope string
+}
+
+func (e *InvalidScopeError) Error() string {
+	return fmt.Sprintf("The scope %q is invalid.", e.Scope)
+}
+
+type ApplicationNotFoundError struct{}
+
+func (e *ApplicationNotFoundError) Error() string {
+	return "The application does not exist."
+}
+
+// ApplicationConflictError is returned when the client id of a new application is taken already,
+// by another application or by a client of the config
+type ApplicationConflictError struct {
+	ClientID string
+}
+
+func (e *ApplicationConflictError) Error() string {
+	return fmt.Sprintf("The client id %q is already taken.", e.ClientID)
+}
+
+// InvalidClientIDError is returned when the client id of a new application isn't made of
+// lowercase letters, digits and the characters . _ -, or is one of the reserved ids users and services
+type InvalidClientIDError struct {
+	ClientID string
+}
+
+func (e *InvalidClientIDError) Error() string {
+	return fmt.Sprintf("The client id %q is invalid.", e.ClientID)
+}
+
+// InvalidRedirectURIError is returned when a redirect uri of an application isn't an absolute http or https url
+type InvalidRedirectURIError struct {
+	URI string
+}
+
+func (e *InvalidRedirectURIError) Error() string {
+	return fmt.Sprintf("The redirect uri %q is invalid.", e.URI)
+}
+
+type ApplicationGrantNotFoundError struct{}
+
+func (e *ApplicationGrantNotFoundError) Error() string {
+	return "The grant does not exist."
+}
+
+// GrantSubjectNotFoundError is returned when a grant is given to a user who doesn't exist
+type GrantSubjectNotFoundError struct {
+	Subject string
+}
+
+func (e *GrantSubjectNotFoundError) Error() string {
+	return fmt.Sprintf("The user %q does not exist.", e.Subject)
+}
+
+type UpstreamProviderNotFoundError struct{}
+
+func (e *UpstreamProviderNotFoundError) Error() string {
+	return "The sign in provider does not exist."
+}
+
+// InvalidFederationStateError is returned when the upstream provider sends the browser back with a
+// state the hub didn't hand out to it, or after the sign in attempt expired
+type InvalidFederationStateError struct{}
+
+func (e *InvalidFederationStateError) Error() string {
+	return "Your sign in attempt has expired. Please try again."
+}
+
+// FederationFailedError is returned when the upstream provider turned the user down or its answer
+// couldn't be verified. Reason is what went wrong, it isn't shown to the user.
+type FederationFailedError struct {
+	Reason string
+}
+
+func (e *FederationFailedError) Error() string {
+	return "Signing in with the provider failed. Please try again."
+}
+
+// FederatedEmailRequiredError is returned when a new user signs in but the provider doesn't share
+// a verified email address to create the account with
+type FederatedEmailRequiredError struct{}
+
+func (e *FederatedEmailRequiredError) Error() string {
+	return "The provider did not share a verified email address, so no account could be created."
+}
+
+// FederatedEmailTakenError is returned when a new identity has the email of an existing user. Linking
+// it automatically would hand the account to whoever controls the identity, so the user has to log in
+// and link it from their profile.
+type FederatedEmailTakenError struct{}
+
+func (e *FederatedEmailTakenError) Error() string {
+	return "An account with this email already exists. Log in and link the provider from your profile."
+}
+
+type IdentityAlreadyLinkedError struct{}
+
+func (e *IdentityAlreadyLinkedError) Error() string {
+	return "This account of the provider is already linked to a user."
+}
+
+type LinkedIdentityNotFoundError struct{}
+
+func (e *LinkedIdentityNotFoundError) Error() string {
+	return "The linked account does not exist."
+}
+
+// DirectoryUserConflictError is returned when a user of the directory logs in for the first time but
+// their username or email belongs to a local user. Taking the local user over is left to an administrator.
+type DirectoryUserConflictError struct{}
+
+func (e *DirectoryUserConflictError) Error() string {
+	return "An account with this username or email already exists. Please ask an administrator to connect it to the directory."
+}
+
+// LoginRequiredError is returned when a request asked about by a reverse proxy needs a logged in user
+type LoginRequiredError struct{}
+
+func (e *LoginRequiredError) Error() string {
+	return "Login is required for this action. Sign in or create an account to continue."
+}
+
+// AccessDeniedError is returned when the user is logged in but isn't allowed to do what they asked for
+type AccessDeniedError struct{}
+
+func (e *AccessDeniedError) Error() string {
+	return "You don't have permission to access this resource."
+}
+
+type UserNotFoundError struct{}
+
+func (e *UserNotFoundError) Error() string {
+	return "The user does not exist."
+}
+
+// UserDisabledError is returned when a disabled user logs in or refreshes their tokens
+type UserDisabledError struct{}
+
+func (e *UserDisabledError) Error() string {
+	return "Your account has been disabled. Please contact an administrator."
+}
+
+// OwnAccountError is returned when administrators would lock themselves out, by disabling or deleting
+// their own account or by changing its role
+type OwnAccountError struct{}
+
+func (e *OwnAccountError) Error() string {
+	return "You can't disable, delete or change the role of your own account."
+}
+
+// InvalidCursorError is returned when the cursor of a page doesn't come from the page before
+type InvalidCursorError struct{}
+
+func (e *InvalidCursorError) Error() string {
+	return "The cursor is invalid."
+}
+
+// RoleConflictError is returned when the name of a role is taken already
+type RoleConflictError struct {
+	Name string
+}
+
+func (e *RoleConflictError) Error() string {
+	return fmt.Sprintf("The role %q already exists.", e.Name)
+}
+
+// InvalidRoleNameError is returned when the name of a role isn't made of lowercase letters, digits
+// and the characters . _ -, role names are listed separated by spaces
+type InvalidRoleNameError struct {
+	Name string
+}
+
+func (e *InvalidRoleNameError) Error() string {
+	return fmt.Sprintf("The role name %q is invalid.", e.Name)
+}
+
+// SystemRoleError is returned when a system role would be renamed or deleted, or the permissions
+// of the admin role would change
+type SystemRoleError struct {
+	Name string
+}
+
+func (e *SystemRoleError) Error() string {
+	return fmt.Sprintf("The role %q is a system role, it can't be changed this way.", e.Name)
+}
+
+// RoleInUseError is returned when a role which is deleted is still assigned to users, or still
+// named by the grants or the allowed roles of applications
+type RoleInUseError struct {
+	Name string
+}
+
+func (e *RoleInUseError) Error() string {
+	return fmt.Sprintf("The role %q is still assigned.", e.Name)
+}
+
+// RoleCycleError is returned when a role would inherit from itself, directly or through its parents
+type RoleCycleError struct {
+	Name string
+}
+
+func (e *RoleCycleError) Error() string {
+	return fmt.Sprintf("The role %q can't inherit from itself.", e.Name)
+}
+
+// PermissionNotFoundError is returned when a role is given a permission which doesn't exist
+type PermissionNotFoundError struct {
+	Name string
+}
+
+func (e *PermissionNotFoundError) Error() string {
+	return fmt.Sprintf("The permission %q does not exist.", e.Name)
+}
+
 type InternalServerError struct{}
 
 func (e *InternalServerError) Error() string {
@@ -0,0 +1,8 @@
+package entity
+
+// What a forward auth rule does with the requests it matches
+const (
+	ForwardAuthPolicyBypass        = "bypass"
+	ForwardAuthPolicyAuthenticated = "authenticated"
+	ForwardAuthPolicyDeny          = "deny"
+)
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LinkedIdentity is an account of an upstream OpenID Connect provider a user signs in with.
+// The issuer and subject identify it, the email is only kept to show which account it is.
+type LinkedIdentity struct {
+	gorm.Model
+	UserID     uint       `gorm:"not null;index"                                            json:"user_id"`
+	Provider   string     `gorm:"size:255;not null"                                         json:"provider"`
+	Issuer     string     `gorm:"size:255;not null;uniqueIndex:idx_linked_identity_subject" json:"issuer"`
+	Subject    string     `gorm:"size:255;not null;uniqueIndex:idx_linked_identity_subject" json:"subject"`
+	Email      string     `gorm:"size:255"                                                  json:"email"`
+	LastUsedAt *time.Time `gorm:"default:null"                                              json:"last_used_at"`
+	User       User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"             json:"-"`
+}
+
+// FederationState is what the hub remembers about a sign in at an upstream provider until the
+// browser comes back. LinkUsername is set when a logged in user links the identity instead.
+type FederationState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	RememberMe   bool   `json:"remember_me"`
+	LinkUsername string `json:"link_username"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Login locks are kept per username and per client ip
+const (
+	LoginLockKindUsername = "username"
+	LoginLockKindIP       = "ip"
+)
+
+// LoginLock blocks logins of a username or from an ip after too many failed attempts
+type LoginLock struct {
+	Kind  string    `json:"kind"`
+	Value string    `json:"value"`
+	Until time.Time `json:"until"`
+}
@@ -0,0 +1,41 @@
+package entity
+
+// The scopes a client can ask for, openid is required
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// The OAuth 2.0 error codes, see RFC 6749 section 4.1.2.1 and 5.2 and OpenID Connect Core section 3.1.2.6
+const (
+	OAuthErrorInvalidRequest          = "invalid_request"
+	OAuthErrorInvalidClient           = "invalid_client"
+	OAuthErrorInvalidGrant            = "invalid_grant"
+	OAuthErrorUnauthorizedClient      = "unauthorized_client"
+	OAuthErrorInvalidScope            = "invalid_scope"
+	OAuthErrorUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthErrorUnsupportedResponseType = "unsupported_response_type"
+	OAuthErrorLoginRequired           = "login_required"
+	OAuthErrorInvalidToken            = "invalid_token"
+	OAuthErrorAccessDenied            = "access_denied"
+)
+
+// AuthorizationCode is what a code issued by the authorization endpoint stands for.
+// It is kept in redis until the client redeems it at the token endpoint.
+type AuthorizationCode struct {
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Username            string `json:"username"`
+	Scope               string `json:"scope"`
+	Nonce               string `json:"nonce"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+}
+
+// OIDCAccessToken is what an access token issued to a client grants at the userinfo endpoint
+type OIDCAccessToken struct {
+	ClientID string `json:"client_id"`
+	Username string `json:"username"`
+	Scope    string `json:"scope"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "gorm.io/gorm"
+
+// Permission allows an action of the hub. Operations of the swagger file ask for permissions with
+// the x-permissions extension, users get them through their role.
+type Permission struct {
+	gorm.Model
+	Name        string `gorm:"size:100;not null;unique" json:"name"`
+	Description string `gorm:"size:255;not null"        json:"description"`
+}
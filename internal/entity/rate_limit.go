@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// Requests are counted per client ip, per logged in user or per api key
+const (
+	RateLimitKeyIP     = "ip"
+	RateLimitKeyUser   = "user"
+	RateLimitKeyAPIKey = "api_key"
+)
+
+// RateLimit allows Limit requests every Period seconds. Up to Burst requests may be sent
+// at once, it defaults to Limit. It is declared on routes or as the x-rate-limit extension
+// of an operation in the swagger file.
+type RateLimit struct {
+	Limit  int    `json:"limit"`
+	Period int    `json:"period"`
+	Burst  int    `json:"burst,omitempty"`
+	Key    string `json:"key,omitempty"`
+}
+
+// RateLimitResult is the state of a token bucket after a request was counted
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
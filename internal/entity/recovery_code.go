@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecoveryCode is a one-time code a user can enter instead of the second factor
+type RecoveryCode struct {
+	gorm.Model
+	UserID   uint       `gorm:"not null;index"                                json:"user_id"`
+	CodeHash string     `gorm:"size:255;not null"                             json:"-"`
+	UsedAt   *time.Time `gorm:"default:null"                                  json:"used_at"`
+	User     User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
@@ -2,9 +2,14 @@ package entity
 
 import "gorm.io/gorm"
 
+// Role is given to users, it grants them its permissions and the ones of its parents. The parents
+// may have parents of their own. System roles are created at boot, they can't be renamed or deleted.
 type Role struct {
 	gorm.Model
-	ID          uint   `gorm:"primary_key"`
-	Name        string `gorm:"size:50;not null;unique" json:"name"`
-	Description string `gorm:"size:255;not null"       json:"description"`
+	ID          uint         `gorm:"primary_key"`
+	Name        string       `gorm:"size:50;not null;unique"                                              json:"name"`
+	Description string       `gorm:"size:255;not null"                                                    json:"description"`
+	System      bool         `gorm:"not null;default:false"                                               json:"system"`
+	Permissions []Permission `gorm:"many2many:role_permissions;"                                          json:"permissions"`
+	Parents     []*Role      `gorm:"many2many:role_parents;joinForeignKey:RoleID;joinReferences:ParentID" json:"parents"`
 }
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ServiceAccount is a client such as a cron job which gets tokens for itself with the
+// client credentials grant instead of a user logging in
+type ServiceAccount struct {
+	gorm.Model
+	ClientID   string     `gorm:"size:255;not null;uniqueIndex" json:"client_id"`
+	SecretHash string     `gorm:"size:255;not null"             json:"-"`
+	Name       string     `gorm:"size:255;not null"             json:"name"`
+	Scopes     string     `gorm:"size:1024;not null;default:''" json:"scopes"`
+	TokenTTL   int        `gorm:"not null;default:0"            json:"token_ttl"`
+	LastUsedAt *time.Time `gorm:"default:null"                  json:"last_used_at"`
+}
+
+// ServiceAccountToken is what a valid token of a service account grants
+type ServiceAccountToken struct {
+	ClientID string
+	Scopes   []string
+}
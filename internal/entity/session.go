@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// Session is a login of a user on one device. It is kept in redis for as long as
+// its latest refresh token is valid, its id is the sid claim of the tokens.
+type Session struct {
+	ID         string    `json:"id"`
+	Username   string    `json:"username"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	RememberMe bool      `json:"remember_me"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// RefreshTokenRotation is the pair of tokens a refresh token was exchanged for. It is kept
+// under the jti of the refresh token, so a concurrent exchange of the same token gets the same pair.
+// AccessTokenJTI is the jti of the access token the refresh token was presented with.
+type RefreshTokenRotation struct {
+	AccessTokenJTI string    `json:"access_token_jti"`
+	AccessToken    string    `json:"access_token"`
+	RefreshToken   string    `json:"refresh_token"`
+	RotatedAt      time.Time `json:"rotated_at"`
+}
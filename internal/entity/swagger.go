@@ -42,7 +42,10 @@ type PathItem struct {
 
 // Operation represents an operation (e.g., GET, POST) for a path
 type Operation struct {
-	Security []interface{} `json:"security"`
+	Security    []interface{} `json:"security"`
+	RateLimit   *RateLimit    `json:"x-rate-limit,omitempty"`
+	Scopes      []string      `json:"x-scopes,omitempty"`
+	Permissions []string      `json:"x-permissions,omitempty"`
 }
 
 type SecurityScheme struct {
@@ -1,15 +1,48 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // User model
 type User struct {
 	gorm.Model
-	ID         uint   `gorm:"primary_key"                                   json:"id"`
-	RoleID     uint   `gorm:"not null;DEFAULT:3"                            json:"role_id"`
-	Username   string `gorm:"size:255;not null;unique"                      json:"username"`
-	Email      string `gorm:"size:255;not null;unique"                      json:"email"`
-	Password   string `gorm:"size:255;not null"                             json:"-"`
-	Role       Role   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
-	RememberMe bool
+	ID            uint       `gorm:"primary_key"                                   json:"id"`
+	RoleID        uint       `gorm:"not null;DEFAULT:3"                            json:"role_id"`
+	Username      string     `gorm:"size:255;not null;unique"                      json:"username"`
+	Email         string     `gorm:"size:255;not null;unique"                      json:"email"`
+	Password      string     `gorm:"size:255;not null"                             json:"-"`
+	Role          Role       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	EmailVerified bool       `gorm:"not null;default:false"                        json:"email_verified"`
+	VerifiedAt    *time.Time `gorm:"default:null"                                  json:"verified_at"`
+	TOTPSecret    string     `gorm:"size:255"                                      json:"-"`
+	TOTPEnabled   bool       `gorm:"not null;default:false"                        json:"totp_enabled"`
+	Disabled      bool       `gorm:"not null;default:false"                        json:"disabled"`
+	RememberMe    bool
+}
+
+const (
+	UserStatusActive   = "active"
+	UserStatusDisabled = "disabled"
+	UserStatusDeleted  = "deleted"
+)
+
+// UserQuery selects a page of users. The users are sorted by the Sort column, in descending order when it
+// starts with a -, then by their id. A page starts after the cursor when there is one. The deleted users
+// are only selected by their status, an empty status selects the others.
+type UserQuery struct {
+	Search string
+	Role   string
+	Status string
+	Sort   string
+	Limit  int
+	After  *UserCursor
+}
+
+// UserCursor is the last user of the page before, its value in the sort column and its id
+type UserCursor struct {
+	Value string
+	ID    uint
 }
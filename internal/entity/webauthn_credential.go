@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebAuthnCredential is a passkey a user registered to sign in without a password
+type WebAuthnCredential struct {
+	gorm.Model
+	UserID          uint       `gorm:"not null;index"                                json:"user_id"`
+	Name            string     `gorm:"size:255;not null"                             json:"name"`
+	CredentialID    []byte     `gorm:"not null;uniqueIndex"                          json:"-"`
+	PublicKey       []byte     `gorm:"not null"                                      json:"-"`
+	AttestationType string     `gorm:"size:255"                                      json:"-"`
+	Transports      string     `gorm:"size:255"                                      json:"transports"`
+	AAGUID          []byte     `gorm:"column:aaguid"                                 json:"-"`
+	SignCount       uint32     `gorm:"not null;default:0"                            json:"-"`
+	BackupEligible  bool       `gorm:"not null;default:false"                        json:"backup_eligible"`
+	BackupState     bool       `gorm:"not null;default:false"                        json:"backup_state"`
+	LastUsedAt      *time.Time `gorm:"default:null"                                  json:"last_used_at"`
+	User            User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
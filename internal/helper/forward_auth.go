@@ -0,0 +1,70 @@
+package helper
+
+import (
+	"net"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+)
+
+// MatchForwardAuthRule returns the first rule matching the host and path, or nil when none does.
+// The path is cleaned first, so /public/../admin isn't taken for a path below /public/.
+func MatchForwardAuthRule(rules []config.ForwardAuthRule, host string, requestPath string) *config.ForwardAuthRule {
+	host = normalizeHost(host)
+	requestPath = cleanPath(requestPath)
+
+	for i := range rules {
+		if matchesHost(rules[i].Host, host) && strings.HasPrefix(requestPath, rules[i].Path) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// IsForwardAuthHost tells if any rule covers the host, so the browser may be sent back to it after the login
+func IsForwardAuthHost(rules []config.ForwardAuthRule, host string) bool {
+	host = normalizeHost(host)
+
+	for _, rule := range rules {
+		if matchesHost(rule.Host, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsURLHost tells if the url is served on the host, the ports aren't compared
+func IsURLHost(rawURL string, host string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Host != "" && normalizeHost(u.Host) == normalizeHost(host)
+}
+
+// matchesHost compares the host of a rule, *.home.lan matches the hosts below home.lan but not home.lan itself
+func matchesHost(pattern string, host string) bool {
+	pattern = strings.ToLower(pattern)
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+	return pattern == host
+}
+
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+func cleanPath(p string) string {
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
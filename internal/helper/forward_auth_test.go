@@ -0,0 +1,57 @@
+package helper_test
+
+import (
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+var forwardAuthRules = []config.ForwardAuthRule{
+	{Host: "photos.home.lan", Path: "/share/", Policy: "bypass"},
+	{Host: "grafana.home.lan", Policy: "authenticated", Roles: []string{"admin"}},
+	{Host: "*.home.lan", Policy: "authenticated"},
+}
+
+func TestMatchForwardAuthRule(t *testing.T) {
+	cases := []struct {
+		name     string
+		host     string
+		path     string
+		expected int
+	}{
+		{name: "Path prefix", host: "photos.home.lan", path: "/share/album?key=1", expected: 0},
+		{name: "Host with port", host: "Grafana.Home.Lan:443", path: "/d/home", expected: 1},
+		{name: "Wildcard", host: "photos.home.lan", path: "/", expected: 2},
+		{name: "Traversal out of the prefix", host: "photos.home.lan", path: "/share/../admin", expected: 2},
+		{name: "No rule", host: "home.lan", path: "/", expected: -1},
+		{name: "Another domain", host: "photos.home.lan.evil.com", path: "/share/", expected: -1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := helper.MatchForwardAuthRule(forwardAuthRules, tc.host, tc.path)
+
+			if tc.expected < 0 {
+				assert.Nil(t, rule)
+				return
+			}
+			assert.Equal(t, &forwardAuthRules[tc.expected], rule)
+		})
+	}
+}
+
+func TestIsForwardAuthHost(t *testing.T) {
+	assert.True(t, helper.IsForwardAuthHost(forwardAuthRules, "wiki.home.lan"))
+	assert.True(t, helper.IsForwardAuthHost(forwardAuthRules, "grafana.home.lan:8443"))
+	assert.False(t, helper.IsForwardAuthHost(forwardAuthRules, "evil.com"))
+	assert.False(t, helper.IsForwardAuthHost(forwardAuthRules, "home.lan"))
+}
+
+func TestIsURLHost(t *testing.T) {
+	assert.True(t, helper.IsURLHost("https://photos.home.lan/albums", "Photos.Home.Lan:443"))
+	assert.True(t, helper.IsURLHost("http://photos.home.lan:8080", "photos.home.lan"))
+	assert.False(t, helper.IsURLHost("https://photos.home.lan", "home.lan"))
+	assert.False(t, helper.IsURLHost("/albums", "photos.home.lan"))
+}
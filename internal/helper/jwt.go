@@ -1,21 +1,39 @@
 package helper
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
 )
 
 const (
 	AccessTokenHeader  string = "Authorization"
 	RefreshTokenHeader string = "Refresh"
+	APIKeyHeader       string = "X-API-Key"
 )
 
+// Apps behind forward auth can't see the storage of the hub, their proxies pass the tokens on in these cookies.
+// In SSO mode they replace the storage of the hub as well.
+const (
+	AccessTokenCookie  string = "access_token"
+	RefreshTokenCookie string = "refresh_token"
+)
+
+var tokenCookies = map[string]string{
+	AccessTokenHeader:  AccessTokenCookie,
+	RefreshTokenHeader: RefreshTokenCookie,
+}
+
 func IsTokenExpired(err error) bool {
 	return err != nil && strings.Contains(err.Error(), "expired")
 }
 
-// Extract token from request headers
+// Extract token from request headers, or from the SSO cookies when the headers don't carry one.
+// Every page of the SSO domain sends the cookies along, so they are only taken from htmx requests,
+// whose HX-Request header other origins can't set.
 func ExtractHeaderToken(c *gin.Context, tokenType string) string {
 	headerToken := c.GetHeader(tokenType)
 	if headerToken != "" && strings.HasPrefix(headerToken, "Bearer ") {
@@ -24,9 +42,91 @@ func ExtractHeaderToken(c *gin.Context, tokenType string) string {
 			return parts[1]
 		}
 	}
+
+	cookieName, ok := tokenCookies[tokenType]
+	if ok && IsSSOEnabled(c) && c.GetHeader("HX-Request") != "" {
+		if cookieToken, err := c.Cookie(cookieName); err == nil {
+			return cookieToken
+		}
+	}
 	return ""
 }
 
+// IsSSOEnabled tells if the tokens are kept in cookies of the SSO domain instead of the storage of the browser
+func IsSSOEnabled(c *gin.Context) bool {
+	return ssoConfig(c).Domain != ""
+}
+
+// SetTokenCookies hands the tokens to the browser as cookies, shared with the subdomains in SSO mode.
+// They only last for the session unless the user asked to be remembered, like the storage the login page picks.
+func SetTokenCookies(c *gin.Context, tokens *dto.JwtTokens, rememberMe bool) {
+	maxAge := 0
+	if rememberMe {
+		maxAge = GetConfig(c).Authen.RefreshTokenTTL
+	}
+
+	domain, secure := tokenCookieScope(c)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(AccessTokenCookie, tokens.AccessToken, maxAge, "/", domain, secure, true)
+	c.SetCookie(RefreshTokenCookie, tokens.RefreshToken, maxAge, "/", domain, secure, true)
+}
+
+func deleteTokenCookies(c *gin.Context) {
+	domain, secure := tokenCookieScope(c)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(AccessTokenCookie, "", -1, "/", domain, secure, true)
+	c.SetCookie(RefreshTokenCookie, "", -1, "/", domain, secure, true)
+}
+
+// tokenCookieScope returns the domain and the secure flag of the token cookies. The cookies of
+// the SSO domain are always secure, the other ones only when the hub is served over https.
+func tokenCookieScope(c *gin.Context) (string, bool) {
+	if domain := ssoConfig(c).Domain; domain != "" {
+		return domain, true
+	}
+	return "", strings.HasPrefix(GetConfig(c).App.BaseURL, "https://")
+}
+
+func ssoConfig(c *gin.Context) config.SSO {
+	cfg, ok := c.Get("config")
+	if !ok {
+		return config.SSO{}
+	}
+	return cfg.(*config.Config).SSO
+}
+
+// SaveTokens hands new tokens to the browser, into its storage through the saveToken event or into the
+// SSO cookies. In SSO mode the storage is emptied instead, tokens left there would shadow the cookies.
+func SaveTokens(c *gin.Context, tokens *dto.JwtTokens, rememberMe bool) error {
+	saveTo := "session"
+	if rememberMe {
+		saveTo = "local"
+	}
+	events := map[string]interface{}{
+		"saveToken": map[string]interface{}{
+			"saveTo":       saveTo,
+			"accessToken":  tokens.AccessToken,
+			"refreshToken": tokens.RefreshToken,
+		},
+	}
+
+	if IsSSOEnabled(c) {
+		SetTokenCookies(c, tokens, rememberMe)
+		events = map[string]interface{}{
+			"deleteToken": map[string]interface{}{
+				"deleteFrom": "all",
+			},
+		}
+	}
+
+	HXTriggerEvents, err := MapToJSONString(events)
+	if err != nil {
+		return err
+	}
+	c.Header("HX-Trigger", HXTriggerEvents)
+	return nil
+}
+
 func DeleteTokens(c *gin.Context, rememberMe bool, deleteAll bool) {
 	deleteFrom := "session"
 	if rememberMe {
@@ -41,4 +141,8 @@ func DeleteTokens(c *gin.Context, rememberMe bool, deleteAll bool) {
 		},
 	})
 	c.Header("HX-Trigger", HXTriggerEvents)
+
+	if IsSSOEnabled(c) {
+		deleteTokenCookies(c)
+	}
 }
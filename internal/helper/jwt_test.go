@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
 	"github.com/minhmannh2001/authconnecthub/internal/helper"
 	"github.com/stretchr/testify/assert"
 )
@@ -96,3 +98,51 @@ func TestDeleteTokens(t *testing.T) {
 		assert.Equal(t, tc.expected, mockContext.Writer.Header().Get("HX-Trigger"))
 	}
 }
+
+func newSSOContext(recorder *httptest.ResponseRecorder) *gin.Context {
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/dashboard", nil)
+	c.Set("config", &config.Config{
+		App:    config.App{BaseURL: "https://hub.home.lan"},
+		Authen: config.Authen{RefreshTokenTTL: 3600},
+		SSO:    config.SSO{Domain: "home.lan"},
+	})
+	return c
+}
+
+func TestExtractHeaderToken_SSOCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c := newSSOContext(httptest.NewRecorder())
+	c.Request.AddCookie(&http.Cookie{Name: helper.AccessTokenCookie, Value: "cookie_token"})
+
+	// other origins can't add the HX-Request header, so their requests don't get the cookie accepted
+	assert.Equal(t, "", helper.ExtractHeaderToken(c, helper.AccessTokenHeader))
+
+	c.Request.Header.Set("HX-Request", "true")
+	assert.Equal(t, "cookie_token", helper.ExtractHeaderToken(c, helper.AccessTokenHeader))
+
+	c.Request.Header.Set(helper.AccessTokenHeader, "Bearer header_token")
+	assert.Equal(t, "header_token", helper.ExtractHeaderToken(c, helper.AccessTokenHeader))
+}
+
+func TestSaveTokens_SSO(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recorder := httptest.NewRecorder()
+	c := newSSOContext(recorder)
+
+	err := helper.SaveTokens(c, &dto.JwtTokens{AccessToken: "access", RefreshToken: "refresh"}, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"deleteToken":{"deleteFrom":"all"}}`, c.Writer.Header().Get("HX-Trigger"))
+	cookies := recorder.Result().Cookies()
+	assert.Len(t, cookies, 2)
+	for _, cookie := range cookies {
+		assert.Equal(t, "home.lan", cookie.Domain)
+		assert.Equal(t, 3600, cookie.MaxAge)
+		assert.True(t, cookie.Secure)
+		assert.True(t, cookie.HttpOnly)
+		assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+	}
+}
@@ -16,6 +16,11 @@ func GenerateValidationMessage(field string, rule string) (message string) {
 			message = "Passwords do not match. Please try again."
 		}
 		return message
+	case "nefield":
+		if field == "Password" {
+			message = "New password must be different from the current one."
+		}
+		return message
 	default:
 		return fmt.Sprintf("Field '%s' is not valid.", field)
 	}
@@ -22,6 +22,11 @@ func TestGenerateValidationMessage_Eqfield_ConfirmPasswordMismatch(t *testing.T)
 	assert.Equal(t, "Passwords do not match. Please try again.", message)
 }
 
+func TestGenerateValidationMessage_Nefield_PasswordUnchanged(t *testing.T) {
+	message := helper.GenerateValidationMessage("Password", "nefield")
+	assert.Equal(t, "New password must be different from the current one.", message)
+}
+
 func TestGenerateValidationMessage_Default(t *testing.T) {
 	message := helper.GenerateValidationMessage("Username", "unknown_rule")
 	assert.Equal(t, "Field 'Username' is not valid.", message)
@@ -78,6 +78,60 @@ func HasSecurityKeyForPathAndMethod(path string, method string, swaggerInfo *ent
 	return len(operation.Security) > 0, nil
 }
 
+// GetRateLimitForPathAndMethod returns the x-rate-limit extension of the operation, nil when it has none
+func GetRateLimitForPathAndMethod(path string, method string, swaggerInfo *entity.SwaggerInfo) *entity.RateLimit {
+	operation := findOperation(path, method, swaggerInfo)
+	if operation == nil {
+		return nil
+	}
+	return operation.RateLimit
+}
+
+// GetScopesForPathAndMethod returns the x-scopes extension of the operation, the scopes a service account
+// needs to use it. Service accounts can't use operations without any.
+func GetScopesForPathAndMethod(path string, method string, swaggerInfo *entity.SwaggerInfo) []string {
+	operation := findOperation(path, method, swaggerInfo)
+	if operation == nil {
+		return nil
+	}
+	return operation.Scopes
+}
+
+// GetPermissionsForPathAndMethod returns the x-permissions extension of the operation, the permissions the role
+// of a user needs to use it. Any logged in user may use operations without any.
+func GetPermissionsForPathAndMethod(path string, method string, swaggerInfo *entity.SwaggerInfo) []string {
+	operation := findOperation(path, method, swaggerInfo)
+	if operation == nil {
+		return nil
+	}
+	return operation.Permissions
+}
+
+func findOperation(path string, method string, swaggerInfo *entity.SwaggerInfo) *entity.Operation {
+	if path == "" || method == "" || swaggerInfo == nil {
+		return nil
+	}
+
+	pathItem, ok := swaggerInfo.Paths[path]
+	if !ok {
+		return nil
+	}
+
+	switch method {
+	case http.MethodGet:
+		return pathItem.Get
+	case http.MethodPost:
+		return pathItem.Post
+	case http.MethodPut:
+		return pathItem.Put
+	case http.MethodDelete:
+		return pathItem.Delete
+	case http.MethodPatch:
+		return pathItem.Patch
+	}
+	return nil
+}
+
 func readSwaggerFile(filePath string) (*entity.SwaggerInfo, error) {
 	// Read the file content
 	data, err := os.ReadFile(filePath)
@@ -1,10 +1,12 @@
 package helper_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/minhmannh2001/authconnecthub/internal/entity"
@@ -176,6 +178,103 @@ func TestHasSecurityKeyForPathAndMethod(t *testing.T) {
 	}
 }
 
+func TestGetRateLimitForPathAndMethod(t *testing.T) {
+	var swaggerInfo entity.SwaggerInfo
+	err := json.Unmarshal([]byte(`{"paths": {"/users": {
+		"get": {},
+		"post": {"x-rate-limit": {"limit": 5, "period": 60, "burst": 10, "key": "user"}}
+	}}}`), &swaggerInfo)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		path     string
+		method   string
+		expected *entity.RateLimit
+	}{
+		{
+			name:     "Operation with a rate limit",
+			path:     "/users",
+			method:   http.MethodPost,
+			expected: &entity.RateLimit{Limit: 5, Period: 60, Burst: 10, Key: "user"},
+		},
+		{
+			name:     "Operation without a rate limit",
+			path:     "/users",
+			method:   http.MethodGet,
+			expected: nil,
+		},
+		{
+			name:     "Method doesn't exist",
+			path:     "/users",
+			method:   http.MethodDelete,
+			expected: nil,
+		},
+		{
+			name:     "Path doesn't exist",
+			path:     "/nonexistent",
+			method:   http.MethodPost,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, helper.GetRateLimitForPathAndMethod(tc.path, tc.method, &swaggerInfo))
+		})
+	}
+
+	assert.Nil(t, helper.GetRateLimitForPathAndMethod("/users", http.MethodPost, nil))
+}
+
+func TestGetScopesForPathAndMethod(t *testing.T) {
+	var swaggerInfo entity.SwaggerInfo
+	err := json.Unmarshal([]byte(`{"paths": {"/users": {
+		"get": {"x-scopes": ["users:read"]},
+		"post": {}
+	}}}`), &swaggerInfo)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"users:read"}, helper.GetScopesForPathAndMethod("/users", http.MethodGet, &swaggerInfo))
+	assert.Nil(t, helper.GetScopesForPathAndMethod("/users", http.MethodPost, &swaggerInfo))
+	assert.Nil(t, helper.GetScopesForPathAndMethod("/users", http.MethodDelete, &swaggerInfo))
+	assert.Nil(t, helper.GetScopesForPathAndMethod("/nonexistent", http.MethodGet, &swaggerInfo))
+	assert.Nil(t, helper.GetScopesForPathAndMethod("/users", http.MethodGet, nil))
+}
+
+func TestGetPermissionsForPathAndMethod(t *testing.T) {
+	var swaggerInfo entity.SwaggerInfo
+	err := json.Unmarshal([]byte(`{"paths": {"/users": {
+		"get": {"x-permissions": ["users:read"]},
+		"post": {}
+	}}}`), &swaggerInfo)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"users:read"}, helper.GetPermissionsForPathAndMethod("/users", http.MethodGet, &swaggerInfo))
+	assert.Nil(t, helper.GetPermissionsForPathAndMethod("/users", http.MethodPost, &swaggerInfo))
+	assert.Nil(t, helper.GetPermissionsForPathAndMethod("/nonexistent", http.MethodGet, &swaggerInfo))
+	assert.Nil(t, helper.GetPermissionsForPathAndMethod("/users", http.MethodGet, nil))
+}
+
+// the admin routes are guarded by their permissions only, one without any would be open to every user
+func TestAdminRoutesDeclarePermissions(t *testing.T) {
+	data, err := os.ReadFile("../../docs/swagger.json")
+	assert.NoError(t, err)
+	var swaggerInfo entity.SwaggerInfo
+	assert.NoError(t, json.Unmarshal(data, &swaggerInfo))
+
+	for path := range swaggerInfo.Paths {
+		if !strings.HasPrefix(path, "/v1/admin/") && !strings.HasPrefix(path, "/v2/admin/") {
+			continue
+		}
+		for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch} {
+			if helper.IsPathMethodInSwagger(path, method, &swaggerInfo) {
+				assert.NotEmpty(t, helper.GetPermissionsForPathAndMethod(path, method, &swaggerInfo), "%s %s", method, path)
+			}
+		}
+	}
+}
+
 func TestGetSwaggerInfo_Success(t *testing.T) {
 	fileContents := []byte(`{"paths": {"/users": {"get": {}}}}`)
 	expectedInfo := &entity.SwaggerInfo{Paths: map[string]entity.PathItem{"/users": {Get: &entity.Operation{}}}}
@@ -1,12 +1,16 @@
 package middlewares
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
 	"github.com/minhmannh2001/authconnecthub/internal/helper"
 	"github.com/minhmannh2001/authconnecthub/internal/usecases"
 )
@@ -19,7 +23,7 @@ func IsHtmxRequest(c *gin.Context) {
 		return
 	}
 
-	if !helper.IsPathMethodInSwagger(c.Request.URL.Path, c.Request.Method, swaggerInfo) {
+	if !helper.IsPathMethodInSwagger(c.Request.URL.Path, c.Request.Method, swaggerInfo) || isAPIRequest(c.Request.URL.Path) || isForwardAuthRequest(c.Request.URL.Path) {
 		c.Next()
 		return
 	}
@@ -70,7 +74,10 @@ func IsAuthorized(auth usecases.IAuthUC) gin.HandlerFunc {
 			accessToken := helper.ExtractHeaderToken(c, helper.AccessTokenHeader)
 
 			if accessToken != "" {
-				username, _ := auth.ValidateToken(accessToken)
+				username, err := auth.ValidateToken(accessToken)
+				if err == nil {
+					touchSession(c, auth, accessToken)
+				}
 
 				c.Set("username", username)
 				c.Next()
@@ -92,33 +99,133 @@ func IsAuthorized(auth usecases.IAuthUC) gin.HandlerFunc {
 		isInBlackList, _ := auth.IsTokenBlacklisted(accessToken)
 
 		if isInBlackList {
+			// revoked tokens of service accounts have no remember_me claim
 			rememberMe, _ := auth.RetrieveFieldFromJwtToken(accessToken, "remember_me", false)
 			toastMessage := "your-token-is-invalid.-please-log-in-to-continue."
-			helper.DeleteTokens(c, rememberMe.(bool), false)
+			helper.DeleteTokens(c, rememberMe == true, false)
 			redirectToLogin(c, toastMessage)
 			return
 		}
 
 		username, err := auth.ValidateToken(accessToken)
 		if err != nil {
+			if isServiceAccountToken(auth, accessToken) {
+				authorizeServiceAccount(c, auth, accessToken, swaggerInfo)
+				return
+			}
 			if helper.IsTokenExpired(err) && c.Request.URL.Path == "/v1/auth/logout" {
 				c.Next()
 				return
 			}
 			log.Printf("Internal error: %v\n", err)
 			rememberMe, _ := auth.RetrieveFieldFromJwtToken(accessToken, "remember_me", true)
-			helper.DeleteTokens(c, rememberMe.(bool), false)
+			helper.DeleteTokens(c, rememberMe == true, false)
 			toastMessage := "your-session-has-expired.-please-log-in-to-continue."
 			redirectToLogin(c, toastMessage)
 			return
 		}
 
+		permissions := helper.GetPermissionsForPathAndMethod(c.Request.URL.Path, c.Request.Method, swaggerInfo)
+		if !hasPermissions(auth, accessToken, permissions) {
+			forbid(c, "You are not allowed to do this.")
+			return
+		}
+
+		touchSession(c, auth, accessToken)
 		c.Set("username", username)
 		c.Next()
 	}
 }
 
+// hasPermissions tells if the permissions claim of the validated token holds all the permissions
+// in the x-permissions extension of the route. The claim is set from the role of the user when the
+// token is issued, so a changed role applies from the next refresh on.
+func hasPermissions(auth usecases.IAuthUC, accessToken string, permissions []string) bool {
+	if len(permissions) == 0 {
+		return true
+	}
+
+	claim, err := auth.RetrieveFieldFromJwtToken(accessToken, "permissions", false)
+	if err != nil {
+		return false
+	}
+	granted, _ := claim.([]interface{})
+
+	return !slices.ContainsFunc(permissions, func(permission string) bool {
+		return !slices.Contains(granted, interface{}(permission))
+	})
+}
+
+// forbid rejects a logged in user who may not use the route
+func forbid(c *gin.Context, message string) {
+	if isAPIRequest(c.Request.URL.Path) {
+		c.AbortWithStatusJSON(http.StatusForbidden, dto.Response{
+			Success: false,
+			Message: message,
+		})
+		return
+	}
+
+	// only the toast is updated, the content of the page stays as it is
+	c.Header("HX-Reswap", "none")
+	c.HTML(http.StatusForbidden, "toast-section", gin.H{
+		"hidden":  false,
+		"type":    dto.ToastTypeDanger,
+		"message": message,
+	})
+	c.Abort()
+}
+
+// authorizeServiceAccount lets a service account through when its token holds all the scopes in the x-scopes
+// extension of the route. Routes without the extension are for users only.
+func authorizeServiceAccount(c *gin.Context, auth usecases.IAuthUC, accessToken string, swaggerInfo *entity.SwaggerInfo) {
+	serviceAccount, err := auth.ValidateServiceToken(accessToken)
+	if err != nil {
+		log.Printf("Invalid service account token: %v\n", err)
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{Success: false, Message: "The token is invalid or has expired."})
+		return
+	}
+
+	scopes := helper.GetScopesForPathAndMethod(c.Request.URL.Path, c.Request.Method, swaggerInfo)
+	missingScope := slices.ContainsFunc(scopes, func(scope string) bool {
+		return !slices.Contains(serviceAccount.Scopes, scope)
+	})
+	if len(scopes) == 0 || missingScope {
+		c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, strings.Join(scopes, " ")))
+		c.AbortWithStatusJSON(http.StatusForbidden, dto.Response{Success: false, Message: "The token doesn't grant access to this endpoint."})
+		return
+	}
+
+	c.Set("service_account", serviceAccount.ClientID)
+	c.Next()
+}
+
+// isServiceAccountToken tells the tokens of service accounts from the ones of users. The token isn't
+// verified, so it may only be used to pick how to check the token.
+func isServiceAccountToken(auth usecases.IAuthUC, accessToken string) bool {
+	clientID, err := auth.RetrieveFieldFromJwtToken(accessToken, "client_id", false)
+	return err == nil && clientID != nil
+}
+
+// touchSession records where the session is used from. It is informative only,
+// so a failure doesn't stop the request.
+func touchSession(c *gin.Context, auth usecases.IAuthUC, accessToken string) {
+	if err := auth.TouchSession(accessToken, c.Request.UserAgent(), c.ClientIP()); err != nil {
+		log.Printf("Failed to touch session: %v\n", err)
+	}
+}
+
 func redirectToLogin(c *gin.Context, message string) {
+	// JSON clients can't follow the HX-Redirect, so tell them why they were rejected instead
+	if isAPIRequest(c.Request.URL.Path) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.Response{
+			Success: false,
+			Message: helper.FormatToastMessage(message),
+		})
+		return
+	}
+
 	toastData := map[string]interface{}{
 		"toast-message": message,
 		"toast-type":    dto.ToastTypeDanger,
@@ -137,11 +244,23 @@ func redirectToLogin(c *gin.Context, message string) {
 
 func IsLoggedIn(auth usecases.IAuthUC) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// applications send the tokens the hub issued to them, which aren't login sessions of the hub
+		// nor are the requests reverse proxies ask about, the endpoint checks their tokens itself
+		if isOIDCClientRequest(c.Request.URL.Path) || isForwardAuthRequest(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
 		accessToken := helper.ExtractHeaderToken(c, helper.AccessTokenHeader)
 
 		// Validate token if present
 		if accessToken != "" {
 			if _, err := auth.ValidateToken(accessToken); err != nil {
+				// service accounts have no session to refresh, IsAuthorized checks their tokens
+				if isServiceAccountToken(auth, accessToken) {
+					c.Next()
+					return
+				}
 				if helper.IsTokenExpired(err) {
 					refreshToken := helper.ExtractHeaderToken(c, helper.RefreshTokenHeader)
 					// check if token is in blacklist or not, if it is then return to login page with error notification
@@ -150,13 +269,17 @@ func IsLoggedIn(auth usecases.IAuthUC) gin.HandlerFunc {
 					if isInBlackList {
 						rememberMe, _ := auth.RetrieveFieldFromJwtToken(accessToken, "remember_me", false)
 						toastMessage := "your-token-is-invalid.-please-log-in-to-continue."
-						helper.DeleteTokens(c, rememberMe.(bool), false)
+						helper.DeleteTokens(c, rememberMe == true, false)
 						redirectToLogin(c, toastMessage)
 						return
 					}
 
 					newAccessToken, newRerefreshToken, err := auth.CheckAndRefreshTokens(accessToken, refreshToken, helper.GetConfig(c))
 					if err != nil {
+						var reusedErr *entity.RefreshTokenReusedError
+						if errors.As(err, &reusedErr) {
+							log.Printf("Refresh token reuse detected for user %s, revoked token family %s\n", reusedErr.Username, reusedErr.Family)
+						}
 						goto SESSION_EXPIRE
 					}
 					// don't change the tokens in header, just keep old tokens
@@ -166,23 +289,10 @@ func IsLoggedIn(auth usecases.IAuthUC) gin.HandlerFunc {
 						return
 					}
 					rememberMe, _ := auth.RetrieveFieldFromJwtToken(newAccessToken, "remember_me", true)
-					var saveTo string
-					if rememberMe.(bool) {
-						saveTo = "local"
-					} else {
-						saveTo = "session"
-					}
-					HXTriggerEvents, err := helper.MapToJSONString(map[string]interface{}{
-						"saveToken": map[string]interface{}{
-							"saveTo":       saveTo,
-							"accessToken":  newAccessToken,
-							"refreshToken": newRerefreshToken,
-						},
-					})
+					err = helper.SaveTokens(c, &dto.JwtTokens{AccessToken: newAccessToken, RefreshToken: newRerefreshToken}, rememberMe == true)
 					if err != nil {
 						goto SESSION_EXPIRE
 					}
-					c.Header("HX-Trigger", HXTriggerEvents)
 					modifyAuthorizationHeaders(c, newAccessToken, newRerefreshToken)
 					goto CONTINUE
 				}
@@ -194,7 +304,7 @@ func IsLoggedIn(auth usecases.IAuthUC) gin.HandlerFunc {
 					helper.HandleInternalError(c, err)
 					return
 				}
-				helper.DeleteTokens(c, rememberMe.(bool), false)
+				helper.DeleteTokens(c, rememberMe == true, false)
 				toastMessage := "your-session-has-expired.-please-log-in-to-continue."
 				redirectToLogin(c, toastMessage)
 				return
@@ -213,6 +323,21 @@ func IsLoggedIn(auth usecases.IAuthUC) gin.HandlerFunc {
 	}
 }
 
+// Determines if a path belongs to the JSON API, the OAuth endpoints or the well-known documents instead of the htmx pages
+func isAPIRequest(path string) bool {
+	return strings.HasPrefix(path, "/v2/") || strings.HasPrefix(path, "/.well-known/") || strings.HasPrefix(path, "/oauth/")
+}
+
+// Determines if a path is an OpenID Connect endpoint called by applications instead of browsers
+func isOIDCClientRequest(path string) bool {
+	return strings.HasPrefix(path, "/v2/oidc/")
+}
+
+// Determines if a path is the endpoint reverse proxies ask whether a request may pass
+func isForwardAuthRequest(path string) bool {
+	return path == "/v1/auth/verify"
+}
+
 // Determines if a path should be redirected to the home page
 func shouldRedirectToHome(path string) bool {
 	return path == "/v1/auth/login" || path == "/v1/auth/register"
@@ -3,6 +3,7 @@ package middlewares_test
 import (
 	"errors"
 	"fmt"
+	"html/template"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -218,6 +219,7 @@ func TestIsAuthorized_PublicRouteNoToken(t *testing.T) {
 func TestIsAuthorized_PublicRouteWithToken(t *testing.T) {
 	mockAuth := mocks.NewIAuthUC(t)
 	mockAuth.On("ValidateToken", mock.Anything).Return("minhmannh2001", nil)
+	mockAuth.On("TouchSession", "accessToken", mock.Anything, mock.Anything).Return(nil)
 
 	gin.SetMode(gin.TestMode)
 	_, engine := gin.CreateTestContext(httptest.NewRecorder())
@@ -334,6 +336,41 @@ func TestIsAuthorized_PrivateRouteMissingToken(t *testing.T) {
 	}
 }
 
+func TestIsAuthorized_APIRouteMissingToken(t *testing.T) {
+	mockAuth := mocks.NewIAuthUC(t)
+
+	gin.SetMode(gin.TestMode)
+	_, engine := gin.CreateTestContext(httptest.NewRecorder())
+	mockConfig := &config.Config{
+		App: config.App{SwaggerPath: "test/swagger.yaml"},
+	}
+	engine.Use(func(c *gin.Context) {
+		c.Set("config", mockConfig)
+		c.Next()
+	})
+	engine.Use(middlewares.IsAuthorized(mockAuth))
+	mockSwaggerInfo := &entity.SwaggerInfo{Paths: map[string]entity.PathItem{"/v2/users": {Get: &entity.Operation{Security: []interface{}{map[string]interface{}{"JWT": nil}}}}}}
+	patch, err := mpatch.PatchMethod(helper.GetSwaggerInfo, func(filePath string) (*entity.SwaggerInfo, error) {
+		return mockSwaggerInfo, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v2/users", nil)
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, w.Result().Header.Get("HX-Redirect"))
+	assert.JSONEq(t, `{"success":false,"message":"Login is required for this action. Sign in or create an account to continue."}`, w.Body.String())
+
+	err = patch.Unpatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestIsAuthorized_PrivateRouteBlacklistedToken(t *testing.T) {
 	mockAuth := mocks.NewIAuthUC(t)
 	mockAuth.On("IsTokenBlacklisted", mock.Anything).Return(true, nil)
@@ -388,6 +425,7 @@ func TestIsAuthorized_PrivateRouteValidToken(t *testing.T) {
 	mockAuth := mocks.NewIAuthUC(t)
 	mockAuth.On("IsTokenBlacklisted", mock.Anything).Return(false, nil)
 	mockAuth.On("ValidateToken", mock.Anything).Return("minhmannh2001", nil)
+	mockAuth.On("TouchSession", "accessToken", mock.Anything, mock.Anything).Return(nil)
 
 	gin.SetMode(gin.TestMode)
 	_, engine := gin.CreateTestContext(httptest.NewRecorder())
@@ -431,6 +469,7 @@ func TestIsAuthorized_PrivateRouteExpiredTokenLogout(t *testing.T) {
 	mockAuth := mocks.NewIAuthUC(t)
 	mockAuth.On("IsTokenBlacklisted", mock.Anything).Return(false, nil)
 	mockAuth.On("ValidateToken", mock.Anything).Return("", errors.New("token expired"))
+	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, "client_id", false).Return(nil, errors.New("missing 'client_id' claim in token"))
 
 	gin.SetMode(gin.TestMode)
 	_, engine := gin.CreateTestContext(httptest.NewRecorder())
@@ -471,6 +510,7 @@ func TestIsAuthorized_PrivateRouteExpiredToken(t *testing.T) {
 	mockAuth := mocks.NewIAuthUC(t)
 	mockAuth.On("IsTokenBlacklisted", mock.Anything).Return(false, nil)
 	mockAuth.On("ValidateToken", mock.Anything).Return("", errors.New("token expired"))
+	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, "client_id", false).Return(nil, errors.New("missing 'client_id' claim in token"))
 	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, mock.Anything, mock.Anything).Return(interface{}(true), nil)
 
 	gin.SetMode(gin.TestMode)
@@ -516,6 +556,180 @@ func TestIsAuthorized_PrivateRouteExpiredToken(t *testing.T) {
 	}
 }
 
+func TestIsAuthorized_ServiceAccount(t *testing.T) {
+	jwt := []interface{}{map[string]interface{}{"JWT": nil}}
+	mockSwaggerInfo := &entity.SwaggerInfo{Paths: map[string]entity.PathItem{
+		"/v2/admin/login-locks": {
+			Get:  &entity.Operation{Security: jwt, Scopes: []string{"login-locks:read"}},
+			Post: &entity.Operation{Security: jwt, Scopes: []string{"login-locks:write"}},
+		},
+		"/v2/auth/password": {Post: &entity.Operation{Security: jwt}},
+	}}
+	patchGetSwaggerInfo, err := mpatch.PatchMethod(helper.GetSwaggerInfo, func(filePath string) (*entity.SwaggerInfo, error) {
+		return mockSwaggerInfo, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = patchGetSwaggerInfo.Unpatch()
+	}()
+
+	cases := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{name: "Token holds the scope of the route", method: http.MethodGet, path: "/v2/admin/login-locks", expectedStatus: http.StatusOK},
+		{name: "Token lacks the scope of the route", method: http.MethodPost, path: "/v2/admin/login-locks", expectedStatus: http.StatusForbidden},
+		{name: "Route is for users only", method: http.MethodPost, path: "/v2/auth/password", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAuth := mocks.NewIAuthUC(t)
+			mockAuth.On("IsTokenBlacklisted", "serviceToken").Return(false, nil)
+			mockAuth.On("ValidateToken", "serviceToken").Return("", errors.New("token isn't meant for the hub"))
+			mockAuth.On("RetrieveFieldFromJwtToken", "serviceToken", "client_id", false).Return(interface{}("svc_backup"), nil)
+			mockAuth.On("ValidateServiceToken", "serviceToken").Return(&entity.ServiceAccountToken{ClientID: "svc_backup", Scopes: []string{"login-locks:read"}}, nil)
+
+			gin.SetMode(gin.TestMode)
+			_, engine := gin.CreateTestContext(httptest.NewRecorder())
+			engine.Use(func(c *gin.Context) {
+				c.Set("config", &config.Config{App: config.App{SwaggerPath: "test/swagger.yaml"}})
+				c.Next()
+			})
+			engine.Use(middlewares.IsAuthorized(mockAuth))
+			engine.Handle(tc.method, tc.path, func(c *gin.Context) {
+				assert.Equal(t, "svc_backup", c.GetString("service_account"))
+				assert.Empty(t, c.GetString("username"))
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", "serviceToken"))
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, w.Header().Get("WWW-Authenticate"), `error="insufficient_scope"`)
+			}
+		})
+	}
+}
+
+func TestIsAuthorized_ServiceAccountInvalidToken(t *testing.T) {
+	mockAuth := mocks.NewIAuthUC(t)
+	mockAuth.On("IsTokenBlacklisted", "serviceToken").Return(false, nil)
+	mockAuth.On("ValidateToken", "serviceToken").Return("", errors.New("token has invalid claims: token is expired"))
+	mockAuth.On("RetrieveFieldFromJwtToken", "serviceToken", "client_id", false).Return(interface{}("svc_backup"), nil)
+	mockAuth.On("ValidateServiceToken", "serviceToken").Return(nil, errors.New("token has invalid claims: token is expired"))
+
+	gin.SetMode(gin.TestMode)
+	_, engine := gin.CreateTestContext(httptest.NewRecorder())
+	engine.Use(func(c *gin.Context) {
+		c.Set("config", &config.Config{App: config.App{SwaggerPath: "test/swagger.yaml"}})
+		c.Next()
+	})
+	engine.Use(middlewares.IsAuthorized(mockAuth))
+	engine.GET("/v2/admin/login-locks", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	mockSwaggerInfo := &entity.SwaggerInfo{Paths: map[string]entity.PathItem{"/v2/admin/login-locks": {Get: &entity.Operation{
+		Security: []interface{}{map[string]interface{}{"JWT": nil}},
+		Scopes:   []string{"login-locks:read"},
+	}}}}
+	patchGetSwaggerInfo, err := mpatch.PatchMethod(helper.GetSwaggerInfo, func(filePath string) (*entity.SwaggerInfo, error) {
+		return mockSwaggerInfo, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = patchGetSwaggerInfo.Unpatch()
+	}()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v2/admin/login-locks", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", "serviceToken"))
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Bearer error="invalid_token"`, w.Header().Get("WWW-Authenticate"))
+}
+
+func TestIsAuthorized_Permissions(t *testing.T) {
+	jwt := []interface{}{map[string]interface{}{"JWT": nil}}
+	mockSwaggerInfo := &entity.SwaggerInfo{Paths: map[string]entity.PathItem{
+		"/v2/admin/applications": {
+			Get:  &entity.Operation{Security: jwt, Permissions: []string{"applications:read"}},
+			Post: &entity.Operation{Security: jwt, Permissions: []string{"applications:write"}},
+		},
+		"/v1/admin/grants":  {Get: &entity.Operation{Security: jwt, Permissions: []string{"application-grants:read"}}},
+		"/v2/auth/password": {Post: &entity.Operation{Security: jwt}},
+	}}
+	patchGetSwaggerInfo, err := mpatch.PatchMethod(helper.GetSwaggerInfo, func(filePath string) (*entity.SwaggerInfo, error) {
+		return mockSwaggerInfo, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = patchGetSwaggerInfo.Unpatch()
+	}()
+
+	cases := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedReswap string
+	}{
+		{name: "Token holds the permission of the route", method: http.MethodGet, path: "/v2/admin/applications", expectedStatus: http.StatusOK},
+		{name: "Route asks for no permission", method: http.MethodPost, path: "/v2/auth/password", expectedStatus: http.StatusOK},
+		{name: "Token lacks the permission of the route", method: http.MethodPost, path: "/v2/admin/applications", expectedStatus: http.StatusForbidden, expectedBody: `{"success":false,"message":"You are not allowed to do this."}`},
+		{name: "Section only shows a toast", method: http.MethodGet, path: "/v1/admin/grants", expectedStatus: http.StatusForbidden, expectedBody: "danger: You are not allowed to do this.", expectedReswap: "none"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAuth := mocks.NewIAuthUC(t)
+			mockAuth.On("IsTokenBlacklisted", "accessToken").Return(false, nil)
+			mockAuth.On("ValidateToken", "accessToken").Return("minhmannh2001", nil)
+			mockAuth.On("RetrieveFieldFromJwtToken", "accessToken", "permissions", false).Return(interface{}([]interface{}{"applications:read"}), nil).Maybe()
+			mockAuth.On("TouchSession", "accessToken", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			gin.SetMode(gin.TestMode)
+			_, engine := gin.CreateTestContext(httptest.NewRecorder())
+			engine.SetHTMLTemplate(template.Must(template.New("").Parse(`{{ define "toast-section" }}{{ .type }}: {{ .message }}{{ end }}`)))
+			engine.Use(func(c *gin.Context) {
+				c.Set("config", &config.Config{App: config.App{SwaggerPath: "test/swagger.yaml"}})
+				c.Next()
+			})
+			engine.Use(middlewares.IsAuthorized(mockAuth))
+			engine.Handle(tc.method, tc.path, func(c *gin.Context) {
+				assert.Equal(t, "minhmannh2001", c.GetString("username"))
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", "accessToken"))
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, w.Body.String())
+			}
+			assert.Equal(t, tc.expectedReswap, w.Header().Get("HX-Reswap"))
+		})
+	}
+}
+
 func TestIsLoggedIn_NotLoggedIn(t *testing.T) {
 	mockAuth := mocks.NewIAuthUC(t)
 
@@ -533,6 +747,48 @@ func TestIsLoggedIn_NotLoggedIn(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestIsLoggedIn_OIDCClientRequest(t *testing.T) {
+	// the access tokens of applications aren't checked as login sessions
+	mockAuth := mocks.NewIAuthUC(t)
+
+	gin.SetMode(gin.TestMode)
+	_, engine := gin.CreateTestContext(httptest.NewRecorder())
+	engine.Use(middlewares.IsLoggedIn(mockAuth))
+	engine.GET("/v2/oidc/userinfo", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v2/oidc/userinfo", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", "opaqueAccessToken"))
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("HX-Trigger"))
+}
+
+func TestIsLoggedIn_ServiceAccountToken(t *testing.T) {
+	// service accounts have no session to refresh, even once their token has expired
+	mockAuth := mocks.NewIAuthUC(t)
+	mockAuth.On("ValidateToken", "serviceToken").Return("", errors.New("token has invalid claims: token is expired"))
+	mockAuth.On("RetrieveFieldFromJwtToken", "serviceToken", "client_id", false).Return(interface{}("svc_backup"), nil)
+
+	gin.SetMode(gin.TestMode)
+	_, engine := gin.CreateTestContext(httptest.NewRecorder())
+	engine.Use(middlewares.IsLoggedIn(mockAuth))
+	engine.GET("/v2/admin/login-locks", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v2/admin/login-locks", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", "serviceToken"))
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("HX-Trigger"))
+}
+
 func TestIsLoggedIn_ValidToken(t *testing.T) {
 	mockAuth := mocks.NewIAuthUC(t)
 	mockAuth.On("ValidateToken", mock.Anything).Return("username", nil)
@@ -575,6 +831,7 @@ func TestIsLoggedIn_LoggedInRedirectHome(t *testing.T) {
 func TestIsLoggedIn_ExpiredTokenUnrefreshable(t *testing.T) {
 	mockAuth := mocks.NewIAuthUC(t)
 	mockAuth.On("ValidateToken", mock.Anything).Return("", errors.New("token expired"))
+	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, "client_id", false).Return(nil, errors.New("missing 'client_id' claim in token"))
 	mockAuth.On("IsTokenBlacklisted", mock.Anything).Return(true, nil)
 	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, mock.Anything, mock.Anything).Return(interface{}(true), nil)
 
@@ -612,6 +869,7 @@ func TestIsLoggedIn_ExpiredTokenUnrefreshable(t *testing.T) {
 func TestIsLoggedIn_CheckAndRefreshTokensFail(t *testing.T) {
 	mockAuth := mocks.NewIAuthUC(t)
 	mockAuth.On("ValidateToken", mock.Anything).Return("", errors.New("token expired"))
+	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, "client_id", false).Return(nil, errors.New("missing 'client_id' claim in token"))
 	mockAuth.On("IsTokenBlacklisted", mock.Anything).Return(false, nil)
 	mockAuth.On("CheckAndRefreshTokens", mock.Anything, mock.Anything, mock.Anything).Return("", "", errors.New("failed"))
 	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, mock.Anything, mock.Anything).Return(interface{}(true), nil)
@@ -657,6 +915,7 @@ func TestIsLoggedIn_CheckAndRefreshTokensFail(t *testing.T) {
 func TestIsLoggedIn_ExpiredTokenRefreshable(t *testing.T) {
 	mockAuth := mocks.NewIAuthUC(t)
 	mockAuth.On("ValidateToken", mock.Anything).Return("", errors.New("token expired"))
+	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, "client_id", false).Return(nil, errors.New("missing 'client_id' claim in token"))
 	mockAuth.On("IsTokenBlacklisted", mock.Anything).Return(false, nil)
 	mockAuth.On("CheckAndRefreshTokens", mock.Anything, mock.Anything, mock.Anything).Return("newAccessToken", "newRerefreshToken", nil)
 	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, mock.Anything, mock.Anything).Return(interface{}(true), nil)
@@ -694,6 +953,7 @@ func TestIsLoggedIn_ExpiredTokenRefreshable(t *testing.T) {
 func TestIsLoggedIn_ExpiredTokenRefreshable_RedirectToHome(t *testing.T) {
 	mockAuth := mocks.NewIAuthUC(t)
 	mockAuth.On("ValidateToken", mock.Anything).Return("", errors.New("token expired"))
+	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, "client_id", false).Return(nil, errors.New("missing 'client_id' claim in token"))
 	mockAuth.On("IsTokenBlacklisted", mock.Anything).Return(false, nil)
 	mockAuth.On("CheckAndRefreshTokens", mock.Anything, mock.Anything, mock.Anything).Return("newAccessToken", "newRerefreshToken", nil)
 	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, mock.Anything, mock.Anything).Return(interface{}(true), nil)
@@ -724,6 +984,7 @@ func TestIsLoggedIn_ExpiredTokenRefreshable_RedirectToHome(t *testing.T) {
 func TestIsLoggedIn_DeleteTokensFromAllPlaces(t *testing.T) {
 	mockAuth := mocks.NewIAuthUC(t)
 	mockAuth.On("ValidateToken", mock.Anything).Return("", errors.New("token expired"))
+	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, "client_id", false).Return(nil, errors.New("missing 'client_id' claim in token"))
 	mockAuth.On("IsTokenBlacklisted", mock.Anything).Return(false, nil)
 	mockAuth.On("CheckAndRefreshTokens", mock.Anything, mock.Anything, mock.Anything).Return("newAccessToken", "newRerefreshToken", nil)
 	mockAuth.On("RetrieveFieldFromJwtToken", mock.Anything, mock.Anything, mock.Anything).Return(interface{}(true), nil).Once()
@@ -0,0 +1,114 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+)
+
+// RateLimit throttles the operations declaring an x-rate-limit extension in the swagger file.
+// It must run after IsAuthorized, so limits per user know who is logged in.
+func RateLimit(rateLimiter usecases.IRateLimitUC) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := helper.GetConfig(c)
+		swaggerInfo, err := helper.GetSwaggerInfo(cfg.App.SwaggerPath)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "error loading swagger information"})
+			return
+		}
+
+		limit := helper.GetRateLimitForPathAndMethod(c.Request.URL.Path, c.Request.Method, swaggerInfo)
+		if limit == nil {
+			c.Next()
+			return
+		}
+
+		limitRate(c, rateLimiter, *limit)
+	}
+}
+
+// RouteRateLimit throttles a single route, for routes which aren't documented in the swagger file
+func RouteRateLimit(rateLimiter usecases.IRateLimitUC, limit entity.RateLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limitRate(c, rateLimiter, limit)
+	}
+}
+
+func limitRate(c *gin.Context, rateLimiter usecases.IRateLimitUC, limit entity.RateLimit) {
+	key := fmt.Sprintf("%s:%s:%s", c.Request.Method, c.Request.URL.Path, rateLimitClient(c, limit))
+	result, err := rateLimiter.Allow(key, limit)
+	if err != nil {
+		// throttling protects the app, it must not take it down when redis is unavailable
+		log.Printf("Failed to check rate limit: %v\n", err)
+		c.Next()
+		return
+	}
+
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+	if result.Allowed {
+		c.Next()
+		return
+	}
+
+	c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+	message := "Too many requests. Please slow down and try again later."
+
+	if isAPIRequest(c.Request.URL.Path) {
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.Response{
+			Success: false,
+			Message: message,
+		})
+		return
+	}
+
+	if c.GetHeader("HX-Request") == "true" {
+		// only the toast is updated, the content of the page stays as it is
+		c.Header("HX-Reswap", "none")
+		c.HTML(http.StatusTooManyRequests, "toast-section", gin.H{
+			"hidden":  false,
+			"type":    dto.ToastTypeWarning,
+			"message": message,
+		})
+		c.Abort()
+		return
+	}
+
+	c.AbortWithStatus(http.StatusTooManyRequests)
+}
+
+// rateLimitClient identifies who the request is counted for. Service accounts count as users,
+// anonymous requests to routes limited per user or api key are counted per ip.
+func rateLimitClient(c *gin.Context, limit entity.RateLimit) string {
+	switch limit.Key {
+	case entity.RateLimitKeyUser:
+		if username := c.GetString("username"); username != "" {
+			return "user:" + username
+		}
+		if clientID := c.GetString("service_account"); clientID != "" {
+			return "service_account:" + clientID
+		}
+	case entity.RateLimitKeyAPIKey:
+		if apiKey := c.GetHeader(helper.APIKeyHeader); apiKey != "" {
+			return fmt.Sprintf("api_key:%x", sha256.Sum256([]byte(apiKey)))
+		}
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
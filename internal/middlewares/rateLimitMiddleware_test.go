@@ -0,0 +1,161 @@
+package middlewares_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/middlewares"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/undefinedlabs/go-mpatch"
+)
+
+func newRateLimitEngine(t *testing.T, path string, username string, rateLimiter *mocks.IRateLimitUC) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	_, engine := gin.CreateTestContext(httptest.NewRecorder())
+	engine.SetHTMLTemplate(template.Must(template.New("").Parse(`{{ define "toast-section" }}{{ .type }}: {{ .message }}{{ end }}`)))
+	engine.Use(func(c *gin.Context) {
+		c.Set("config", &config.Config{App: config.App{SwaggerPath: "test/swagger.yaml"}})
+		if username != "" {
+			c.Set("username", username)
+		}
+		c.Next()
+	})
+	engine.Use(middlewares.RateLimit(rateLimiter))
+	engine.POST(path, func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	mockSwaggerInfo := &entity.SwaggerInfo{Paths: map[string]entity.PathItem{
+		"/v2/limited": {Post: &entity.Operation{RateLimit: &entity.RateLimit{Limit: 5, Period: 60, Key: entity.RateLimitKeyUser}}},
+		"/limited":    {Post: &entity.Operation{RateLimit: &entity.RateLimit{Limit: 5, Period: 60}}},
+		"/unlimited":  {Post: &entity.Operation{}},
+	}}
+	patch, err := mpatch.PatchMethod(helper.GetSwaggerInfo, func(filePath string) (*entity.SwaggerInfo, error) {
+		return mockSwaggerInfo, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := patch.Unpatch(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	return engine
+}
+
+func TestRateLimit_Allowed(t *testing.T) {
+	rateLimiter := mocks.NewIRateLimitUC(t)
+	rateLimiter.On("Allow", "POST:/v2/limited:user:minhmannh2001", entity.RateLimit{Limit: 5, Period: 60, Key: entity.RateLimitKeyUser}).
+		Return(&entity.RateLimitResult{Allowed: true, Limit: 5, Remaining: 4, Reset: 12 * time.Second}, nil)
+
+	engine := newRateLimitEngine(t, "/v2/limited", "minhmannh2001", rateLimiter)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v2/limited", nil)
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "5", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "4", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "12", w.Header().Get("RateLimit-Reset"))
+	assert.Empty(t, w.Header().Get("Retry-After"))
+}
+
+func TestRateLimit_APIRequestRejected(t *testing.T) {
+	rateLimiter := mocks.NewIRateLimitUC(t)
+	rateLimiter.On("Allow", mock.Anything, mock.Anything).
+		Return(&entity.RateLimitResult{Allowed: false, Limit: 5, Remaining: 0, Reset: time.Minute, RetryAfter: 11500 * time.Millisecond}, nil)
+
+	engine := newRateLimitEngine(t, "/v2/limited", "minhmannh2001", rateLimiter)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v2/limited", nil)
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "12", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"success": false, "message": "Too many requests. Please slow down and try again later."}`, w.Body.String())
+}
+
+func TestRateLimit_HtmxRequestRejected(t *testing.T) {
+	rateLimiter := mocks.NewIRateLimitUC(t)
+	// anonymous requests are counted per ip
+	rateLimiter.On("Allow", "POST:/limited:ip:203.0.113.7", mock.Anything).
+		Return(&entity.RateLimitResult{Allowed: false, Limit: 5, RetryAfter: time.Second}, nil)
+
+	engine := newRateLimitEngine(t, "/limited", "", rateLimiter)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/limited", nil)
+	req.RemoteAddr = "203.0.113.7:54321"
+	req.Header.Set("HX-Request", "true")
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "none", w.Header().Get("HX-Reswap"))
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Equal(t, "warning: Too many requests. Please slow down and try again later.", w.Body.String())
+}
+
+func TestRateLimit_NoLimitDeclared(t *testing.T) {
+	rateLimiter := mocks.NewIRateLimitUC(t)
+
+	engine := newRateLimitEngine(t, "/unlimited", "", rateLimiter)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/unlimited", nil)
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimit_StoreUnavailable(t *testing.T) {
+	rateLimiter := mocks.NewIRateLimitUC(t)
+	rateLimiter.On("Allow", mock.Anything, mock.Anything).Return(nil, errors.New("redis error"))
+
+	engine := newRateLimitEngine(t, "/limited", "", rateLimiter)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/limited", nil)
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRouteRateLimit_APIKey(t *testing.T) {
+	limit := entity.RateLimit{Limit: 100, Period: 60, Key: entity.RateLimitKeyAPIKey}
+
+	rateLimiter := mocks.NewIRateLimitUC(t)
+	// the api key itself isn't stored, only its hash
+	rateLimiter.On("Allow", fmt.Sprintf("GET:/keys:api_key:%x", sha256.Sum256([]byte("secret-key"))), limit).Return(&entity.RateLimitResult{Allowed: true, Limit: 100, Remaining: 99}, nil)
+
+	gin.SetMode(gin.TestMode)
+	_, engine := gin.CreateTestContext(httptest.NewRecorder())
+	engine.GET("/keys", middlewares.RouteRateLimit(rateLimiter, limit), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/keys", nil)
+	req.Header.Set(helper.APIKeyHeader, "secret-key")
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "99", w.Header().Get("RateLimit-Remaining"))
+}
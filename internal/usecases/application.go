@@ -0,0 +1,252 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var clientIDPattern = regexp.MustCompile(`^[a-z0-9._-]+$`)
+
+// reservedClientIDs are the audiences of the tokens of the hub. ID tokens name the client as their
+// audience, an application with one of these ids would get ID tokens passing for hub tokens.
+var reservedClientIDs = []string{hubAudience, serviceAudience}
+
+// oidcClient is an application which logs its users in with the hub, registered in the application
+// registry or listed in the config. The application id is 0 for the clients of the config.
+type oidcClient struct {
+	applicationID  uint
+	id             string
+	secret         string
+	secretHash     string
+	redirectURIs   []string
+	allowedRoles   []string
+	accessTokenTTL int
+	idTokenTTL     int
+}
+
+func (c *oidcClient) isPublic() bool {
+	return c.secret == "" && c.secretHash == ""
+}
+
+// authenticate checks the secret of confidential clients, public clients only name themselves
+func (c *oidcClient) authenticate(secret string) bool {
+	switch {
+	case c.secretHash != "":
+		return bcrypt.CompareHashAndPassword([]byte(c.secretHash), []byte(secret)) == nil
+	case c.secret != "":
+		return subtle.ConstantTimeCompare([]byte(c.secret), []byte(secret)) == 1
+	}
+	return true
+}
+
+// CreateApplication registers an application. Confidential applications get a random secret which
+// is returned once, only its hash is kept.
+func (au *AuthUseCase) CreateApplication(req dto.ApplicationCreateRequestBody, cfg *config.Config) (*dto.ApplicationCredentials, error) {
+	clientID := req.ClientID
+	if clientID == "" {
+		var err error
+		if clientID, err = generateApplicationClientID(); err != nil {
+			return nil, err
+		}
+	} else if !clientIDPattern.MatchString(clientID) || slices.Contains(reservedClientIDs, clientID) {
+		return nil, &entity.InvalidClientIDError{ClientID: clientID}
+	}
+
+	redirectURIs, err := parseRedirectURIs(req.RedirectURIs)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := au.findOIDCClient(clientID, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, &entity.ApplicationConflictError{ClientID: clientID}
+	}
+
+	var secret, secretHash string
+	if !req.Public {
+		if secret, err = generateRandomToken(); err != nil {
+			return nil, err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash client secret: %w", err)
+		}
+		secretHash = string(hash)
+	}
+
+	application, err := au.applicationRepo.CreateApplication(entity.Application{
+		ClientID:       clientID,
+		SecretHash:     secretHash,
+		Name:           req.Name,
+		URL:            req.URL,
+		Icon:           req.Icon,
+		RedirectURIs:   redirectURIs,
+		AllowedRoles:   strings.Join(uniqueFields(req.AllowedRoles), " "),
+		AccessTokenTTL: req.AccessTokenTTL,
+		IDTokenTTL:     req.IDTokenTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ApplicationCredentials{
+		ClientID:     application.ClientID,
+		ClientSecret: secret,
+		Name:         application.Name,
+	}, nil
+}
+
+func (au *AuthUseCase) ListApplications() ([]entity.Application, error) {
+	return au.applicationRepo.FindApplications()
+}
+
+// UpdateApplication replaces the settings of an application, its client id and secret stay
+func (au *AuthUseCase) UpdateApplication(req dto.ApplicationUpdateRequestBody) (*entity.Application, error) {
+	application, err := au.applicationRepo.FindApplicationByClientID(req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if application == nil {
+		return nil, &entity.ApplicationNotFoundError{}
+	}
+
+	redirectURIs, err := parseRedirectURIs(req.RedirectURIs)
+	if err != nil {
+		return nil, err
+	}
+
+	application.Name = req.Name
+	application.URL = req.URL
+	application.Icon = req.Icon
+	application.RedirectURIs = redirectURIs
+	application.AllowedRoles = strings.Join(uniqueFields(req.AllowedRoles), " ")
+	application.AccessTokenTTL = req.AccessTokenTTL
+	application.IDTokenTTL = req.IDTokenTTL
+
+	updated, err := au.applicationRepo.UpdateApplication(*application)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteApplication removes the application, it can't log its users in anymore
+func (au *AuthUseCase) DeleteApplication(clientID string) error {
+	deleted, err := au.applicationRepo.DeleteApplication(clientID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &entity.ApplicationNotFoundError{}
+	}
+
+	return nil
+}
+
+// ListLauncherApplications returns the applications the user may open
+func (au *AuthUseCase) ListLauncherApplications(username string) ([]entity.Application, error) {
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return nil, err
+	}
+
+	applications, err := au.applicationRepo.FindApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := au.applicationRepo.FindApplicationGrants()
+	if err != nil {
+		return nil, err
+	}
+	grantsByApplication := map[uint][]entity.ApplicationGrant{}
+	for _, grant := range grants {
+		grantsByApplication[grant.ApplicationID] = append(grantsByApplication[grant.ApplicationID], grant)
+	}
+
+	return slices.DeleteFunc(applications, func(application entity.Application) bool {
+		_, ok := applicationAccess(strings.Fields(application.AllowedRoles), grantsByApplication[application.ID], user)
+		return !ok
+	}), nil
+}
+
+// findOIDCClient looks the client up in the config first, then in the application registry.
+// It returns nil when neither knows the client id.
+func (au *AuthUseCase) findOIDCClient(clientID string, cfg *config.Config) (*oidcClient, error) {
+	// clients of the config or registered before the ids were reserved don't exist as far as the hub is concerned
+	if slices.Contains(reservedClientIDs, clientID) {
+		return nil, nil
+	}
+
+	for _, client := range cfg.OIDC.Clients {
+		if client.ID == clientID {
+			return &oidcClient{id: client.ID, secret: client.Secret, redirectURIs: client.RedirectURIs}, nil
+		}
+	}
+
+	application, err := au.applicationRepo.FindApplicationByClientID(clientID)
+	if err != nil || application == nil {
+		return nil, err
+	}
+
+	return &oidcClient{
+		applicationID:  application.ID,
+		id:             application.ClientID,
+		secretHash:     application.SecretHash,
+		redirectURIs:   strings.Fields(application.RedirectURIs),
+		allowedRoles:   strings.Fields(application.AllowedRoles),
+		accessTokenTTL: application.AccessTokenTTL,
+		idTokenTTL:     application.IDTokenTTL,
+	}, nil
+}
+
+// roleAllowed tells if a user with the role may open an application, any role will do when none are listed
+func roleAllowed(allowedRoles []string, role string) bool {
+	return len(allowedRoles) == 0 || slices.Contains(allowedRoles, role)
+}
+
+// parseRedirectURIs checks that the space separated redirect uris are absolute http or https urls
+// without a fragment, see RFC 6749 section 3.1.2
+func parseRedirectURIs(redirectURIs string) (string, error) {
+	uris := uniqueFields(redirectURIs)
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.Fragment != "" {
+			return "", &entity.InvalidRedirectURIError{URI: uri}
+		}
+	}
+
+	return strings.Join(uris, " "), nil
+}
+
+func uniqueFields(s string) []string {
+	var fields []string
+	for _, field := range strings.Fields(s) {
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func generateApplicationClientID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate client id: %w", err)
+	}
+	return "app_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,152 @@
+package usecases
+
+import (
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// appRoleRanks orders the roles of an application, a higher one includes the lower ones
+var appRoleRanks = map[string]int{
+	entity.AppRoleViewer: 1,
+	entity.AppRoleEditor: 2,
+	entity.AppRoleAdmin:  3,
+}
+
+// GrantApplicationAccess gives a user, or the users with a role, a role within the application.
+// A subject granted already gets the new role instead.
+func (au *AuthUseCase) GrantApplicationAccess(req dto.ApplicationGrantRequestBody) (*entity.ApplicationGrant, error) {
+	application, err := au.applicationRepo.FindApplicationByClientID(req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if application == nil {
+		return nil, &entity.ApplicationNotFoundError{}
+	}
+
+	subject := req.Subject
+	if req.SubjectType == entity.GrantSubjectUser {
+		user, err := au.userUseCase.FindByUsernameOrEmail(req.Subject, "")
+		if err != nil {
+			if helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+				return nil, &entity.GrantSubjectNotFoundError{Subject: req.Subject}
+			}
+			return nil, err
+		}
+		// users may be named by their email, the grant keeps the username
+		subject = user.Username
+	}
+
+	grant, err := au.applicationRepo.SaveApplicationGrant(entity.ApplicationGrant{
+		ApplicationID: application.ID,
+		SubjectType:   req.SubjectType,
+		Subject:       subject,
+		AppRole:       req.AppRole,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &grant, nil
+}
+
+// RevokeApplicationAccess takes the grant of the subject away, the application is closed to it
+// unless another grant covers it
+func (au *AuthUseCase) RevokeApplicationAccess(req dto.ApplicationGrantDeleteRequestBody) error {
+	application, err := au.applicationRepo.FindApplicationByClientID(req.ClientID)
+	if err != nil {
+		return err
+	}
+	if application == nil {
+		return &entity.ApplicationNotFoundError{}
+	}
+
+	deleted, err := au.applicationRepo.DeleteApplicationGrant(application.ID, req.SubjectType, req.Subject)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &entity.ApplicationGrantNotFoundError{}
+	}
+
+	return nil
+}
+
+// ApplicationGrantMatrix returns the role each granted subject has within each application
+func (au *AuthUseCase) ApplicationGrantMatrix() (*dto.ApplicationGrantMatrix, error) {
+	applications, err := au.applicationRepo.FindApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := au.applicationRepo.FindApplicationGrants()
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &dto.ApplicationGrantMatrix{
+		Applications: []dto.ApplicationGrantColumn{},
+		Rows:         []dto.ApplicationGrantRow{},
+	}
+	columns := map[uint]int{}
+	for i, application := range applications {
+		columns[application.ID] = i
+		matrix.Applications = append(matrix.Applications, dto.ApplicationGrantColumn{ClientID: application.ClientID, Name: application.Name})
+	}
+
+	// the grants come sorted by subject, so the ones of a subject follow each other
+	for _, grant := range grants {
+		column, ok := columns[grant.ApplicationID]
+		if !ok {
+			continue
+		}
+
+		last := len(matrix.Rows) - 1
+		if last < 0 || matrix.Rows[last].SubjectType != grant.SubjectType || matrix.Rows[last].Subject != grant.Subject {
+			matrix.Rows = append(matrix.Rows, dto.ApplicationGrantRow{
+				SubjectType: grant.SubjectType,
+				Subject:     grant.Subject,
+				AppRoles:    make([]string, len(applications)),
+			})
+			last++
+		}
+		matrix.Rows[last].AppRoles[column] = grant.AppRole
+	}
+
+	return matrix, nil
+}
+
+// clientAccess tells if the user may use the client and with which role of the application.
+// The clients of the config are open to every user and give no role.
+func (au *AuthUseCase) clientAccess(client *oidcClient, user *entity.User) (string, bool, error) {
+	if client.applicationID == 0 {
+		return "", true, nil
+	}
+
+	grants, err := au.applicationRepo.FindApplicationGrantsByApplicationID(client.applicationID)
+	if err != nil {
+		return "", false, err
+	}
+
+	appRole, ok := applicationAccess(client.allowedRoles, grants, user)
+	return appRole, ok, nil
+}
+
+// applicationAccess tells if the user may use an application and with which of its roles, the highest
+// one among the grants covering the user. Applications without grants fall back to their allowed roles
+// and give no role.
+func applicationAccess(allowedRoles []string, grants []entity.ApplicationGrant, user *entity.User) (string, bool) {
+	if len(grants) == 0 {
+		return "", roleAllowed(allowedRoles, user.Role.Name)
+	}
+
+	appRole := ""
+	for _, grant := range grants {
+		covered := (grant.SubjectType == entity.GrantSubjectUser && grant.Subject == user.Username) ||
+			(grant.SubjectType == entity.GrantSubjectRole && grant.Subject == user.Role.Name)
+		if covered && appRoleRanks[grant.AppRole] > appRoleRanks[appRole] {
+			appRole = grant.AppRole
+		}
+	}
+	return appRole, appRole != ""
+}
@@ -0,0 +1,97 @@
+package usecases_test
+
+import (
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestAuthUseCase_GrantApplicationAccess(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockApplicationRepo.On("FindApplicationByClientID", "photos").Return(newApplication(t, 1, "photos", "", ""), nil)
+	mockApplicationRepo.On("SaveApplicationGrant", entity.ApplicationGrant{
+		ApplicationID: 1,
+		SubjectType:   entity.GrantSubjectUser,
+		Subject:       "testuser",
+		AppRole:       entity.AppRoleEditor,
+	}).Return(func(grant entity.ApplicationGrant) (entity.ApplicationGrant, error) {
+		return grant, nil
+	})
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser@home.lan", "").Return(&entity.User{Username: "testuser"}, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, mockUserUC, nil, newOIDCConfig(t))
+
+	// the grant keeps the username of users named by their email
+	grant, err := uc.GrantApplicationAccess(dto.ApplicationGrantRequestBody{
+		ClientID:    "photos",
+		SubjectType: entity.GrantSubjectUser,
+		Subject:     "testuser@home.lan",
+		AppRole:     entity.AppRoleEditor,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "testuser", grant.Subject)
+}
+
+func TestAuthUseCase_GrantApplicationAccess_Rejected(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockApplicationRepo.On("FindApplicationByClientID", "photos").Return(newApplication(t, 1, "photos", "", ""), nil)
+	mockApplicationRepo.On("FindApplicationByClientID", "unknown").Return(nil, nil)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "nobody", "").Return(nil, &entity.InvalidCredentialsError{})
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, mockUserUC, nil, newOIDCConfig(t))
+
+	_, err := uc.GrantApplicationAccess(dto.ApplicationGrantRequestBody{ClientID: "unknown", SubjectType: entity.GrantSubjectRole, Subject: "customer", AppRole: entity.AppRoleViewer})
+	assert.Equal(t, &entity.ApplicationNotFoundError{}, err)
+
+	_, err = uc.GrantApplicationAccess(dto.ApplicationGrantRequestBody{ClientID: "photos", SubjectType: entity.GrantSubjectUser, Subject: "nobody", AppRole: entity.AppRoleViewer})
+	assert.Equal(t, &entity.GrantSubjectNotFoundError{Subject: "nobody"}, err)
+	mockApplicationRepo.AssertNotCalled(t, "SaveApplicationGrant", mock.Anything)
+}
+
+func TestAuthUseCase_RevokeApplicationAccess_NotFound(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockApplicationRepo.On("FindApplicationByClientID", "photos").Return(newApplication(t, 1, "photos", "", ""), nil)
+	mockApplicationRepo.On("DeleteApplicationGrant", uint(1), entity.GrantSubjectRole, "customer").Return(false, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, nil, nil, newOIDCConfig(t))
+
+	err := uc.RevokeApplicationAccess(dto.ApplicationGrantDeleteRequestBody{ClientID: "photos", SubjectType: entity.GrantSubjectRole, Subject: "customer"})
+
+	assert.Equal(t, &entity.ApplicationGrantNotFoundError{}, err)
+}
+
+func TestAuthUseCase_ApplicationGrantMatrix(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockApplicationRepo.On("FindApplications").Return([]entity.Application{
+		{Model: gorm.Model{ID: 1}, ClientID: "grafana", Name: "Grafana"},
+		{Model: gorm.Model{ID: 2}, ClientID: "photos", Name: "Photos"},
+	}, nil)
+	mockApplicationRepo.On("FindApplicationGrants").Return([]entity.ApplicationGrant{
+		{ApplicationID: 2, SubjectType: entity.GrantSubjectRole, Subject: "customer", AppRole: entity.AppRoleViewer},
+		{ApplicationID: 1, SubjectType: entity.GrantSubjectUser, Subject: "testuser", AppRole: entity.AppRoleAdmin},
+		{ApplicationID: 2, SubjectType: entity.GrantSubjectUser, Subject: "testuser", AppRole: entity.AppRoleEditor},
+	}, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, nil, nil, newOIDCConfig(t))
+
+	matrix, err := uc.ApplicationGrantMatrix()
+
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.ApplicationGrantMatrix{
+		Applications: []dto.ApplicationGrantColumn{{ClientID: "grafana", Name: "Grafana"}, {ClientID: "photos", Name: "Photos"}},
+		Rows: []dto.ApplicationGrantRow{
+			{SubjectType: entity.GrantSubjectRole, Subject: "customer", AppRoles: []string{"", entity.AppRoleViewer}},
+			{SubjectType: entity.GrantSubjectUser, Subject: "testuser", AppRoles: []string{entity.AppRoleAdmin, entity.AppRoleEditor}},
+		},
+	}, matrix)
+}
@@ -0,0 +1,239 @@
+package usecases_test
+
+import (
+	"crypto/rsa"
+	"net/url"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func newApplication(t *testing.T, id uint, clientID string, secret string, allowedRoles string) *entity.Application {
+	var secretHash string
+	if secret != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.MinCost)
+		assert.NoError(t, err)
+		secretHash = string(hash)
+	}
+
+	return &entity.Application{
+		Model:        gorm.Model{ID: id},
+		ClientID:     clientID,
+		SecretHash:   secretHash,
+		Name:         clientID,
+		URL:          "https://" + clientID + ".home.lan",
+		RedirectURIs: "https://" + clientID + ".home.lan/callback",
+		AllowedRoles: allowedRoles,
+	}
+}
+
+func TestAuthUseCase_CreateApplication(t *testing.T) {
+	cfg := newOIDCConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	var created entity.Application
+	mockApplicationRepo.On("FindApplicationByClientID", "photos").Return(nil, nil)
+	mockApplicationRepo.On("CreateApplication", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(0).(entity.Application)
+	}).Return(func(application entity.Application) (entity.Application, error) {
+		return application, nil
+	})
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, nil, nil, cfg)
+
+	credentials, err := uc.CreateApplication(dto.ApplicationCreateRequestBody{
+		ClientID:     "photos",
+		Name:         "Photos",
+		URL:          "https://photos.home.lan",
+		RedirectURIs: "https://photos.home.lan/callback https://photos.home.lan/callback",
+		AllowedRoles: "admin customer admin",
+	}, cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "photos", credentials.ClientID)
+	assert.NotEmpty(t, credentials.ClientSecret)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(created.SecretHash), []byte(credentials.ClientSecret)))
+	assert.Equal(t, "https://photos.home.lan/callback", created.RedirectURIs)
+	assert.Equal(t, "admin customer", created.AllowedRoles)
+}
+
+func TestAuthUseCase_CreateApplication_Public(t *testing.T) {
+	cfg := newOIDCConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockApplicationRepo.On("FindApplicationByClientID", mock.Anything).Return(nil, nil)
+	mockApplicationRepo.On("CreateApplication", mock.Anything).Return(func(application entity.Application) (entity.Application, error) {
+		return application, nil
+	})
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, nil, nil, cfg)
+
+	credentials, err := uc.CreateApplication(dto.ApplicationCreateRequestBody{Name: "Notes", URL: "https://notes.home.lan", Public: true}, cfg)
+
+	assert.NoError(t, err)
+	assert.Regexp(t, `^app_[0-9a-f]{16}$`, credentials.ClientID)
+	assert.Empty(t, credentials.ClientSecret)
+}
+
+func TestAuthUseCase_CreateApplication_Rejected(t *testing.T) {
+	cfg := newOIDCConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, cfg)
+
+	// the clients of the config keep their ids
+	_, err := uc.CreateApplication(dto.ApplicationCreateRequestBody{ClientID: "grafana", Name: "Grafana", URL: "https://grafana.home"}, cfg)
+	assert.Equal(t, &entity.ApplicationConflictError{ClientID: "grafana"}, err)
+
+	_, err = uc.CreateApplication(dto.ApplicationCreateRequestBody{ClientID: "Photos!", Name: "Photos", URL: "https://photos.home.lan"}, cfg)
+	assert.Equal(t, &entity.InvalidClientIDError{ClientID: "Photos!"}, err)
+
+	// the audiences of the hub tokens
+	for _, clientID := range []string{"users", "services"} {
+		_, err = uc.CreateApplication(dto.ApplicationCreateRequestBody{ClientID: clientID, Name: "Sneaky", URL: "https://sneaky.home.lan"}, cfg)
+		assert.Equal(t, &entity.InvalidClientIDError{ClientID: clientID}, err)
+	}
+
+	for _, redirectURI := range []string{"/callback", "javascript:alert(1)", "https://photos.home.lan/callback#token"} {
+		_, err = uc.CreateApplication(dto.ApplicationCreateRequestBody{ClientID: "photos", Name: "Photos", URL: "https://photos.home.lan", RedirectURIs: redirectURI}, cfg)
+		assert.Equal(t, &entity.InvalidRedirectURIError{URI: redirectURI}, err)
+	}
+	mockAuthRepo.AssertNotCalled(t, "CreateApplication", mock.Anything)
+}
+
+func TestAuthUseCase_DeleteApplication_NotFound(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockApplicationRepo.On("DeleteApplication", "photos").Return(false, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, nil, nil, newOIDCConfig(t))
+
+	err := uc.DeleteApplication("photos")
+
+	assert.Equal(t, &entity.ApplicationNotFoundError{}, err)
+}
+
+func TestAuthUseCase_ListLauncherApplications(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockApplicationRepo.On("FindApplications").Return([]entity.Application{
+		*newApplication(t, 1, "grafana", "", "admin"),
+		*newApplication(t, 2, "photos", "", "admin customer"),
+		*newApplication(t, 3, "wiki", "", ""),
+		*newApplication(t, 4, "backup", "", ""),
+		*newApplication(t, 5, "notes", "", "admin"),
+	}, nil)
+	// the grants take over from the allowed roles
+	mockApplicationRepo.On("FindApplicationGrants").Return([]entity.ApplicationGrant{
+		{ApplicationID: 4, SubjectType: entity.GrantSubjectUser, Subject: "someone", AppRole: entity.AppRoleAdmin},
+		{ApplicationID: 5, SubjectType: entity.GrantSubjectRole, Subject: "customer", AppRole: entity.AppRoleViewer},
+	}, nil)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser", Role: entity.Role{Name: "customer"}}, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, mockUserUC, nil, newOIDCConfig(t))
+
+	applications, err := uc.ListLauncherApplications("testuser")
+
+	assert.NoError(t, err)
+	var clientIDs []string
+	for _, application := range applications {
+		clientIDs = append(clientIDs, application.ClientID)
+	}
+	assert.Equal(t, []string{"photos", "wiki", "notes"}, clientIDs)
+}
+
+func TestAuthUseCase_Authorize_ApplicationAccessDenied(t *testing.T) {
+	cfg := newOIDCConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockApplicationRepo.On("FindApplicationByClientID", "photos").Return(newApplication(t, 1, "photos", "secret", "admin"), nil)
+	mockApplicationRepo.On("FindApplicationGrantsByApplicationID", uint(1)).Return(nil, nil)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser", Role: entity.Role{Name: "customer"}}, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, mockUserUC, nil, cfg)
+
+	redirectURL, err := uc.Authorize(dto.AuthorizeRequest{
+		ResponseType: "code",
+		ClientID:     "photos",
+		RedirectURI:  "https://photos.home.lan/callback",
+		Scope:        "openid",
+		State:        "xyz",
+	}, "testuser", cfg)
+
+	assert.NoError(t, err)
+	parsed, err := url.Parse(redirectURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "access_denied", parsed.Query().Get("error"))
+	assert.Equal(t, "xyz", parsed.Query().Get("state"))
+	mockAuthRepo.AssertNotCalled(t, "SaveAuthorizationCode", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_ExchangeAuthorizationCode_Application(t *testing.T) {
+	cfg := newOIDCConfig(t)
+	application := newApplication(t, 1, "photos", "photos-secret", "")
+	application.AccessTokenTTL = 600
+	application.IDTokenTTL = 300
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockApplicationRepo.On("FindApplicationByClientID", "photos").Return(application, nil)
+	mockApplicationRepo.On("FindApplicationGrantsByApplicationID", uint(1)).Return([]entity.ApplicationGrant{
+		{ApplicationID: 1, SubjectType: entity.GrantSubjectRole, Subject: "customer", AppRole: entity.AppRoleViewer},
+		{ApplicationID: 1, SubjectType: entity.GrantSubjectUser, Subject: "testuser", AppRole: entity.AppRoleEditor},
+	}, nil)
+	authorizationCode := &entity.AuthorizationCode{
+		ClientID:    "photos",
+		RedirectURI: "https://photos.home.lan/callback",
+		Username:    "testuser",
+		Scope:       "openid",
+	}
+	mockAuthRepo.On("FindAuthorizationCode", "the-code").Return(authorizationCode, nil)
+	mockAuthRepo.On("ConsumeAuthorizationCode", "the-code").Return(authorizationCode, nil)
+	mockAuthRepo.On("SaveOIDCAccessToken", mock.Anything, entity.OIDCAccessToken{ClientID: "photos", Username: "testuser", Scope: "openid"}, 600).Return(nil)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser", Role: entity.Role{Name: "customer"}}, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, mockUserUC, nil, cfg)
+
+	_, err := uc.ExchangeAuthorizationCode(dto.TokenRequestBody{
+		GrantType:    "authorization_code",
+		Code:         "the-code",
+		RedirectURI:  "https://photos.home.lan/callback",
+		ClientID:     "photos",
+		ClientSecret: "wrong",
+	}, cfg)
+	var oauthErr *entity.OAuthError
+	assert.ErrorAs(t, err, &oauthErr)
+	assert.Equal(t, entity.OAuthErrorInvalidClient, oauthErr.Code)
+
+	tokens, err := uc.ExchangeAuthorizationCode(dto.TokenRequestBody{
+		GrantType:    "authorization_code",
+		Code:         "the-code",
+		RedirectURI:  "https://photos.home.lan/callback",
+		ClientID:     "photos",
+		ClientSecret: "photos-secret",
+	}, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, 600, tokens.ExpiresIn)
+
+	// the registry is the source of the audience
+	privateKey := cfg.Authen.JwtPrivateKey.(*rsa.PrivateKey)
+	idToken, err := jwt.Parse(tokens.IDToken, func(token *jwt.Token) (interface{}, error) {
+		return &privateKey.PublicKey, nil
+	}, jwt.WithAudience("photos"))
+	assert.NoError(t, err)
+	claims := idToken.Claims.(jwt.MapClaims)
+	assert.InDelta(t, claims["iat"].(float64)+300, claims["exp"].(float64), 1)
+	// the highest role among the grants covering the user
+	assert.Equal(t, entity.AppRoleEditor, claims["app_role"])
+
+	// the ID token doesn't pass for a token of the hub
+	_, err = uc.ValidateToken(tokens.IDToken)
+	assert.EqualError(t, err, "token isn't meant for the hub")
+	_, err = uc.ValidateServiceToken(tokens.IDToken)
+	assert.EqualError(t, err, "token isn't meant for a service account")
+}
@@ -1,9 +1,13 @@
 package usecases
 
 import (
-	"crypto/rsa"
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,50 +18,208 @@ import (
 	"github.com/minhmannh2001/authconnecthub/internal/entity"
 	"github.com/minhmannh2001/authconnecthub/internal/helper"
 	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+	"github.com/minhmannh2001/authconnecthub/pkg/keyring"
+	"github.com/minhmannh2001/authconnecthub/pkg/mailer"
+	"github.com/minhmannh2001/authconnecthub/pkg/oidcclient"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// hubAudience is the audience of the tokens the hub issues for itself
+	hubAudience               = "users"
+	emailVerificationAudience = "email-verification"
+
+	// the token_use claim tells the tokens signed with the hub keys apart, whatever their audience
+	tokenUseAccess  = "access"
+	tokenUseRefresh = "refresh"
+	tokenUseService = "service"
+	tokenUseID      = "id"
+
+	// refreshTokenReuseGrace is how long after an exchange a request which raced with it still gets the
+	// pair the refresh token was exchanged for, see reuseRefreshToken
+	refreshTokenReuseGrace = 10 * time.Second
+)
+
 type AuthUseCase struct {
-	authRepo    repos.IAuthRepo
-	userUseCase IUserUC
-	privateKey  *rsa.PrivateKey
+	authRepo               repos.IAuthRepo
+	serviceAccountRepo     repos.IServiceAccountRepo
+	recoveryCodeRepo       repos.IRecoveryCodeRepo
+	webAuthnCredentialRepo repos.IWebAuthnCredentialRepo
+	linkedIdentityRepo     repos.ILinkedIdentityRepo
+	applicationRepo        repos.IApplicationRepo
+	userUseCase            IUserUC
+	mailer                 mailer.Mailer
+	keyring                *keyring.Keyring
+	upstreamProviders      map[string]*oidcclient.Provider
+	verifiers              []ICredentialVerifier
 }
 
-func NewAuthUseCase(ar repos.IAuthRepo, uu IUserUC, c *config.Config) *AuthUseCase {
+// NewAuthUseCase creates the use case. Passwords are checked against the local users first,
+// then against the verifiers given, such as a directory.
+func NewAuthUseCase(ar repos.IAuthRepo, sar repos.IServiceAccountRepo, rcr repos.IRecoveryCodeRepo, wcr repos.IWebAuthnCredentialRepo, lir repos.ILinkedIdentityRepo, apr repos.IApplicationRepo, uu IUserUC, m mailer.Mailer, c *config.Config, verifiers ...ICredentialVerifier) *AuthUseCase {
+	keys := c.JwtKeyring
+	if keys == nil && c.JwtPrivateKey != nil {
+		keys = keyring.FromPrivateKey(c.JwtPrivateKey)
+	}
+
 	return &AuthUseCase{
-		authRepo:    ar,
-		userUseCase: uu,
-		privateKey:  c.JwtPrivateKey,
+		authRepo:               ar,
+		serviceAccountRepo:     sar,
+		recoveryCodeRepo:       rcr,
+		webAuthnCredentialRepo: wcr,
+		linkedIdentityRepo:     lir,
+		applicationRepo:        apr,
+		userUseCase:            uu,
+		mailer:                 m,
+		keyring:                keys,
+		upstreamProviders:      newUpstreamProviders(c),
+		verifiers:              append([]ICredentialVerifier{&passwordVerifier{userUseCase: uu}}, verifiers...),
 	}
 }
 
 func (au *AuthUseCase) Login(c *gin.Context, requestBody dto.LoginRequestBody) (*dto.JwtTokens, error) {
-	user, err := au.userUseCase.FindByUsernameOrEmail(requestBody.Username, "")
+	_cfg, ok := c.Get("config")
+	if !ok {
+		return nil, errors.New("config not found")
+	}
+	cfg := _cfg.(*config.Config)
+
+	// locked usernames and ips are rejected before the expensive password check
+	subjects := loginSubjects(requestBody.Username, c.ClientIP(), cfg)
+	if err := au.checkLoginLocks(subjects); err != nil {
+		return nil, err
+	}
+
+	user, err := au.verifyCredentials(requestBody.Username, requestBody.Password)
 	if err != nil {
+		// unknown usernames count as failures too, otherwise they could be told apart
+		if helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+			return nil, au.loginFailed(subjects, cfg)
+		}
 		return nil, err
 	}
+	if user.Disabled {
+		return nil, &entity.UserDisabledError{}
+	}
 
 	if requestBody.RememberMe == "on" {
 		user.RememberMe = true
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(requestBody.Password)); err != nil {
-		return nil, &entity.InvalidCredentialsError{}
+	// only the username starts over, an ip guessing many accounts stays counted
+	if err := au.authRepo.ClearLoginFailures(subjects[0].kind, subjects[0].value); err != nil {
+		return nil, err
 	}
 
-	_cfg, ok := c.Get("config")
-	if !ok {
-		return nil, errors.New("config not found")
+	if cfg.Authen.RequireEmailVerification && !user.EmailVerified {
+		// the previous link may be lost or expired, so hand out a fresh one once it can't be used anymore
+		if err := au.resendVerificationEmail(*user, cfg); err != nil {
+			return nil, err
+		}
+		return nil, &entity.EmailNotVerifiedError{}
+	}
+
+	if user.TOTPEnabled {
+		// no tokens until the second factor is verified
+		pendingToken, err := generateRandomToken()
+		if err != nil {
+			return nil, err
+		}
+
+		err = au.authRepo.SaveMFAPendingToken(pendingToken, user.Username, cfg.Authen.MFAPendingTokenTTL)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &entity.MFARequiredError{Token: pendingToken}
 	}
-	cfg := _cfg.(*config.Config)
 
 	// Generate and return JWT tokens upon successful login
-	accessToken, err := au.CreateAccessToken(*user, cfg.Authen.AccessTokenTTL)
+	return au.GenerateTokens(*user, cfg)
+}
+
+// verifyCredentials asks the verifiers in turn, the first one which knows the password wins
+func (au *AuthUseCase) verifyCredentials(username string, password string) (*entity.User, error) {
+	for _, verifier := range au.verifiers {
+		user, err := verifier.Verify(username, password)
+		if err == nil {
+			return user, nil
+		}
+		if !helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+			return nil, err
+		}
+	}
+
+	return nil, &entity.InvalidCredentialsError{}
+}
+
+// passwordVerifier checks the password against the hash of the local user
+type passwordVerifier struct {
+	userUseCase IUserUC
+}
+
+func (v *passwordVerifier) Verify(username string, password string) (*entity.User, error) {
+	user, err := v.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, &entity.InvalidCredentialsError{}
+	}
+
+	return user, nil
+}
+
+// GenerateTokens issues the first pair of tokens of a new login, which starts a new session.
+// The tokens are tracked, so they can be revoked together when the user's credentials change.
+func (au *AuthUseCase) GenerateTokens(user entity.User, cfg *config.Config) (*dto.JwtTokens, error) {
+	sid := uuid.NewString()
+
+	tokens, err := au.generateTokens(user, sid, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := au.CreateRefreshToken(*user, accessToken, cfg.Authen.RefreshTokenTTL)
+	if err := au.saveSession(user, sid, cfg); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// generateTokens issues a pair of tokens of the given session. The tokens are tracked
+// per user and per session, so either can be revoked at once.
+func (au *AuthUseCase) generateTokens(user entity.User, sid string, cfg *config.Config) (*dto.JwtTokens, error) {
+	tokens, err := au.createTokens(user, sid, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := au.trackTokens(user.Username, sid, tokens, cfg); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// createTokens signs a pair of tokens of the given session without tracking them
+func (au *AuthUseCase) createTokens(user entity.User, sid string, cfg *config.Config) (*dto.JwtTokens, error) {
+	// the user is looked up on every issue, so a changed role or a disabled user applies from the next refresh on
+	current, err := au.userUseCase.FindByUsernameOrEmail(user.Username, "")
+	if err != nil {
+		return nil, err
+	}
+	if current.Disabled {
+		return nil, &entity.UserDisabledError{}
+	}
+	user.Role = current.Role
+
+	accessToken, err := au.createAccessToken(user, sid, cfg.Authen.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := au.createRefreshToken(user, accessToken, sid, cfg.Authen.RefreshTokenTTL)
 	if err != nil {
 		return nil, err
 	}
@@ -68,10 +230,35 @@ func (au *AuthUseCase) Login(c *gin.Context, requestBody dto.LoginRequestBody) (
 	}, nil
 }
 
+func (au *AuthUseCase) trackTokens(username string, sid string, tokens *dto.JwtTokens, cfg *config.Config) error {
+	err := au.authRepo.AddUserToken(username, tokens.AccessToken, cfg.Authen.AccessTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	err = au.authRepo.AddUserToken(username, tokens.RefreshToken, cfg.Authen.RefreshTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	err = au.authRepo.AddFamilyToken(sid, tokens.AccessToken, cfg.Authen.AccessTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	return au.authRepo.AddFamilyToken(sid, tokens.RefreshToken, cfg.Authen.RefreshTokenTTL)
+}
+
 func (au *AuthUseCase) Register() {
 }
 
+// CreateAccessToken creates an access token which doesn't belong to any session. The roles and
+// permissions claims come from the role of the user, when it is loaded.
 func (au *AuthUseCase) CreateAccessToken(user entity.User, expireTime int) (string, error) {
+	return au.createAccessToken(user, "", expireTime)
+}
+
+func (au *AuthUseCase) createAccessToken(user entity.User, sid string, expireTime int) (string, error) {
 	if expireTime <= 0 {
 		return "", errors.New("invalid expiration time")
 	}
@@ -80,19 +267,30 @@ func (au *AuthUseCase) CreateAccessToken(user entity.User, expireTime int) (stri
 		"iss":         "AuthConnect Hub",
 		"sub":         user.Username,
 		"remember_me": user.RememberMe,
-		"aud":         "users",
+		"aud":         hubAudience,
+		"token_use":   tokenUseAccess,
 		"exp":         time.Now().Add(time.Second * time.Duration(expireTime)).Unix(),
 		"nbf":         time.Now().Unix(),
 		"iat":         time.Now().Unix(),
 		"jti":         uuid.NewString(),
 	}
+	if sid != "" {
+		claims["sid"] = sid
+	}
+	if user.Role.Name != "" {
+		permissions := make([]string, 0, len(user.Role.Permissions))
+		for _, permission := range user.Role.Permissions {
+			permissions = append(permissions, permission.Name)
+		}
+		claims["roles"] = []string{user.Role.Name}
+		claims["permissions"] = permissions
+	}
 
-	if au.privateKey == nil {
+	if au.keyring == nil {
 		return "", errors.New("missing access token private key")
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	accessToken, err := token.SignedString(au.privateKey)
+	accessToken, err := au.signToken(claims)
 	if err != nil {
 		return "", fmt.Errorf("error signing token: %v", err)
 	}
@@ -100,7 +298,14 @@ func (au *AuthUseCase) CreateAccessToken(user entity.User, expireTime int) (stri
 	return accessToken, nil
 }
 
+// CreateRefreshToken creates a refresh token which starts a new token family
 func (au *AuthUseCase) CreateRefreshToken(user entity.User, accessToken string, expireTime int) (string, error) {
+	return au.createRefreshToken(user, accessToken, uuid.NewString(), expireTime)
+}
+
+// createRefreshToken creates a refresh token of the given session. All refresh tokens
+// descending from one login share the session id, so they can be revoked together.
+func (au *AuthUseCase) createRefreshToken(user entity.User, accessToken string, sid string, expireTime int) (string, error) {
 	if expireTime <= 0 {
 		return "", errors.New("invalid expiration time")
 	}
@@ -113,20 +318,21 @@ func (au *AuthUseCase) CreateRefreshToken(user entity.User, accessToken string,
 	claims := jwt.MapClaims{
 		"iss":              "AuthConnect Hub",
 		"sub":              user.Username,
-		"aud":              "users",
+		"aud":              hubAudience,
+		"token_use":        tokenUseRefresh,
 		"exp":              time.Now().Add(time.Second * time.Duration(expireTime)).Unix(),
 		"nbf":              time.Now().Unix(),
 		"iat":              time.Now().Unix(),
 		"jti":              uuid.NewString(),
 		"access_token_jti": accessTokenJti.(string),
+		"sid":              sid,
 	}
 
-	if au.privateKey == nil {
+	if au.keyring == nil {
 		return "", errors.New("missing refresh token private key")
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	refreshToken, err := token.SignedString(au.privateKey)
+	refreshToken, err := au.signToken(claims)
 	if err != nil {
 		return "", fmt.Errorf("error signing refresh token. Error: %v", err)
 	}
@@ -134,13 +340,45 @@ func (au *AuthUseCase) CreateRefreshToken(user entity.User, accessToken string,
 	return refreshToken, nil
 }
 
-func (au *AuthUseCase) ValidateToken(jwtToken string) (string, error) {
-	token, err := jwt.Parse(jwtToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+// signToken signs the claims with the active key and stamps its kid in the header
+func (au *AuthUseCase) signToken(claims jwt.MapClaims) (string, error) {
+	key := au.keyring.Active()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
+// parseToken parses and validates a token signed with a key of the keyring
+func (au *AuthUseCase) parseToken(jwtToken string) (*jwt.Token, error) {
+	if au.keyring == nil {
+		return nil, errors.New("missing token public key")
+	}
+
+	return jwt.Parse(jwtToken, au.verificationKey, jwt.WithValidMethods(au.keyring.Algorithms()))
+}
+
+// verificationKey picks the key a token was signed with by its kid, so tokens signed
+// before a rotation stay valid as long as their key is in the keyring. The token
+// has to be signed with the algorithm of that key.
+func (au *AuthUseCase) verificationKey(token *jwt.Token) (interface{}, error) {
+	// tokens issued before kids were stamped were signed with the only key there was
+	key := au.keyring.Active()
+	if kid, ok := token.Header["kid"].(string); ok {
+		var found bool
+		key, found = au.keyring.Find(kid)
+		if !found {
+			return nil, fmt.Errorf("unknown signing key: %s", kid)
 		}
-		return au.privateKey.Public(), nil
-	})
+	}
+
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.PrivateKey.Public(), nil
+}
+
+func (au *AuthUseCase) ValidateToken(jwtToken string) (string, error) {
+	token, err := au.parseToken(jwtToken)
 	if err != nil {
 		return "", err
 	}
@@ -150,6 +388,15 @@ func (au *AuthUseCase) ValidateToken(jwtToken string) (string, error) {
 		return "", errors.New("invalid token")
 	}
 
+	// ID tokens and service tokens are signed with the same keys, but they don't let anyone in.
+	// Tokens without any audience are accepted as well, ID tokens always name their client.
+	tokenUse := tokenUseOf(claims)
+	audience, _ := claims.GetAudience()
+	withoutAudience := tokenUse == "" && len(audience) == 0
+	if !withoutAudience && ((tokenUse != tokenUseAccess && tokenUse != tokenUseRefresh) || !slices.Contains(audience, hubAudience)) {
+		return "", errors.New("token isn't meant for the hub")
+	}
+
 	username, ok := claims["sub"].(string)
 	if !ok {
 		return "", errors.New("missing 'sub' claim in token")
@@ -158,6 +405,23 @@ func (au *AuthUseCase) ValidateToken(jwtToken string) (string, error) {
 	return username, nil
 }
 
+// tokenUseOf returns the token_use claim. The hub tokens issued before it existed are told apart by
+// their audience instead, no client can share it since it is a reserved client id.
+func tokenUseOf(claims jwt.MapClaims) string {
+	if tokenUse, ok := claims["token_use"].(string); ok {
+		return tokenUse
+	}
+
+	audience, _ := claims.GetAudience()
+	if !slices.Contains(audience, hubAudience) {
+		return ""
+	}
+	if _, ok := claims["access_token_jti"]; ok {
+		return tokenUseRefresh
+	}
+	return tokenUseAccess
+}
+
 func (au *AuthUseCase) IsRefreshTokenValidForAccessToken(accessToken string, refreshToken string) (bool, error) {
 	accessTokenJti, err := au.RetrieveFieldFromJwtToken(accessToken, "jti", false)
 	if err != nil {
@@ -184,21 +448,85 @@ func (au *AuthUseCase) CheckAndRefreshTokens(oldAccessToken string, oldRefreshTo
 		return "", "", err
 	}
 
+	jti, err := au.RetrieveFieldFromJwtToken(oldRefreshToken, "jti", true)
+	if err != nil {
+		return "", "", err
+	}
+
+	// the session id doubles as the family of the refresh tokens
+	sid, err := au.RetrieveFieldFromJwtToken(oldRefreshToken, "sid", true)
+	legacy := err != nil
+	if legacy {
+		// tokens issued before sessions were introduced start their own
+		sid = jti
+	}
+
+	// checked by IsRefreshTokenValidForAccessToken already
+	accessTokenJti, _ := au.RetrieveFieldFromJwtToken(oldAccessToken, "jti", false)
+
+	// every refresh token can only be exchanged once, a token exchanged before gets no new pair
+	previous, err := au.authRepo.FindRefreshTokenRotation(jti.(string))
+	if err != nil {
+		return "", "", err
+	}
+	if previous != nil {
+		return au.reuseRefreshToken(*previous, accessTokenJti.(string), username, sid.(string), cfg)
+	}
+
 	rememberMe, _ := au.RetrieveFieldFromJwtToken(oldAccessToken, "remember_me", false)
 	user := entity.User{Username: username, RememberMe: rememberMe.(bool)}
 
-	// Create new access token
-	newAccessToken, err := au.CreateAccessToken(user, cfg.Authen.AccessTokenTTL)
+	// Create new pair of tokens in the same session
+	tokens, err := au.createTokens(user, sid.(string), cfg)
+	if err != nil {
+		return "", "", err
+	}
+
+	previous, err = au.authRepo.RotateRefreshToken(jti.(string), entity.RefreshTokenRotation{
+		AccessTokenJTI: accessTokenJti.(string),
+		AccessToken:    tokens.AccessToken,
+		RefreshToken:   tokens.RefreshToken,
+		RotatedAt:      time.Now(),
+	}, cfg.Authen.RefreshTokenTTL)
 	if err != nil {
 		return "", "", err
 	}
+	if previous != nil {
+		// another request exchanged the token in the meantime, the pair created here is dropped unused
+		return au.reuseRefreshToken(*previous, accessTokenJti.(string), username, sid.(string), cfg)
+	}
+
+	if err := au.trackTokens(username, sid.(string), tokens, cfg); err != nil {
+		return "", "", err
+	}
 
-	newRefreshToken, err := au.CreateRefreshToken(user, newAccessToken, cfg.Authen.RefreshTokenTTL)
+	if legacy {
+		err = au.saveSession(user, sid.(string), cfg)
+	} else {
+		err = au.authRepo.ExtendSession(sid.(string), username, cfg.Authen.RefreshTokenTTL)
+	}
 	if err != nil {
 		return "", "", err
 	}
 
-	return newAccessToken, newRefreshToken, nil
+	return tokens.AccessToken, tokens.RefreshToken, nil
+}
+
+// reuseRefreshToken answers a refresh token which was exchanged before. Parallel requests of a page
+// present the same pair of tokens, so for refreshTokenReuseGrace after the exchange a request with
+// the very access token the refresh token was exchanged with gets the pair issued then; nothing new
+// is minted for it. Any other request is a replay: either the thief or the user is replaying an
+// exchanged token, we can't tell who, so the whole session is revoked and both have to log in again.
+func (au *AuthUseCase) reuseRefreshToken(previous entity.RefreshTokenRotation, accessTokenJti string, username string, sid string, cfg *config.Config) (string, string, error) {
+	// rotations recorded without the access token or the rotation time count as replays
+	if previous.AccessTokenJTI == accessTokenJti && time.Since(previous.RotatedAt) <= refreshTokenReuseGrace {
+		return previous.AccessToken, previous.RefreshToken, nil
+	}
+
+	if err := au.endSession(username, sid, cfg); err != nil {
+		return "", "", err
+	}
+	return "", "", &entity.RefreshTokenReusedError{Username: username, Family: sid}
 }
 
 func (au *AuthUseCase) RetrieveFieldFromJwtToken(jwtToken string, fieldName string, validate bool) (interface{}, error) {
@@ -207,12 +535,7 @@ func (au *AuthUseCase) RetrieveFieldFromJwtToken(jwtToken string, fieldName stri
 
 	if validate {
 		// Parse with validation
-		token, err = jwt.Parse(jwtToken, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return au.privateKey.Public(), nil
-		})
+		token, err = au.parseToken(jwtToken)
 	} else {
 		// Parse without validation
 		token, _ = jwt.Parse(jwtToken, nil)
@@ -265,6 +588,14 @@ func (au *AuthUseCase) Logout(c *gin.Context) error {
 		return err
 	}
 
+	sid, _ := au.RetrieveFieldFromJwtToken(accessToken, "sid", false)
+	username, _ := au.RetrieveFieldFromJwtToken(accessToken, "sub", false)
+	if sid, ok := sid.(string); ok {
+		if err := au.authRepo.DeleteSession(sid, fmt.Sprint(username)); err != nil {
+			return err
+		}
+	}
+
 	helper.DeleteTokens(c, rememberMe.(bool), false)
 
 	return nil
@@ -273,3 +604,216 @@ func (au *AuthUseCase) Logout(c *gin.Context) error {
 func (au *AuthUseCase) IsTokenBlacklisted(token string) (bool, error) {
 	return au.authRepo.IsTokenBlacklisted(token)
 }
+
+// RequestPasswordReset mails a single-use reset link to the owner of the email.
+// Unknown emails are ignored silently so the endpoint can't be used to discover accounts.
+func (au *AuthUseCase) RequestPasswordReset(email string, cfg *config.Config) error {
+	user, err := au.userUseCase.FindByUsernameOrEmail("", email)
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := generateRandomToken()
+	if err != nil {
+		return err
+	}
+
+	err = au.authRepo.SaveResetPasswordToken(token, user.Username, cfg.Authen.ResetPasswordTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	resetLink := fmt.Sprintf("%s/v1/auth/reset-password?token=%s", strings.TrimRight(cfg.App.BaseURL, "/"), url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Hi %s,\r\n\r\nWe received a request to reset your password. Open the link below to choose a new one:\r\n\r\n%s\r\n\r\nThe link expires in %d minutes and can only be used once. If you didn't ask for it, you can ignore this email.\r\n",
+		user.Username, resetLink, cfg.Authen.ResetPasswordTokenTTL/60,
+	)
+
+	return au.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body:    body,
+	})
+}
+
+// ResetPassword sets a new password for the user the reset token was issued for
+// and revokes every token the user still holds
+func (au *AuthUseCase) ResetPassword(token string, newPassword string, cfg *config.Config) error {
+	username, err := au.authRepo.ConsumeResetPasswordToken(token)
+	if err != nil {
+		return err
+	}
+	if username == "" {
+		return &entity.InvalidResetPasswordTokenError{}
+	}
+
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+			return &entity.InvalidResetPasswordTokenError{}
+		}
+		return err
+	}
+
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	user.Password = string(encryptedPassword)
+	_, err = au.userUseCase.Update(*user)
+	if err != nil {
+		return err
+	}
+
+	return au.endUserSessions(user.Username, cfg)
+}
+
+// ChangePassword replaces the password of the logged in user after checking the current one.
+// Every other token of the user is revoked, only the tokens of the current request stay valid.
+func (au *AuthUseCase) ChangePassword(c *gin.Context, requestBody dto.ChangePasswordRequestBody) error {
+	username := c.GetString("username")
+
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(requestBody.CurrentPassword)); err != nil {
+		return &entity.IncorrectPasswordError{}
+	}
+
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(requestBody.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	user.Password = string(encryptedPassword)
+	_, err = au.userUseCase.Update(*user)
+	if err != nil {
+		return err
+	}
+
+	accessToken := helper.ExtractHeaderToken(c, helper.AccessTokenHeader)
+	refreshToken := helper.ExtractHeaderToken(c, helper.RefreshTokenHeader)
+
+	// the session changing the password stays logged in
+	sid, _ := au.RetrieveFieldFromJwtToken(accessToken, "sid", false)
+	currentSid, _ := sid.(string)
+
+	cfg := helper.GetConfig(c)
+	if err := au.authRepo.BlacklistUserTokens(user.Username, cfg.Authen.RefreshTokenTTL, accessToken, refreshToken); err != nil {
+		return err
+	}
+
+	return au.authRepo.DeleteUserSessions(user.Username, currentSid)
+}
+
+// SendVerificationEmail mails the user a signed link proving they own their email address.
+// The link is stateless, it carries the username and the email it was issued for.
+func (au *AuthUseCase) SendVerificationEmail(user entity.User, cfg *config.Config) error {
+	if cfg.Authen.EmailVerificationTokenTTL <= 0 {
+		return errors.New("invalid expiration time")
+	}
+
+	// logins don't mail another link while this one is valid
+	if _, err := au.authRepo.MarkVerificationEmailSent(user.Username, cfg.Authen.EmailVerificationTokenTTL); err != nil {
+		return err
+	}
+
+	return au.mailVerificationLink(user, cfg)
+}
+
+// resendVerificationEmail mails an unverified user who tries to log in a new link, unless the last
+// one is still valid. Otherwise anyone knowing the password could flood the inbox of the user.
+func (au *AuthUseCase) resendVerificationEmail(user entity.User, cfg *config.Config) error {
+	if cfg.Authen.EmailVerificationTokenTTL <= 0 {
+		return errors.New("invalid expiration time")
+	}
+
+	sent, err := au.authRepo.MarkVerificationEmailSent(user.Username, cfg.Authen.EmailVerificationTokenTTL)
+	if err != nil || !sent {
+		return err
+	}
+
+	return au.mailVerificationLink(user, cfg)
+}
+
+func (au *AuthUseCase) mailVerificationLink(user entity.User, cfg *config.Config) error {
+	claims := jwt.MapClaims{
+		"iss":   "AuthConnect Hub",
+		"sub":   user.Username,
+		"email": user.Email,
+		"aud":   emailVerificationAudience,
+		"exp":   time.Now().Add(time.Second * time.Duration(cfg.Authen.EmailVerificationTokenTTL)).Unix(),
+		"iat":   time.Now().Unix(),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.Authen.SecretKey))
+	if err != nil {
+		return fmt.Errorf("error signing verification token: %v", err)
+	}
+
+	verifyLink := fmt.Sprintf("%s/v1/auth/verify-email?token=%s", strings.TrimRight(cfg.App.BaseURL, "/"), url.QueryEscape(token))
+	body := fmt.Sprintf(
+		"Hi %s,\r\n\r\nThanks for signing up. Open the link below to verify your email address:\r\n\r\n%s\r\n\r\nThe link expires in %d hours. If you didn't create an account, you can ignore this email.\r\n",
+		user.Username, verifyLink, cfg.Authen.EmailVerificationTokenTTL/3600,
+	)
+
+	return au.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body:    body,
+	})
+}
+
+// VerifyEmail marks the email of the user the verification token was issued for as verified
+func (au *AuthUseCase) VerifyEmail(token string, cfg *config.Config) error {
+	parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		return []byte(cfg.Authen.SecretKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(emailVerificationAudience))
+	if err != nil {
+		return &entity.InvalidEmailVerificationTokenError{}
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return &entity.InvalidEmailVerificationTokenError{}
+	}
+	username, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+			return &entity.InvalidEmailVerificationTokenError{}
+		}
+		return err
+	}
+
+	// the email changed after the link was sent
+	if user.Email != email {
+		return &entity.InvalidEmailVerificationTokenError{}
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	verifiedAt := time.Now()
+	user.EmailVerified = true
+	user.VerifiedAt = &verifiedAt
+	_, err = au.userUseCase.Update(*user)
+	return err
+}
+
+func generateRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -3,8 +3,10 @@ package usecases_test
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +19,8 @@ import (
 	"github.com/minhmannh2001/authconnecthub/internal/usecases"
 	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
 	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/minhmannh2001/authconnecthub/pkg/mailer"
+	mailerMocks "github.com/minhmannh2001/authconnecthub/pkg/mailer/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
@@ -49,14 +53,20 @@ func TestAuthUseCase_Login_Success(t *testing.T) {
 		JwtPrivateKey:   privateKey,
 	}}
 
-	mockContext := gin.Context{}
-	mockContext.Set("config", mockConfig)
+	mockContext := newLoginContext(mockConfig)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLoginUnlocked(mockAuthRepo, "testuser")
+	mockAuthRepo.On("ClearLoginFailures", "username", "testuser").Return(nil)
+	mockAuthRepo.On("AddUserToken", "testuser", mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("AddFamilyToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("SaveSession", mock.Anything, 3600).Return(nil)
 
 	// Create use case with mocks
-	uc := usecases.NewAuthUseCase(nil, mockUserRepo, mockConfig)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserRepo, nil, mockConfig)
 
 	// Call Login
-	tokens, err := uc.Login(&mockContext, requestBody)
+	tokens, err := uc.Login(mockContext, requestBody)
 
 	// Assertions
 	assert.NoError(t, err)
@@ -65,6 +75,25 @@ func TestAuthUseCase_Login_Success(t *testing.T) {
 	assert.NotEmpty(t, tokens.RefreshToken)
 }
 
+func TestAuthUseCase_Login_UserDisabled(t *testing.T) {
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Password: string(encryptedPassword), Disabled: true}, nil)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLoginUnlocked(mockAuthRepo, "testuser")
+
+	mockConfig := &config.Config{}
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	tokens, err := uc.Login(newLoginContext(mockConfig), dto.LoginRequestBody{Username: "testuser", Password: "secret"})
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.UserDisabledError{}, err)
+	mockAuthRepo.AssertNotCalled(t, "AddUserToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestAuthUseCase_Login_UserNotFound(t *testing.T) {
 	// Login request
 	requestBody := dto.LoginRequestBody{Username: "testuser", Password: "secret", RememberMe: "on"}
@@ -85,13 +114,17 @@ func TestAuthUseCase_Login_UserNotFound(t *testing.T) {
 		JwtPrivateKey:   privateKey,
 	}}
 
-	mockContext := gin.Context{}
-	mockContext.Set("config", mockConfig)
+	mockContext := newLoginContext(mockConfig)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLoginUnlocked(mockAuthRepo, "testuser")
+	mockAuthRepo.On("RecordLoginFailure", "username", "testuser", 0).Return(int64(1), nil)
+	mockAuthRepo.On("RecordLoginFailure", "ip", "203.0.113.7", 0).Return(int64(1), nil)
 
 	// Create use case with mocks
-	uc := usecases.NewAuthUseCase(nil, mockUserRepo, mockConfig)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserRepo, nil, mockConfig)
 
-	tokens, err := uc.Login(&mockContext, requestBody)
+	tokens, err := uc.Login(mockContext, requestBody)
 
 	// Assertions
 	assert.Error(t, err)
@@ -126,14 +159,18 @@ func TestAuthUseCase_Login_InvalidCredentials(t *testing.T) {
 		JwtPrivateKey:   privateKey,
 	}}
 
-	mockContext := gin.Context{}
-	mockContext.Set("config", mockConfig)
+	mockContext := newLoginContext(mockConfig)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLoginUnlocked(mockAuthRepo, "testuser")
+	mockAuthRepo.On("RecordLoginFailure", "username", "testuser", 0).Return(int64(1), nil)
+	mockAuthRepo.On("RecordLoginFailure", "ip", "203.0.113.7", 0).Return(int64(1), nil)
 
 	// Create use case with mocks
-	uc := usecases.NewAuthUseCase(nil, mockUserRepo, mockConfig)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserRepo, nil, mockConfig)
 
 	// Call Login
-	tokens, err := uc.Login(&mockContext, requestBody)
+	tokens, err := uc.Login(mockContext, requestBody)
 
 	// Assertions
 	assert.Error(t, err)
@@ -167,7 +204,7 @@ func TestAuthUseCase_ValidateToken_Success(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Create use case with private key
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	// Call ValidateToken
 	username, err := uc.ValidateToken(tokenString)
@@ -196,7 +233,7 @@ func TestAuthUseCase_ValidateToken_InvalidToken(t *testing.T) {
 	}
 
 	// Create use case with private key (doesn't matter for these tests)
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	for _, token := range invalidTokens {
 		username, err := uc.ValidateToken(token)
@@ -230,7 +267,7 @@ func TestAuthUseCase_ValidateToken_ExpiredToken(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Create use case with private key
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	// Call ValidateToken
 	username, err := uc.ValidateToken(tokenString)
@@ -241,6 +278,33 @@ func TestAuthUseCase_ValidateToken_ExpiredToken(t *testing.T) {
 	assert.Empty(t, username)
 }
 
+func TestAuthUseCase_ValidateToken_HubAudienceWithoutTokenUse(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	mockConfig := &config.Config{Authen: config.Authen{JwtPrivateKey: privateKey}}
+
+	// an access token issued before the token_use claim existed, no client can have the hub audience
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":         "AuthConnect Hub",
+		"sub":         "testuser",
+		"remember_me": false,
+		"aud":         "users",
+		"exp":         time.Now().Add(time.Minute * 10).Unix(),
+		"nbf":         time.Now().Unix(),
+		"iat":         time.Now().Unix(),
+		"jti":         "legacy-jti",
+	}).SignedString(privateKey)
+	assert.NoError(t, err)
+
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
+
+	username, err := uc.ValidateToken(tokenString)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "testuser", username)
+}
+
 func TestAuthUseCase_ValidateToken_MissingSubClaim(t *testing.T) {
 	reader := rand.Reader
 	bitSize := 2048
@@ -264,7 +328,7 @@ func TestAuthUseCase_ValidateToken_MissingSubClaim(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Create use case with private key
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	// Call ValidateToken
 	username, err := uc.ValidateToken(tokenString)
@@ -288,7 +352,7 @@ func TestAuthUseCase_IsRefreshTokenValidForAccessToken_Valid(t *testing.T) {
 		JwtPrivateKey:   privateKey,
 	}}
 
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	mockUser := entity.User{ID: 1, Username: "testuser"}
 
@@ -317,7 +381,7 @@ func TestAuthUseCase_IsRefreshTokenValidForAccessToken_InvalidJti(t *testing.T)
 		JwtPrivateKey:   privateKey,
 	}}
 
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	mockUser := entity.User{ID: 1, Username: "testuser"}
 
@@ -345,7 +409,7 @@ func TestAuthUseCase_IsRefreshTokenValidForAccessToken_ErrorRetrievingRefreshTok
 		AccessTokenTTL:  600,
 	}}
 
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	isValid, err := uc.IsRefreshTokenValidForAccessToken(accessToken, refreshToken)
 
@@ -366,7 +430,10 @@ func TestAuthUseCase_CheckAndRefreshTokens_Success(t *testing.T) {
 		JwtPrivateKey:   privateKey,
 	}}
 
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
 
 	mockUser := entity.User{ID: 1, Username: "testuser"}
 
@@ -376,6 +443,26 @@ func TestAuthUseCase_CheckAndRefreshTokens_Success(t *testing.T) {
 	refreshToken, err := uc.CreateRefreshToken(mockUser, accessToken, mockConfig.Authen.RefreshTokenTTL)
 	assert.NoError(t, err)
 
+	jti, err := uc.RetrieveFieldFromJwtToken(refreshToken, "jti", true)
+	assert.NoError(t, err)
+	sid, err := uc.RetrieveFieldFromJwtToken(refreshToken, "sid", true)
+	assert.NoError(t, err)
+
+	// the role has changed since the login
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{
+		Username: "testuser",
+		Role:     entity.Role{Name: "operator", Permissions: []entity.Permission{{Name: "login-locks:read"}}},
+	}, nil)
+	accessTokenJti, err := uc.RetrieveFieldFromJwtToken(accessToken, "jti", true)
+	assert.NoError(t, err)
+	mockAuthRepo.On("FindRefreshTokenRotation", jti).Return(nil, nil)
+	mockAuthRepo.On("RotateRefreshToken", jti, mock.MatchedBy(func(r entity.RefreshTokenRotation) bool {
+		return r.AccessTokenJTI == accessTokenJti && r.AccessToken != "" && r.RefreshToken != ""
+	}), 3600).Return(nil, nil)
+	mockAuthRepo.On("AddUserToken", "testuser", mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("AddFamilyToken", sid, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("ExtendSession", sid, "testuser", 3600).Return(nil)
+
 	time.Sleep(2 * time.Second)
 
 	newAccessToken, newRefreshToken, err := uc.CheckAndRefreshTokens(accessToken, refreshToken, mockConfig)
@@ -383,6 +470,269 @@ func TestAuthUseCase_CheckAndRefreshTokens_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEmpty(t, newAccessToken)
 	assert.NotEmpty(t, newRefreshToken)
+
+	// the new tokens continue the session of the old ones
+	newSid, err := uc.RetrieveFieldFromJwtToken(newRefreshToken, "sid", true)
+	assert.NoError(t, err)
+	assert.Equal(t, sid, newSid)
+	newAccessTokenSid, err := uc.RetrieveFieldFromJwtToken(newAccessToken, "sid", true)
+	assert.NoError(t, err)
+	assert.Equal(t, sid, newAccessTokenSid)
+
+	roles, err := uc.RetrieveFieldFromJwtToken(newAccessToken, "roles", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"operator"}, roles)
+	permissions, err := uc.RetrieveFieldFromJwtToken(newAccessToken, "permissions", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"login-locks:read"}, permissions)
+}
+
+func TestAuthUseCase_CheckAndRefreshTokens_ReusedRefreshToken(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		RefreshTokenTTL: 3600,
+		AccessTokenTTL:  600,
+		JwtPrivateKey:   privateKey,
+	}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	mockUser := entity.User{ID: 1, Username: "testuser"}
+
+	accessToken, err := uc.CreateAccessToken(mockUser, mockConfig.Authen.AccessTokenTTL)
+	assert.NoError(t, err)
+
+	refreshToken, err := uc.CreateRefreshToken(mockUser, accessToken, mockConfig.Authen.RefreshTokenTTL)
+	assert.NoError(t, err)
+
+	jti, err := uc.RetrieveFieldFromJwtToken(refreshToken, "jti", true)
+	assert.NoError(t, err)
+	sid, err := uc.RetrieveFieldFromJwtToken(refreshToken, "sid", true)
+	assert.NoError(t, err)
+
+	accessTokenJti, err := uc.RetrieveFieldFromJwtToken(accessToken, "jti", true)
+	assert.NoError(t, err)
+
+	// the token has been exchanged a minute ago, out of the grace window
+	mockAuthRepo.On("FindRefreshTokenRotation", jti).Return(&entity.RefreshTokenRotation{
+		AccessTokenJTI: accessTokenJti.(string),
+		AccessToken:    "issued-access-token",
+		RefreshToken:   "issued-refresh-token",
+		RotatedAt:      time.Now().Add(-time.Minute),
+	}, nil)
+	mockAuthRepo.On("RevokeTokenFamily", sid, 3600).Return(nil)
+	mockAuthRepo.On("DeleteSession", sid, "testuser").Return(nil)
+
+	newAccessToken, newRefreshToken, err := uc.CheckAndRefreshTokens(accessToken, refreshToken, mockConfig)
+
+	assert.Equal(t, &entity.RefreshTokenReusedError{Username: "testuser", Family: sid.(string)}, err)
+	assert.Empty(t, newAccessToken)
+	assert.Empty(t, newRefreshToken)
+	// no new pair is created for a replay
+	mockUserUC.AssertNotCalled(t, "FindByUsernameOrEmail", mock.Anything, mock.Anything)
+	mockAuthRepo.AssertNotCalled(t, "RotateRefreshToken", mock.Anything, mock.Anything, mock.Anything)
+	mockAuthRepo.AssertNotCalled(t, "AddUserToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_CheckAndRefreshTokens_ConcurrentRefresh(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		RefreshTokenTTL: 3600,
+		AccessTokenTTL:  600,
+		JwtPrivateKey:   privateKey,
+	}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	mockUser := entity.User{ID: 1, Username: "testuser"}
+
+	accessToken, err := uc.CreateAccessToken(mockUser, mockConfig.Authen.AccessTokenTTL)
+	assert.NoError(t, err)
+
+	refreshToken, err := uc.CreateRefreshToken(mockUser, accessToken, mockConfig.Authen.RefreshTokenTTL)
+	assert.NoError(t, err)
+
+	jti, err := uc.RetrieveFieldFromJwtToken(refreshToken, "jti", true)
+	assert.NoError(t, err)
+
+	accessTokenJti, err := uc.RetrieveFieldFromJwtToken(accessToken, "jti", true)
+	assert.NoError(t, err)
+
+	// another request of the same page exchanged the token a moment ago
+	mockAuthRepo.On("FindRefreshTokenRotation", jti).Return(&entity.RefreshTokenRotation{
+		AccessTokenJTI: accessTokenJti.(string),
+		AccessToken:    "issued-access-token",
+		RefreshToken:   "issued-refresh-token",
+		RotatedAt:      time.Now().Add(-time.Second),
+	}, nil)
+
+	newAccessToken, newRefreshToken, err := uc.CheckAndRefreshTokens(accessToken, refreshToken, mockConfig)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "issued-access-token", newAccessToken)
+	assert.Equal(t, "issued-refresh-token", newRefreshToken)
+	// the pair issued then is handed out, nothing new is created
+	mockUserUC.AssertNotCalled(t, "FindByUsernameOrEmail", mock.Anything, mock.Anything)
+	mockAuthRepo.AssertNotCalled(t, "RotateRefreshToken", mock.Anything, mock.Anything, mock.Anything)
+	mockAuthRepo.AssertNotCalled(t, "RevokeTokenFamily", mock.Anything, mock.Anything)
+	mockAuthRepo.AssertNotCalled(t, "AddUserToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_CheckAndRefreshTokens_ConcurrentRefreshOtherAccessToken(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		RefreshTokenTTL: 3600,
+		AccessTokenTTL:  600,
+		JwtPrivateKey:   privateKey,
+	}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	mockUser := entity.User{ID: 1, Username: "testuser"}
+
+	accessToken, err := uc.CreateAccessToken(mockUser, mockConfig.Authen.AccessTokenTTL)
+	assert.NoError(t, err)
+
+	refreshToken, err := uc.CreateRefreshToken(mockUser, accessToken, mockConfig.Authen.RefreshTokenTTL)
+	assert.NoError(t, err)
+
+	jti, err := uc.RetrieveFieldFromJwtToken(refreshToken, "jti", true)
+	assert.NoError(t, err)
+	sid, err := uc.RetrieveFieldFromJwtToken(refreshToken, "sid", true)
+	assert.NoError(t, err)
+
+	// inside the grace window, but the token was exchanged with another access token
+	mockAuthRepo.On("FindRefreshTokenRotation", jti).Return(&entity.RefreshTokenRotation{
+		AccessTokenJTI: "other-access-jti",
+		AccessToken:    "issued-access-token",
+		RefreshToken:   "issued-refresh-token",
+		RotatedAt:      time.Now().Add(-time.Second),
+	}, nil)
+	mockAuthRepo.On("RevokeTokenFamily", sid, 3600).Return(nil)
+	mockAuthRepo.On("DeleteSession", sid, "testuser").Return(nil)
+
+	newAccessToken, newRefreshToken, err := uc.CheckAndRefreshTokens(accessToken, refreshToken, mockConfig)
+
+	assert.Equal(t, &entity.RefreshTokenReusedError{Username: "testuser", Family: sid.(string)}, err)
+	assert.Empty(t, newAccessToken)
+	assert.Empty(t, newRefreshToken)
+}
+
+func TestAuthUseCase_CheckAndRefreshTokens_RaceLost(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		RefreshTokenTTL: 3600,
+		AccessTokenTTL:  600,
+		JwtPrivateKey:   privateKey,
+	}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	mockUser := entity.User{ID: 1, Username: "testuser"}
+
+	accessToken, err := uc.CreateAccessToken(mockUser, mockConfig.Authen.AccessTokenTTL)
+	assert.NoError(t, err)
+
+	refreshToken, err := uc.CreateRefreshToken(mockUser, accessToken, mockConfig.Authen.RefreshTokenTTL)
+	assert.NoError(t, err)
+
+	jti, err := uc.RetrieveFieldFromJwtToken(refreshToken, "jti", true)
+	assert.NoError(t, err)
+	accessTokenJti, err := uc.RetrieveFieldFromJwtToken(accessToken, "jti", true)
+	assert.NoError(t, err)
+
+	// a parallel request exchanged the token between the lookup and the rotation
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser"}, nil)
+	mockAuthRepo.On("FindRefreshTokenRotation", jti).Return(nil, nil)
+	mockAuthRepo.On("RotateRefreshToken", jti, mock.Anything, 3600).Return(&entity.RefreshTokenRotation{
+		AccessTokenJTI: accessTokenJti.(string),
+		AccessToken:    "issued-access-token",
+		RefreshToken:   "issued-refresh-token",
+		RotatedAt:      time.Now(),
+	}, nil)
+
+	newAccessToken, newRefreshToken, err := uc.CheckAndRefreshTokens(accessToken, refreshToken, mockConfig)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "issued-access-token", newAccessToken)
+	assert.Equal(t, "issued-refresh-token", newRefreshToken)
+	// the pair created by the losing request is never tracked
+	mockAuthRepo.AssertNotCalled(t, "AddUserToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_CheckAndRefreshTokens_TokenWithoutSession(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		RefreshTokenTTL: 3600,
+		AccessTokenTTL:  600,
+		JwtPrivateKey:   privateKey,
+	}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser"}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	// tokens issued before sessions and the token_use claim existed
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":         "AuthConnect Hub",
+		"sub":         "testuser",
+		"remember_me": false,
+		"aud":         "users",
+		"exp":         time.Now().Add(time.Minute * 10).Unix(),
+		"nbf":         time.Now().Unix(),
+		"iat":         time.Now().Unix(),
+		"jti":         "legacy-access-jti",
+	}).SignedString(privateKey)
+	assert.NoError(t, err)
+	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":              "AuthConnect Hub",
+		"sub":              "testuser",
+		"aud":              "users",
+		"exp":              time.Now().Add(time.Hour).Unix(),
+		"nbf":              time.Now().Unix(),
+		"iat":              time.Now().Unix(),
+		"jti":              "legacy-jti",
+		"access_token_jti": "legacy-access-jti",
+	}).SignedString(privateKey)
+	assert.NoError(t, err)
+
+	mockAuthRepo.On("FindRefreshTokenRotation", "legacy-jti").Return(nil, nil)
+	mockAuthRepo.On("RotateRefreshToken", "legacy-jti", mock.Anything, 3600).Return(nil, nil)
+	mockAuthRepo.On("AddUserToken", "testuser", mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("AddFamilyToken", "legacy-jti", mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("SaveSession", mock.MatchedBy(func(s entity.Session) bool {
+		return s.ID == "legacy-jti" && s.Username == "testuser"
+	}), 3600).Return(nil)
+
+	newAccessToken, newRefreshToken, err := uc.CheckAndRefreshTokens(accessToken, refreshToken, mockConfig)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, newAccessToken)
+	assert.NotEmpty(t, newRefreshToken)
 }
 
 func TestAuthUseCase_CheckAndRefreshTokens_InvalidRefreshToken(t *testing.T) {
@@ -398,7 +748,7 @@ func TestAuthUseCase_CheckAndRefreshTokens_InvalidRefreshToken(t *testing.T) {
 		JwtPrivateKey:   privateKey,
 	}}
 
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	mockUser := entity.User{ID: 1, Username: "testuser"}
 
@@ -430,7 +780,7 @@ func TestAuthUseCase_CheckAndRefreshTokens_InvalidAccessTokenJti(t *testing.T) {
 		JwtPrivateKey:   privateKey,
 	}}
 
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	mockUser := entity.User{ID: 1, Username: "testuser"}
 
@@ -472,7 +822,7 @@ func TestAuthUseCase_RetrieveFieldFromJwtToken_ValidToken_RequiredValidation(t *
 	mockConfig := &config.Config{Authen: config.Authen{
 		JwtPrivateKey: privateKey,
 	}}
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	fieldValue, err := uc.RetrieveFieldFromJwtToken(tokenString, "username", true) // Required validation
 
@@ -494,7 +844,7 @@ func TestAuthUseCase_RetrieveFieldFromJwtToken_InvalidToken(t *testing.T) {
 	mockConfig := &config.Config{Authen: config.Authen{
 		JwtPrivateKey: privateKey,
 	}}
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	for _, token := range invalidTokens {
 		fieldValue, err := uc.RetrieveFieldFromJwtToken(token, "username", true) // Required validation
@@ -525,7 +875,7 @@ func TestAuthUseCase_RetrieveFieldFromJwtToken_MissingField(t *testing.T) {
 	mockConfig := &config.Config{Authen: config.Authen{
 		JwtPrivateKey: privateKey,
 	}}
-	uc := usecases.NewAuthUseCase(nil, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	fieldValue, err := uc.RetrieveFieldFromJwtToken(tokenString, "missing_field", true) // Required validation
 
@@ -552,7 +902,7 @@ func TestAuthUseCase_Logout_Success(t *testing.T) {
 	mockAuthRepo := repoMocks.NewIAuthRepo(t)
 	mockAuthRepo.On("BlacklistToken", mock.Anything, mock.Anything).Return(nil) // Successful blacklist
 
-	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, mockConfig)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, mockConfig)
 
 	mockUser := entity.User{ID: 1, Username: "testuser"}
 
@@ -574,3 +924,480 @@ func TestAuthUseCase_Logout_Success(t *testing.T) {
 	assert.NoError(t, err)
 	mockAuthRepo.AssertExpectations(t) // Ensure blacklist calls were made
 }
+
+func TestAuthUseCase_GenerateTokens_TrackTokenFails(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		RefreshTokenTTL: 3600,
+		AccessTokenTTL:  600,
+		JwtPrivateKey:   privateKey,
+	}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("AddUserToken", "testuser", mock.Anything, 600).Return(errors.New("redis error"))
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser"}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	tokens, err := uc.GenerateTokens(entity.User{Username: "testuser"}, mockConfig)
+
+	assert.EqualError(t, err, "redis error")
+	assert.Nil(t, tokens)
+}
+
+func TestAuthUseCase_RequestPasswordReset_Success(t *testing.T) {
+	mockConfig := &config.Config{
+		App:    config.App{BaseURL: "http://localhost:8080/"},
+		Authen: config.Authen{ResetPasswordTokenTTL: 900},
+	}
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "", "test@example.com").Return(&entity.User{Username: "testuser", Email: "test@example.com"}, nil)
+
+	var savedToken string
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("SaveResetPasswordToken", mock.Anything, "testuser", 900).Run(func(args mock.Arguments) {
+		savedToken = args.String(0)
+	}).Return(nil)
+
+	mockMailer := mailerMocks.NewMailer(t)
+	mockMailer.On("Send", mock.MatchedBy(func(msg mailer.Message) bool {
+		return msg.To == "test@example.com" &&
+			strings.Contains(msg.Body, "http://localhost:8080/v1/auth/reset-password?token="+savedToken) &&
+			strings.Contains(msg.Body, "15 minutes")
+	})).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, mockMailer, mockConfig)
+
+	err := uc.RequestPasswordReset("test@example.com", mockConfig)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, savedToken)
+}
+
+func TestAuthUseCase_RequestPasswordReset_UnknownEmail(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{ResetPasswordTokenTTL: 900}}
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "", "unknown@example.com").Return(nil, &entity.InvalidCredentialsError{})
+
+	// neither a token is stored nor a mail is sent
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockMailer := mailerMocks.NewMailer(t)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, mockMailer, mockConfig)
+
+	err := uc.RequestPasswordReset("unknown@example.com", mockConfig)
+
+	assert.NoError(t, err)
+}
+
+func TestAuthUseCase_RequestPasswordReset_SaveTokenFails(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{ResetPasswordTokenTTL: 900}}
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "", "test@example.com").Return(&entity.User{Username: "testuser", Email: "test@example.com"}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("SaveResetPasswordToken", mock.Anything, "testuser", 900).Return(errors.New("redis error"))
+
+	mockMailer := mailerMocks.NewMailer(t)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, mockMailer, mockConfig)
+
+	err := uc.RequestPasswordReset("test@example.com", mockConfig)
+
+	assert.EqualError(t, err, "redis error")
+}
+
+func TestAuthUseCase_ResetPassword_Success(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("ConsumeResetPasswordToken", "reset-token").Return("testuser", nil)
+	mockAuthRepo.On("BlacklistUserTokens", "testuser", 3600).Return(nil)
+	mockAuthRepo.On("DeleteUserSessions", "testuser").Return(nil)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Password: "old"}, nil)
+	mockUserUC.On("Update", mock.MatchedBy(func(u entity.User) bool {
+		return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("new-password")) == nil
+	})).Return(entity.User{}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	err := uc.ResetPassword("reset-token", "new-password", mockConfig)
+
+	assert.NoError(t, err)
+	mockUserUC.AssertExpectations(t)
+}
+
+func TestAuthUseCase_ResetPassword_InvalidToken(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("ConsumeResetPasswordToken", "used-token").Return("", nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, mockConfig)
+
+	err := uc.ResetPassword("used-token", "new-password", mockConfig)
+
+	assert.Equal(t, &entity.InvalidResetPasswordTokenError{}, err)
+}
+
+func TestAuthUseCase_ResetPassword_UserDeleted(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("ConsumeResetPasswordToken", "reset-token").Return("testuser", nil)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(nil, &entity.InvalidCredentialsError{})
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	err := uc.ResetPassword("reset-token", "new-password", mockConfig)
+
+	assert.Equal(t, &entity.InvalidResetPasswordTokenError{}, err)
+}
+
+func TestAuthUseCase_ResetPassword_UpdateFails(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("ConsumeResetPasswordToken", "reset-token").Return("testuser", nil)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser"}, nil)
+	mockUserUC.On("Update", mock.Anything).Return(entity.User{}, errors.New("database error"))
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	err := uc.ResetPassword("reset-token", "new-password", mockConfig)
+
+	assert.EqualError(t, err, "database error")
+}
+
+func newChangePasswordContext(t *testing.T, mockConfig *config.Config) *gin.Context {
+	mockContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	mockContext.Set("config", mockConfig)
+	mockContext.Set("username", "testuser")
+	mockContext.Request = &http.Request{Header: http.Header{}}
+	mockContext.Request.Header.Set(helper.AccessTokenHeader, "Bearer current-access-token")
+	mockContext.Request.Header.Set(helper.RefreshTokenHeader, "Bearer current-refresh-token")
+	return mockContext
+}
+
+func TestAuthUseCase_ChangePassword_Success(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+	mockContext := newChangePasswordContext(t, mockConfig)
+
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.DefaultCost)
+	assert.NoError(t, err)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Password: string(encryptedPassword)}, nil)
+	mockUserUC.On("Update", mock.MatchedBy(func(u entity.User) bool {
+		return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("new-password")) == nil
+	})).Return(entity.User{}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("BlacklistUserTokens", "testuser", 3600, "current-access-token", "current-refresh-token").Return(nil)
+	mockAuthRepo.On("DeleteUserSessions", "testuser", "").Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	err = uc.ChangePassword(mockContext, dto.ChangePasswordRequestBody{
+		CurrentPassword: "old-password",
+		Password:        "new-password",
+		ConfirmPassword: "new-password",
+	})
+
+	assert.NoError(t, err)
+	mockUserUC.AssertExpectations(t)
+}
+
+func TestAuthUseCase_ChangePassword_IncorrectCurrentPassword(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+	mockContext := newChangePasswordContext(t, mockConfig)
+
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.DefaultCost)
+	assert.NoError(t, err)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Password: string(encryptedPassword)}, nil)
+
+	// no token is revoked
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	err = uc.ChangePassword(mockContext, dto.ChangePasswordRequestBody{
+		CurrentPassword: "wrong-password",
+		Password:        "new-password",
+		ConfirmPassword: "new-password",
+	})
+
+	assert.Equal(t, &entity.IncorrectPasswordError{}, err)
+	mockUserUC.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestAuthUseCase_ChangePassword_UpdateFails(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+	mockContext := newChangePasswordContext(t, mockConfig)
+
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.DefaultCost)
+	assert.NoError(t, err)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Password: string(encryptedPassword)}, nil)
+	mockUserUC.On("Update", mock.Anything).Return(entity.User{}, errors.New("database error"))
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	err = uc.ChangePassword(mockContext, dto.ChangePasswordRequestBody{
+		CurrentPassword: "old-password",
+		Password:        "new-password",
+		ConfirmPassword: "new-password",
+	})
+
+	assert.EqualError(t, err, "database error")
+}
+
+func TestAuthUseCase_Login_EmailNotVerified(t *testing.T) {
+	requestBody := dto.LoginRequestBody{Username: "testuser", Password: "secret"}
+
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(requestBody.Password), bcrypt.DefaultCost)
+	assert.NoError(t, err)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Email: "test@example.com", Password: string(encryptedPassword)}, nil)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		RequireEmailVerification:  true,
+		EmailVerificationTokenTTL: 86400,
+		SecretKey:                 "secret",
+	}}
+
+	mockContext := newLoginContext(mockConfig)
+
+	// no token is issued, a new verification link is sent instead
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLoginUnlocked(mockAuthRepo, "testuser")
+	mockAuthRepo.On("ClearLoginFailures", "username", "testuser").Return(nil)
+	mockAuthRepo.On("MarkVerificationEmailSent", "testuser", 86400).Return(true, nil)
+	mockMailer := mailerMocks.NewMailer(t)
+	mockMailer.On("Send", mock.MatchedBy(func(msg mailer.Message) bool {
+		return msg.To == "test@example.com"
+	})).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, mockMailer, mockConfig)
+
+	tokens, err := uc.Login(mockContext, requestBody)
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.EmailNotVerifiedError{}, err)
+}
+
+func TestAuthUseCase_Login_EmailNotVerified_LinkStillValid(t *testing.T) {
+	requestBody := dto.LoginRequestBody{Username: "testuser", Password: "secret"}
+
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(requestBody.Password), bcrypt.DefaultCost)
+	assert.NoError(t, err)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Email: "test@example.com", Password: string(encryptedPassword)}, nil)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		RequireEmailVerification:  true,
+		EmailVerificationTokenTTL: 86400,
+		SecretKey:                 "secret",
+	}}
+
+	mockContext := newLoginContext(mockConfig)
+
+	// the link mailed before hasn't expired, so no other mail is sent
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLoginUnlocked(mockAuthRepo, "testuser")
+	mockAuthRepo.On("ClearLoginFailures", "username", "testuser").Return(nil)
+	mockAuthRepo.On("MarkVerificationEmailSent", "testuser", 86400).Return(false, nil)
+	mockMailer := mailerMocks.NewMailer(t)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, mockMailer, mockConfig)
+
+	tokens, err := uc.Login(mockContext, requestBody)
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.EmailNotVerifiedError{}, err)
+	mockMailer.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestAuthUseCase_Login_EmailVerificationNotRequired(t *testing.T) {
+	requestBody := dto.LoginRequestBody{Username: "testuser", Password: "secret"}
+
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(requestBody.Password), bcrypt.DefaultCost)
+	assert.NoError(t, err)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Password: string(encryptedPassword)}, nil)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		RefreshTokenTTL: 3600,
+		AccessTokenTTL:  600,
+		JwtPrivateKey:   privateKey,
+	}}
+
+	mockContext := newLoginContext(mockConfig)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLoginUnlocked(mockAuthRepo, "testuser")
+	mockAuthRepo.On("ClearLoginFailures", "username", "testuser").Return(nil)
+	mockAuthRepo.On("AddUserToken", "testuser", mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("AddFamilyToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("SaveSession", mock.Anything, 3600).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	tokens, err := uc.Login(mockContext, requestBody)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, tokens)
+}
+
+// sendVerificationEmail returns the token of the link mailed to the user
+func sendVerificationEmail(t *testing.T, user entity.User, mockConfig *config.Config) string {
+	var body string
+	mockMailer := mailerMocks.NewMailer(t)
+	mockMailer.On("Send", mock.MatchedBy(func(msg mailer.Message) bool {
+		return msg.To == user.Email && msg.Subject == "Verify your email address"
+	})).Run(func(args mock.Arguments) {
+		body = args.Get(0).(mailer.Message).Body
+	}).Return(nil)
+
+	// signing up always mails the link, even while an earlier one is still valid
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("MarkVerificationEmailSent", user.Username, mockConfig.Authen.EmailVerificationTokenTTL).Return(false, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, new(mocks.IUserUC), mockMailer, mockConfig)
+	err := uc.SendVerificationEmail(user, mockConfig)
+	assert.NoError(t, err)
+
+	prefix := strings.TrimRight(mockConfig.App.BaseURL, "/") + "/v1/auth/verify-email?token="
+	start := strings.Index(body, prefix)
+	assert.NotEqual(t, -1, start)
+	link := body[start+len(prefix):]
+	return link[:strings.Index(link, "\r\n")]
+}
+
+func TestAuthUseCase_VerifyEmail_Success(t *testing.T) {
+	mockConfig := &config.Config{
+		App:    config.App{BaseURL: "http://localhost:8080"},
+		Authen: config.Authen{EmailVerificationTokenTTL: 86400, SecretKey: "secret"},
+	}
+	user := entity.User{ID: 1, Username: "testuser", Email: "test@example.com"}
+	token := sendVerificationEmail(t, user, mockConfig)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&user, nil)
+	mockUserUC.On("Update", mock.MatchedBy(func(u entity.User) bool {
+		return u.EmailVerified && u.VerifiedAt != nil
+	})).Return(entity.User{}, nil)
+
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	err := uc.VerifyEmail(token, mockConfig)
+
+	assert.NoError(t, err)
+	mockUserUC.AssertExpectations(t)
+}
+
+func TestAuthUseCase_VerifyEmail_AlreadyVerified(t *testing.T) {
+	mockConfig := &config.Config{
+		App:    config.App{BaseURL: "http://localhost:8080"},
+		Authen: config.Authen{EmailVerificationTokenTTL: 86400, SecretKey: "secret"},
+	}
+	user := entity.User{ID: 1, Username: "testuser", Email: "test@example.com"}
+	token := sendVerificationEmail(t, user, mockConfig)
+
+	verifiedAt := time.Now()
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Email: "test@example.com", EmailVerified: true, VerifiedAt: &verifiedAt}, nil)
+
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	err := uc.VerifyEmail(token, mockConfig)
+
+	assert.NoError(t, err)
+	mockUserUC.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestAuthUseCase_VerifyEmail_EmailChanged(t *testing.T) {
+	mockConfig := &config.Config{
+		App:    config.App{BaseURL: "http://localhost:8080"},
+		Authen: config.Authen{EmailVerificationTokenTTL: 86400, SecretKey: "secret"},
+	}
+	token := sendVerificationEmail(t, entity.User{ID: 1, Username: "testuser", Email: "old@example.com"}, mockConfig)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Email: "new@example.com"}, nil)
+
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	err := uc.VerifyEmail(token, mockConfig)
+
+	assert.Equal(t, &entity.InvalidEmailVerificationTokenError{}, err)
+}
+
+func TestAuthUseCase_VerifyEmail_InvalidToken(t *testing.T) {
+	mockConfig := &config.Config{
+		App:    config.App{BaseURL: "http://localhost:8080"},
+		Authen: config.Authen{EmailVerificationTokenTTL: 86400, SecretKey: "secret"},
+	}
+	token := sendVerificationEmail(t, entity.User{ID: 1, Username: "testuser", Email: "test@example.com"}, mockConfig)
+
+	testCases := []struct {
+		name  string
+		token string
+		cfg   *config.Config
+	}{
+		{"Malformed", "not-a-token", mockConfig},
+		{"Tampered", token + "x", mockConfig},
+		{"OtherSecret", token, &config.Config{Authen: config.Authen{SecretKey: "other"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, new(mocks.IUserUC), nil, mockConfig)
+
+			err := uc.VerifyEmail(tc.token, tc.cfg)
+
+			assert.Equal(t, &entity.InvalidEmailVerificationTokenError{}, err)
+		})
+	}
+}
+
+func TestAuthUseCase_VerifyEmail_Expired(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{SecretKey: "secret"}}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "testuser",
+		"email": "test@example.com",
+		"aud":   "email-verification",
+		"exp":   time.Now().Add(-time.Minute).Unix(),
+	}).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, new(mocks.IUserUC), nil, mockConfig)
+
+	err = uc.VerifyEmail(token, mockConfig)
+
+	assert.Equal(t, &entity.InvalidEmailVerificationTokenError{}, err)
+}
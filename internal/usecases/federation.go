@@ -0,0 +1,343 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/pkg/oidcclient"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// federationCallbackPath is where upstream providers send the browser back to, it has to be registered with them
+const federationCallbackPath = "/v1/auth/federation/callback"
+
+// maxFederatedUsernameLength leaves room for the suffix added when the username is taken
+const maxFederatedUsernameLength = 32
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// newUpstreamProviders creates the clients of the upstream providers of the config. They don't
+// talk to the providers until the first sign in.
+func newUpstreamProviders(cfg *config.Config) map[string]*oidcclient.Provider {
+	redirectURL := strings.TrimSuffix(cfg.App.BaseURL, "/") + federationCallbackPath
+
+	providers := map[string]*oidcclient.Provider{}
+	for _, p := range cfg.Federation.Providers {
+		providers[p.ID] = oidcclient.New(p.Issuer, p.ClientID, p.ClientSecret, redirectURL, p.Scopes)
+	}
+	return providers
+}
+
+// BeginFederation starts a sign in at the upstream provider. It returns the url of the login page of the
+// provider and the state the browser has to present when it comes back. A link username links the
+// identity to that user instead of logging in with it.
+func (au *AuthUseCase) BeginFederation(ctx context.Context, providerID string, rememberMe bool, linkUsername string, cfg *config.Config) (string, string, error) {
+	provider, ok := au.upstreamProviders[providerID]
+	if !ok {
+		return "", "", &entity.UpstreamProviderNotFoundError{}
+	}
+
+	state, err := generateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := generateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	codeVerifier, err := generateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	err = au.authRepo.SaveFederationState(state, entity.FederationState{
+		Provider:     providerID,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		RememberMe:   rememberMe,
+		LinkUsername: linkUsername,
+	}, cfg.Federation.StateTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	sum := sha256.Sum256([]byte(codeVerifier))
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, base64.RawURLEncoding.EncodeToString(sum[:]))
+	if err != nil {
+		return "", "", err
+	}
+
+	return authURL, state, nil
+}
+
+// FinishFederation handles the browser coming back from the upstream provider. The browser state is
+// the state handed out to this browser by BeginFederation, so a sign in started elsewhere can't be
+// finished here. New identities get a user with the given role, unless their email is taken already.
+func (au *AuthUseCase) FinishFederation(ctx context.Context, req dto.FederationCallbackQuery, browserState string, username string, roleID uint, cfg *config.Config) (*dto.FederationResult, error) {
+	if req.State == "" || req.State != browserState {
+		return nil, &entity.InvalidFederationStateError{}
+	}
+
+	state, err := au.authRepo.ConsumeFederationState(req.State)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, &entity.InvalidFederationStateError{}
+	}
+
+	providerConfig := findUpstreamProvider(state.Provider, cfg)
+	provider, ok := au.upstreamProviders[state.Provider]
+	if !ok || providerConfig == nil {
+		return nil, &entity.UpstreamProviderNotFoundError{}
+	}
+
+	if req.Error != "" {
+		return nil, &entity.FederationFailedError{Reason: strings.TrimSpace(req.Error + " " + req.ErrorDescription)}
+	}
+
+	identity, err := provider.Exchange(ctx, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		return nil, &entity.FederationFailedError{Reason: err.Error()}
+	}
+
+	linkedIdentity, err := au.linkedIdentityRepo.FindLinkedIdentity(identity.Issuer, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.LinkUsername != "" {
+		// the user who started linking has to be the one who finishes it
+		if state.LinkUsername != username {
+			return nil, &entity.InvalidFederationStateError{}
+		}
+		if err := au.linkIdentity(username, providerConfig, identity, linkedIdentity); err != nil {
+			return nil, err
+		}
+		return &dto.FederationResult{Provider: providerConfig.Name, Linked: true}, nil
+	}
+
+	var user entity.User
+	if linkedIdentity != nil {
+		// the user of the identity isn't loaded when it has been deleted, disabled users get
+		// neither mail nor a second factor prompt
+		user = linkedIdentity.User
+		if user.ID == 0 {
+			return nil, &entity.InvalidCredentialsError{}
+		}
+		if user.Disabled {
+			return nil, &entity.UserDisabledError{}
+		}
+		if err := au.linkedIdentityRepo.UpdateLinkedIdentityUsage(linkedIdentity.ID); err != nil {
+			return nil, err
+		}
+	} else {
+		user, err = au.createFederatedUser(providerConfig, identity, roleID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	user.RememberMe = state.RememberMe
+
+	if cfg.Authen.RequireEmailVerification && !user.EmailVerified {
+		if err := au.resendVerificationEmail(user, cfg); err != nil {
+			return nil, err
+		}
+		return nil, &entity.EmailNotVerifiedError{}
+	}
+
+	result := &dto.FederationResult{Provider: providerConfig.Name, RememberMe: state.RememberMe}
+
+	// the provider stands in for the password, the second factor is still asked for
+	if user.TOTPEnabled {
+		pendingToken, err := generateRandomToken()
+		if err != nil {
+			return nil, err
+		}
+
+		err = au.authRepo.SaveMFAPendingToken(pendingToken, user.Username, cfg.Authen.MFAPendingTokenTTL)
+		if err != nil {
+			return nil, err
+		}
+		result.MFAToken = pendingToken
+		return result, nil
+	}
+
+	result.Tokens, err = au.GenerateTokens(user, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (au *AuthUseCase) linkIdentity(username string, provider *config.UpstreamProvider, identity *oidcclient.Identity, linkedIdentity *entity.LinkedIdentity) error {
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return err
+	}
+
+	if linkedIdentity != nil {
+		if linkedIdentity.UserID == user.ID {
+			return nil
+		}
+		return &entity.IdentityAlreadyLinkedError{}
+	}
+
+	_, err = au.linkedIdentityRepo.CreateLinkedIdentity(entity.LinkedIdentity{
+		UserID:   user.ID,
+		Provider: provider.ID,
+		Issuer:   identity.Issuer,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	return err
+}
+
+// createFederatedUser creates the user of an identity which signs in for the first time. The user
+// gets a random password, a password of their own can be set with the forgotten password link.
+func (au *AuthUseCase) createFederatedUser(provider *config.UpstreamProvider, identity *oidcclient.Identity, roleID uint) (entity.User, error) {
+	if identity.Email == "" || !identity.EmailVerified {
+		return entity.User{}, &entity.FederatedEmailRequiredError{}
+	}
+
+	_, err := au.userUseCase.FindByUsernameOrEmail("", identity.Email)
+	if err == nil {
+		return entity.User{}, &entity.FederatedEmailTakenError{}
+	}
+	if !helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+		return entity.User{}, err
+	}
+
+	username, err := au.availableUsername(identity)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	passwordHash, err := randomPasswordHash()
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	// the provider has verified the email already
+	verifiedAt := time.Now()
+	user, err := au.userUseCase.Create(entity.User{
+		Username:      username,
+		Email:         identity.Email,
+		Password:      passwordHash,
+		RoleID:        roleID,
+		EmailVerified: true,
+		VerifiedAt:    &verifiedAt,
+	})
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	_, err = au.linkedIdentityRepo.CreateLinkedIdentity(entity.LinkedIdentity{
+		UserID:   user.ID,
+		Provider: provider.ID,
+		Issuer:   identity.Issuer,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return user, nil
+}
+
+// randomPasswordHash hashes a password nobody knows, for users who sign in elsewhere
+func randomPasswordHash() (string, error) {
+	password, err := generateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(passwordHash), nil
+}
+
+// availableUsername derives a username from the preferred username or the email of the identity,
+// a random suffix is added while it is taken
+func (au *AuthUseCase) availableUsername(identity *oidcclient.Identity) (string, error) {
+	base := identity.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(identity.Email, "@")
+	}
+	base = strings.Trim(usernameInvalidChars.ReplaceAllString(strings.ToLower(base), "-"), "-")
+	if len(base) > maxFederatedUsernameLength {
+		base = base[:maxFederatedUsernameLength]
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	username := base
+	for i := 0; i < 5; i++ {
+		_, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+		if helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+			return username, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", fmt.Errorf("failed to generate username: %w", err)
+		}
+		username = base + "-" + hex.EncodeToString(suffix)
+	}
+
+	return "", fmt.Errorf("no username available for %q", base)
+}
+
+// ListLinkedIdentities returns the identities of upstream providers the user can sign in with
+func (au *AuthUseCase) ListLinkedIdentities(username string) ([]entity.LinkedIdentity, error) {
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return au.linkedIdentityRepo.FindLinkedIdentities(user.ID)
+}
+
+func (au *AuthUseCase) UnlinkIdentity(username string, id uint) error {
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return err
+	}
+
+	deleted, err := au.linkedIdentityRepo.DeleteLinkedIdentity(user.ID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &entity.LinkedIdentityNotFoundError{}
+	}
+
+	return nil
+}
+
+func findUpstreamProvider(id string, cfg *config.Config) *config.UpstreamProvider {
+	for i := range cfg.Federation.Providers {
+		if cfg.Federation.Providers[i].ID == id {
+			return &cfg.Federation.Providers[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+package usecases_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"regexp"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/minhmannh2001/authconnecthub/pkg/oidcclient/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newFederationConfig(t *testing.T, issuer *oidctest.Issuer) *config.Config {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	return &config.Config{
+		App: config.App{BaseURL: "https://hub.home"},
+		Authen: config.Authen{
+			JwtPrivateKey:      privateKey,
+			AccessTokenTTL:     600,
+			RefreshTokenTTL:    3600,
+			MFAPendingTokenTTL: 300,
+		},
+		Federation: config.Federation{
+			StateTTL: 600,
+			Providers: []config.UpstreamProvider{
+				{ID: "keycloak", Name: "Keycloak", Issuer: issuer.URL, ClientID: "hub", ClientSecret: "hub-secret"},
+			},
+		},
+	}
+}
+
+// signInUpstream starts a sign in, lets the issuer sign in whoever the claims say and returns how
+// the browser comes back
+func signInUpstream(t *testing.T, uc *usecases.AuthUseCase, mockAuthRepo *repoMocks.IAuthRepo, issuer *oidctest.Issuer, claims jwt.MapClaims, linkUsername string, cfg *config.Config) dto.FederationCallbackQuery {
+	var saved entity.FederationState
+	mockAuthRepo.On("SaveFederationState", mock.Anything, mock.Anything, 600).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(entity.FederationState)
+	}).Return(nil).Once()
+
+	authURL, state, err := uc.BeginFederation(context.Background(), "keycloak", true, linkUsername, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "keycloak", saved.Provider)
+	assert.Equal(t, linkUsername, saved.LinkUsername)
+	mockAuthRepo.On("ConsumeFederationState", state).Return(&saved, nil).Once()
+
+	code, returnedState, err := issuer.Login(authURL, claims)
+	assert.NoError(t, err)
+	return dto.FederationCallbackQuery{Code: code, State: returnedState}
+}
+
+func mockSessionTokens(mockAuthRepo *repoMocks.IAuthRepo, mockUserUC *mocks.IUserUC, username string) {
+	mockUserUC.On("FindByUsernameOrEmail", username, "").Return(&entity.User{Username: username, Role: entity.Role{Name: "customer"}}, nil).Once()
+	mockAuthRepo.On("AddUserToken", username, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("AddFamilyToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("SaveSession", mock.Anything, mock.Anything).Return(nil).Once()
+}
+
+func TestAuthUseCase_BeginFederation_UnknownProvider(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	cfg := newFederationConfig(t, issuer)
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, mocks.NewIUserUC(t), nil, cfg)
+
+	_, _, err := uc.BeginFederation(context.Background(), "github", false, "", cfg)
+
+	assert.True(t, helper.IsErrOfType(err, &entity.UpstreamProviderNotFoundError{}))
+}
+
+func TestAuthUseCase_FinishFederation_NewUser(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	cfg := newFederationConfig(t, issuer)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, mockLinkedIdentityRepo, nil, mockUserUC, nil, cfg)
+
+	query := signInUpstream(t, uc, mockAuthRepo, issuer, jwt.MapClaims{
+		"sub":                "u-42",
+		"email":              "anna@example.com",
+		"email_verified":     true,
+		"preferred_username": "Anna Smith",
+	}, "", cfg)
+
+	mockLinkedIdentityRepo.On("FindLinkedIdentity", issuer.URL, "u-42").Return(nil, nil)
+	mockUserUC.On("FindByUsernameOrEmail", "", "anna@example.com").Return(nil, &entity.InvalidCredentialsError{})
+	// the username is taken, so a suffix is added
+	mockUserUC.On("FindByUsernameOrEmail", "anna-smith", "").Return(&entity.User{ID: 3, Username: "anna-smith"}, nil)
+	mockUserUC.On("FindByUsernameOrEmail", mock.MatchedBy(regexp.MustCompile(`^anna-smith-[0-9a-f]{6}$`).MatchString), "").Return(nil, &entity.InvalidCredentialsError{}).Once()
+	// the role of the new user is looked up for its tokens
+	mockUserUC.On("FindByUsernameOrEmail", mock.MatchedBy(regexp.MustCompile(`^anna-smith-[0-9a-f]{6}$`).MatchString), "").Return(func(username string, email string) (*entity.User, error) {
+		return &entity.User{ID: 9, Username: username, Role: entity.Role{Name: "customer"}}, nil
+	}).Once()
+
+	var created entity.User
+	mockUserUC.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(0).(entity.User)
+	}).Return(func(user entity.User) (entity.User, error) {
+		user.ID = 9
+		return user, nil
+	})
+	mockLinkedIdentityRepo.On("CreateLinkedIdentity", mock.MatchedBy(func(identity entity.LinkedIdentity) bool {
+		return identity.UserID == 9 && identity.Provider == "keycloak" && identity.Issuer == issuer.URL && identity.Subject == "u-42"
+	})).Return(entity.LinkedIdentity{}, nil)
+	mockAuthRepo.On("AddUserToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("AddFamilyToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("SaveSession", mock.Anything, mock.Anything).Return(nil).Once()
+
+	result, err := uc.FinishFederation(context.Background(), query, query.State, "", 3, cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Keycloak", result.Provider)
+	assert.True(t, result.RememberMe)
+	assert.NotNil(t, result.Tokens)
+	assert.Regexp(t, `^anna-smith-[0-9a-f]{6}$`, created.Username)
+	assert.Equal(t, "anna@example.com", created.Email)
+	assert.Equal(t, uint(3), created.RoleID)
+	assert.True(t, created.EmailVerified)
+	assert.NotEmpty(t, created.Password)
+}
+
+func TestAuthUseCase_FinishFederation_LinkedUser(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	cfg := newFederationConfig(t, issuer)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, mockLinkedIdentityRepo, nil, mockUserUC, nil, cfg)
+
+	query := signInUpstream(t, uc, mockAuthRepo, issuer, jwt.MapClaims{"sub": "u-42"}, "", cfg)
+
+	linkedIdentity := &entity.LinkedIdentity{UserID: 3, User: entity.User{ID: 3, Username: "anna"}}
+	linkedIdentity.ID = 5
+	mockLinkedIdentityRepo.On("FindLinkedIdentity", issuer.URL, "u-42").Return(linkedIdentity, nil)
+	mockLinkedIdentityRepo.On("UpdateLinkedIdentityUsage", uint(5)).Return(nil)
+	mockSessionTokens(mockAuthRepo, mockUserUC, "anna")
+
+	result, err := uc.FinishFederation(context.Background(), query, query.State, "", 3, cfg)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result.Tokens)
+
+	username, err := uc.ValidateToken(result.Tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "anna", username)
+}
+
+func TestAuthUseCase_FinishFederation_MFARequired(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	cfg := newFederationConfig(t, issuer)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, mockLinkedIdentityRepo, nil, mocks.NewIUserUC(t), nil, cfg)
+
+	query := signInUpstream(t, uc, mockAuthRepo, issuer, jwt.MapClaims{"sub": "u-42"}, "", cfg)
+
+	linkedIdentity := &entity.LinkedIdentity{UserID: 3, User: entity.User{ID: 3, Username: "anna", TOTPEnabled: true}}
+	mockLinkedIdentityRepo.On("FindLinkedIdentity", issuer.URL, "u-42").Return(linkedIdentity, nil)
+	mockLinkedIdentityRepo.On("UpdateLinkedIdentityUsage", mock.Anything).Return(nil)
+	var pendingToken string
+	mockAuthRepo.On("SaveMFAPendingToken", mock.Anything, "anna", 300).Run(func(args mock.Arguments) {
+		pendingToken = args.String(0)
+	}).Return(nil)
+
+	result, err := uc.FinishFederation(context.Background(), query, query.State, "", 3, cfg)
+
+	assert.NoError(t, err)
+	assert.Nil(t, result.Tokens)
+	assert.NotEmpty(t, pendingToken)
+	assert.Equal(t, pendingToken, result.MFAToken)
+}
+
+func TestAuthUseCase_FinishFederation_LinkedUserRejected(t *testing.T) {
+	deleted := entity.User{}
+	disabled := entity.User{ID: 3, Username: "anna", TOTPEnabled: true, Disabled: true}
+
+	cases := []struct {
+		name string
+		user entity.User
+		err  error
+	}{
+		{"deleted user", deleted, &entity.InvalidCredentialsError{}},
+		{"disabled user", disabled, &entity.UserDisabledError{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+			cfg := newFederationConfig(t, issuer)
+			cfg.Authen.RequireEmailVerification = true
+			mockAuthRepo := repoMocks.NewIAuthRepo(t)
+			mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+			uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, mockLinkedIdentityRepo, nil, mocks.NewIUserUC(t), nil, cfg)
+
+			query := signInUpstream(t, uc, mockAuthRepo, issuer, jwt.MapClaims{"sub": "u-42"}, "", cfg)
+
+			// soft deleted users aren't preloaded with the identity
+			mockLinkedIdentityRepo.On("FindLinkedIdentity", issuer.URL, "u-42").Return(&entity.LinkedIdentity{UserID: 3, User: tc.user}, nil)
+
+			result, err := uc.FinishFederation(context.Background(), query, query.State, "", 3, cfg)
+
+			assert.Nil(t, result)
+			assert.Equal(t, tc.err, err)
+			mockAuthRepo.AssertNotCalled(t, "SaveMFAPendingToken", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthUseCase_FinishFederation_NewUserRejected(t *testing.T) {
+	cases := []struct {
+		name          string
+		claims        jwt.MapClaims
+		emailTaken    bool
+		expectedError entity.CustomErrorType
+	}{
+		{
+			name:          "No email",
+			claims:        jwt.MapClaims{"sub": "u-42"},
+			expectedError: &entity.FederatedEmailRequiredError{},
+		},
+		{
+			name:          "Unverified email",
+			claims:        jwt.MapClaims{"sub": "u-42", "email": "anna@example.com", "email_verified": false},
+			expectedError: &entity.FederatedEmailRequiredError{},
+		},
+		{
+			name:          "Email of another user",
+			claims:        jwt.MapClaims{"sub": "u-42", "email": "anna@example.com", "email_verified": true},
+			emailTaken:    true,
+			expectedError: &entity.FederatedEmailTakenError{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+			cfg := newFederationConfig(t, issuer)
+			mockAuthRepo := repoMocks.NewIAuthRepo(t)
+			mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+			mockUserUC := mocks.NewIUserUC(t)
+			uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, mockLinkedIdentityRepo, nil, mockUserUC, nil, cfg)
+
+			query := signInUpstream(t, uc, mockAuthRepo, issuer, tc.claims, "", cfg)
+			mockLinkedIdentityRepo.On("FindLinkedIdentity", issuer.URL, "u-42").Return(nil, nil)
+			if tc.emailTaken {
+				mockUserUC.On("FindByUsernameOrEmail", "", "anna@example.com").Return(&entity.User{ID: 3, Username: "anna"}, nil)
+			}
+
+			_, err := uc.FinishFederation(context.Background(), query, query.State, "", 3, cfg)
+
+			assert.True(t, helper.IsErrOfType(err, tc.expectedError), err)
+		})
+	}
+}
+
+func TestAuthUseCase_FinishFederation_InvalidState(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	cfg := newFederationConfig(t, issuer)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("ConsumeFederationState", "expired-state").Return(nil, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mocks.NewIUserUC(t), nil, cfg)
+
+	// the browser has to be the one the sign in was started in
+	_, err := uc.FinishFederation(context.Background(), dto.FederationCallbackQuery{Code: "code", State: "state"}, "another-state", "", 3, cfg)
+	assert.True(t, helper.IsErrOfType(err, &entity.InvalidFederationStateError{}))
+
+	_, err = uc.FinishFederation(context.Background(), dto.FederationCallbackQuery{Code: "code", State: "expired-state"}, "expired-state", "", 3, cfg)
+	assert.True(t, helper.IsErrOfType(err, &entity.InvalidFederationStateError{}))
+}
+
+func TestAuthUseCase_FinishFederation_ProviderError(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	cfg := newFederationConfig(t, issuer)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mocks.NewIUserUC(t), nil, cfg)
+
+	query := signInUpstream(t, uc, mockAuthRepo, issuer, jwt.MapClaims{"sub": "u-42"}, "", cfg)
+	query.Code = ""
+	query.Error = "access_denied"
+
+	_, err := uc.FinishFederation(context.Background(), query, query.State, "", 3, cfg)
+
+	var federationFailedErr *entity.FederationFailedError
+	assert.ErrorAs(t, err, &federationFailedErr)
+	assert.Equal(t, "access_denied", federationFailedErr.Reason)
+}
+
+func TestAuthUseCase_FinishFederation_Link(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	cfg := newFederationConfig(t, issuer)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "anna", "").Return(&entity.User{ID: 3, Username: "anna"}, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, mockLinkedIdentityRepo, nil, mockUserUC, nil, cfg)
+
+	query := signInUpstream(t, uc, mockAuthRepo, issuer, jwt.MapClaims{"sub": "u-42", "email": "anna@work.example.com"}, "anna", cfg)
+	mockLinkedIdentityRepo.On("FindLinkedIdentity", issuer.URL, "u-42").Return(nil, nil)
+	mockLinkedIdentityRepo.On("CreateLinkedIdentity", entity.LinkedIdentity{
+		UserID:   3,
+		Provider: "keycloak",
+		Issuer:   issuer.URL,
+		Subject:  "u-42",
+		Email:    "anna@work.example.com",
+	}).Return(entity.LinkedIdentity{}, nil)
+
+	result, err := uc.FinishFederation(context.Background(), query, query.State, "anna", 3, cfg)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Linked)
+	assert.Nil(t, result.Tokens)
+}
+
+func TestAuthUseCase_FinishFederation_LinkRejected(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	cfg := newFederationConfig(t, issuer)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "anna", "").Return(&entity.User{ID: 3, Username: "anna"}, nil)
+	mockLinkedIdentityRepo.On("FindLinkedIdentity", issuer.URL, "u-42").Return(&entity.LinkedIdentity{UserID: 4}, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, mockLinkedIdentityRepo, nil, mockUserUC, nil, cfg)
+
+	// the identity belongs to another user already
+	query := signInUpstream(t, uc, mockAuthRepo, issuer, jwt.MapClaims{"sub": "u-42"}, "anna", cfg)
+	_, err := uc.FinishFederation(context.Background(), query, query.State, "anna", 3, cfg)
+	assert.True(t, helper.IsErrOfType(err, &entity.IdentityAlreadyLinkedError{}))
+
+	// someone else is logged in by the time the browser comes back
+	query = signInUpstream(t, uc, mockAuthRepo, issuer, jwt.MapClaims{"sub": "u-42"}, "anna", cfg)
+	_, err = uc.FinishFederation(context.Background(), query, query.State, "bob", 3, cfg)
+	assert.True(t, helper.IsErrOfType(err, &entity.InvalidFederationStateError{}))
+}
+
+func TestAuthUseCase_UnlinkIdentity_NotFound(t *testing.T) {
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "anna", "").Return(&entity.User{ID: 3, Username: "anna"}, nil)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+	mockLinkedIdentityRepo.On("DeleteLinkedIdentity", uint(3), uint(5)).Return(false, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, mockLinkedIdentityRepo, nil, mockUserUC, nil, &config.Config{})
+
+	err := uc.UnlinkIdentity("anna", 5)
+
+	assert.True(t, helper.IsErrOfType(err, &entity.LinkedIdentityNotFoundError{}))
+}
@@ -0,0 +1,133 @@
+package usecases
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// ForwardAuth decides whether a reverse proxy lets a request through, by the first rule matching its host
+// and path. Expired tokens of cookies are refreshed and the proxy hands the new ones to the browser,
+// tokens of headers belong to clients which refresh them on their own.
+func (au *AuthUseCase) ForwardAuth(req dto.ForwardAuthRequest, cfg *config.Config) (*dto.ForwardAuthResult, error) {
+	rule := helper.MatchForwardAuthRule(cfg.ForwardAuth.Rules, req.Host, req.Path)
+	if rule == nil {
+		return nil, &entity.AccessDeniedError{}
+	}
+
+	switch rule.Policy {
+	case entity.ForwardAuthPolicyBypass:
+		return &dto.ForwardAuthResult{}, nil
+	case entity.ForwardAuthPolicyAuthenticated:
+	default:
+		// unknown policies are denied, a typo mustn't open an app up
+		return nil, &entity.AccessDeniedError{}
+	}
+
+	if req.AccessToken == "" {
+		return nil, &entity.LoginRequiredError{}
+	}
+
+	blacklisted, err := au.authRepo.IsTokenBlacklisted(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	if blacklisted {
+		return nil, &entity.LoginRequiredError{}
+	}
+
+	result := &dto.ForwardAuthResult{}
+	username, err := au.ValidateToken(req.AccessToken)
+	if err != nil {
+		if !helper.IsTokenExpired(err) || !req.FromCookie || req.RefreshToken == "" {
+			return nil, &entity.LoginRequiredError{}
+		}
+
+		result.Tokens, err = au.refreshForwardAuthTokens(req, cfg)
+		if err != nil {
+			return nil, err
+		}
+		username, err = au.ValidateToken(result.Tokens.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		rememberMe, _ := au.RetrieveFieldFromJwtToken(result.Tokens.AccessToken, "remember_me", true)
+		result.RememberMe = rememberMe == true
+	}
+
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		// the user is gone, their tokens are worthless
+		if helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+			return nil, &entity.LoginRequiredError{}
+		}
+		return nil, err
+	}
+
+	if len(rule.Roles) > 0 && !slices.Contains(rule.Roles, user.Role.Name) {
+		return nil, &entity.AccessDeniedError{}
+	}
+
+	// the grants of the application served on the host apply on top of the rule
+	appRole, ok, err := au.hostAccess(req.Host, user)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &entity.AccessDeniedError{}
+	}
+	result.AppRole = appRole
+
+	result.Username = user.Username
+	result.Email = user.Email
+	if user.Role.Name != "" {
+		result.Groups = []string{user.Role.Name}
+	}
+	return result, nil
+}
+
+// hostAccess tells if the user may use the application served on the host and with which of its roles.
+// Hosts of no application are up to the rules alone.
+func (au *AuthUseCase) hostAccess(host string, user *entity.User) (string, bool, error) {
+	applications, err := au.applicationRepo.FindApplications()
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, application := range applications {
+		if !helper.IsURLHost(application.URL, host) {
+			continue
+		}
+
+		grants, err := au.applicationRepo.FindApplicationGrantsByApplicationID(application.ID)
+		if err != nil {
+			return "", false, err
+		}
+		appRole, ok := applicationAccess(strings.Fields(application.AllowedRoles), grants, user)
+		return appRole, ok, nil
+	}
+
+	return "", true, nil
+}
+
+func (au *AuthUseCase) refreshForwardAuthTokens(req dto.ForwardAuthRequest, cfg *config.Config) (*dto.JwtTokens, error) {
+	blacklisted, err := au.authRepo.IsTokenBlacklisted(req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if blacklisted {
+		return nil, &entity.LoginRequiredError{}
+	}
+
+	accessToken, refreshToken, err := au.CheckAndRefreshTokens(req.AccessToken, req.RefreshToken, cfg)
+	// like IsLoggedIn, a session which can't be refreshed is over, a reused refresh token has revoked its family already
+	if err != nil || accessToken == "" {
+		return nil, &entity.LoginRequiredError{}
+	}
+
+	return &dto.JwtTokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
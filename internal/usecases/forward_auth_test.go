@@ -0,0 +1,134 @@
+package usecases_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func newForwardAuthConfig(t *testing.T) *config.Config {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	return &config.Config{
+		Authen: config.Authen{JwtPrivateKey: privateKey},
+		ForwardAuth: config.ForwardAuth{Rules: []config.ForwardAuthRule{
+			{Host: "photos.home.lan", Path: "/share/", Policy: entity.ForwardAuthPolicyBypass},
+			{Host: "grafana.home.lan", Policy: entity.ForwardAuthPolicyAuthenticated, Roles: []string{"admin"}},
+			{Host: "backup.home.lan", Policy: entity.ForwardAuthPolicyDeny},
+			{Host: "*.home.lan", Policy: entity.ForwardAuthPolicyAuthenticated},
+		}},
+	}
+}
+
+func TestAuthUseCase_ForwardAuth_Bypass(t *testing.T) {
+	cfg := newForwardAuthConfig(t)
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, nil, nil, cfg)
+
+	result, err := uc.ForwardAuth(dto.ForwardAuthRequest{Host: "photos.home.lan", Path: "/share/album"}, cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.ForwardAuthResult{}, result)
+}
+
+func TestAuthUseCase_ForwardAuth_Denied(t *testing.T) {
+	cfg := newForwardAuthConfig(t)
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, nil, nil, cfg)
+
+	_, err := uc.ForwardAuth(dto.ForwardAuthRequest{Host: "backup.home.lan", Path: "/"}, cfg)
+	assert.True(t, helper.IsErrOfType(err, &entity.AccessDeniedError{}))
+
+	// hosts no rule covers are denied as well
+	_, err = uc.ForwardAuth(dto.ForwardAuthRequest{Host: "evil.com", Path: "/"}, cfg)
+	assert.True(t, helper.IsErrOfType(err, &entity.AccessDeniedError{}))
+}
+
+func TestAuthUseCase_ForwardAuth_LoginRequired(t *testing.T) {
+	cfg := newForwardAuthConfig(t)
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, nil, nil, cfg)
+
+	_, err := uc.ForwardAuth(dto.ForwardAuthRequest{Host: "wiki.home.lan", Path: "/"}, cfg)
+
+	assert.True(t, helper.IsErrOfType(err, &entity.LoginRequiredError{}))
+}
+
+func TestAuthUseCase_ForwardAuth_Authenticated(t *testing.T) {
+	cfg := newForwardAuthConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, mockUserUC, nil, cfg)
+
+	accessToken, err := uc.CreateAccessToken(entity.User{Username: "testuser"}, 600)
+	assert.NoError(t, err)
+	mockAuthRepo.On("IsTokenBlacklisted", accessToken).Return(false, nil)
+	mockApplicationRepo.On("FindApplications").Return([]entity.Application{}, nil)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{
+		Username: "testuser",
+		Email:    "testuser@home.lan",
+		Role:     entity.Role{Name: "customer"},
+	}, nil)
+
+	result, err := uc.ForwardAuth(dto.ForwardAuthRequest{Host: "wiki.home.lan", Path: "/", AccessToken: accessToken}, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.ForwardAuthResult{Username: "testuser", Email: "testuser@home.lan", Groups: []string{"customer"}}, result)
+
+	// grafana is for admins only
+	_, err = uc.ForwardAuth(dto.ForwardAuthRequest{Host: "grafana.home.lan", Path: "/", AccessToken: accessToken}, cfg)
+	assert.True(t, helper.IsErrOfType(err, &entity.AccessDeniedError{}))
+}
+
+func TestAuthUseCase_ForwardAuth_Blacklisted(t *testing.T) {
+	cfg := newForwardAuthConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, cfg)
+
+	accessToken, err := uc.CreateAccessToken(entity.User{Username: "testuser"}, 600)
+	assert.NoError(t, err)
+	mockAuthRepo.On("IsTokenBlacklisted", accessToken).Return(true, nil)
+
+	_, err = uc.ForwardAuth(dto.ForwardAuthRequest{Host: "wiki.home.lan", Path: "/", AccessToken: accessToken}, cfg)
+
+	assert.True(t, helper.IsErrOfType(err, &entity.LoginRequiredError{}))
+}
+
+func TestAuthUseCase_ForwardAuth_ApplicationGrants(t *testing.T) {
+	cfg := newForwardAuthConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, mockUserUC, nil, cfg)
+
+	accessToken, err := uc.CreateAccessToken(entity.User{Username: "testuser"}, 600)
+	assert.NoError(t, err)
+	mockAuthRepo.On("IsTokenBlacklisted", accessToken).Return(false, nil)
+	mockApplicationRepo.On("FindApplications").Return([]entity.Application{
+		{Model: gorm.Model{ID: 1}, ClientID: "wiki", URL: "https://wiki.home.lan"},
+		{Model: gorm.Model{ID: 2}, ClientID: "notes", URL: "https://notes.home.lan/app"},
+	}, nil)
+	mockApplicationRepo.On("FindApplicationGrantsByApplicationID", uint(1)).Return([]entity.ApplicationGrant{
+		{ApplicationID: 1, SubjectType: entity.GrantSubjectRole, Subject: "customer", AppRole: entity.AppRoleEditor},
+	}, nil)
+	mockApplicationRepo.On("FindApplicationGrantsByApplicationID", uint(2)).Return([]entity.ApplicationGrant{
+		{ApplicationID: 2, SubjectType: entity.GrantSubjectUser, Subject: "someone", AppRole: entity.AppRoleViewer},
+	}, nil)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser", Role: entity.Role{Name: "customer"}}, nil)
+
+	result, err := uc.ForwardAuth(dto.ForwardAuthRequest{Host: "wiki.home.lan", Path: "/", AccessToken: accessToken}, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.AppRoleEditor, result.AppRole)
+
+	// the rule lets every user in, the grants of the application don't cover testuser
+	_, err = uc.ForwardAuth(dto.ForwardAuthRequest{Host: "notes.home.lan", Path: "/", AccessToken: accessToken}, cfg)
+	assert.True(t, helper.IsErrOfType(err, &entity.AccessDeniedError{}))
+}
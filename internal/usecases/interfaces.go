@@ -1,6 +1,8 @@
 package usecases
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"github.com/minhmannh2001/authconnecthub/config"
 	"github.com/minhmannh2001/authconnecthub/internal/dto"
@@ -19,14 +21,85 @@ type (
 		CheckAndRefreshTokens(string, string, *config.Config) (string, string, error)
 		Logout(c *gin.Context) error
 		IsTokenBlacklisted(string) (bool, error)
+		GenerateTokens(entity.User, *config.Config) (*dto.JwtTokens, error)
+		RequestPasswordReset(string, *config.Config) error
+		ResetPassword(string, string, *config.Config) error
+		ChangePassword(*gin.Context, dto.ChangePasswordRequestBody) error
+		SendVerificationEmail(entity.User, *config.Config) error
+		VerifyEmail(string, *config.Config) error
+		EnrollTOTP(string, *config.Config) (*dto.TOTPEnrollment, error)
+		ConfirmTOTP(string, string) ([]string, error)
+		DisableTOTP(string, string) error
+		VerifyMFALogin(dto.MFALoginRequestBody, string, *config.Config) (*dto.JwtTokens, error)
+		RegenerateRecoveryCodes(string, string) ([]string, error)
+		CountRecoveryCodes(string) (int64, error)
+		BeginPasskeyRegistration(string, *config.Config) (*dto.PasskeyCeremony, error)
+		FinishPasskeyRegistration(string, dto.PasskeyRegistrationRequestBody, *config.Config) error
+		BeginPasskeyLogin(*config.Config) (*dto.PasskeyCeremony, error)
+		FinishPasskeyLogin(dto.PasskeyLoginRequestBody, *config.Config) (*dto.JwtTokens, error)
+		ListPasskeys(string) ([]entity.WebAuthnCredential, error)
+		DeletePasskey(string, uint) error
+		ListSessions(string) ([]entity.Session, error)
+		RevokeSession(string, string, *config.Config) error
+		LogoutEverywhere(string, *config.Config) error
+		TouchSession(string, string, string) error
+		ListLoginLocks() ([]entity.LoginLock, error)
+		ClearLoginLock(string, string) error
+		Authorize(dto.AuthorizeRequest, string, *config.Config) (string, error)
+		ExchangeAuthorizationCode(dto.TokenRequestBody, *config.Config) (*dto.OIDCTokens, error)
+		UserInfo(string) (map[string]interface{}, error)
+		OpenIDConfiguration(*config.Config) dto.OpenIDConfiguration
+		CreateServiceAccount(dto.ServiceAccountCreateRequestBody) (*dto.ServiceAccountCredentials, error)
+		ListServiceAccounts() ([]entity.ServiceAccount, error)
+		DeleteServiceAccount(string) error
+		CreateApplication(dto.ApplicationCreateRequestBody, *config.Config) (*dto.ApplicationCredentials, error)
+		ListApplications() ([]entity.Application, error)
+		UpdateApplication(dto.ApplicationUpdateRequestBody) (*entity.Application, error)
+		DeleteApplication(string) error
+		ListLauncherApplications(string) ([]entity.Application, error)
+		GrantApplicationAccess(dto.ApplicationGrantRequestBody) (*entity.ApplicationGrant, error)
+		RevokeApplicationAccess(dto.ApplicationGrantDeleteRequestBody) error
+		ApplicationGrantMatrix() (*dto.ApplicationGrantMatrix, error)
+		IssueServiceToken(dto.TokenRequestBody, *config.Config) (*dto.ServiceToken, error)
+		ValidateServiceToken(string) (*entity.ServiceAccountToken, error)
+		IntrospectToken(dto.IntrospectionRequestBody, *config.Config) (*dto.TokenIntrospection, error)
+		RevokeToken(dto.RevocationRequestBody, *config.Config) error
+		BeginFederation(context.Context, string, bool, string, *config.Config) (string, string, error)
+		FinishFederation(context.Context, dto.FederationCallbackQuery, string, string, uint, *config.Config) (*dto.FederationResult, error)
+		ListLinkedIdentities(string) ([]entity.LinkedIdentity, error)
+		UnlinkIdentity(string, uint) error
+		ForwardAuth(dto.ForwardAuthRequest, *config.Config) (*dto.ForwardAuthResult, error)
+	}
+
+	// ICredentialVerifier checks a username and password and returns the user they belong to.
+	// It returns an InvalidCredentialsError when they don't match, so the next verifier is tried.
+	ICredentialVerifier interface {
+		Verify(string, string) (*entity.User, error)
 	}
 
 	IUserUC interface {
 		Create(entity.User) (entity.User, error)
 		FindByUsernameOrEmail(string, string) (*entity.User, error)
+		Update(entity.User) (entity.User, error)
+		ListUsers(dto.UserListQuery) (*dto.UserPage, error)
+		GetUser(uint) (*dto.AdminUser, error)
+		CreateUser(dto.UserCreateRequestBody) (*dto.AdminUser, error)
+		UpdateUser(dto.UserUpdateRequestBody, string) (*dto.AdminUser, error)
+		SetUserDisabled(uint, bool, string, *config.Config) (*dto.AdminUser, error)
+		DeleteUser(uint, string, *config.Config) (*dto.AdminUser, error)
+		RestoreUser(uint) (*dto.AdminUser, error)
 	}
 
 	IRoleUC interface {
 		GetRoleIDByName(string) (uint, error)
+		ListRoles() ([]dto.AdminRole, error)
+		ListPermissions() ([]dto.AdminPermission, error)
+		CreateRole(dto.RoleCreateRequestBody) (*dto.AdminRole, error)
+		UpdateRole(dto.RoleUpdateRequestBody) (*dto.AdminRole, error)
+		DeleteRole(string) error
+	}
+
+	IRateLimitUC interface {
+		Allow(string, entity.RateLimit) (*entity.RateLimitResult, error)
 	}
 )
@@ -0,0 +1,224 @@
+package usecases
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+)
+
+// oauthClient is who asks about or revokes a token, an application of the config or a service account
+type oauthClient struct {
+	id           string
+	confidential bool
+}
+
+// IntrospectToken tells a resource server whether a token is still good and what it grants, see RFC 7662.
+// Revoked tokens and tokens of ended sessions or deleted service accounts are inactive.
+func (au *AuthUseCase) IntrospectToken(req dto.IntrospectionRequestBody, cfg *config.Config) (*dto.TokenIntrospection, error) {
+	client, err := au.authenticateClient(req.ClientID, req.ClientSecret, cfg)
+	if err != nil {
+		return nil, err
+	}
+	// anyone could name a public client, so only confidential ones learn about tokens
+	if !client.confidential {
+		return nil, &entity.OAuthError{Code: entity.OAuthErrorInvalidClient, Description: "Public clients can't introspect tokens."}
+	}
+
+	if req.Token == "" {
+		return nil, &entity.OAuthError{Code: entity.OAuthErrorInvalidRequest, Description: "The token is missing."}
+	}
+
+	if !isJwt(req.Token) {
+		return au.introspectOIDCAccessToken(req.Token)
+	}
+	return au.introspectJwt(req.Token)
+}
+
+func (au *AuthUseCase) introspectOIDCAccessToken(token string) (*dto.TokenIntrospection, error) {
+	accessToken, _, err := au.findActiveOIDCAccessToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if accessToken == nil {
+		return &dto.TokenIntrospection{Active: false}, nil
+	}
+
+	return &dto.TokenIntrospection{
+		Active:    true,
+		Scope:     accessToken.Scope,
+		ClientID:  accessToken.ClientID,
+		Username:  accessToken.Username,
+		TokenType: "access_token",
+		Sub:       accessToken.Username,
+	}, nil
+}
+
+func (au *AuthUseCase) introspectJwt(jwtToken string) (*dto.TokenIntrospection, error) {
+	inactive := &dto.TokenIntrospection{Active: false}
+
+	token, err := au.parseToken(jwtToken)
+	if err != nil {
+		return inactive, nil
+	}
+
+	blacklisted, err := au.authRepo.IsTokenBlacklisted(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	if blacklisted {
+		return inactive, nil
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return inactive, nil
+	}
+
+	audience, _ := claims.GetAudience()
+	subject, _ := claims.GetSubject()
+	issuer, _ := claims.GetIssuer()
+	jti, _ := claims["jti"].(string)
+	introspection := &dto.TokenIntrospection{
+		Active:    true,
+		TokenType: "access_token",
+		Exp:       unixTime(claims.GetExpirationTime()),
+		Iat:       unixTime(claims.GetIssuedAt()),
+		Nbf:       unixTime(claims.GetNotBefore()),
+		Sub:       subject,
+		Aud:       audience,
+		Iss:       issuer,
+		Jti:       jti,
+	}
+
+	tokenUse := tokenUseOf(claims)
+	switch {
+	case tokenUse == tokenUseService && slices.Contains(audience, serviceAudience):
+		clientID, _ := claims["client_id"].(string)
+		serviceAccount, err := au.serviceAccountRepo.FindServiceAccountByClientID(clientID)
+		if err != nil {
+			return nil, err
+		}
+		if serviceAccount == nil {
+			return inactive, nil
+		}
+
+		introspection.ClientID = clientID
+		introspection.Scope, _ = claims["scope"].(string)
+	case (tokenUse == tokenUseAccess || tokenUse == tokenUseRefresh) && slices.Contains(audience, hubAudience):
+		if sid, _ := claims["sid"].(string); sid != "" {
+			session, err := au.authRepo.FindSession(sid)
+			if err != nil {
+				return nil, err
+			}
+			if session == nil {
+				return inactive, nil
+			}
+		}
+
+		introspection.Username = subject
+		if tokenUse == tokenUseRefresh {
+			introspection.TokenType = "refresh_token"
+		}
+	default:
+		// ID tokens are signed with the same keys, but they don't grant access to anything
+		return inactive, nil
+	}
+
+	return introspection, nil
+}
+
+// RevokeToken revokes an access or refresh token, see RFC 7009. Unknown, invalid and expired tokens
+// need no revoking, so they aren't an error. Tokens the hub issued to a client can only be revoked by
+// that client, the tokens of users by any client they were shown to.
+func (au *AuthUseCase) RevokeToken(req dto.RevocationRequestBody, cfg *config.Config) error {
+	client, err := au.authenticateClient(req.ClientID, req.ClientSecret, cfg)
+	if err != nil {
+		return err
+	}
+
+	if req.Token == "" {
+		return &entity.OAuthError{Code: entity.OAuthErrorInvalidRequest, Description: "The token is missing."}
+	}
+
+	notIssuedToClientErr := &entity.OAuthError{Code: entity.OAuthErrorUnauthorizedClient, Description: "The token wasn't issued to the client."}
+
+	if !isJwt(req.Token) {
+		accessToken, err := au.authRepo.FindOIDCAccessToken(req.Token)
+		if err != nil {
+			return err
+		}
+		if accessToken == nil {
+			return nil
+		}
+		if accessToken.ClientID != client.id {
+			return notIssuedToClientErr
+		}
+		return au.authRepo.DeleteOIDCAccessToken(req.Token)
+	}
+
+	token, err := au.parseToken(req.Token)
+	if err != nil {
+		return nil
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil
+	}
+
+	tokenUse := tokenUseOf(claims)
+	audience, _ := claims.GetAudience()
+	switch {
+	case tokenUse == tokenUseService && slices.Contains(audience, serviceAudience):
+		if clientID, _ := claims["client_id"].(string); clientID != client.id {
+			return notIssuedToClientErr
+		}
+	case (tokenUse != tokenUseAccess && tokenUse != tokenUseRefresh) || !slices.Contains(audience, hubAudience):
+		return nil
+	}
+
+	// the blacklist only has to remember the token until it expires
+	expiration := 1
+	if expiresAt, _ := claims.GetExpirationTime(); expiresAt != nil {
+		expiration = max(expiration, int(math.Ceil(time.Until(expiresAt.Time).Seconds())))
+	}
+	return au.authRepo.BlacklistToken(req.Token, expiration)
+}
+
+// authenticateClient accepts the applications of the config and the registry and the service accounts
+func (au *AuthUseCase) authenticateClient(clientID string, clientSecret string, cfg *config.Config) (*oauthClient, error) {
+	client, err := au.findOIDCClient(clientID, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if client != nil {
+		if !client.authenticate(clientSecret) {
+			return nil, &entity.OAuthError{Code: entity.OAuthErrorInvalidClient, Description: "The client credentials are invalid."}
+		}
+		return &oauthClient{id: client.id, confidential: !client.isPublic()}, nil
+	}
+
+	serviceAccount, err := au.authenticateServiceAccount(clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &oauthClient{id: serviceAccount.ClientID, confidential: true}, nil
+}
+
+// isJwt tells the tokens the hub signs from the opaque access tokens it issues to applications
+func isJwt(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+func unixTime(date *jwt.NumericDate, err error) int64 {
+	if err != nil || date == nil {
+		return 0
+	}
+	return date.Unix()
+}
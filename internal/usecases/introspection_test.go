@@ -0,0 +1,216 @@
+package usecases_test
+
+import (
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newSessionTokens logs testuser in, so the tokens belong to a session
+func newSessionTokens(t *testing.T, mockAuthRepo *repoMocks.IAuthRepo, mockUserUC *mocks.IUserUC, uc *usecases.AuthUseCase, cfg *config.Config) (*dto.JwtTokens, string) {
+	var sid string
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser", Role: entity.Role{Name: "customer"}}, nil).Once()
+	mockAuthRepo.On("AddUserToken", "testuser", mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("AddFamilyToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("SaveSession", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sid = args.Get(0).(entity.Session).ID
+	}).Return(nil).Once()
+
+	tokens, err := uc.GenerateTokens(entity.User{Username: "testuser"}, cfg)
+	assert.NoError(t, err)
+	return tokens, sid
+}
+
+func newIntrospectionConfig(t *testing.T) *config.Config {
+	cfg := newOIDCConfig(t)
+	cfg.Authen.AccessTokenTTL = 600
+	cfg.Authen.RefreshTokenTTL = 3600
+	cfg.Authen.ServiceTokenTTL = 3600
+	return cfg
+}
+
+func TestAuthUseCase_IntrospectToken_SessionTokens(t *testing.T) {
+	cfg := newIntrospectionConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, cfg)
+	tokens, sid := newSessionTokens(t, mockAuthRepo, mockUserUC, uc, cfg)
+
+	mockAuthRepo.On("IsTokenBlacklisted", mock.Anything).Return(false, nil)
+	mockAuthRepo.On("FindSession", sid).Return(&entity.Session{ID: sid, Username: "testuser"}, nil).Twice()
+
+	introspection, err := uc.IntrospectToken(dto.IntrospectionRequestBody{Token: tokens.AccessToken, ClientID: "grafana", ClientSecret: "grafana-secret"}, cfg)
+	assert.NoError(t, err)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, "testuser", introspection.Username)
+	assert.Equal(t, "access_token", introspection.TokenType)
+	assert.Equal(t, []string{"users"}, introspection.Aud)
+	assert.NotZero(t, introspection.Exp)
+
+	introspection, err = uc.IntrospectToken(dto.IntrospectionRequestBody{Token: tokens.RefreshToken, ClientID: "grafana", ClientSecret: "grafana-secret"}, cfg)
+	assert.NoError(t, err)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, "refresh_token", introspection.TokenType)
+
+	// once the session has ended, its tokens are inactive
+	mockAuthRepo.On("FindSession", sid).Return(nil, nil).Once()
+
+	introspection, err = uc.IntrospectToken(dto.IntrospectionRequestBody{Token: tokens.AccessToken, ClientID: "grafana", ClientSecret: "grafana-secret"}, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.TokenIntrospection{Active: false}, introspection)
+}
+
+func TestAuthUseCase_IntrospectToken_Blacklisted(t *testing.T) {
+	cfg := newIntrospectionConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, cfg)
+
+	accessToken, err := uc.CreateAccessToken(entity.User{Username: "testuser"}, 600)
+	assert.NoError(t, err)
+	mockAuthRepo.On("IsTokenBlacklisted", accessToken).Return(true, nil)
+
+	introspection, err := uc.IntrospectToken(dto.IntrospectionRequestBody{Token: accessToken, ClientID: "grafana", ClientSecret: "grafana-secret"}, cfg)
+
+	assert.NoError(t, err)
+	assert.False(t, introspection.Active)
+}
+
+func TestAuthUseCase_IntrospectToken_OIDCAccessToken(t *testing.T) {
+	cfg := newIntrospectionConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("FindOIDCAccessToken", "opaque-token").Return(&entity.OIDCAccessToken{ClientID: "spa", Username: "testuser", Scope: "openid email"}, nil)
+	mockAuthRepo.On("FindOIDCAccessToken", "unknown-token").Return(nil, nil)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser"}, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, cfg)
+
+	introspection, err := uc.IntrospectToken(dto.IntrospectionRequestBody{Token: "opaque-token", ClientID: "grafana", ClientSecret: "grafana-secret"}, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.TokenIntrospection{
+		Active:    true,
+		Scope:     "openid email",
+		ClientID:  "spa",
+		Username:  "testuser",
+		TokenType: "access_token",
+		Sub:       "testuser",
+	}, introspection)
+
+	introspection, err = uc.IntrospectToken(dto.IntrospectionRequestBody{Token: "unknown-token", ClientID: "grafana", ClientSecret: "grafana-secret"}, cfg)
+	assert.NoError(t, err)
+	assert.False(t, introspection.Active)
+}
+
+func TestAuthUseCase_IntrospectToken_OIDCAccessTokenOfDisabledUser(t *testing.T) {
+	cfg := newIntrospectionConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("FindOIDCAccessToken", "disabled-token").Return(&entity.OIDCAccessToken{ClientID: "spa", Username: "testuser"}, nil)
+	mockAuthRepo.On("FindOIDCAccessToken", "deleted-token").Return(&entity.OIDCAccessToken{ClientID: "spa", Username: "gone"}, nil)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser", Disabled: true}, nil)
+	mockUserUC.On("FindByUsernameOrEmail", "gone", "").Return(nil, &entity.InvalidCredentialsError{})
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, cfg)
+
+	for _, token := range []string{"disabled-token", "deleted-token"} {
+		introspection, err := uc.IntrospectToken(dto.IntrospectionRequestBody{Token: token, ClientID: "grafana", ClientSecret: "grafana-secret"}, cfg)
+		assert.NoError(t, err)
+		assert.False(t, introspection.Active)
+	}
+}
+
+func TestAuthUseCase_IntrospectToken_ServiceAccountToken(t *testing.T) {
+	cfg := newIntrospectionConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockServiceAccountRepo := repoMocks.NewIServiceAccountRepo(t)
+	serviceAccount := newServiceAccount(t, "secret", "login-locks:read", 0)
+	mockServiceAccountRepo.On("FindServiceAccountByClientID", "svc_backup").Return(serviceAccount, nil)
+	mockApplicationRepo.On("FindApplicationByClientID", "svc_backup").Return(nil, nil)
+	mockServiceAccountRepo.On("UpdateServiceAccountUsage", uint(7)).Return(nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, mockServiceAccountRepo, nil, nil, nil, mockApplicationRepo, nil, nil, cfg)
+
+	token, err := uc.IssueServiceToken(dto.TokenRequestBody{GrantType: "client_credentials", ClientID: "svc_backup", ClientSecret: "secret"}, cfg)
+	assert.NoError(t, err)
+	mockAuthRepo.On("IsTokenBlacklisted", token.AccessToken).Return(false, nil)
+
+	// service accounts can ask about tokens as well
+	introspection, err := uc.IntrospectToken(dto.IntrospectionRequestBody{Token: token.AccessToken, ClientID: "svc_backup", ClientSecret: "secret"}, cfg)
+
+	assert.NoError(t, err)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, "svc_backup", introspection.ClientID)
+	assert.Equal(t, "login-locks:read", introspection.Scope)
+	assert.Empty(t, introspection.Username)
+}
+
+func TestAuthUseCase_IntrospectToken_ClientRejected(t *testing.T) {
+	cfg := newIntrospectionConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockServiceAccountRepo := repoMocks.NewIServiceAccountRepo(t)
+	mockServiceAccountRepo.On("FindServiceAccountByClientID", "unknown").Return(nil, nil)
+	mockApplicationRepo.On("FindApplicationByClientID", "unknown").Return(nil, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, mockServiceAccountRepo, nil, nil, nil, mockApplicationRepo, nil, nil, cfg)
+
+	for _, req := range []dto.IntrospectionRequestBody{
+		{Token: "token", ClientID: "grafana", ClientSecret: "wrong"},
+		{Token: "token", ClientID: "spa"},
+		{Token: "token", ClientID: "unknown", ClientSecret: "secret"},
+	} {
+		_, err := uc.IntrospectToken(req, cfg)
+
+		var oauthErr *entity.OAuthError
+		assert.ErrorAs(t, err, &oauthErr)
+		assert.Equal(t, entity.OAuthErrorInvalidClient, oauthErr.Code)
+	}
+}
+
+func TestAuthUseCase_RevokeToken_UserToken(t *testing.T) {
+	cfg := newIntrospectionConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, cfg)
+	tokens, _ := newSessionTokens(t, mockAuthRepo, mockUserUC, uc, cfg)
+
+	// the blacklist keeps the refresh token until it would have expired
+	mockAuthRepo.On("BlacklistToken", tokens.RefreshToken, mock.MatchedBy(func(expiration int) bool {
+		return expiration > 3500 && expiration <= 3600
+	})).Return(nil)
+
+	err := uc.RevokeToken(dto.RevocationRequestBody{Token: tokens.RefreshToken, TokenTypeHint: "refresh_token", ClientID: "spa"}, cfg)
+
+	assert.NoError(t, err)
+}
+
+func TestAuthUseCase_RevokeToken_OIDCAccessToken(t *testing.T) {
+	cfg := newIntrospectionConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("FindOIDCAccessToken", "opaque-token").Return(&entity.OIDCAccessToken{ClientID: "spa", Username: "testuser"}, nil)
+	mockAuthRepo.On("DeleteOIDCAccessToken", "opaque-token").Return(nil).Once()
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, cfg)
+
+	// only the client the token was issued to can revoke it
+	err := uc.RevokeToken(dto.RevocationRequestBody{Token: "opaque-token", ClientID: "grafana", ClientSecret: "grafana-secret"}, cfg)
+	var oauthErr *entity.OAuthError
+	assert.ErrorAs(t, err, &oauthErr)
+	assert.Equal(t, entity.OAuthErrorUnauthorizedClient, oauthErr.Code)
+
+	err = uc.RevokeToken(dto.RevocationRequestBody{Token: "opaque-token", ClientID: "spa"}, cfg)
+	assert.NoError(t, err)
+}
+
+func TestAuthUseCase_RevokeToken_InvalidToken(t *testing.T) {
+	// invalid tokens need no revoking, so the client isn't told anything went wrong
+	cfg := newIntrospectionConfig(t)
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, nil, nil, cfg)
+
+	err := uc.RevokeToken(dto.RevocationRequestBody{Token: "not.a.jwt", ClientID: "spa"}, cfg)
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,178 @@
+package usecases_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/pkg/keyring"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRotatedKeyring(t *testing.T) (*keyring.Keyring, *rsa.PrivateKey, *rsa.PrivateKey) {
+	activeKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	retiredKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	k, err := keyring.New(
+		keyring.Key{ID: "2026-10", Status: keyring.StatusActive, Algorithm: keyring.AlgorithmRS256, PrivateKey: activeKey},
+		keyring.Key{ID: "2026-09", Status: keyring.StatusRetired, Algorithm: keyring.AlgorithmRS256, PrivateKey: retiredKey},
+	)
+	assert.NoError(t, err)
+
+	return k, activeKey, retiredKey
+}
+
+func signWithKid(t *testing.T, privateKey crypto.Signer, kid string) string {
+	return signWithMethod(t, jwt.SigningMethodRS256, privateKey, kid)
+}
+
+func signWithMethod(t *testing.T, method jwt.SigningMethod, privateKey crypto.Signer, kid string) string {
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"sub": "testuser",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	tokenString, err := token.SignedString(privateKey)
+	assert.NoError(t, err)
+	return tokenString
+}
+
+func TestAuthUseCase_CreateAccessToken_StampsKid(t *testing.T) {
+	k, activeKey, _ := newRotatedKeyring(t)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, &config.Config{Authen: config.Authen{JwtKeyring: k}})
+
+	accessToken, err := uc.CreateAccessToken(entity.User{Username: "testuser"}, 600)
+	assert.NoError(t, err)
+
+	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
+		return activeKey.Public(), nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-10", token.Header["kid"])
+}
+
+func TestAuthUseCase_ValidateToken_RetiredKey(t *testing.T) {
+	k, _, retiredKey := newRotatedKeyring(t)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, &config.Config{Authen: config.Authen{JwtKeyring: k}})
+
+	// tokens signed before the rotation stay valid
+	username, err := uc.ValidateToken(signWithKid(t, retiredKey, "2026-09"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "testuser", username)
+}
+
+func TestAuthUseCase_ValidateToken_UnknownKid(t *testing.T) {
+	k, activeKey, _ := newRotatedKeyring(t)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, &config.Config{Authen: config.Authen{JwtKeyring: k}})
+
+	username, err := uc.ValidateToken(signWithKid(t, activeKey, "2026-08"))
+
+	assert.ErrorContains(t, err, "unknown signing key: 2026-08")
+	assert.Empty(t, username)
+}
+
+func TestAuthUseCase_ValidateToken_KidOfAnotherKey(t *testing.T) {
+	k, _, retiredKey := newRotatedKeyring(t)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, &config.Config{Authen: config.Authen{JwtKeyring: k}})
+
+	username, err := uc.ValidateToken(signWithKid(t, retiredKey, "2026-10"))
+
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	assert.Empty(t, username)
+}
+
+func TestAuthUseCase_RetrieveFieldFromJwtToken_RetiredKey(t *testing.T) {
+	k, _, retiredKey := newRotatedKeyring(t)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, &config.Config{Authen: config.Authen{JwtKeyring: k}})
+
+	sub, err := uc.RetrieveFieldFromJwtToken(signWithKid(t, retiredKey, "2026-09"), "sub", true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "testuser", sub)
+}
+
+func TestAuthUseCase_Tokens_EllipticCurveAlgorithms(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		algorithm  string
+		privateKey crypto.Signer
+	}{
+		{keyring.AlgorithmES256, ecKey},
+		{keyring.AlgorithmEdDSA, edKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			k, err := keyring.New(keyring.Key{ID: "k1", Status: keyring.StatusActive, Algorithm: tt.algorithm, PrivateKey: tt.privateKey})
+			assert.NoError(t, err)
+			uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, &config.Config{Authen: config.Authen{JwtKeyring: k}})
+
+			accessToken, err := uc.CreateAccessToken(entity.User{Username: "testuser"}, 600)
+			assert.NoError(t, err)
+
+			token, _, err := jwt.NewParser().ParseUnverified(accessToken, jwt.MapClaims{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.algorithm, token.Header["alg"])
+
+			username, err := uc.ValidateToken(accessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, "testuser", username)
+		})
+	}
+}
+
+func TestAuthUseCase_ValidateToken_AlgorithmOfAnotherKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	k, err := keyring.New(
+		keyring.Key{ID: "ec", Status: keyring.StatusActive, Algorithm: keyring.AlgorithmES256, PrivateKey: ecKey},
+		keyring.Key{ID: "rsa", Status: keyring.StatusRetired, Algorithm: keyring.AlgorithmRS256, PrivateKey: rsaKey},
+	)
+	assert.NoError(t, err)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, &config.Config{Authen: config.Authen{JwtKeyring: k}})
+
+	// RS256 is allowed, but only for the rsa key
+	username, err := uc.ValidateToken(signWithMethod(t, jwt.SigningMethodRS256, rsaKey, "ec"))
+	assert.ErrorContains(t, err, "unexpected signing method: RS256")
+	assert.Empty(t, username)
+
+	username, err = uc.ValidateToken(signWithMethod(t, jwt.SigningMethodRS256, rsaKey, "rsa"))
+	assert.NoError(t, err)
+	assert.Equal(t, "testuser", username)
+}
+
+func TestAuthUseCase_ValidateToken_AlgorithmNotConfigured(t *testing.T) {
+	k, _, _ := newRotatedKeyring(t)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, &config.Config{Authen: config.Authen{JwtKeyring: k}})
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	username, err := uc.ValidateToken(signWithMethod(t, jwt.SigningMethodEdDSA, edKey, "2026-10"))
+
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	assert.ErrorContains(t, err, "signing method EdDSA is invalid")
+	assert.Empty(t, username)
+}
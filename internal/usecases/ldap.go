@@ -0,0 +1,179 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+	"github.com/minhmannh2001/authconnecthub/pkg/ldapauth"
+)
+
+// ldapProvider names the linked identities which tie users to their entry of the directory
+const ldapProvider = "ldap"
+
+// NewCredentialVerifiers creates the verifiers of the config which are asked after the local passwords
+func NewCredentialVerifiers(cfg *config.Config, lir repos.ILinkedIdentityRepo, uu IUserUC, ru IRoleUC) []ICredentialVerifier {
+	if cfg.LDAP.URL == "" {
+		return nil
+	}
+
+	return []ICredentialVerifier{NewLDAPVerifier(cfg.LDAP, lir, uu, ru)}
+}
+
+// LDAPVerifier checks passwords against a directory. Users of the directory get a local user on their
+// first login, linked to their entry, and its email and role are synced on every login after.
+type LDAPVerifier struct {
+	directory          *ldapauth.Directory
+	config             config.LDAP
+	linkedIdentityRepo repos.ILinkedIdentityRepo
+	userUseCase        IUserUC
+	roleUseCase        IRoleUC
+}
+
+func NewLDAPVerifier(cfg config.LDAP, lir repos.ILinkedIdentityRepo, uu IUserUC, ru IRoleUC) *LDAPVerifier {
+	return &LDAPVerifier{
+		directory: ldapauth.New(ldapauth.Config{
+			URL:               cfg.URL,
+			StartTLS:          cfg.StartTLS,
+			BindDN:            cfg.BindDN,
+			BindPassword:      cfg.BindPassword,
+			BaseDN:            cfg.BaseDN,
+			UserFilter:        cfg.UserFilter,
+			IDAttribute:       cfg.IDAttribute,
+			UsernameAttribute: cfg.UsernameAttribute,
+			EmailAttribute:    cfg.EmailAttribute,
+			GroupBaseDN:       cfg.GroupBaseDN,
+			GroupFilter:       cfg.GroupFilter,
+			Timeout:           time.Duration(cfg.Timeout) * time.Second,
+		}),
+		config:             cfg,
+		linkedIdentityRepo: lir,
+		userUseCase:        uu,
+		roleUseCase:        ru,
+	}
+}
+
+func (v *LDAPVerifier) Verify(username string, password string) (*entity.User, error) {
+	entry, err := v.directory.Authenticate(username, password)
+	if err != nil {
+		if errors.Is(err, ldapauth.ErrInvalidCredentials) {
+			return nil, &entity.InvalidCredentialsError{}
+		}
+		return nil, err
+	}
+
+	// members of none of the groups are turned away, unless there is a default role
+	roleName := v.roleOf(entry.Groups)
+	if roleName == "" {
+		return nil, &entity.InvalidCredentialsError{}
+	}
+	roleID, err := v.roleUseCase.GetRoleIDByName(roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	linkedIdentity, err := v.linkedIdentityRepo.FindLinkedIdentity(v.issuer(), entry.ID)
+	if err != nil {
+		return nil, err
+	}
+	if linkedIdentity == nil {
+		return v.createUser(entry, roleID)
+	}
+
+	// the user of the identity isn't loaded when it has been deleted
+	user := linkedIdentity.User
+	if user.ID == 0 {
+		return nil, &entity.InvalidCredentialsError{}
+	}
+	if user.Disabled {
+		return nil, &entity.UserDisabledError{}
+	}
+
+	if (entry.Email != "" && user.Email != entry.Email) || user.RoleID != roleID {
+		if entry.Email != "" {
+			user.Email = entry.Email
+		}
+		user.RoleID = roleID
+		user, err = v.userUseCase.Update(user)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := v.linkedIdentityRepo.UpdateLinkedIdentityUsage(linkedIdentity.ID); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// createUser creates the user of an entry which logs in for the first time. The password stays
+// with the directory, the local user gets a random one.
+func (v *LDAPVerifier) createUser(entry *ldapauth.Entry, roleID uint) (*entity.User, error) {
+	if entry.Email == "" {
+		return nil, fmt.Errorf("directory entry %s has no email", entry.DN)
+	}
+
+	for _, lookup := range [][2]string{{entry.Username, ""}, {"", entry.Email}} {
+		_, err := v.userUseCase.FindByUsernameOrEmail(lookup[0], lookup[1])
+		if err == nil {
+			return nil, &entity.DirectoryUserConflictError{}
+		}
+		if !helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+			return nil, err
+		}
+	}
+
+	passwordHash, err := randomPasswordHash()
+	if err != nil {
+		return nil, err
+	}
+
+	// the directory is trusted with the email
+	verifiedAt := time.Now()
+	user, err := v.userUseCase.Create(entity.User{
+		Username:      entry.Username,
+		Email:         entry.Email,
+		Password:      passwordHash,
+		RoleID:        roleID,
+		EmailVerified: true,
+		VerifiedAt:    &verifiedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = v.linkedIdentityRepo.CreateLinkedIdentity(entity.LinkedIdentity{
+		UserID:   user.ID,
+		Provider: ldapProvider,
+		Issuer:   v.issuer(),
+		Subject:  entry.ID,
+		Email:    entry.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (v *LDAPVerifier) roleOf(groups []string) string {
+	for _, groupRole := range v.config.GroupRoles {
+		for _, group := range groups {
+			if strings.EqualFold(group, groupRole.Group) {
+				return groupRole.Role
+			}
+		}
+	}
+	return v.config.DefaultRole
+}
+
+// issuer identifies the directory by its base DN rather than its url, which changes more often
+func (v *LDAPVerifier) issuer() string {
+	return ldapProvider + ":" + strings.ToLower(v.config.BaseDN)
+}
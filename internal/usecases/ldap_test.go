@@ -0,0 +1,234 @@
+package usecases_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/minhmannh2001/authconnecthub/pkg/ldapauth/ldaptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const (
+	ldapIssuer = "ldap:dc=home,dc=example"
+	annaID     = "5f0c1d2e-0000-4000-8000-000000000001"
+)
+
+// newLDAPConfig starts a directory with anna in the family group and bob in no group at all
+func newLDAPConfig(t *testing.T) config.LDAP {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "cn=hub,ou=services,dc=home,dc=example", Password: "service-secret"},
+		ldaptest.Entry{DN: "uid=anna,ou=people,dc=home,dc=example", Password: "anna-secret", Attributes: map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"anna"},
+			"mail":        {"anna@home.example"},
+			"entryUUID":   {annaID},
+		}},
+		ldaptest.Entry{DN: "uid=bob,ou=people,dc=home,dc=example", Password: "bob-secret", Attributes: map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"bob"},
+			"mail":        {"bob@home.example"},
+		}},
+		ldaptest.Entry{DN: "cn=family,ou=groups,dc=home,dc=example", Attributes: map[string][]string{
+			"member": {"uid=anna,ou=people,dc=home,dc=example"},
+		}},
+	)
+
+	return config.LDAP{
+		URL:          server.URL,
+		BindDN:       "cn=hub,ou=services,dc=home,dc=example",
+		BindPassword: "service-secret",
+		BaseDN:       "dc=home,dc=example",
+		GroupRoles: []config.LDAPGroupRole{
+			{Group: "cn=admins,ou=groups,dc=home,dc=example", Role: "admin"},
+			{Group: "cn=family,ou=groups,dc=home,dc=example", Role: "customer"},
+		},
+	}
+}
+
+func TestLDAPVerifier_Verify_NewUser(t *testing.T) {
+	mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockRoleUC := mocks.NewIRoleUC(t)
+	verifier := usecases.NewLDAPVerifier(newLDAPConfig(t), mockLinkedIdentityRepo, mockUserUC, mockRoleUC)
+
+	mockRoleUC.On("GetRoleIDByName", "customer").Return(uint(2), nil)
+	mockLinkedIdentityRepo.On("FindLinkedIdentity", ldapIssuer, annaID).Return(nil, nil)
+	mockUserUC.On("FindByUsernameOrEmail", "anna", "").Return(nil, &entity.InvalidCredentialsError{})
+	mockUserUC.On("FindByUsernameOrEmail", "", "anna@home.example").Return(nil, &entity.InvalidCredentialsError{})
+	mockUserUC.On("Create", mock.MatchedBy(func(u entity.User) bool {
+		return u.Username == "anna" && u.Email == "anna@home.example" && u.RoleID == 2 && u.EmailVerified && u.Password != ""
+	})).Return(entity.User{ID: 7, Username: "anna", Email: "anna@home.example", RoleID: 2}, nil)
+	mockLinkedIdentityRepo.On("CreateLinkedIdentity", entity.LinkedIdentity{
+		UserID: 7, Provider: "ldap", Issuer: ldapIssuer, Subject: annaID, Email: "anna@home.example",
+	}).Return(entity.LinkedIdentity{}, nil)
+
+	user, err := verifier.Verify("anna", "anna-secret")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), user.ID)
+}
+
+func TestLDAPVerifier_Verify_SyncsLinkedUser(t *testing.T) {
+	mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockRoleUC := mocks.NewIRoleUC(t)
+	verifier := usecases.NewLDAPVerifier(newLDAPConfig(t), mockLinkedIdentityRepo, mockUserUC, mockRoleUC)
+
+	// anna was an admin with another email before
+	linkedUser := entity.User{ID: 7, Username: "anna", Email: "anna@old.example", RoleID: 1}
+	mockRoleUC.On("GetRoleIDByName", "customer").Return(uint(2), nil)
+	mockLinkedIdentityRepo.On("FindLinkedIdentity", ldapIssuer, annaID).Return(&entity.LinkedIdentity{Model: gorm.Model{ID: 3}, UserID: 7, User: linkedUser}, nil)
+	mockUserUC.On("Update", entity.User{ID: 7, Username: "anna", Email: "anna@home.example", RoleID: 2}).
+		Return(entity.User{ID: 7, Username: "anna", Email: "anna@home.example", RoleID: 2}, nil)
+	mockLinkedIdentityRepo.On("UpdateLinkedIdentityUsage", uint(3)).Return(nil)
+
+	user, err := verifier.Verify("anna", "anna-secret")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), user.RoleID)
+	assert.Equal(t, "anna@home.example", user.Email)
+}
+
+func TestLDAPVerifier_Verify_DeletedLinkedUser(t *testing.T) {
+	mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockRoleUC := mocks.NewIRoleUC(t)
+	verifier := usecases.NewLDAPVerifier(newLDAPConfig(t), mockLinkedIdentityRepo, mockUserUC, mockRoleUC)
+
+	// soft deleted users aren't preloaded with the identity
+	mockRoleUC.On("GetRoleIDByName", "customer").Return(uint(2), nil)
+	mockLinkedIdentityRepo.On("FindLinkedIdentity", ldapIssuer, annaID).Return(&entity.LinkedIdentity{Model: gorm.Model{ID: 3}, UserID: 7}, nil)
+
+	user, err := verifier.Verify("anna", "anna-secret")
+
+	assert.Nil(t, user)
+	assert.Equal(t, &entity.InvalidCredentialsError{}, err)
+	mockUserUC.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestLDAPVerifier_Verify_DisabledLinkedUser(t *testing.T) {
+	mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockRoleUC := mocks.NewIRoleUC(t)
+	verifier := usecases.NewLDAPVerifier(newLDAPConfig(t), mockLinkedIdentityRepo, mockUserUC, mockRoleUC)
+
+	linkedUser := entity.User{ID: 7, Username: "anna", Email: "anna@home.example", RoleID: 2, Disabled: true}
+	mockRoleUC.On("GetRoleIDByName", "customer").Return(uint(2), nil)
+	mockLinkedIdentityRepo.On("FindLinkedIdentity", ldapIssuer, annaID).Return(&entity.LinkedIdentity{Model: gorm.Model{ID: 3}, UserID: 7, User: linkedUser}, nil)
+
+	user, err := verifier.Verify("anna", "anna-secret")
+
+	assert.Nil(t, user)
+	assert.Equal(t, &entity.UserDisabledError{}, err)
+}
+
+func TestLDAPVerifier_Verify_LocalUserConflict(t *testing.T) {
+	mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockRoleUC := mocks.NewIRoleUC(t)
+	verifier := usecases.NewLDAPVerifier(newLDAPConfig(t), mockLinkedIdentityRepo, mockUserUC, mockRoleUC)
+
+	mockRoleUC.On("GetRoleIDByName", "customer").Return(uint(2), nil)
+	mockLinkedIdentityRepo.On("FindLinkedIdentity", ldapIssuer, annaID).Return(nil, nil)
+	mockUserUC.On("FindByUsernameOrEmail", "anna", "").Return(&entity.User{ID: 1, Username: "anna"}, nil)
+
+	user, err := verifier.Verify("anna", "anna-secret")
+
+	assert.Nil(t, user)
+	assert.True(t, helper.IsErrOfType(err, &entity.DirectoryUserConflictError{}))
+}
+
+func TestLDAPVerifier_Verify_Rejected(t *testing.T) {
+	cases := []struct {
+		name     string
+		username string
+		password string
+	}{
+		{name: "Wrong password", username: "anna", password: "wrong"},
+		{name: "Unknown user", username: "carol", password: "carol-secret"},
+		{name: "Not in a group", username: "bob", password: "bob-secret"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			verifier := usecases.NewLDAPVerifier(newLDAPConfig(t), repoMocks.NewILinkedIdentityRepo(t), mocks.NewIUserUC(t), mocks.NewIRoleUC(t))
+
+			user, err := verifier.Verify(tc.username, tc.password)
+
+			assert.Nil(t, user)
+			assert.True(t, helper.IsErrOfType(err, &entity.InvalidCredentialsError{}))
+		})
+	}
+}
+
+func TestLDAPVerifier_Verify_DefaultRole(t *testing.T) {
+	cfg := newLDAPConfig(t)
+	cfg.DefaultRole = "customer"
+	mockLinkedIdentityRepo := repoMocks.NewILinkedIdentityRepo(t)
+	mockRoleUC := mocks.NewIRoleUC(t)
+	verifier := usecases.NewLDAPVerifier(cfg, mockLinkedIdentityRepo, mocks.NewIUserUC(t), mockRoleUC)
+
+	bob := entity.User{ID: 8, Username: "bob", Email: "bob@home.example", RoleID: 2}
+	mockRoleUC.On("GetRoleIDByName", "customer").Return(uint(2), nil)
+	// without an entryUUID the entry is known by its DN
+	mockLinkedIdentityRepo.On("FindLinkedIdentity", ldapIssuer, "uid=bob,ou=people,dc=home,dc=example").Return(&entity.LinkedIdentity{Model: gorm.Model{ID: 4}, UserID: 8, User: bob}, nil)
+	mockLinkedIdentityRepo.On("UpdateLinkedIdentityUsage", uint(4)).Return(nil)
+
+	user, err := verifier.Verify("bob", "bob-secret")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &bob, user)
+}
+
+func TestAuthUseCase_Login_CredentialVerifier(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600, AccessTokenTTL: 600, JwtPrivateKey: privateKey}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockVerifier := mocks.NewICredentialVerifier(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig, mockVerifier)
+
+	// anna has no local user, the directory knows her
+	mockLoginUnlocked(mockAuthRepo, "anna")
+	mockUserUC.On("FindByUsernameOrEmail", "anna", "").Return(nil, &entity.InvalidCredentialsError{}).Once()
+	mockVerifier.On("Verify", "anna", "anna-secret").Return(&entity.User{ID: 7, Username: "anna"}, nil)
+	mockAuthRepo.On("ClearLoginFailures", "username", "anna").Return(nil)
+	mockSessionTokens(mockAuthRepo, mockUserUC, "anna")
+
+	tokens, err := uc.Login(newLoginContext(mockConfig), dto.LoginRequestBody{Username: "anna", Password: "anna-secret"})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+}
+
+func TestAuthUseCase_Login_CredentialVerifierUnavailable(t *testing.T) {
+	mockConfig := &config.Config{}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockVerifier := mocks.NewICredentialVerifier(t)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig, mockVerifier)
+
+	// a directory which is down doesn't count as a failed login
+	mockLoginUnlocked(mockAuthRepo, "anna")
+	mockUserUC.On("FindByUsernameOrEmail", "anna", "").Return(nil, &entity.InvalidCredentialsError{}).Once()
+	mockVerifier.On("Verify", "anna", "anna-secret").Return(nil, errors.New("failed to connect to directory"))
+
+	tokens, err := uc.Login(newLoginContext(mockConfig), dto.LoginRequestBody{Username: "anna", Password: "anna-secret"})
+
+	assert.Nil(t, tokens)
+	assert.ErrorContains(t, err, "failed to connect to directory")
+}
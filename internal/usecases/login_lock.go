@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"strings"
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+)
+
+// maxLoginFailureDelay caps the wait after failed logins, so a typo doesn't keep the user waiting for long
+const maxLoginFailureDelay = 8 * time.Second
+
+// loginSubject is what failed logins are counted for, the username and the client ip
+type loginSubject struct {
+	kind        string
+	value       string
+	maxFailures int
+}
+
+func loginSubjects(username string, ip string, cfg *config.Config) []loginSubject {
+	subjects := []loginSubject{{
+		kind:        entity.LoginLockKindUsername,
+		value:       strings.ToLower(username),
+		maxFailures: cfg.Authen.LoginMaxFailures,
+	}}
+	if ip != "" {
+		subjects = append(subjects, loginSubject{
+			kind:        entity.LoginLockKindIP,
+			value:       ip,
+			maxFailures: cfg.Authen.LoginMaxFailuresPerIP,
+		})
+	}
+	return subjects
+}
+
+// checkLoginLocks rejects the login before the password is checked when the username or the ip is locked,
+// or when it has to wait a little longer after its last failed login
+func (au *AuthUseCase) checkLoginLocks(subjects []loginSubject) error {
+	for _, subject := range subjects {
+		lock, err := au.authRepo.FindLoginLock(subject.kind, subject.value)
+		if err != nil {
+			return err
+		}
+		if lock != nil {
+			return &entity.LoginLockedError{Until: lock.Until}
+		}
+
+		until, err := au.authRepo.FindLoginDelay(subject.kind, subject.value)
+		if err != nil {
+			return err
+		}
+		if until.After(time.Now()) {
+			return &entity.LoginThrottledError{Until: until}
+		}
+	}
+
+	return nil
+}
+
+// loginFailed counts the failed login for the username and the ip. Each has to wait a little longer
+// with every failure before its next attempt, and it is locked once it has failed too often.
+func (au *AuthUseCase) loginFailed(subjects []loginSubject, cfg *config.Config) error {
+	var lockErr error
+	for _, subject := range subjects {
+		count, err := au.authRepo.RecordLoginFailure(subject.kind, subject.value, cfg.Authen.LoginFailureWindow)
+		if err != nil {
+			return err
+		}
+
+		if subject.maxFailures > 0 && count >= int64(subject.maxFailures) {
+			if err := au.authRepo.LockLogin(subject.kind, subject.value, cfg.Authen.LoginLockoutDuration); err != nil {
+				return err
+			}
+			lockErr = &entity.LoginLockedError{Until: time.Now().Add(time.Duration(cfg.Authen.LoginLockoutDuration) * time.Second)}
+			continue
+		}
+
+		// the next attempt is rejected early, rather than holding this response back
+		if delay := loginFailureDelay(count, cfg); delay > 0 {
+			if err := au.authRepo.DelayLogin(subject.kind, subject.value, delay); err != nil {
+				return err
+			}
+		}
+	}
+
+	if lockErr != nil {
+		return lockErr
+	}
+	return &entity.InvalidCredentialsError{}
+}
+
+// loginFailureDelay doubles with every failure within the window
+func loginFailureDelay(failures int64, cfg *config.Config) time.Duration {
+	if cfg.Authen.LoginFailureDelay <= 0 || failures <= 0 {
+		return 0
+	}
+
+	delay := time.Duration(cfg.Authen.LoginFailureDelay) * time.Millisecond
+	for i := int64(1); i < failures && delay < maxLoginFailureDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxLoginFailureDelay)
+}
+
+// ListLoginLocks returns the usernames and ips which currently can't log in
+func (au *AuthUseCase) ListLoginLocks() ([]entity.LoginLock, error) {
+	return au.authRepo.FindLoginLocks()
+}
+
+// ClearLoginLock lets a username or an ip log in again right away
+func (au *AuthUseCase) ClearLoginLock(kind string, value string) error {
+	if kind == entity.LoginLockKindUsername {
+		value = strings.ToLower(value)
+	}
+
+	deleted, err := au.authRepo.DeleteLoginLock(kind, value)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &entity.LoginLockNotFoundError{}
+	}
+
+	return nil
+}
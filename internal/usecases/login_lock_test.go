@@ -0,0 +1,168 @@
+package usecases_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newLoginContext is a login request sent from 203.0.113.7
+func newLoginContext(mockConfig *config.Config) *gin.Context {
+	mockContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	mockContext.Request = httptest.NewRequest(http.MethodPost, "/v1/auth/login", nil)
+	mockContext.Request.RemoteAddr = "203.0.113.7:54321"
+	mockContext.Set("config", mockConfig)
+	return mockContext
+}
+
+func mockLoginUnlocked(mockAuthRepo *repoMocks.IAuthRepo, username string) {
+	mockAuthRepo.On("FindLoginLock", "username", username).Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "username", username).Return(time.Time{}, nil)
+	mockAuthRepo.On("FindLoginLock", "ip", "203.0.113.7").Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "ip", "203.0.113.7").Return(time.Time{}, nil)
+}
+
+func TestAuthUseCase_Login_UsernameLocked(t *testing.T) {
+	mockConfig := &config.Config{}
+	until := time.Now().Add(10 * time.Minute)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("FindLoginLock", "username", "testuser").Return(&entity.LoginLock{Kind: "username", Value: "testuser", Until: until}, nil)
+
+	// the password isn't even checked
+	mockUserUC := new(mocks.IUserUC)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	tokens, err := uc.Login(newLoginContext(mockConfig), dto.LoginRequestBody{Username: "TestUser", Password: "secret"})
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.LoginLockedError{Until: until}, err)
+	assert.Equal(t, "Too many failed login attempts. Please try again in 10 minutes.", err.Error())
+	mockUserUC.AssertNotCalled(t, "FindByUsernameOrEmail", mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_Login_IPLocked(t *testing.T) {
+	mockConfig := &config.Config{}
+	until := time.Now().Add(time.Minute)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("FindLoginLock", "username", "testuser").Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "username", "testuser").Return(time.Time{}, nil)
+	mockAuthRepo.On("FindLoginLock", "ip", "203.0.113.7").Return(&entity.LoginLock{Kind: "ip", Value: "203.0.113.7", Until: until}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, new(mocks.IUserUC), nil, mockConfig)
+
+	tokens, err := uc.Login(newLoginContext(mockConfig), dto.LoginRequestBody{Username: "testuser", Password: "secret"})
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.LoginLockedError{Until: until}, err)
+}
+
+func TestAuthUseCase_Login_LocksAfterTooManyFailures(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{
+		LoginFailureWindow:    900,
+		LoginMaxFailures:      5,
+		LoginMaxFailuresPerIP: 20,
+		LoginLockoutDuration:  600,
+	}}
+
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.DefaultCost)
+	assert.NoError(t, err)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Password: string(encryptedPassword)}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLoginUnlocked(mockAuthRepo, "testuser")
+	mockAuthRepo.On("RecordLoginFailure", "username", "testuser", 900).Return(int64(5), nil)
+	mockAuthRepo.On("RecordLoginFailure", "ip", "203.0.113.7", 900).Return(int64(5), nil)
+	mockAuthRepo.On("LockLogin", "username", "testuser", 600).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	tokens, err := uc.Login(newLoginContext(mockConfig), dto.LoginRequestBody{Username: "testuser", Password: "wrong"})
+
+	assert.Nil(t, tokens)
+	var loginLockedErr *entity.LoginLockedError
+	assert.True(t, errors.As(err, &loginLockedErr))
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), loginLockedErr.Until, 5*time.Second)
+	// the ip is far from its own limit
+	mockAuthRepo.AssertNotCalled(t, "LockLogin", "ip", mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_Login_FailureDelaysNextAttempt(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{
+		LoginFailureWindow:    900,
+		LoginMaxFailures:      5,
+		LoginMaxFailuresPerIP: 20,
+		LoginFailureDelay:     50,
+	}}
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "nobody", "").Return(nil, &entity.InvalidCredentialsError{})
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLoginUnlocked(mockAuthRepo, "nobody")
+	mockAuthRepo.On("RecordLoginFailure", "username", "nobody", 900).Return(int64(2), nil)
+	mockAuthRepo.On("RecordLoginFailure", "ip", "203.0.113.7", 900).Return(int64(3), nil)
+	// 50ms doubled with every failure but the first
+	mockAuthRepo.On("DelayLogin", "username", "nobody", 100*time.Millisecond).Return(nil)
+	mockAuthRepo.On("DelayLogin", "ip", "203.0.113.7", 200*time.Millisecond).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	start := time.Now()
+	tokens, err := uc.Login(newLoginContext(mockConfig), dto.LoginRequestBody{Username: "nobody", Password: "wrong"})
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.InvalidCredentialsError{}, err)
+	// the response itself isn't held back
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestAuthUseCase_Login_Throttled(t *testing.T) {
+	mockConfig := &config.Config{}
+	until := time.Now().Add(2 * time.Second)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("FindLoginLock", "username", "testuser").Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "username", "testuser").Return(time.Time{}, nil)
+	mockAuthRepo.On("FindLoginLock", "ip", "203.0.113.7").Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "ip", "203.0.113.7").Return(until, nil)
+
+	// the password isn't checked until the wait is over
+	mockUserUC := new(mocks.IUserUC)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	tokens, err := uc.Login(newLoginContext(mockConfig), dto.LoginRequestBody{Username: "testuser", Password: "secret"})
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.LoginThrottledError{Until: until}, err)
+	mockUserUC.AssertNotCalled(t, "FindByUsernameOrEmail", mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_ClearLoginLock(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("DeleteLoginLock", "username", "testuser").Return(true, nil)
+	mockAuthRepo.On("DeleteLoginLock", "ip", "203.0.113.7").Return(false, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+	assert.NoError(t, uc.ClearLoginLock("username", "TestUser"))
+	assert.Equal(t, &entity.LoginLockNotFoundError{}, uc.ClearLoginLock("ip", "203.0.113.7"))
+}
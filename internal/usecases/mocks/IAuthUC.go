@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	context "context"
+
 	config "github.com/minhmannh2001/authconnecthub/config"
+
 	dto "github.com/minhmannh2001/authconnecthub/internal/dto"
 
 	entity "github.com/minhmannh2001/authconnecthub/internal/entity"
@@ -18,34 +21,92 @@ type IAuthUC struct {
 	mock.Mock
 }
 
-// CheckAndRefreshTokens provides a mock function with given fields: _a0, _a1, _a2
-func (_m *IAuthUC) CheckAndRefreshTokens(_a0 string, _a1 string, _a2 *config.Config) (string, string, error) {
+// ApplicationGrantMatrix provides a mock function with given fields:
+func (_m *IAuthUC) ApplicationGrantMatrix() (*dto.ApplicationGrantMatrix, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplicationGrantMatrix")
+	}
+
+	var r0 *dto.ApplicationGrantMatrix
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*dto.ApplicationGrantMatrix, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *dto.ApplicationGrantMatrix); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ApplicationGrantMatrix)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Authorize provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthUC) Authorize(_a0 dto.AuthorizeRequest, _a1 string, _a2 *config.Config) (string, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CheckAndRefreshTokens")
+		panic("no return value specified for Authorize")
 	}
 
 	var r0 string
-	var r1 string
-	var r2 error
-	if rf, ok := ret.Get(0).(func(string, string, *config.Config) (string, string, error)); ok {
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.AuthorizeRequest, string, *config.Config) (string, error)); ok {
 		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, *config.Config) string); ok {
+	if rf, ok := ret.Get(0).(func(dto.AuthorizeRequest, string, *config.Config) string); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, *config.Config) string); ok {
+	if rf, ok := ret.Get(1).(func(dto.AuthorizeRequest, string, *config.Config) error); ok {
 		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BeginFederation provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *IAuthUC) BeginFederation(_a0 context.Context, _a1 string, _a2 bool, _a3 string, _a4 *config.Config) (string, string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginFederation")
+	}
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, string, *config.Config) (string, string, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, string, *config.Config) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool, string, *config.Config) string); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
-	if rf, ok := ret.Get(2).(func(string, string, *config.Config) error); ok {
-		r2 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(2).(func(context.Context, string, bool, string, *config.Config) error); ok {
+		r2 = rf(_a0, _a1, _a2, _a3, _a4)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -53,26 +114,58 @@ func (_m *IAuthUC) CheckAndRefreshTokens(_a0 string, _a1 string, _a2 *config.Con
 	return r0, r1, r2
 }
 
-// CreateAccessToken provides a mock function with given fields: _a0, _a1
-func (_m *IAuthUC) CreateAccessToken(_a0 entity.User, _a1 int) (string, error) {
+// BeginPasskeyLogin provides a mock function with given fields: _a0
+func (_m *IAuthUC) BeginPasskeyLogin(_a0 *config.Config) (*dto.PasskeyCeremony, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginPasskeyLogin")
+	}
+
+	var r0 *dto.PasskeyCeremony
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*config.Config) (*dto.PasskeyCeremony, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(*config.Config) *dto.PasskeyCeremony); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.PasskeyCeremony)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*config.Config) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BeginPasskeyRegistration provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) BeginPasskeyRegistration(_a0 string, _a1 *config.Config) (*dto.PasskeyCeremony, error) {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateAccessToken")
+		panic("no return value specified for BeginPasskeyRegistration")
 	}
 
-	var r0 string
+	var r0 *dto.PasskeyCeremony
 	var r1 error
-	if rf, ok := ret.Get(0).(func(entity.User, int) (string, error)); ok {
+	if rf, ok := ret.Get(0).(func(string, *config.Config) (*dto.PasskeyCeremony, error)); ok {
 		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(entity.User, int) string); ok {
+	if rf, ok := ret.Get(0).(func(string, *config.Config) *dto.PasskeyCeremony); ok {
 		r0 = rf(_a0, _a1)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.PasskeyCeremony)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(entity.User, int) error); ok {
+	if rf, ok := ret.Get(1).(func(string, *config.Config) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -81,51 +174,96 @@ func (_m *IAuthUC) CreateAccessToken(_a0 entity.User, _a1 int) (string, error) {
 	return r0, r1
 }
 
-// CreateRefreshToken provides a mock function with given fields: _a0, _a1, _a2
-func (_m *IAuthUC) CreateRefreshToken(_a0 entity.User, _a1 string, _a2 int) (string, error) {
+// ChangePassword provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) ChangePassword(_a0 *gin.Context, _a1 dto.ChangePasswordRequestBody) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, dto.ChangePasswordRequestBody) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CheckAndRefreshTokens provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthUC) CheckAndRefreshTokens(_a0 string, _a1 string, _a2 *config.Config) (string, string, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateRefreshToken")
+		panic("no return value specified for CheckAndRefreshTokens")
 	}
 
 	var r0 string
-	var r1 error
-	if rf, ok := ret.Get(0).(func(entity.User, string, int) (string, error)); ok {
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, string, *config.Config) (string, string, error)); ok {
 		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(entity.User, string, int) string); ok {
+	if rf, ok := ret.Get(0).(func(string, string, *config.Config) string); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(entity.User, string, int) error); ok {
+	if rf, ok := ret.Get(1).(func(string, string, *config.Config) string); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(string)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(string, string, *config.Config) error); ok {
+		r2 = rf(_a0, _a1, _a2)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// IsRefreshTokenValidForAccessToken provides a mock function with given fields: _a0, _a1
-func (_m *IAuthUC) IsRefreshTokenValidForAccessToken(_a0 string, _a1 string) (bool, error) {
+// ClearLoginLock provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) ClearLoginLock(_a0 string, _a1 string) error {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IsRefreshTokenValidForAccessToken")
+		panic("no return value specified for ClearLoginLock")
 	}
 
-	var r0 bool
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmTOTP provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) ConfirmTOTP(_a0 string, _a1 string) ([]string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmTOTP")
+	}
+
+	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (bool, error)); ok {
+	if rf, ok := ret.Get(0).(func(string, string) ([]string, error)); ok {
 		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
+	if rf, ok := ret.Get(0).(func(string, string) []string); ok {
 		r0 = rf(_a0, _a1)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
 	}
 
 	if rf, ok := ret.Get(1).(func(string, string) error); ok {
@@ -137,23 +275,23 @@ func (_m *IAuthUC) IsRefreshTokenValidForAccessToken(_a0 string, _a1 string) (bo
 	return r0, r1
 }
 
-// IsTokenBlacklisted provides a mock function with given fields: _a0
-func (_m *IAuthUC) IsTokenBlacklisted(_a0 string) (bool, error) {
+// CountRecoveryCodes provides a mock function with given fields: _a0
+func (_m *IAuthUC) CountRecoveryCodes(_a0 string) (int64, error) {
 	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IsTokenBlacklisted")
+		panic("no return value specified for CountRecoveryCodes")
 	}
 
-	var r0 bool
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+	if rf, ok := ret.Get(0).(func(string) (int64, error)); ok {
 		return rf(_a0)
 	}
-	if rf, ok := ret.Get(0).(func(string) bool); ok {
+	if rf, ok := ret.Get(0).(func(string) int64); ok {
 		r0 = rf(_a0)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Get(0).(int64)
 	}
 
 	if rf, ok := ret.Get(1).(func(string) error); ok {
@@ -165,28 +303,26 @@ func (_m *IAuthUC) IsTokenBlacklisted(_a0 string) (bool, error) {
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: _a0, _a1
-func (_m *IAuthUC) Login(_a0 *gin.Context, _a1 dto.LoginRequestBody) (*dto.JwtTokens, error) {
+// CreateAccessToken provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) CreateAccessToken(_a0 entity.User, _a1 int) (string, error) {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for CreateAccessToken")
 	}
 
-	var r0 *dto.JwtTokens
+	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*gin.Context, dto.LoginRequestBody) (*dto.JwtTokens, error)); ok {
+	if rf, ok := ret.Get(0).(func(entity.User, int) (string, error)); ok {
 		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(*gin.Context, dto.LoginRequestBody) *dto.JwtTokens); ok {
+	if rf, ok := ret.Get(0).(func(entity.User, int) string); ok {
 		r0 = rf(_a0, _a1)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*dto.JwtTokens)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*gin.Context, dto.LoginRequestBody) error); ok {
+	if rf, ok := ret.Get(1).(func(entity.User, int) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -195,51 +331,56 @@ func (_m *IAuthUC) Login(_a0 *gin.Context, _a1 dto.LoginRequestBody) (*dto.JwtTo
 	return r0, r1
 }
 
-// Logout provides a mock function with given fields: c
-func (_m *IAuthUC) Logout(c *gin.Context) error {
-	ret := _m.Called(c)
+// CreateApplication provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) CreateApplication(_a0 dto.ApplicationCreateRequestBody, _a1 *config.Config) (*dto.ApplicationCredentials, error) {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Logout")
+		panic("no return value specified for CreateApplication")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*gin.Context) error); ok {
-		r0 = rf(c)
+	var r0 *dto.ApplicationCredentials
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.ApplicationCreateRequestBody, *config.Config) (*dto.ApplicationCredentials, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(dto.ApplicationCreateRequestBody, *config.Config) *dto.ApplicationCredentials); ok {
+		r0 = rf(_a0, _a1)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ApplicationCredentials)
+		}
 	}
 
-	return r0
-}
+	if rf, ok := ret.Get(1).(func(dto.ApplicationCreateRequestBody, *config.Config) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
 
-// Register provides a mock function with given fields:
-func (_m *IAuthUC) Register() {
-	_m.Called()
+	return r0, r1
 }
 
-// RetrieveFieldFromJwtToken provides a mock function with given fields: _a0, _a1, _a2
-func (_m *IAuthUC) RetrieveFieldFromJwtToken(_a0 string, _a1 string, _a2 bool) (interface{}, error) {
+// CreateRefreshToken provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthUC) CreateRefreshToken(_a0 entity.User, _a1 string, _a2 int) (string, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RetrieveFieldFromJwtToken")
+		panic("no return value specified for CreateRefreshToken")
 	}
 
-	var r0 interface{}
+	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, bool) (interface{}, error)); ok {
+	if rf, ok := ret.Get(0).(func(entity.User, string, int) (string, error)); ok {
 		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, bool) interface{}); ok {
+	if rf, ok := ret.Get(0).(func(entity.User, string, int) string); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(interface{})
-		}
+		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, bool) error); ok {
+	if rf, ok := ret.Get(1).(func(entity.User, string, int) error); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
@@ -248,26 +389,28 @@ func (_m *IAuthUC) RetrieveFieldFromJwtToken(_a0 string, _a1 string, _a2 bool) (
 	return r0, r1
 }
 
-// ValidateToken provides a mock function with given fields: _a0
-func (_m *IAuthUC) ValidateToken(_a0 string) (string, error) {
+// CreateServiceAccount provides a mock function with given fields: _a0
+func (_m *IAuthUC) CreateServiceAccount(_a0 dto.ServiceAccountCreateRequestBody) (*dto.ServiceAccountCredentials, error) {
 	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ValidateToken")
+		panic("no return value specified for CreateServiceAccount")
 	}
 
-	var r0 string
+	var r0 *dto.ServiceAccountCredentials
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+	if rf, ok := ret.Get(0).(func(dto.ServiceAccountCreateRequestBody) (*dto.ServiceAccountCredentials, error)); ok {
 		return rf(_a0)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
+	if rf, ok := ret.Get(0).(func(dto.ServiceAccountCreateRequestBody) *dto.ServiceAccountCredentials); ok {
 		r0 = rf(_a0)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ServiceAccountCredentials)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
+	if rf, ok := ret.Get(1).(func(dto.ServiceAccountCreateRequestBody) error); ok {
 		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
@@ -276,6 +419,1091 @@ func (_m *IAuthUC) ValidateToken(_a0 string) (string, error) {
 	return r0, r1
 }
 
+// DeleteApplication provides a mock function with given fields: _a0
+func (_m *IAuthUC) DeleteApplication(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteApplication")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePasskey provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) DeletePasskey(_a0 string, _a1 uint) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePasskey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, uint) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteServiceAccount provides a mock function with given fields: _a0
+func (_m *IAuthUC) DeleteServiceAccount(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteServiceAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableTOTP provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) DisableTOTP(_a0 string, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnrollTOTP provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) EnrollTOTP(_a0 string, _a1 *config.Config) (*dto.TOTPEnrollment, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollTOTP")
+	}
+
+	var r0 *dto.TOTPEnrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, *config.Config) (*dto.TOTPEnrollment, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, *config.Config) *dto.TOTPEnrollment); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.TOTPEnrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *config.Config) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExchangeAuthorizationCode provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) ExchangeAuthorizationCode(_a0 dto.TokenRequestBody, _a1 *config.Config) (*dto.OIDCTokens, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExchangeAuthorizationCode")
+	}
+
+	var r0 *dto.OIDCTokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.TokenRequestBody, *config.Config) (*dto.OIDCTokens, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(dto.TokenRequestBody, *config.Config) *dto.OIDCTokens); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.OIDCTokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.TokenRequestBody, *config.Config) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishFederation provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4, _a5
+func (_m *IAuthUC) FinishFederation(_a0 context.Context, _a1 dto.FederationCallbackQuery, _a2 string, _a3 string, _a4 uint, _a5 *config.Config) (*dto.FederationResult, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4, _a5)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishFederation")
+	}
+
+	var r0 *dto.FederationResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.FederationCallbackQuery, string, string, uint, *config.Config) (*dto.FederationResult, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.FederationCallbackQuery, string, string, uint, *config.Config) *dto.FederationResult); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.FederationResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.FederationCallbackQuery, string, string, uint, *config.Config) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4, _a5)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishPasskeyLogin provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) FinishPasskeyLogin(_a0 dto.PasskeyLoginRequestBody, _a1 *config.Config) (*dto.JwtTokens, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishPasskeyLogin")
+	}
+
+	var r0 *dto.JwtTokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.PasskeyLoginRequestBody, *config.Config) (*dto.JwtTokens, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(dto.PasskeyLoginRequestBody, *config.Config) *dto.JwtTokens); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.JwtTokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.PasskeyLoginRequestBody, *config.Config) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishPasskeyRegistration provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthUC) FinishPasskeyRegistration(_a0 string, _a1 dto.PasskeyRegistrationRequestBody, _a2 *config.Config) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishPasskeyRegistration")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, dto.PasskeyRegistrationRequestBody, *config.Config) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ForwardAuth provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) ForwardAuth(_a0 dto.ForwardAuthRequest, _a1 *config.Config) (*dto.ForwardAuthResult, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForwardAuth")
+	}
+
+	var r0 *dto.ForwardAuthResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.ForwardAuthRequest, *config.Config) (*dto.ForwardAuthResult, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(dto.ForwardAuthRequest, *config.Config) *dto.ForwardAuthResult); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ForwardAuthResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.ForwardAuthRequest, *config.Config) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateTokens provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) GenerateTokens(_a0 entity.User, _a1 *config.Config) (*dto.JwtTokens, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateTokens")
+	}
+
+	var r0 *dto.JwtTokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.User, *config.Config) (*dto.JwtTokens, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(entity.User, *config.Config) *dto.JwtTokens); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.JwtTokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.User, *config.Config) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantApplicationAccess provides a mock function with given fields: _a0
+func (_m *IAuthUC) GrantApplicationAccess(_a0 dto.ApplicationGrantRequestBody) (*entity.ApplicationGrant, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantApplicationAccess")
+	}
+
+	var r0 *entity.ApplicationGrant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.ApplicationGrantRequestBody) (*entity.ApplicationGrant, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(dto.ApplicationGrantRequestBody) *entity.ApplicationGrant); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ApplicationGrant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.ApplicationGrantRequestBody) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IntrospectToken provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) IntrospectToken(_a0 dto.IntrospectionRequestBody, _a1 *config.Config) (*dto.TokenIntrospection, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IntrospectToken")
+	}
+
+	var r0 *dto.TokenIntrospection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.IntrospectionRequestBody, *config.Config) (*dto.TokenIntrospection, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(dto.IntrospectionRequestBody, *config.Config) *dto.TokenIntrospection); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.TokenIntrospection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.IntrospectionRequestBody, *config.Config) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsRefreshTokenValidForAccessToken provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) IsRefreshTokenValidForAccessToken(_a0 string, _a1 string) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsRefreshTokenValidForAccessToken")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (bool, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsTokenBlacklisted provides a mock function with given fields: _a0
+func (_m *IAuthUC) IsTokenBlacklisted(_a0 string) (bool, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTokenBlacklisted")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueServiceToken provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) IssueServiceToken(_a0 dto.TokenRequestBody, _a1 *config.Config) (*dto.ServiceToken, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueServiceToken")
+	}
+
+	var r0 *dto.ServiceToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.TokenRequestBody, *config.Config) (*dto.ServiceToken, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(dto.TokenRequestBody, *config.Config) *dto.ServiceToken); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ServiceToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.TokenRequestBody, *config.Config) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListApplications provides a mock function with given fields:
+func (_m *IAuthUC) ListApplications() ([]entity.Application, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListApplications")
+	}
+
+	var r0 []entity.Application
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.Application, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.Application); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Application)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListLauncherApplications provides a mock function with given fields: _a0
+func (_m *IAuthUC) ListLauncherApplications(_a0 string) ([]entity.Application, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLauncherApplications")
+	}
+
+	var r0 []entity.Application
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]entity.Application, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []entity.Application); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Application)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListLinkedIdentities provides a mock function with given fields: _a0
+func (_m *IAuthUC) ListLinkedIdentities(_a0 string) ([]entity.LinkedIdentity, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLinkedIdentities")
+	}
+
+	var r0 []entity.LinkedIdentity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]entity.LinkedIdentity, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []entity.LinkedIdentity); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.LinkedIdentity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListLoginLocks provides a mock function with given fields:
+func (_m *IAuthUC) ListLoginLocks() ([]entity.LoginLock, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLoginLocks")
+	}
+
+	var r0 []entity.LoginLock
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.LoginLock, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.LoginLock); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.LoginLock)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPasskeys provides a mock function with given fields: _a0
+func (_m *IAuthUC) ListPasskeys(_a0 string) ([]entity.WebAuthnCredential, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPasskeys")
+	}
+
+	var r0 []entity.WebAuthnCredential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]entity.WebAuthnCredential, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []entity.WebAuthnCredential); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.WebAuthnCredential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListServiceAccounts provides a mock function with given fields:
+func (_m *IAuthUC) ListServiceAccounts() ([]entity.ServiceAccount, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListServiceAccounts")
+	}
+
+	var r0 []entity.ServiceAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.ServiceAccount, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.ServiceAccount); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ServiceAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSessions provides a mock function with given fields: _a0
+func (_m *IAuthUC) ListSessions(_a0 string) ([]entity.Session, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessions")
+	}
+
+	var r0 []entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]entity.Session, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []entity.Session); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) Login(_a0 *gin.Context, _a1 dto.LoginRequestBody) (*dto.JwtTokens, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *dto.JwtTokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, dto.LoginRequestBody) (*dto.JwtTokens, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, dto.LoginRequestBody) *dto.JwtTokens); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.JwtTokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, dto.LoginRequestBody) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: c
+func (_m *IAuthUC) Logout(c *gin.Context) error {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gin.Context) error); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LogoutEverywhere provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) LogoutEverywhere(_a0 string, _a1 *config.Config) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LogoutEverywhere")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *config.Config) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OpenIDConfiguration provides a mock function with given fields: _a0
+func (_m *IAuthUC) OpenIDConfiguration(_a0 *config.Config) dto.OpenIDConfiguration {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenIDConfiguration")
+	}
+
+	var r0 dto.OpenIDConfiguration
+	if rf, ok := ret.Get(0).(func(*config.Config) dto.OpenIDConfiguration); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(dto.OpenIDConfiguration)
+	}
+
+	return r0
+}
+
+// RegenerateRecoveryCodes provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) RegenerateRecoveryCodes(_a0 string, _a1 string) ([]string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegenerateRecoveryCodes")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Register provides a mock function with given fields:
+func (_m *IAuthUC) Register() {
+	_m.Called()
+}
+
+// RequestPasswordReset provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) RequestPasswordReset(_a0 string, _a1 *config.Config) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestPasswordReset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *config.Config) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthUC) ResetPassword(_a0 string, _a1 string, _a2 *config.Config) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, *config.Config) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RetrieveFieldFromJwtToken provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthUC) RetrieveFieldFromJwtToken(_a0 string, _a1 string, _a2 bool) (interface{}, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetrieveFieldFromJwtToken")
+	}
+
+	var r0 interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, bool) (interface{}, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, bool) interface{}); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeApplicationAccess provides a mock function with given fields: _a0
+func (_m *IAuthUC) RevokeApplicationAccess(_a0 dto.ApplicationGrantDeleteRequestBody) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeApplicationAccess")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(dto.ApplicationGrantDeleteRequestBody) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeSession provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthUC) RevokeSession(_a0 string, _a1 string, _a2 *config.Config) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, *config.Config) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeToken provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) RevokeToken(_a0 dto.RevocationRequestBody, _a1 *config.Config) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(dto.RevocationRequestBody, *config.Config) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendVerificationEmail provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) SendVerificationEmail(_a0 entity.User, _a1 *config.Config) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendVerificationEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.User, *config.Config) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchSession provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthUC) TouchSession(_a0 string, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnlinkIdentity provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) UnlinkIdentity(_a0 string, _a1 uint) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlinkIdentity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, uint) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateApplication provides a mock function with given fields: _a0
+func (_m *IAuthUC) UpdateApplication(_a0 dto.ApplicationUpdateRequestBody) (*entity.Application, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateApplication")
+	}
+
+	var r0 *entity.Application
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.ApplicationUpdateRequestBody) (*entity.Application, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(dto.ApplicationUpdateRequestBody) *entity.Application); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Application)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.ApplicationUpdateRequestBody) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserInfo provides a mock function with given fields: _a0
+func (_m *IAuthUC) UserInfo(_a0 string) (map[string]interface{}, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserInfo")
+	}
+
+	var r0 map[string]interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (map[string]interface{}, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) map[string]interface{}); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ValidateServiceToken provides a mock function with given fields: _a0
+func (_m *IAuthUC) ValidateServiceToken(_a0 string) (*entity.ServiceAccountToken, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateServiceToken")
+	}
+
+	var r0 *entity.ServiceAccountToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.ServiceAccountToken, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.ServiceAccountToken); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ServiceAccountToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ValidateToken provides a mock function with given fields: _a0
+func (_m *IAuthUC) ValidateToken(_a0 string) (string, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyEmail provides a mock function with given fields: _a0, _a1
+func (_m *IAuthUC) VerifyEmail(_a0 string, _a1 *config.Config) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *config.Config) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyMFALogin provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthUC) VerifyMFALogin(_a0 dto.MFALoginRequestBody, _a1 string, _a2 *config.Config) (*dto.JwtTokens, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyMFALogin")
+	}
+
+	var r0 *dto.JwtTokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.MFALoginRequestBody, string, *config.Config) (*dto.JwtTokens, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(dto.MFALoginRequestBody, string, *config.Config) *dto.JwtTokens); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.JwtTokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.MFALoginRequestBody, string, *config.Config) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewIAuthUC creates a new instance of IAuthUC. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIAuthUC(t interface {
@@ -2,13 +2,64 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	dto "github.com/minhmannh2001/authconnecthub/internal/dto"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // IRoleUC is an autogenerated mock type for the IRoleUC type
 type IRoleUC struct {
 	mock.Mock
 }
 
+// CreateRole provides a mock function with given fields: _a0
+func (_m *IRoleUC) CreateRole(_a0 dto.RoleCreateRequestBody) (*dto.AdminRole, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRole")
+	}
+
+	var r0 *dto.AdminRole
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.RoleCreateRequestBody) (*dto.AdminRole, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(dto.RoleCreateRequestBody) *dto.AdminRole); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.AdminRole)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.RoleCreateRequestBody) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteRole provides a mock function with given fields: _a0
+func (_m *IRoleUC) DeleteRole(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetRoleIDByName provides a mock function with given fields: _a0
 func (_m *IRoleUC) GetRoleIDByName(_a0 string) (uint, error) {
 	ret := _m.Called(_a0)
@@ -37,6 +88,96 @@ func (_m *IRoleUC) GetRoleIDByName(_a0 string) (uint, error) {
 	return r0, r1
 }
 
+// ListPermissions provides a mock function with given fields:
+func (_m *IRoleUC) ListPermissions() ([]dto.AdminPermission, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPermissions")
+	}
+
+	var r0 []dto.AdminPermission
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]dto.AdminPermission, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []dto.AdminPermission); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.AdminPermission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRoles provides a mock function with given fields:
+func (_m *IRoleUC) ListRoles() ([]dto.AdminRole, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRoles")
+	}
+
+	var r0 []dto.AdminRole
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]dto.AdminRole, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []dto.AdminRole); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.AdminRole)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRole provides a mock function with given fields: _a0
+func (_m *IRoleUC) UpdateRole(_a0 dto.RoleUpdateRequestBody) (*dto.AdminRole, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRole")
+	}
+
+	var r0 *dto.AdminRole
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.RoleUpdateRequestBody) (*dto.AdminRole, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(dto.RoleUpdateRequestBody) *dto.AdminRole); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.AdminRole)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.RoleUpdateRequestBody) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewIRoleUC creates a new instance of IRoleUC. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIRoleUC(t interface {
@@ -3,6 +3,9 @@
 package mocks
 
 import (
+	config "github.com/minhmannh2001/authconnecthub/config"
+
+	dto "github.com/minhmannh2001/authconnecthub/internal/dto"
 	entity "github.com/minhmannh2001/authconnecthub/internal/entity"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -40,6 +43,66 @@ func (_m *IUserUC) Create(_a0 entity.User) (entity.User, error) {
 	return r0, r1
 }
 
+// CreateUser provides a mock function with given fields: _a0
+func (_m *IUserUC) CreateUser(_a0 dto.UserCreateRequestBody) (*dto.AdminUser, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 *dto.AdminUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.UserCreateRequestBody) (*dto.AdminUser, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(dto.UserCreateRequestBody) *dto.AdminUser); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.AdminUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.UserCreateRequestBody) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteUser provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IUserUC) DeleteUser(_a0 uint, _a1 string, _a2 *config.Config) (*dto.AdminUser, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 *dto.AdminUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, string, *config.Config) (*dto.AdminUser, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(uint, string, *config.Config) *dto.AdminUser); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.AdminUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, string, *config.Config) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByUsernameOrEmail provides a mock function with given fields: _a0, _a1
 func (_m *IUserUC) FindByUsernameOrEmail(_a0 string, _a1 string) (*entity.User, error) {
 	ret := _m.Called(_a0, _a1)
@@ -70,6 +133,184 @@ func (_m *IUserUC) FindByUsernameOrEmail(_a0 string, _a1 string) (*entity.User,
 	return r0, r1
 }
 
+// GetUser provides a mock function with given fields: _a0
+func (_m *IUserUC) GetUser(_a0 uint) (*dto.AdminUser, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUser")
+	}
+
+	var r0 *dto.AdminUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*dto.AdminUser, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *dto.AdminUser); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.AdminUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUsers provides a mock function with given fields: _a0
+func (_m *IUserUC) ListUsers(_a0 dto.UserListQuery) (*dto.UserPage, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 *dto.UserPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.UserListQuery) (*dto.UserPage, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(dto.UserListQuery) *dto.UserPage); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.UserPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.UserListQuery) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreUser provides a mock function with given fields: _a0
+func (_m *IUserUC) RestoreUser(_a0 uint) (*dto.AdminUser, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreUser")
+	}
+
+	var r0 *dto.AdminUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*dto.AdminUser, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *dto.AdminUser); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.AdminUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetUserDisabled provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *IUserUC) SetUserDisabled(_a0 uint, _a1 bool, _a2 string, _a3 *config.Config) (*dto.AdminUser, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserDisabled")
+	}
+
+	var r0 *dto.AdminUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, bool, string, *config.Config) (*dto.AdminUser, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(uint, bool, string, *config.Config) *dto.AdminUser); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.AdminUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, bool, string, *config.Config) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: _a0
+func (_m *IUserUC) Update(_a0 entity.User) (entity.User, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.User) (entity.User, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(entity.User) entity.User); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(entity.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.User) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUser provides a mock function with given fields: _a0, _a1
+func (_m *IUserUC) UpdateUser(_a0 dto.UserUpdateRequestBody, _a1 string) (*dto.AdminUser, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 *dto.AdminUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dto.UserUpdateRequestBody, string) (*dto.AdminUser, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(dto.UserUpdateRequestBody, string) *dto.AdminUser); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.AdminUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dto.UserUpdateRequestBody, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewIUserUC creates a new instance of IUserUC. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIUserUC(t interface {
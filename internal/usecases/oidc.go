@@ -0,0 +1,342 @@
+package usecases
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+)
+
+// codeChallengeMethodS256 is the only PKCE method accepted, plain would leak the verifier with the challenge
+const codeChallengeMethodS256 = "S256"
+
+// oidcScopes are the scopes the hub understands, others are ignored
+var oidcScopes = []string{entity.ScopeOpenID, entity.ScopeProfile, entity.ScopeEmail}
+
+// Authorize checks an authorization request of a client on behalf of the logged in user. It returns
+// where to send the browser: back to the client with an authorization code, or with an error the
+// client has to handle. An empty username means the user still has to log in.
+func (au *AuthUseCase) Authorize(req dto.AuthorizeRequest, username string, cfg *config.Config) (string, error) {
+	client, err := au.findOIDCClient(req.ClientID, cfg)
+	if err != nil {
+		return "", err
+	}
+	if client == nil || !slices.Contains(client.redirectURIs, req.RedirectURI) {
+		return "", &entity.InvalidOIDCClientError{}
+	}
+
+	scope, oauthErr := checkAuthorizeRequest(req, client)
+	if oauthErr == nil && username == "" {
+		if req.Prompt != "none" {
+			return "", &entity.OIDCLoginRequiredError{}
+		}
+		oauthErr = &entity.OAuthError{Code: entity.OAuthErrorLoginRequired, Description: "The user isn't logged in."}
+	}
+	if oauthErr == nil && client.applicationID != 0 {
+		user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+		if err != nil {
+			return "", err
+		}
+		_, ok, err := au.clientAccess(client, user)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			oauthErr = &entity.OAuthError{Code: entity.OAuthErrorAccessDenied, Description: "The user may not use the application."}
+		}
+	}
+	if oauthErr != nil {
+		return authorizationRedirect(req.RedirectURI, url.Values{
+			"error":             {oauthErr.Code},
+			"error_description": {oauthErr.Description},
+		}, req.State), nil
+	}
+
+	code, err := generateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = au.authRepo.SaveAuthorizationCode(code, entity.AuthorizationCode{
+		ClientID:            client.id,
+		RedirectURI:         req.RedirectURI,
+		Username:            username,
+		Scope:               scope,
+		Nonce:               req.Nonce,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+	}, cfg.OIDC.AuthorizationCodeTTL)
+	if err != nil {
+		return "", err
+	}
+
+	return authorizationRedirect(req.RedirectURI, url.Values{"code": {code}}, req.State), nil
+}
+
+// checkAuthorizeRequest returns the scopes granted to the client
+func checkAuthorizeRequest(req dto.AuthorizeRequest, client *oidcClient) (string, *entity.OAuthError) {
+	if req.ResponseType != "code" {
+		return "", &entity.OAuthError{Code: entity.OAuthErrorUnsupportedResponseType, Description: "Only the authorization code flow is supported."}
+	}
+
+	var scopes []string
+	for _, scope := range strings.Fields(req.Scope) {
+		if slices.Contains(oidcScopes, scope) && !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if !slices.Contains(scopes, entity.ScopeOpenID) {
+		return "", &entity.OAuthError{Code: entity.OAuthErrorInvalidScope, Description: "The openid scope is required."}
+	}
+
+	if req.CodeChallenge == "" {
+		if client.isPublic() {
+			return "", &entity.OAuthError{Code: entity.OAuthErrorInvalidRequest, Description: "Public clients have to use PKCE."}
+		}
+	} else if req.CodeChallengeMethod != codeChallengeMethodS256 {
+		return "", &entity.OAuthError{Code: entity.OAuthErrorInvalidRequest, Description: "The code challenge method has to be S256."}
+	}
+
+	return strings.Join(scopes, " "), nil
+}
+
+func authorizationRedirect(redirectURI string, params url.Values, state string) string {
+	if state != "" {
+		params.Set("state", state)
+	}
+
+	separator := "?"
+	if strings.Contains(redirectURI, "?") {
+		separator = "&"
+	}
+	return redirectURI + separator + params.Encode()
+}
+
+// ExchangeAuthorizationCode redeems an authorization code for an access token and an ID token
+func (au *AuthUseCase) ExchangeAuthorizationCode(req dto.TokenRequestBody, cfg *config.Config) (*dto.OIDCTokens, error) {
+	if req.GrantType != "authorization_code" {
+		return nil, &entity.OAuthError{Code: entity.OAuthErrorUnsupportedGrantType, Description: "Only the authorization_code grant is supported."}
+	}
+
+	client, err := au.authenticateOIDCClient(req.ClientID, req.ClientSecret, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Code == "" {
+		return nil, &entity.OAuthError{Code: entity.OAuthErrorInvalidRequest, Description: "The code is missing."}
+	}
+
+	// the code is checked before it is redeemed, so another client can't throw it away
+	authorizationCode, err := au.authRepo.FindAuthorizationCode(req.Code)
+	if err != nil {
+		return nil, err
+	}
+	invalidGrantErr := &entity.OAuthError{Code: entity.OAuthErrorInvalidGrant, Description: "The authorization code is invalid or has expired."}
+	if authorizationCode == nil || authorizationCode.ClientID != client.id || authorizationCode.RedirectURI != req.RedirectURI {
+		return nil, invalidGrantErr
+	}
+	if !verifyCodeChallenge(authorizationCode.CodeChallenge, req.CodeVerifier) {
+		return nil, invalidGrantErr
+	}
+
+	// only one of concurrent requests redeems the code
+	authorizationCode, err = au.authRepo.ConsumeAuthorizationCode(req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if authorizationCode == nil {
+		return nil, invalidGrantErr
+	}
+
+	// the user may have been disabled or deleted since the code was issued
+	user, err := au.userUseCase.FindByUsernameOrEmail(authorizationCode.Username, "")
+	if helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+		return nil, invalidGrantErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, invalidGrantErr
+	}
+
+	// the grant of the user may have been taken away since the code was issued
+	appRole, ok, err := au.clientAccess(client, user)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, invalidGrantErr
+	}
+
+	accessTokenTTL := client.accessTokenTTL
+	if accessTokenTTL <= 0 {
+		accessTokenTTL = cfg.OIDC.AccessTokenTTL
+	}
+
+	accessToken, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+	err = au.authRepo.SaveOIDCAccessToken(accessToken, entity.OIDCAccessToken{
+		ClientID: client.id,
+		Username: user.Username,
+		Scope:    authorizationCode.Scope,
+	}, accessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := au.createIDToken(*user, client, appRole, authorizationCode, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OIDCTokens{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   accessTokenTTL,
+		IDToken:     idToken,
+		Scope:       authorizationCode.Scope,
+	}, nil
+}
+
+// authenticateOIDCClient checks the secret of confidential clients, public clients only name themselves
+func (au *AuthUseCase) authenticateOIDCClient(clientID string, clientSecret string, cfg *config.Config) (*oidcClient, error) {
+	client, err := au.findOIDCClient(clientID, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil || !client.authenticate(clientSecret) {
+		return nil, &entity.OAuthError{Code: entity.OAuthErrorInvalidClient, Description: "The client credentials are invalid."}
+	}
+
+	return client, nil
+}
+
+// verifyCodeChallenge checks the PKCE verifier against the challenge of the authorization request.
+// A verifier without a challenge is rejected too, it means the request was tampered with.
+func verifyCodeChallenge(challenge string, verifier string) bool {
+	if challenge == "" {
+		return verifier == ""
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// createIDToken issues the ID token for the client, the client id is its audience. The role the user
+// has within the application is in the app_role claim.
+func (au *AuthUseCase) createIDToken(user entity.User, client *oidcClient, appRole string, authorizationCode *entity.AuthorizationCode, cfg *config.Config) (string, error) {
+	idTokenTTL := client.idTokenTTL
+	if idTokenTTL <= 0 {
+		idTokenTTL = cfg.OIDC.IDTokenTTL
+	}
+
+	claims := jwt.MapClaims(userInfoClaims(user, authorizationCode.Scope))
+	claims["iss"] = oidcIssuer(cfg)
+	claims["aud"] = client.id
+	claims["token_use"] = tokenUseID
+	claims["exp"] = time.Now().Add(time.Second * time.Duration(idTokenTTL)).Unix()
+	claims["iat"] = time.Now().Unix()
+	if authorizationCode.Nonce != "" {
+		claims["nonce"] = authorizationCode.Nonce
+	}
+	if appRole != "" {
+		claims["app_role"] = appRole
+	}
+
+	return au.signToken(claims)
+}
+
+// UserInfo returns the claims about the user the access token was issued for, as far as its scope allows
+func (au *AuthUseCase) UserInfo(accessToken string) (map[string]interface{}, error) {
+	token, user, err := au.findActiveOIDCAccessToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, &entity.OAuthError{Code: entity.OAuthErrorInvalidToken, Description: "The access token is invalid or has expired."}
+	}
+
+	return userInfoClaims(*user, token.Scope), nil
+}
+
+// findActiveOIDCAccessToken finds an access token with its user. The tokens of users who were disabled
+// or deleted since they were issued are reported as missing, they can't be revoked one by one.
+func (au *AuthUseCase) findActiveOIDCAccessToken(accessToken string) (*entity.OIDCAccessToken, *entity.User, error) {
+	token, err := au.authRepo.FindOIDCAccessToken(accessToken)
+	if err != nil || token == nil {
+		return nil, nil, err
+	}
+
+	user, err := au.userUseCase.FindByUsernameOrEmail(token.Username, "")
+	if helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.Disabled {
+		return nil, nil, nil
+	}
+	return token, user, nil
+}
+
+func userInfoClaims(user entity.User, scope string) map[string]interface{} {
+	scopes := strings.Fields(scope)
+
+	claims := map[string]interface{}{"sub": user.Username}
+	if slices.Contains(scopes, entity.ScopeProfile) {
+		claims["preferred_username"] = user.Username
+		claims["name"] = user.Username
+	}
+	if slices.Contains(scopes, entity.ScopeEmail) {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.EmailVerified
+	}
+	return claims
+}
+
+// OpenIDConfiguration returns the discovery document which tells clients where the endpoints are
+func (au *AuthUseCase) OpenIDConfiguration(cfg *config.Config) dto.OpenIDConfiguration {
+	issuer := oidcIssuer(cfg)
+
+	var algorithms []string
+	if au.keyring != nil {
+		algorithms = au.keyring.Algorithms()
+	}
+
+	return dto.OpenIDConfiguration{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/v1/oidc/authorize",
+		TokenEndpoint:                     issuer + "/v2/oidc/token",
+		UserinfoEndpoint:                  issuer + "/v2/oidc/userinfo",
+		JwksURI:                           issuer + "/.well-known/jwks.json",
+		IntrospectionEndpoint:             issuer + "/oauth/introspect",
+		RevocationEndpoint:                issuer + "/oauth/revoke",
+		ScopesSupported:                   oidcScopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  algorithms,
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{codeChallengeMethodS256},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "nonce", "preferred_username", "name", "email", "email_verified"},
+	}
+}
+
+func oidcIssuer(cfg *config.Config) string {
+	return strings.TrimSuffix(cfg.App.BaseURL, "/")
+}
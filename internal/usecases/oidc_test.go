@@ -0,0 +1,343 @@
+package usecases_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const codeVerifier = "dBjftJeZ4CVP-mJ0kTBcasdfgAqJ2-0jZtCTVkM-0PAk"
+
+func newOIDCConfig(t *testing.T) *config.Config {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	return &config.Config{
+		App:    config.App{BaseURL: "https://hub.home/"},
+		Authen: config.Authen{JwtPrivateKey: privateKey},
+		OIDC: config.OIDC{
+			AuthorizationCodeTTL: 60,
+			AccessTokenTTL:       3600,
+			IDTokenTTL:           3600,
+			Clients: []config.OIDCClient{
+				{ID: "grafana", Secret: "grafana-secret", Name: "Grafana", RedirectURIs: []string{"https://grafana.home/login/generic_oauth"}},
+				{ID: "spa", Name: "Single Page App", RedirectURIs: []string{"https://spa.home/callback?source=hub"}},
+			},
+		},
+	}
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestAuthUseCase_Authorize_Success(t *testing.T) {
+	cfg := newOIDCConfig(t)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	var code string
+	mockAuthRepo.On("SaveAuthorizationCode", mock.Anything, entity.AuthorizationCode{
+		ClientID:            "spa",
+		RedirectURI:         "https://spa.home/callback?source=hub",
+		Username:            "testuser",
+		Scope:               "openid email",
+		Nonce:               "n-0S6_WzA2Mj",
+		CodeChallenge:       codeChallenge(codeVerifier),
+		CodeChallengeMethod: "S256",
+	}, 60).Run(func(args mock.Arguments) {
+		code = args.String(0)
+	}).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, cfg)
+
+	redirectURL, err := uc.Authorize(dto.AuthorizeRequest{
+		ResponseType:        "code",
+		ClientID:            "spa",
+		RedirectURI:         "https://spa.home/callback?source=hub",
+		Scope:               "openid email offline_access email",
+		State:               "af0ifjsldkj",
+		Nonce:               "n-0S6_WzA2Mj",
+		CodeChallenge:       codeChallenge(codeVerifier),
+		CodeChallengeMethod: "S256",
+	}, "testuser", cfg)
+
+	assert.NoError(t, err)
+	parsed, err := url.Parse(redirectURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "spa.home", parsed.Host)
+	assert.Equal(t, "hub", parsed.Query().Get("source"))
+	assert.Equal(t, "af0ifjsldkj", parsed.Query().Get("state"))
+	assert.NotEmpty(t, code)
+	assert.Equal(t, code, parsed.Query().Get("code"))
+}
+
+func TestAuthUseCase_Authorize_UnknownClient(t *testing.T) {
+	cfg := newOIDCConfig(t)
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockApplicationRepo := repoMocks.NewIApplicationRepo(t)
+	mockApplicationRepo.On("FindApplicationByClientID", "gitea").Return(nil, nil)
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, nil, nil, cfg)
+
+	tests := []dto.AuthorizeRequest{
+		{ResponseType: "code", ClientID: "gitea", RedirectURI: "https://gitea.home/callback", Scope: "openid"},
+		// the user mustn't be sent to a redirect uri which isn't registered, not even with an error
+		{ResponseType: "code", ClientID: "grafana", RedirectURI: "https://evil.example/callback", Scope: "openid"},
+	}
+
+	for _, req := range tests {
+		redirectURL, err := uc.Authorize(req, "testuser", cfg)
+
+		assert.Equal(t, &entity.InvalidOIDCClientError{}, err)
+		assert.Empty(t, redirectURL)
+	}
+}
+
+func TestAuthUseCase_Authorize_LoginRequired(t *testing.T) {
+	cfg := newOIDCConfig(t)
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, nil, nil, cfg)
+	req := dto.AuthorizeRequest{ResponseType: "code", ClientID: "grafana", RedirectURI: "https://grafana.home/login/generic_oauth", Scope: "openid", State: "xyz"}
+
+	redirectURL, err := uc.Authorize(req, "", cfg)
+	assert.Equal(t, &entity.OIDCLoginRequiredError{}, err)
+	assert.Empty(t, redirectURL)
+
+	// applications checking silently whether the user is logged in get an error instead of the login page
+	req.Prompt = "none"
+	redirectURL, err = uc.Authorize(req, "", cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://grafana.home/login/generic_oauth?error=login_required&error_description=The+user+isn%27t+logged+in.&state=xyz", redirectURL)
+}
+
+func TestAuthUseCase_Authorize_InvalidRequest(t *testing.T) {
+	cfg := newOIDCConfig(t)
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, nil, nil, cfg)
+
+	tests := []struct {
+		name  string
+		req   dto.AuthorizeRequest
+		error string
+	}{
+		{"implicit flow", dto.AuthorizeRequest{ResponseType: "id_token", ClientID: "grafana", RedirectURI: "https://grafana.home/login/generic_oauth", Scope: "openid"}, "unsupported_response_type"},
+		{"no openid scope", dto.AuthorizeRequest{ResponseType: "code", ClientID: "grafana", RedirectURI: "https://grafana.home/login/generic_oauth", Scope: "profile email"}, "invalid_scope"},
+		{"public client without PKCE", dto.AuthorizeRequest{ResponseType: "code", ClientID: "spa", RedirectURI: "https://spa.home/callback?source=hub", Scope: "openid"}, "invalid_request"},
+		{"plain PKCE", dto.AuthorizeRequest{ResponseType: "code", ClientID: "grafana", RedirectURI: "https://grafana.home/login/generic_oauth", Scope: "openid", CodeChallenge: codeVerifier, CodeChallengeMethod: "plain"}, "invalid_request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redirectURL, err := uc.Authorize(tt.req, "testuser", cfg)
+
+			assert.NoError(t, err)
+			parsed, err := url.Parse(redirectURL)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.error, parsed.Query().Get("error"))
+			assert.Empty(t, parsed.Query().Get("code"))
+		})
+	}
+}
+
+func TestAuthUseCase_ExchangeAuthorizationCode_Success(t *testing.T) {
+	cfg := newOIDCConfig(t)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	authorizationCode := &entity.AuthorizationCode{
+		ClientID:            "spa",
+		RedirectURI:         "https://spa.home/callback?source=hub",
+		Username:            "testuser",
+		Scope:               "openid email",
+		Nonce:               "n-0S6_WzA2Mj",
+		CodeChallenge:       codeChallenge(codeVerifier),
+		CodeChallengeMethod: "S256",
+	}
+	mockAuthRepo.On("FindAuthorizationCode", "the-code").Return(authorizationCode, nil)
+	mockAuthRepo.On("ConsumeAuthorizationCode", "the-code").Return(authorizationCode, nil)
+	mockAuthRepo.On("SaveOIDCAccessToken", mock.Anything, entity.OIDCAccessToken{ClientID: "spa", Username: "testuser", Scope: "openid email"}, 3600).Return(nil)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser", Email: "test@example.com", EmailVerified: true}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, cfg)
+
+	tokens, err := uc.ExchangeAuthorizationCode(dto.TokenRequestBody{
+		GrantType:    "authorization_code",
+		Code:         "the-code",
+		RedirectURI:  "https://spa.home/callback?source=hub",
+		ClientID:     "spa",
+		CodeVerifier: codeVerifier,
+	}, cfg)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.Equal(t, 3600, tokens.ExpiresIn)
+	assert.Equal(t, "openid email", tokens.Scope)
+
+	privateKey := cfg.Authen.JwtPrivateKey.(*rsa.PrivateKey)
+	idToken, err := jwt.Parse(tokens.IDToken, func(token *jwt.Token) (interface{}, error) {
+		return &privateKey.PublicKey, nil
+	}, jwt.WithIssuer("https://hub.home"), jwt.WithAudience("spa"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, idToken.Header["kid"])
+
+	claims := idToken.Claims.(jwt.MapClaims)
+	assert.Equal(t, "testuser", claims["sub"])
+	assert.Equal(t, "n-0S6_WzA2Mj", claims["nonce"])
+	assert.Equal(t, "test@example.com", claims["email"])
+	assert.Equal(t, true, claims["email_verified"])
+	assert.NotContains(t, claims, "preferred_username")
+
+	// the ID token isn't accepted by the hub itself
+	username, err := uc.ValidateToken(tokens.IDToken)
+	assert.EqualError(t, err, "token isn't meant for the hub")
+	assert.Empty(t, username)
+}
+
+func TestAuthUseCase_ExchangeAuthorizationCode_Rejected(t *testing.T) {
+	grafanaCode := &entity.AuthorizationCode{ClientID: "grafana", RedirectURI: "https://grafana.home/login/generic_oauth", Username: "testuser", Scope: "openid"}
+	pkceCode := &entity.AuthorizationCode{ClientID: "spa", RedirectURI: "https://spa.home/callback?source=hub", Username: "testuser", Scope: "openid", CodeChallenge: codeChallenge(codeVerifier), CodeChallengeMethod: "S256"}
+
+	tests := []struct {
+		name  string
+		req   dto.TokenRequestBody
+		code  *entity.AuthorizationCode
+		error string
+	}{
+		{"refresh token grant", dto.TokenRequestBody{GrantType: "refresh_token", ClientID: "grafana", ClientSecret: "grafana-secret"}, nil, "unsupported_grant_type"},
+		{"wrong secret", dto.TokenRequestBody{GrantType: "authorization_code", Code: "the-code", ClientID: "grafana", ClientSecret: "wrong"}, nil, "invalid_client"},
+		{"unknown client", dto.TokenRequestBody{GrantType: "authorization_code", Code: "the-code", ClientID: "gitea"}, nil, "invalid_client"},
+		{"missing code", dto.TokenRequestBody{GrantType: "authorization_code", ClientID: "grafana", ClientSecret: "grafana-secret"}, nil, "invalid_request"},
+		{"unknown code", dto.TokenRequestBody{GrantType: "authorization_code", Code: "the-code", RedirectURI: "https://grafana.home/login/generic_oauth", ClientID: "grafana", ClientSecret: "grafana-secret"}, nil, "invalid_grant"},
+		{"code of another client", dto.TokenRequestBody{GrantType: "authorization_code", Code: "the-code", RedirectURI: "https://spa.home/callback?source=hub", ClientID: "spa", CodeVerifier: codeVerifier}, grafanaCode, "invalid_grant"},
+		{"other redirect uri", dto.TokenRequestBody{GrantType: "authorization_code", Code: "the-code", RedirectURI: "https://grafana.home/other", ClientID: "grafana", ClientSecret: "grafana-secret"}, grafanaCode, "invalid_grant"},
+		{"wrong verifier", dto.TokenRequestBody{GrantType: "authorization_code", Code: "the-code", RedirectURI: "https://spa.home/callback?source=hub", ClientID: "spa", CodeVerifier: "wrong"}, pkceCode, "invalid_grant"},
+		{"verifier without challenge", dto.TokenRequestBody{GrantType: "authorization_code", Code: "the-code", RedirectURI: "https://grafana.home/login/generic_oauth", ClientID: "grafana", ClientSecret: "grafana-secret", CodeVerifier: codeVerifier}, grafanaCode, "invalid_grant"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newOIDCConfig(t)
+
+			mockAuthRepo := new(repoMocks.IAuthRepo)
+			mockApplicationRepo := new(repoMocks.IApplicationRepo)
+			mockAuthRepo.On("FindAuthorizationCode", "the-code").Return(tt.code, nil)
+			mockApplicationRepo.On("FindApplicationByClientID", "gitea").Return(nil, nil)
+
+			uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, mockApplicationRepo, nil, nil, cfg)
+
+			tokens, err := uc.ExchangeAuthorizationCode(tt.req, cfg)
+
+			assert.Nil(t, tokens)
+			var oauthErr *entity.OAuthError
+			assert.ErrorAs(t, err, &oauthErr)
+			assert.Equal(t, tt.error, oauthErr.Code)
+			mockAuthRepo.AssertNotCalled(t, "SaveOIDCAccessToken", mock.Anything, mock.Anything, mock.Anything)
+			// the code stays for the client it was issued to
+			mockAuthRepo.AssertNotCalled(t, "ConsumeAuthorizationCode", mock.Anything)
+		})
+	}
+}
+
+func TestAuthUseCase_ExchangeAuthorizationCode_UserRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    *entity.User
+		userErr error
+	}{
+		{"disabled user", &entity.User{Username: "testuser", Disabled: true}, nil},
+		{"deleted user", nil, &entity.InvalidCredentialsError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newOIDCConfig(t)
+
+			authorizationCode := &entity.AuthorizationCode{ClientID: "grafana", RedirectURI: "https://grafana.home/login/generic_oauth", Username: "testuser", Scope: "openid"}
+			mockAuthRepo := repoMocks.NewIAuthRepo(t)
+			mockAuthRepo.On("FindAuthorizationCode", "the-code").Return(authorizationCode, nil)
+			mockAuthRepo.On("ConsumeAuthorizationCode", "the-code").Return(authorizationCode, nil)
+
+			mockUserUC := mocks.NewIUserUC(t)
+			mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(tt.user, tt.userErr)
+
+			uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, cfg)
+
+			tokens, err := uc.ExchangeAuthorizationCode(dto.TokenRequestBody{
+				GrantType:    "authorization_code",
+				Code:         "the-code",
+				RedirectURI:  "https://grafana.home/login/generic_oauth",
+				ClientID:     "grafana",
+				ClientSecret: "grafana-secret",
+			}, cfg)
+
+			assert.Nil(t, tokens)
+			var oauthErr *entity.OAuthError
+			assert.ErrorAs(t, err, &oauthErr)
+			assert.Equal(t, "invalid_grant", oauthErr.Code)
+		})
+	}
+}
+
+func TestAuthUseCase_UserInfo(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("FindOIDCAccessToken", "access-token").Return(&entity.OIDCAccessToken{ClientID: "grafana", Username: "testuser", Scope: "openid profile"}, nil)
+	mockAuthRepo.On("FindOIDCAccessToken", "expired-token").Return(nil, nil)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser", Email: "test@example.com"}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	claims, err := uc.UserInfo("access-token")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"sub":                "testuser",
+		"preferred_username": "testuser",
+		"name":               "testuser",
+	}, claims)
+
+	claims, err = uc.UserInfo("expired-token")
+	assert.Nil(t, claims)
+	assert.Equal(t, &entity.OAuthError{Code: "invalid_token", Description: "The access token is invalid or has expired."}, err)
+}
+
+func TestAuthUseCase_UserInfo_DisabledUser(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("FindOIDCAccessToken", "access-token").Return(&entity.OIDCAccessToken{ClientID: "grafana", Username: "testuser", Scope: "openid profile"}, nil)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{Username: "testuser", Disabled: true}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	claims, err := uc.UserInfo("access-token")
+	assert.Nil(t, claims)
+	assert.Equal(t, &entity.OAuthError{Code: "invalid_token", Description: "The access token is invalid or has expired."}, err)
+}
+
+func TestAuthUseCase_OpenIDConfiguration(t *testing.T) {
+	cfg := newOIDCConfig(t)
+	uc := usecases.NewAuthUseCase(nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+
+	configuration := uc.OpenIDConfiguration(cfg)
+
+	assert.Equal(t, "https://hub.home", configuration.Issuer)
+	assert.Equal(t, "https://hub.home/v1/oidc/authorize", configuration.AuthorizationEndpoint)
+	assert.Equal(t, "https://hub.home/v2/oidc/token", configuration.TokenEndpoint)
+	assert.Equal(t, "https://hub.home/.well-known/jwks.json", configuration.JwksURI)
+	assert.Equal(t, []string{"RS256"}, configuration.IDTokenSigningAlgValuesSupported)
+	assert.Equal(t, []string{"S256"}, configuration.CodeChallengeMethodsSupported)
+}
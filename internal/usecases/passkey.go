@@ -0,0 +1,344 @@
+package usecases
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+)
+
+const passkeyDefaultName = "Passkey"
+
+// passkeyUser adapts a user and their passkeys to what the webauthn library expects
+type passkeyUser struct {
+	user        entity.User
+	credentials []entity.WebAuthnCredential
+}
+
+func (u passkeyUser) WebAuthnID() []byte {
+	return passkeyUserHandle(u.user)
+}
+
+func (u passkeyUser) WebAuthnName() string {
+	return u.user.Username
+}
+
+func (u passkeyUser) WebAuthnDisplayName() string {
+	return u.user.Username
+}
+
+func (u passkeyUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.credentials))
+	for _, c := range u.credentials {
+		credentials = append(credentials, webauthn.Credential{
+			ID:              c.CredentialID,
+			PublicKey:       c.PublicKey,
+			AttestationType: c.AttestationType,
+			Transport:       splitTransports(c.Transports),
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: c.BackupEligible,
+				BackupState:    c.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    c.AAGUID,
+				SignCount: c.SignCount,
+			},
+		})
+	}
+	return credentials
+}
+
+// BeginPasskeyRegistration starts adding a passkey to the account of the logged in user
+func (au *AuthUseCase) BeginPasskeyRegistration(username string, cfg *config.Config) (*dto.PasskeyCeremony, error) {
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := au.webAuthnCredentialRepo.FindWebAuthnCredentials(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	wa, err := newWebAuthn(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	pu := passkeyUser{user: *user, credentials: credentials}
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(credentials))
+	for _, c := range pu.WebAuthnCredentials() {
+		exclusions = append(exclusions, c.Descriptor())
+	}
+
+	// the same authenticator must not be registered twice
+	creation, session, err := wa.BeginRegistration(pu, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin passkey registration: %w", err)
+	}
+
+	return au.savePasskeySession(*session, creation, cfg)
+}
+
+// FinishPasskeyRegistration checks the answer of the authenticator and stores the new passkey
+func (au *AuthUseCase) FinishPasskeyRegistration(username string, requestBody dto.PasskeyRegistrationRequestBody, cfg *config.Config) error {
+	session, err := au.consumePasskeySession(requestBody.Session)
+	if err != nil {
+		return err
+	}
+
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return err
+	}
+
+	credentials, err := au.webAuthnCredentialRepo.FindWebAuthnCredentials(user.ID)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(strings.NewReader(requestBody.Credential))
+	if err != nil {
+		return &entity.InvalidPasskeyError{}
+	}
+
+	wa, err := newWebAuthn(cfg)
+	if err != nil {
+		return err
+	}
+
+	credential, err := wa.CreateCredential(passkeyUser{user: *user, credentials: credentials}, session, parsed)
+	if err != nil {
+		return &entity.InvalidPasskeyError{}
+	}
+
+	name := strings.TrimSpace(requestBody.Name)
+	if name == "" {
+		name = passkeyDefaultName
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, t := range credential.Transport {
+		transports = append(transports, string(t))
+	}
+
+	_, err = au.webAuthnCredentialRepo.CreateWebAuthnCredential(entity.WebAuthnCredential{
+		UserID:          user.ID,
+		Name:            name,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	})
+	return err
+}
+
+// BeginPasskeyLogin starts a login without a username, the browser offers every passkey it knows for this site
+func (au *AuthUseCase) BeginPasskeyLogin(cfg *config.Config) (*dto.PasskeyCeremony, error) {
+	wa, err := newWebAuthn(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	assertion, session, err := wa.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin passkey login: %w", err)
+	}
+
+	return au.savePasskeySession(*session, assertion, cfg)
+}
+
+// FinishPasskeyLogin checks the signature of the authenticator and issues tokens for the owner of the passkey.
+// A passkey with user verification already combines possession and a PIN or biometric,
+// so no second factor is asked for.
+func (au *AuthUseCase) FinishPasskeyLogin(requestBody dto.PasskeyLoginRequestBody, cfg *config.Config) (*dto.JwtTokens, error) {
+	session, err := au.consumePasskeySession(requestBody.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(strings.NewReader(requestBody.Credential))
+	if err != nil {
+		return nil, &entity.InvalidPasskeyError{}
+	}
+
+	wa, err := newWebAuthn(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored *entity.WebAuthnCredential
+	var lookupErr error
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		stored, lookupErr = au.webAuthnCredentialRepo.FindWebAuthnCredentialByCredentialID(rawID)
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		if stored == nil {
+			return nil, &entity.InvalidPasskeyError{}
+		}
+
+		credentials, err := au.webAuthnCredentialRepo.FindWebAuthnCredentials(stored.UserID)
+		if err != nil {
+			lookupErr = err
+			return nil, err
+		}
+
+		return passkeyUser{user: stored.User, credentials: credentials}, nil
+	}
+
+	credential, err := wa.ValidateDiscoverableLogin(handler, session, parsed)
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+	if err != nil {
+		return nil, &entity.InvalidPasskeyError{}
+	}
+
+	// a signature counter going backwards means the private key was copied
+	if credential.Authenticator.CloneWarning {
+		return nil, &entity.InvalidPasskeyError{}
+	}
+
+	err = au.webAuthnCredentialRepo.UpdateWebAuthnCredentialUsage(stored.ID, credential.Authenticator.SignCount, credential.Flags.BackupState)
+	if err != nil {
+		return nil, err
+	}
+
+	user := stored.User
+	if cfg.Authen.RequireEmailVerification && !user.EmailVerified {
+		if err := au.resendVerificationEmail(user, cfg); err != nil {
+			return nil, err
+		}
+		return nil, &entity.EmailNotVerifiedError{}
+	}
+
+	if requestBody.RememberMe == "on" {
+		user.RememberMe = true
+	}
+
+	return au.GenerateTokens(user, cfg)
+}
+
+func (au *AuthUseCase) ListPasskeys(username string) ([]entity.WebAuthnCredential, error) {
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return au.webAuthnCredentialRepo.FindWebAuthnCredentials(user.ID)
+}
+
+func (au *AuthUseCase) DeletePasskey(username string, id uint) error {
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return err
+	}
+
+	deleted, err := au.webAuthnCredentialRepo.DeleteWebAuthnCredential(user.ID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &entity.PasskeyNotFoundError{}
+	}
+
+	return nil
+}
+
+// savePasskeySession keeps the challenge in redis under a random id the browser sends back with its answer
+func (au *AuthUseCase) savePasskeySession(session webauthn.SessionData, options interface{}, cfg *config.Config) (*dto.PasskeyCeremony, error) {
+	id, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webauthn session: %w", err)
+	}
+
+	if err := au.authRepo.SaveWebAuthnSession(id, data, cfg.Authen.WebAuthnSessionTTL); err != nil {
+		return nil, err
+	}
+
+	return &dto.PasskeyCeremony{Session: id, Options: options}, nil
+}
+
+// consumePasskeySession loads the challenge of a ceremony, every challenge can only be answered once
+func (au *AuthUseCase) consumePasskeySession(id string) (webauthn.SessionData, error) {
+	var session webauthn.SessionData
+
+	data, err := au.authRepo.ConsumeWebAuthnSession(id)
+	if err != nil {
+		return session, err
+	}
+	if data == nil {
+		return session, &entity.InvalidPasskeyError{}
+	}
+
+	if err := json.Unmarshal(data, &session); err != nil {
+		return session, fmt.Errorf("failed to decode webauthn session: %w", err)
+	}
+
+	return session, nil
+}
+
+// newWebAuthn derives the relying party from the base url, passkeys are bound to its host name
+func newWebAuthn(cfg *config.Config) (*webauthn.WebAuthn, error) {
+	baseURL, err := url.Parse(cfg.App.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:          baseURL.Hostname(),
+		RPDisplayName: cfg.App.Name,
+		RPOrigins:     []string{baseURL.Scheme + "://" + baseURL.Host},
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure webauthn: %w", err)
+	}
+
+	return wa, nil
+}
+
+// passkeyUserHandle identifies the user towards the authenticator. It must not
+// contain personal information like the username or email, so the id is used.
+func passkeyUserHandle(user entity.User) []byte {
+	handle := make([]byte, 8)
+	binary.BigEndian.PutUint64(handle, uint64(user.ID))
+	return handle
+}
+
+func splitTransports(transports string) []protocol.AuthenticatorTransport {
+	if transports == "" {
+		return nil
+	}
+
+	var result []protocol.AuthenticatorTransport
+	for _, t := range strings.Split(transports, ",") {
+		result = append(result, protocol.AuthenticatorTransport(t))
+	}
+	return result
+}
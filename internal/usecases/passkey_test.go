@@ -0,0 +1,379 @@
+package usecases_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testPasskeyOrigin = "http://localhost:8080"
+	testPasskeyRPID   = "localhost"
+)
+
+// softAuthenticator plays the part of a platform authenticator, it creates
+// the same answers a browser returns from navigator.credentials
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	assert.NoError(t, err)
+
+	return &softAuthenticator{key: key, credentialID: credentialID}
+}
+
+func (a *softAuthenticator) publicKey(t *testing.T) []byte {
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.key.PublicKey.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	assert.NoError(t, err)
+	return publicKey
+}
+
+// authenticatorData is laid out as described in https://www.w3.org/TR/webauthn/#sctn-authenticator-data
+func (a *softAuthenticator) authenticatorData(t *testing.T, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(testPasskeyRPID))
+	// user present and user verified
+	flags := byte(protocol.FlagUserPresent | protocol.FlagUserVerified)
+	if attested {
+		flags |= byte(protocol.FlagAttestedCredentialData)
+	}
+
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...) // aaguid
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.publicKey(t)...)
+	}
+	return data
+}
+
+func (a *softAuthenticator) create(t *testing.T, ceremony *dto.PasskeyCeremony) string {
+	options := ceremony.Options.(*protocol.CredentialCreation).Response
+	a.userHandle = options.User.ID.(protocol.URLEncodedBase64)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authenticatorData(t, true),
+	})
+	assert.NoError(t, err)
+
+	return a.credentialJSON(t, map[string]interface{}{
+		"attestationObject": base64url(attestationObject),
+		"clientDataJSON":    base64url(clientDataJSON(t, "webauthn.create", options.Challenge.String())),
+		"transports":        []string{"internal"},
+	})
+}
+
+func (a *softAuthenticator) get(t *testing.T, ceremony *dto.PasskeyCeremony) string {
+	options := ceremony.Options.(*protocol.CredentialAssertion).Response
+	a.signCount++
+
+	authData := a.authenticatorData(t, false)
+	clientData := clientDataJSON(t, "webauthn.get", options.Challenge.String())
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	assert.NoError(t, err)
+
+	return a.credentialJSON(t, map[string]interface{}{
+		"authenticatorData": base64url(authData),
+		"clientDataJSON":    base64url(clientData),
+		"signature":         base64url(signature),
+		"userHandle":        base64url(a.userHandle),
+	})
+}
+
+func (a *softAuthenticator) credentialJSON(t *testing.T, response map[string]interface{}) string {
+	credential, err := json.Marshal(map[string]interface{}{
+		"id":       base64url(a.credentialID),
+		"rawId":    base64url(a.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	assert.NoError(t, err)
+	return string(credential)
+}
+
+// stored returns the credential as it was saved on registration
+func (a *softAuthenticator) stored(t *testing.T, user entity.User) entity.WebAuthnCredential {
+	return entity.WebAuthnCredential{
+		UserID:          user.ID,
+		Name:            "Laptop",
+		CredentialID:    a.credentialID,
+		PublicKey:       a.publicKey(t),
+		AttestationType: "none",
+		User:            user,
+	}
+}
+
+func clientDataJSON(t *testing.T, ceremonyType string, challenge string) []byte {
+	clientData, err := json.Marshal(map[string]string{
+		"type":      ceremonyType,
+		"challenge": challenge,
+		"origin":    testPasskeyOrigin,
+	})
+	assert.NoError(t, err)
+	return clientData
+}
+
+func base64url(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func newPasskeyConfig(t *testing.T) *config.Config {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	return &config.Config{
+		App: config.App{Name: "AuthConnect Hub", BaseURL: testPasskeyOrigin},
+		Authen: config.Authen{
+			AccessTokenTTL:     600,
+			RefreshTokenTTL:    3600,
+			WebAuthnSessionTTL: 300,
+			JwtPrivateKey:      privateKey,
+		},
+	}
+}
+
+// mockPasskeySession keeps the saved webauthn session, so it can be handed back on the next step
+func mockPasskeySession(mockAuthRepo *repoMocks.IAuthRepo) {
+	var session []byte
+	mockAuthRepo.On("SaveWebAuthnSession", mock.Anything, mock.Anything, 300).Run(func(args mock.Arguments) {
+		session = args.Get(1).([]byte)
+	}).Return(nil).Once()
+	mockAuthRepo.On("ConsumeWebAuthnSession", mock.Anything).Return(func(string) []byte {
+		return session
+	}, nil).Once()
+}
+
+func TestAuthUseCase_PasskeyRegistration_Success(t *testing.T) {
+	mockConfig := newPasskeyConfig(t)
+	user := entity.User{ID: 1, Username: "testuser"}
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&user, nil)
+
+	authenticator := newSoftAuthenticator(t)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockWebAuthnCredentialRepo := repoMocks.NewIWebAuthnCredentialRepo(t)
+	mockPasskeySession(mockAuthRepo)
+	mockWebAuthnCredentialRepo.On("FindWebAuthnCredentials", uint(1)).Return([]entity.WebAuthnCredential{}, nil).Twice()
+	mockWebAuthnCredentialRepo.On("CreateWebAuthnCredential", mock.MatchedBy(func(c entity.WebAuthnCredential) bool {
+		return c.UserID == 1 &&
+			c.Name == "Laptop" &&
+			string(c.CredentialID) == string(authenticator.credentialID) &&
+			string(c.PublicKey) == string(authenticator.publicKey(t)) &&
+			c.Transports == "internal"
+	})).Return(entity.WebAuthnCredential{}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, mockWebAuthnCredentialRepo, nil, nil, mockUserUC, nil, mockConfig)
+
+	ceremony, err := uc.BeginPasskeyRegistration("testuser", mockConfig)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ceremony.Session)
+
+	err = uc.FinishPasskeyRegistration("testuser", dto.PasskeyRegistrationRequestBody{
+		Session:    ceremony.Session,
+		Credential: authenticator.create(t, ceremony),
+		Name:       " Laptop ",
+	}, mockConfig)
+
+	assert.NoError(t, err)
+}
+
+func TestAuthUseCase_PasskeyRegistration_WrongChallenge(t *testing.T) {
+	mockConfig := newPasskeyConfig(t)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser"}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockWebAuthnCredentialRepo := repoMocks.NewIWebAuthnCredentialRepo(t)
+	mockPasskeySession(mockAuthRepo)
+	mockAuthRepo.On("SaveWebAuthnSession", mock.Anything, mock.Anything, 300).Return(nil).Once()
+	mockWebAuthnCredentialRepo.On("FindWebAuthnCredentials", uint(1)).Return([]entity.WebAuthnCredential{}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, mockWebAuthnCredentialRepo, nil, nil, mockUserUC, nil, mockConfig)
+
+	ceremony, err := uc.BeginPasskeyRegistration("testuser", mockConfig)
+	assert.NoError(t, err)
+	otherCeremony, err := uc.BeginPasskeyRegistration("testuser", mockConfig)
+	assert.NoError(t, err)
+
+	// the answer belongs to another ceremony than the session it is sent with
+	err = uc.FinishPasskeyRegistration("testuser", dto.PasskeyRegistrationRequestBody{
+		Session:    ceremony.Session,
+		Credential: newSoftAuthenticator(t).create(t, otherCeremony),
+	}, mockConfig)
+
+	assert.Equal(t, &entity.InvalidPasskeyError{}, err)
+	mockWebAuthnCredentialRepo.AssertNotCalled(t, "CreateWebAuthnCredential", mock.Anything)
+}
+
+func TestAuthUseCase_FinishPasskeyRegistration_ExpiredSession(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("ConsumeWebAuthnSession", "expired-session").Return(nil, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, new(mocks.IUserUC), nil, &config.Config{})
+
+	err := uc.FinishPasskeyRegistration("testuser", dto.PasskeyRegistrationRequestBody{
+		Session:    "expired-session",
+		Credential: "{}",
+	}, &config.Config{})
+
+	assert.Equal(t, &entity.InvalidPasskeyError{}, err)
+}
+
+func TestAuthUseCase_PasskeyLogin_Success(t *testing.T) {
+	mockConfig := newPasskeyConfig(t)
+	user := entity.User{ID: 1, Username: "testuser"}
+
+	authenticator := newSoftAuthenticator(t)
+	authenticator.userHandle = []byte{0, 0, 0, 0, 0, 0, 0, 1}
+	stored := authenticator.stored(t, user)
+	stored.ID = 7
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockWebAuthnCredentialRepo := repoMocks.NewIWebAuthnCredentialRepo(t)
+	mockPasskeySession(mockAuthRepo)
+	mockWebAuthnCredentialRepo.On("FindWebAuthnCredentialByCredentialID", authenticator.credentialID).Return(&stored, nil)
+	mockWebAuthnCredentialRepo.On("FindWebAuthnCredentials", uint(1)).Return([]entity.WebAuthnCredential{stored}, nil)
+	mockWebAuthnCredentialRepo.On("UpdateWebAuthnCredentialUsage", uint(7), uint32(1), false).Return(nil)
+	mockAuthRepo.On("AddUserToken", "testuser", mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("AddFamilyToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("SaveSession", mock.Anything, 3600).Return(nil)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&user, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, mockWebAuthnCredentialRepo, nil, nil, mockUserUC, nil, mockConfig)
+
+	ceremony, err := uc.BeginPasskeyLogin(mockConfig)
+	assert.NoError(t, err)
+
+	tokens, err := uc.FinishPasskeyLogin(dto.PasskeyLoginRequestBody{
+		Session:    ceremony.Session,
+		Credential: authenticator.get(t, ceremony),
+		RememberMe: "on",
+	}, mockConfig)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+
+	username, err := uc.ValidateToken(tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "testuser", username)
+
+	rememberMe, err := uc.RetrieveFieldFromJwtToken(tokens.AccessToken, "remember_me", false)
+	assert.NoError(t, err)
+	assert.Equal(t, true, rememberMe)
+}
+
+func TestAuthUseCase_PasskeyLogin_ClonedAuthenticator(t *testing.T) {
+	mockConfig := newPasskeyConfig(t)
+	user := entity.User{ID: 1, Username: "testuser"}
+
+	authenticator := newSoftAuthenticator(t)
+	authenticator.userHandle = []byte{0, 0, 0, 0, 0, 0, 0, 1}
+	stored := authenticator.stored(t, user)
+	// the original authenticator has signed more often than the one answering
+	stored.SignCount = 5
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockWebAuthnCredentialRepo := repoMocks.NewIWebAuthnCredentialRepo(t)
+	mockPasskeySession(mockAuthRepo)
+	mockWebAuthnCredentialRepo.On("FindWebAuthnCredentialByCredentialID", authenticator.credentialID).Return(&stored, nil)
+	mockWebAuthnCredentialRepo.On("FindWebAuthnCredentials", uint(1)).Return([]entity.WebAuthnCredential{stored}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, mockWebAuthnCredentialRepo, nil, nil, new(mocks.IUserUC), nil, mockConfig)
+
+	ceremony, err := uc.BeginPasskeyLogin(mockConfig)
+	assert.NoError(t, err)
+
+	tokens, err := uc.FinishPasskeyLogin(dto.PasskeyLoginRequestBody{
+		Session:    ceremony.Session,
+		Credential: authenticator.get(t, ceremony),
+	}, mockConfig)
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.InvalidPasskeyError{}, err)
+	mockWebAuthnCredentialRepo.AssertNotCalled(t, "UpdateWebAuthnCredentialUsage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_PasskeyLogin_UnknownCredential(t *testing.T) {
+	mockConfig := newPasskeyConfig(t)
+
+	authenticator := newSoftAuthenticator(t)
+	authenticator.userHandle = []byte{0, 0, 0, 0, 0, 0, 0, 1}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockWebAuthnCredentialRepo := repoMocks.NewIWebAuthnCredentialRepo(t)
+	mockPasskeySession(mockAuthRepo)
+	mockWebAuthnCredentialRepo.On("FindWebAuthnCredentialByCredentialID", authenticator.credentialID).Return(nil, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, mockWebAuthnCredentialRepo, nil, nil, new(mocks.IUserUC), nil, mockConfig)
+
+	ceremony, err := uc.BeginPasskeyLogin(mockConfig)
+	assert.NoError(t, err)
+
+	tokens, err := uc.FinishPasskeyLogin(dto.PasskeyLoginRequestBody{
+		Session:    ceremony.Session,
+		Credential: authenticator.get(t, ceremony),
+	}, mockConfig)
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.InvalidPasskeyError{}, err)
+}
+
+func TestAuthUseCase_DeletePasskey_NotFound(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser"}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockWebAuthnCredentialRepo := repoMocks.NewIWebAuthnCredentialRepo(t)
+	mockWebAuthnCredentialRepo.On("DeleteWebAuthnCredential", uint(1), uint(42)).Return(false, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, mockWebAuthnCredentialRepo, nil, nil, mockUserUC, nil, &config.Config{})
+
+	err := uc.DeletePasskey("testuser", 42)
+
+	assert.Equal(t, &entity.PasskeyNotFoundError{}, err)
+}
@@ -0,0 +1,31 @@
+package usecases
+
+import (
+	"errors"
+
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+)
+
+type RateLimitUseCase struct {
+	rateLimitRepo repos.IRateLimitRepo
+}
+
+func NewRateLimitUseCase(rr repos.IRateLimitRepo) *RateLimitUseCase {
+	return &RateLimitUseCase{rateLimitRepo: rr}
+}
+
+// Allow counts a request of the client identified by key. The bucket of the client
+// holds limit.Burst requests and refills with limit.Limit requests every limit.Period seconds.
+func (uc *RateLimitUseCase) Allow(key string, limit entity.RateLimit) (*entity.RateLimitResult, error) {
+	if limit.Limit <= 0 || limit.Period <= 0 {
+		return nil, errors.New("invalid rate limit")
+	}
+
+	capacity := limit.Burst
+	if capacity <= 0 {
+		capacity = limit.Limit
+	}
+
+	return uc.rateLimitRepo.TakeToken(key, capacity, float64(limit.Limit)/float64(limit.Period))
+}
@@ -0,0 +1,42 @@
+package usecases_test
+
+import (
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitUseCase_Allow_BurstDefaultsToLimit(t *testing.T) {
+	mockRateLimitRepo := repoMocks.NewIRateLimitRepo(t)
+	mockRateLimitRepo.On("TakeToken", "client", 10, 0.5).Return(&entity.RateLimitResult{Allowed: true, Limit: 10, Remaining: 9}, nil)
+
+	uc := usecases.NewRateLimitUseCase(mockRateLimitRepo)
+
+	result, err := uc.Allow("client", entity.RateLimit{Limit: 10, Period: 20})
+
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+}
+
+func TestRateLimitUseCase_Allow_Burst(t *testing.T) {
+	mockRateLimitRepo := repoMocks.NewIRateLimitRepo(t)
+	mockRateLimitRepo.On("TakeToken", "client", 30, 1.0).Return(&entity.RateLimitResult{Allowed: true, Limit: 30, Remaining: 29}, nil)
+
+	uc := usecases.NewRateLimitUseCase(mockRateLimitRepo)
+
+	_, err := uc.Allow("client", entity.RateLimit{Limit: 60, Period: 60, Burst: 30})
+
+	assert.NoError(t, err)
+}
+
+func TestRateLimitUseCase_Allow_InvalidLimit(t *testing.T) {
+	uc := usecases.NewRateLimitUseCase(repoMocks.NewIRateLimitRepo(t))
+
+	result, err := uc.Allow("client", entity.RateLimit{Limit: 10})
+
+	assert.EqualError(t, err, "invalid rate limit")
+	assert.Nil(t, result)
+}
@@ -0,0 +1,127 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+	// no 0/o, 1/l/i to keep codes readable when written down
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user with a new batch.
+// A valid TOTP code is required, so a stolen session can't take over the recovery codes.
+func (au *AuthUseCase) RegenerateRecoveryCodes(username string, code string) ([]string, error) {
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.TOTPEnabled {
+		return nil, &entity.TOTPNotEnrolledError{}
+	}
+
+	if err := au.validateTOTPCode(*user, code); err != nil {
+		return nil, err
+	}
+
+	return au.generateRecoveryCodes(*user)
+}
+
+// CountRecoveryCodes returns how many recovery codes of the user are still unused
+func (au *AuthUseCase) CountRecoveryCodes(username string) (int64, error) {
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return 0, err
+	}
+
+	return au.recoveryCodeRepo.CountUnusedRecoveryCodes(user.ID)
+}
+
+// generateRecoveryCodes stores the hashes of a new batch of codes and returns the codes in clear text,
+// this is the only time they can be shown to the user
+func (au *AuthUseCase) generateRecoveryCodes(user entity.User) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	codeHashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codeHash, err := bcrypt.GenerateFromPassword([]byte(normalizeRecoveryCode(code)), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+		codeHashes = append(codeHashes, string(codeHash))
+	}
+
+	if err := au.recoveryCodeRepo.ReplaceRecoveryCodes(user.ID, codeHashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// useRecoveryCode consumes the matching unused recovery code of the user
+func (au *AuthUseCase) useRecoveryCode(user entity.User, code string) error {
+	codes, err := au.recoveryCodeRepo.FindUnusedRecoveryCodes(user.ID)
+	if err != nil {
+		return err
+	}
+
+	normalized := []byte(normalizeRecoveryCode(code))
+	for _, recoveryCode := range codes {
+		if bcrypt.CompareHashAndPassword([]byte(recoveryCode.CodeHash), normalized) != nil {
+			continue
+		}
+
+		used, err := au.recoveryCodeRepo.MarkRecoveryCodeUsed(recoveryCode.ID)
+		if err != nil {
+			return err
+		}
+		if !used {
+			break
+		}
+		return nil
+	}
+
+	return &entity.InvalidTOTPCodeError{}
+}
+
+// generateRecoveryCode returns a code formatted as "xxxxx-xxxxx"
+func generateRecoveryCode() (string, error) {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+
+	for i := 0; i < recoveryCodeLength; i++ {
+		if i == recoveryCodeLength/2 {
+			sb.WriteByte('-')
+		}
+
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		sb.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+
+	return sb.String(), nil
+}
+
+// normalizeRecoveryCode makes the check forgiving about case, spaces and dashes
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,167 @@
+package usecases_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthUseCase_RegenerateRecoveryCodes_Success(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPEnabled: true, TOTPSecret: testTOTPSecret}, nil)
+
+	var savedHashes []string
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockRecoveryCodeRepo := repoMocks.NewIRecoveryCodeRepo(t)
+	mockAuthRepo.On("MarkTOTPCodeUsed", "testuser", mock.Anything, 90).Return(true, nil)
+	mockRecoveryCodeRepo.On("ReplaceRecoveryCodes", uint(1), mock.Anything).Run(func(args mock.Arguments) {
+		savedHashes = args.Get(1).([]string)
+	}).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, mockRecoveryCodeRepo, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	recoveryCodes, err := uc.RegenerateRecoveryCodes("testuser", currentTOTPCode(t))
+
+	assert.NoError(t, err)
+	assert.Len(t, recoveryCodes, 10)
+	assert.Len(t, savedHashes, 10)
+
+	codePattern := regexp.MustCompile(`^[a-z2-9]{5}-[a-z2-9]{5}$`)
+	unique := map[string]bool{}
+	for i, code := range recoveryCodes {
+		assert.Regexp(t, codePattern, code)
+		unique[code] = true
+		// only hashes of the codes are stored, without the dash
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(savedHashes[i]), []byte(code[:5]+code[6:])))
+	}
+	assert.Len(t, unique, 10)
+}
+
+func TestAuthUseCase_RegenerateRecoveryCodes_TOTPNotEnabled(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser"}, nil)
+
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	recoveryCodes, err := uc.RegenerateRecoveryCodes("testuser", "123456")
+
+	assert.Nil(t, recoveryCodes)
+	assert.Equal(t, &entity.TOTPNotEnrolledError{}, err)
+}
+
+func TestAuthUseCase_CountRecoveryCodes(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser"}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockRecoveryCodeRepo := repoMocks.NewIRecoveryCodeRepo(t)
+	mockRecoveryCodeRepo.On("CountUnusedRecoveryCodes", uint(1)).Return(int64(7), nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, mockRecoveryCodeRepo, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	count, err := uc.CountRecoveryCodes("testuser")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), count)
+}
+
+func TestAuthUseCase_VerifyMFALogin_RecoveryCode(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		RefreshTokenTTL: 3600,
+		AccessTokenTTL:  600,
+		JwtPrivateKey:   privateKey,
+	}}
+
+	otherHash, err := bcrypt.GenerateFromPassword([]byte("zzzzzzzzzz"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	codeHash, err := bcrypt.GenerateFromPassword([]byte("abcde23456"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPEnabled: true, TOTPSecret: testTOTPSecret}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockRecoveryCodeRepo := repoMocks.NewIRecoveryCodeRepo(t)
+	mockAuthRepo.On("GetMFAPendingToken", "pending-token").Return("testuser", nil)
+	mockAuthRepo.On("FindLoginLock", "username", "testuser").Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "username", "testuser").Return(time.Time{}, nil)
+	mockRecoveryCodeRepo.On("FindUnusedRecoveryCodes", uint(1)).Return([]entity.RecoveryCode{
+		{CodeHash: string(otherHash)},
+		{CodeHash: string(codeHash)},
+	}, nil)
+	mockRecoveryCodeRepo.On("MarkRecoveryCodeUsed", mock.Anything).Return(true, nil).Once()
+	mockAuthRepo.On("DeleteMFAPendingToken", "pending-token").Return(nil)
+	mockAuthRepo.On("ClearLoginFailures", "username", "testuser").Return(nil)
+	mockAuthRepo.On("AddUserToken", "testuser", mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("AddFamilyToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("SaveSession", mock.Anything, 3600).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, mockRecoveryCodeRepo, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	// case and dashes don't matter
+	tokens, err := uc.VerifyMFALogin(dto.MFALoginRequestBody{Token: "pending-token", Code: "ABCDE-23456"}, "", mockConfig)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+}
+
+func TestAuthUseCase_VerifyMFALogin_RecoveryCodeUsedConcurrently(t *testing.T) {
+	codeHash, err := bcrypt.GenerateFromPassword([]byte("abcde23456"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPEnabled: true, TOTPSecret: testTOTPSecret}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockRecoveryCodeRepo := repoMocks.NewIRecoveryCodeRepo(t)
+	mockAuthRepo.On("GetMFAPendingToken", "pending-token").Return("testuser", nil)
+	mockAuthRepo.On("FindLoginLock", "username", "testuser").Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "username", "testuser").Return(time.Time{}, nil)
+	mockAuthRepo.On("RecordMFAFailure", "pending-token", 0).Return(int64(1), nil)
+	mockAuthRepo.On("RecordLoginFailure", "username", "testuser", 0).Return(int64(1), nil)
+	mockRecoveryCodeRepo.On("FindUnusedRecoveryCodes", uint(1)).Return([]entity.RecoveryCode{{CodeHash: string(codeHash)}}, nil)
+	mockRecoveryCodeRepo.On("MarkRecoveryCodeUsed", mock.Anything).Return(false, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, mockRecoveryCodeRepo, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	tokens, err := uc.VerifyMFALogin(dto.MFALoginRequestBody{Token: "pending-token", Code: "abcde-23456"}, "", &config.Config{})
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.InvalidTOTPCodeError{}, err)
+}
+
+func TestAuthUseCase_VerifyMFALogin_UnknownRecoveryCode(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPEnabled: true, TOTPSecret: testTOTPSecret}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockRecoveryCodeRepo := repoMocks.NewIRecoveryCodeRepo(t)
+	mockAuthRepo.On("GetMFAPendingToken", "pending-token").Return("testuser", nil)
+	mockAuthRepo.On("FindLoginLock", "username", "testuser").Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "username", "testuser").Return(time.Time{}, nil)
+	mockAuthRepo.On("RecordMFAFailure", "pending-token", 0).Return(int64(1), nil)
+	mockAuthRepo.On("RecordLoginFailure", "username", "testuser", 0).Return(int64(1), nil)
+	mockRecoveryCodeRepo.On("FindUnusedRecoveryCodes", uint(1)).Return([]entity.RecoveryCode{}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, mockRecoveryCodeRepo, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	tokens, err := uc.VerifyMFALogin(dto.MFALoginRequestBody{Token: "pending-token", Code: "abcde-23456"}, "", &config.Config{})
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.InvalidTOTPCodeError{}, err)
+}
@@ -0,0 +1,114 @@
+package repos
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ApplicationRepo struct {
+	*postgres.Postgres
+}
+
+func NewApplicationRepo(pg *postgres.Postgres) *ApplicationRepo {
+	return &ApplicationRepo{pg}
+}
+
+func (r *ApplicationRepo) CreateApplication(application entity.Application) (entity.Application, error) {
+	if err := r.Conn.Create(&application).Error; err != nil {
+		return entity.Application{}, fmt.Errorf("failed to create application: %w", err)
+	}
+
+	return application, nil
+}
+
+func (r *ApplicationRepo) FindApplications() ([]entity.Application, error) {
+	var applications []entity.Application
+	err := r.Conn.Order("name").Find(&applications).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find applications: %w", err)
+	}
+
+	return applications, nil
+}
+
+// FindApplicationByClientID returns nil when no application has the client id
+func (r *ApplicationRepo) FindApplicationByClientID(clientID string) (*entity.Application, error) {
+	var application entity.Application
+	err := r.Conn.Where("client_id = ?", clientID).First(&application).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find application: %w", err)
+	}
+
+	return &application, nil
+}
+
+func (r *ApplicationRepo) UpdateApplication(application entity.Application) (entity.Application, error) {
+	if err := r.Conn.Save(&application).Error; err != nil {
+		return entity.Application{}, fmt.Errorf("failed to update application: %w", err)
+	}
+
+	return application, nil
+}
+
+// DeleteApplication returns false when there was nothing to delete
+func (r *ApplicationRepo) DeleteApplication(clientID string) (bool, error) {
+	result := r.Conn.Unscoped().Where("client_id = ?", clientID).Delete(&entity.Application{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete application: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// SaveApplicationGrant gives the subject the role within the application, or changes the role it has
+func (r *ApplicationRepo) SaveApplicationGrant(grant entity.ApplicationGrant) (entity.ApplicationGrant, error) {
+	err := r.Conn.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "application_id"}, {Name: "subject_type"}, {Name: "subject"}},
+		DoUpdates: clause.AssignmentColumns([]string{"app_role", "updated_at"}),
+	}).Create(&grant).Error
+	if err != nil {
+		return entity.ApplicationGrant{}, fmt.Errorf("failed to save application grant: %w", err)
+	}
+
+	return grant, nil
+}
+
+func (r *ApplicationRepo) FindApplicationGrants() ([]entity.ApplicationGrant, error) {
+	var grants []entity.ApplicationGrant
+	err := r.Conn.Order("subject_type, subject").Find(&grants).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find application grants: %w", err)
+	}
+
+	return grants, nil
+}
+
+func (r *ApplicationRepo) FindApplicationGrantsByApplicationID(applicationID uint) ([]entity.ApplicationGrant, error) {
+	var grants []entity.ApplicationGrant
+	err := r.Conn.Where("application_id = ?", applicationID).Order("subject_type, subject").Find(&grants).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find application grants: %w", err)
+	}
+
+	return grants, nil
+}
+
+// DeleteApplicationGrant returns false when there was nothing to delete
+func (r *ApplicationRepo) DeleteApplicationGrant(applicationID uint, subjectType string, subject string) (bool, error) {
+	result := r.Conn.Unscoped().
+		Where("application_id = ? AND subject_type = ? AND subject = ?", applicationID, subjectType, subject).
+		Delete(&entity.ApplicationGrant{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete application grant: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
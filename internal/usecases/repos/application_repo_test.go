@@ -0,0 +1,166 @@
+package repos_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
+	"github.com/minhmannh2001/authconnecthub/tests/testhelpers"
+	"github.com/stretchr/testify/suite"
+)
+
+type ApplicationRepoTestSuite struct {
+	suite.Suite
+	pgContainer     *testhelpers.PostgresContainer
+	pg              *postgres.Postgres
+	applicationRepo *repos.ApplicationRepo
+	ctx             context.Context
+}
+
+func (suite *ApplicationRepoTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	pgContainer, err := testhelpers.CreatePostgresContainer(suite.ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pgContainer = pgContainer
+	host, err := pgContainer.ExtractHost(pgContainer.ConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	port, err := pgContainer.ExtractPort(pgContainer.ConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pg, err := postgres.New(&config.Config{
+		PG: config.PG{
+			Host:     host,
+			Port:     port,
+			Username: "postgres",
+			Password: "postgres",
+			Dbname:   "test-db",
+			Sslmode:  "disable",
+		},
+		Authen: config.Authen{
+			AdminUsername: "admin",
+			AdminPassword: "password",
+			AdminEmail:    "admin@localhost",
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pg = pg
+	suite.applicationRepo = repos.NewApplicationRepo(pg)
+}
+
+func (suite *ApplicationRepoTestSuite) TearDownSuite() {
+	if err := suite.pgContainer.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating postgres container: %s", err)
+	}
+}
+
+func (suite *ApplicationRepoTestSuite) TestApplications_Lifecycle() {
+	created, err := suite.applicationRepo.CreateApplication(entity.Application{
+		ClientID:     "grafana",
+		SecretHash:   "hash",
+		Name:         "Grafana",
+		URL:          "https://grafana.home.lan",
+		RedirectURIs: "https://grafana.home.lan/login/generic_oauth",
+		AllowedRoles: "admin",
+	})
+	suite.Nil(err)
+	suite.NotZero(created.ID)
+
+	// client ids are unique
+	_, err = suite.applicationRepo.CreateApplication(entity.Application{ClientID: "grafana", Name: "Copy", URL: "https://copy.home.lan"})
+	suite.NotNil(err)
+
+	_, err = suite.applicationRepo.CreateApplication(entity.Application{ClientID: "photos", Name: "Photos", URL: "https://photos.home.lan"})
+	suite.Nil(err)
+
+	applications, err := suite.applicationRepo.FindApplications()
+	suite.Nil(err)
+	suite.Len(applications, 2)
+	suite.Equal("Grafana", applications[0].Name)
+
+	created.Name = "Dashboards"
+	_, err = suite.applicationRepo.UpdateApplication(created)
+	suite.Nil(err)
+
+	found, err := suite.applicationRepo.FindApplicationByClientID("grafana")
+	suite.Nil(err)
+	suite.Equal("Dashboards", found.Name)
+	suite.Equal("hash", found.SecretHash)
+
+	deleted, err := suite.applicationRepo.DeleteApplication("grafana")
+	suite.Nil(err)
+	suite.True(deleted)
+
+	deleted, err = suite.applicationRepo.DeleteApplication("grafana")
+	suite.Nil(err)
+	suite.False(deleted)
+
+	found, err = suite.applicationRepo.FindApplicationByClientID("grafana")
+	suite.Nil(err)
+	suite.Nil(found)
+}
+
+func (suite *ApplicationRepoTestSuite) TestApplicationGrants_Lifecycle() {
+	application, err := suite.applicationRepo.CreateApplication(entity.Application{ClientID: "wiki", Name: "Wiki", URL: "https://wiki.home.lan"})
+	suite.Nil(err)
+
+	_, err = suite.applicationRepo.SaveApplicationGrant(entity.ApplicationGrant{
+		ApplicationID: application.ID,
+		SubjectType:   entity.GrantSubjectUser,
+		Subject:       "testuser",
+		AppRole:       entity.AppRoleViewer,
+	})
+	suite.Nil(err)
+	_, err = suite.applicationRepo.SaveApplicationGrant(entity.ApplicationGrant{
+		ApplicationID: application.ID,
+		SubjectType:   entity.GrantSubjectRole,
+		Subject:       "customer",
+		AppRole:       entity.AppRoleViewer,
+	})
+	suite.Nil(err)
+
+	// granting the subject again changes its role
+	_, err = suite.applicationRepo.SaveApplicationGrant(entity.ApplicationGrant{
+		ApplicationID: application.ID,
+		SubjectType:   entity.GrantSubjectUser,
+		Subject:       "testuser",
+		AppRole:       entity.AppRoleEditor,
+	})
+	suite.Nil(err)
+
+	grants, err := suite.applicationRepo.FindApplicationGrantsByApplicationID(application.ID)
+	suite.Nil(err)
+	suite.Len(grants, 2)
+	suite.Equal(entity.GrantSubjectRole, grants[0].SubjectType)
+	suite.Equal(entity.AppRoleEditor, grants[1].AppRole)
+
+	deleted, err := suite.applicationRepo.DeleteApplicationGrant(application.ID, entity.GrantSubjectRole, "customer")
+	suite.Nil(err)
+	suite.True(deleted)
+
+	deleted, err = suite.applicationRepo.DeleteApplicationGrant(application.ID, entity.GrantSubjectRole, "customer")
+	suite.Nil(err)
+	suite.False(deleted)
+
+	// the grants go with their application
+	_, err = suite.applicationRepo.DeleteApplication("wiki")
+	suite.Nil(err)
+
+	grants, err = suite.applicationRepo.FindApplicationGrants()
+	suite.Nil(err)
+	suite.Empty(grants)
+}
+
+func TestApplicationRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(ApplicationRepoTestSuite))
+}
@@ -2,9 +2,16 @@ package repos
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
 	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
 	redis_pkg "github.com/minhmannh2001/authconnecthub/pkg/redis"
 	"github.com/redis/go-redis/v9"
@@ -40,3 +47,778 @@ func (a *AuthRepo) IsTokenBlacklisted(token string) (bool, error) {
 	}
 	return val == "", nil
 }
+
+// AddUserToken keeps track of a token issued to the user until it expires,
+// so that all of them can be revoked at once later on
+func (a *AuthRepo) AddUserToken(username string, token string, expiration int) error {
+	return a.trackToken("user_tokens:"+username, token, expiration)
+}
+
+// trackToken adds the member to the sorted set at key, scored by its expiry
+func (a *AuthRepo) trackToken(key string, member string, expiration int) error {
+	ctx := context.Background()
+	now := time.Now().Unix()
+
+	_, err := a.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		// drop tokens which are already expired
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now, 10))
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now + int64(expiration)), Member: member})
+		// keep the set alive as long as its longest-lived token
+		pipe.ExpireGT(ctx, key, time.Duration(expiration)*time.Second)
+		pipe.ExpireNX(ctx, key, time.Duration(expiration)*time.Second)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to track token: %w", err)
+	}
+
+	return nil
+}
+
+// BlacklistUserTokens blacklists every outstanding token of the user except the given ones
+func (a *AuthRepo) BlacklistUserTokens(username string, expiration int, excepts ...string) error {
+	ctx := context.Background()
+	key := "user_tokens:" + username
+
+	tokens, err := a.Client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve user tokens: %w", err)
+	}
+
+	skipped := make(map[string]bool, len(excepts))
+	for _, token := range excepts {
+		skipped[token] = true
+	}
+
+	_, err = a.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, token := range tokens {
+			if skipped[token] {
+				continue
+			}
+			pipe.Set(ctx, "blacklist:"+token, "", time.Duration(expiration)*time.Second)
+			pipe.ZRem(ctx, key, token)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to blacklist user tokens: %w", err)
+	}
+
+	return nil
+}
+
+// AddFamilyToken keeps track of a token issued by a chain of refreshes that started with one login
+func (a *AuthRepo) AddFamilyToken(family string, token string, expiration int) error {
+	return a.trackToken("token_family:"+family, token, expiration)
+}
+
+// RotateRefreshToken records the pair of tokens the refresh token was exchanged for. When it was
+// exchanged before, the pair recorded then is returned instead and nothing is recorded.
+func (a *AuthRepo) RotateRefreshToken(jti string, rotation entity.RefreshTokenRotation, expiration int) (*entity.RefreshTokenRotation, error) {
+	ctx := context.Background()
+	key := "refresh_rotated:" + jti
+
+	data, err := json.Marshal(rotation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode refresh token rotation: %w", err)
+	}
+
+	ok, err := a.Client.SetNX(ctx, key, data, time.Duration(expiration)*time.Second).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark refresh token as rotated: %w", err)
+	}
+	if ok {
+		return nil, nil
+	}
+
+	var previous entity.RefreshTokenRotation
+	val, err := a.Client.Get(ctx, key).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to retrieve refresh token rotation: %w", err)
+	}
+	// tokens rotated before the pair was recorded have no rotation time, they count as reused
+	_ = json.Unmarshal([]byte(val), &previous)
+	return &previous, nil
+}
+
+// FindRefreshTokenRotation returns the pair of tokens the refresh token was exchanged for, nil
+// when it wasn't exchanged yet
+func (a *AuthRepo) FindRefreshTokenRotation(jti string) (*entity.RefreshTokenRotation, error) {
+	ctx := context.Background()
+	val, err := a.Client.Get(ctx, "refresh_rotated:"+jti).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve refresh token rotation: %w", err)
+	}
+
+	var rotation entity.RefreshTokenRotation
+	_ = json.Unmarshal([]byte(val), &rotation)
+	return &rotation, nil
+}
+
+// RevokeTokenFamily blacklists every outstanding token of the family
+func (a *AuthRepo) RevokeTokenFamily(family string, expiration int) error {
+	ctx := context.Background()
+	key := "token_family:" + family
+
+	tokens, err := a.Client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve token family: %w", err)
+	}
+
+	_, err = a.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, token := range tokens {
+			pipe.Set(ctx, "blacklist:"+token, "", time.Duration(expiration)*time.Second)
+		}
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+
+	return nil
+}
+
+// SaveSession records a new login, the session expires together with its refresh token
+func (a *AuthRepo) SaveSession(session entity.Session, expiration int) error {
+	ctx := context.Background()
+	key := sessionKey(session.ID)
+
+	_, err := a.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"username", session.Username,
+			"user_agent", session.UserAgent,
+			"ip", session.IP,
+			"remember_me", session.RememberMe,
+			"created_at", session.CreatedAt.Unix(),
+			"last_seen_at", session.LastSeenAt.Unix(),
+		)
+		pipe.Expire(ctx, key, time.Duration(expiration)*time.Second)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	return a.trackToken("user_sessions:"+session.Username, session.ID, expiration)
+}
+
+// ExtendSession keeps the session alive as long as the refresh token issued last
+func (a *AuthRepo) ExtendSession(id string, username string, expiration int) error {
+	ctx := context.Background()
+	err := a.Client.Expire(ctx, sessionKey(id), time.Duration(expiration)*time.Second).Err()
+	if err != nil {
+		return fmt.Errorf("failed to extend session: %w", err)
+	}
+
+	return a.trackToken("user_sessions:"+username, id, expiration)
+}
+
+// touchSessionScript only updates sessions which still exist, so a request racing
+// with a revocation can't bring the session back
+var touchSessionScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("HSET", KEYS[1], unpack(ARGV))
+end
+return 0
+`)
+
+// TouchSession records the device and the time the session was last used from
+func (a *AuthRepo) TouchSession(id string, userAgent string, ip string) error {
+	ctx := context.Background()
+	err := touchSessionScript.Run(ctx, a.Client, []string{sessionKey(id)},
+		"user_agent", userAgent,
+		"ip", ip,
+		"last_seen_at", time.Now().Unix(),
+	).Err()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+
+	return nil
+}
+
+// FindSession returns the session with the given id. A nil session means it ended or never existed.
+func (a *AuthRepo) FindSession(id string) (*entity.Session, error) {
+	ctx := context.Background()
+	fields, err := a.Client.HGetAll(ctx, sessionKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return parseSession(id, fields), nil
+}
+
+// FindSessions returns the sessions of the user, the most recently used first
+func (a *AuthRepo) FindSessions(username string) ([]entity.Session, error) {
+	ctx := context.Background()
+
+	ids, err := a.Client.ZRangeByScore(ctx, "user_sessions:"+username, &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user sessions: %w", err)
+	}
+
+	cmds := make([]*redis.MapStringStringCmd, 0, len(ids))
+	_, err = a.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			cmds = append(cmds, pipe.HGetAll(ctx, sessionKey(id)))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions: %w", err)
+	}
+
+	sessions := make([]entity.Session, 0, len(ids))
+	for i, cmd := range cmds {
+		// the session may have been deleted while its id is still indexed
+		if len(cmd.Val()) == 0 {
+			continue
+		}
+		sessions = append(sessions, *parseSession(ids[i], cmd.Val()))
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+func (a *AuthRepo) DeleteSession(id string, username string) error {
+	ctx := context.Background()
+	_, err := a.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, sessionKey(id))
+		pipe.ZRem(ctx, "user_sessions:"+username, id)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteUserSessions deletes every session of the user except the given ones
+func (a *AuthRepo) DeleteUserSessions(username string, excepts ...string) error {
+	ctx := context.Background()
+	key := "user_sessions:" + username
+
+	ids, err := a.Client.ZRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve user sessions: %w", err)
+	}
+
+	skipped := make(map[string]bool, len(excepts))
+	for _, id := range excepts {
+		skipped[id] = true
+	}
+
+	_, err = a.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			if skipped[id] {
+				continue
+			}
+			pipe.Del(ctx, sessionKey(id))
+			pipe.ZRem(ctx, key, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+
+	return nil
+}
+
+// RecordLoginFailure counts a failed login of a username or from an ip and returns the
+// number of failures within the sliding window, the current one included
+func (a *AuthRepo) RecordLoginFailure(kind string, value string, window int) (int64, error) {
+	ctx := context.Background()
+	key := loginFailuresKey(kind, value)
+	now := time.Now()
+
+	var count *redis.IntCmd
+	_, err := a.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-time.Duration(window)*time.Second).UnixNano(), 10))
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixNano()), Member: now.UnixNano()})
+		count = pipe.ZCard(ctx, key)
+		pipe.Expire(ctx, key, time.Duration(window)*time.Second)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	return count.Val(), nil
+}
+
+// ClearLoginFailures forgets the failed logins of a username or an ip, and lifts its delay
+func (a *AuthRepo) ClearLoginFailures(kind string, value string) error {
+	ctx := context.Background()
+	if err := a.Client.Del(ctx, loginFailuresKey(kind, value), loginDelayKey(kind, value)).Err(); err != nil {
+		return fmt.Errorf("failed to clear login failures: %w", err)
+	}
+
+	return nil
+}
+
+// DelayLogin makes a username or an ip wait before it may try to log in again
+func (a *AuthRepo) DelayLogin(kind string, value string, delay time.Duration) error {
+	ctx := context.Background()
+	until := time.Now().Add(delay)
+	err := a.Client.Set(ctx, loginDelayKey(kind, value), until.UnixMilli(), delay).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delay login: %w", err)
+	}
+
+	return nil
+}
+
+// FindLoginDelay returns until when a username or an ip has to wait. The zero time means it doesn't.
+func (a *AuthRepo) FindLoginDelay(kind string, value string) (time.Time, error) {
+	ctx := context.Background()
+	until, err := a.Client.Get(ctx, loginDelayKey(kind, value)).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to find login delay: %w", err)
+	}
+
+	return time.UnixMilli(until), nil
+}
+
+// LockLogin blocks logins of a username or from an ip for the given number of seconds
+func (a *AuthRepo) LockLogin(kind string, value string, duration int) error {
+	ctx := context.Background()
+	until := time.Now().Add(time.Duration(duration) * time.Second)
+	err := a.Client.Set(ctx, loginLockKey(kind, value), until.Unix(), time.Duration(duration)*time.Second).Err()
+	if err != nil {
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+
+	return nil
+}
+
+// FindLoginLock returns the lock of a username or an ip. A nil lock means logins are allowed.
+func (a *AuthRepo) FindLoginLock(kind string, value string) (*entity.LoginLock, error) {
+	ctx := context.Background()
+	until, err := a.Client.Get(ctx, loginLockKey(kind, value)).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find login lock: %w", err)
+	}
+
+	return &entity.LoginLock{Kind: kind, Value: value, Until: time.Unix(until, 0)}, nil
+}
+
+// FindLoginLocks returns every active lock, the ones lifted first come first
+func (a *AuthRepo) FindLoginLocks() ([]entity.LoginLock, error) {
+	ctx := context.Background()
+
+	var keys []string
+	iter := a.Client.Scan(ctx, 0, "login_lock:*", 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find login locks: %w", err)
+	}
+
+	locks := []entity.LoginLock{}
+	if len(keys) == 0 {
+		return locks, nil
+	}
+
+	values, err := a.Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find login locks: %w", err)
+	}
+
+	for i, key := range keys {
+		// the lock expired between the scan and the read
+		value, ok := values[i].(string)
+		if !ok {
+			continue
+		}
+		until, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(key, "login_lock:"), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		locks = append(locks, entity.LoginLock{Kind: parts[0], Value: parts[1], Until: time.Unix(until, 0)})
+	}
+
+	sort.Slice(locks, func(i, j int) bool {
+		return locks[i].Until.Before(locks[j].Until)
+	})
+
+	return locks, nil
+}
+
+// DeleteLoginLock lifts the lock of a username or an ip and forgets its failed logins.
+// It reports whether there was a lock.
+func (a *AuthRepo) DeleteLoginLock(kind string, value string) (bool, error) {
+	ctx := context.Background()
+	deleted, err := a.Client.Del(ctx, loginLockKey(kind, value)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete login lock: %w", err)
+	}
+
+	if err := a.ClearLoginFailures(kind, value); err != nil {
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
+
+// SaveResetPasswordToken stores a hash of the reset token, so a leaked redis dump can't be used to reset passwords
+func (a *AuthRepo) SaveResetPasswordToken(token string, username string, expiration int) error {
+	ctx := context.Background()
+	err := a.Client.Set(ctx, resetPasswordKey(token), username, time.Duration(expiration)*time.Second).Err()
+	if err != nil {
+		return fmt.Errorf("failed to save reset password token: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeResetPasswordToken returns the username the token was issued for and deletes it,
+// so every token can only be used once. An empty username means the token is unknown or expired.
+func (a *AuthRepo) ConsumeResetPasswordToken(token string) (string, error) {
+	ctx := context.Background()
+	username, err := a.Client.GetDel(ctx, resetPasswordKey(token)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to consume reset password token: %w", err)
+	}
+
+	return username, nil
+}
+
+// SaveMFAPendingToken remembers which user passed the first login step with the given token
+func (a *AuthRepo) SaveMFAPendingToken(token string, username string, expiration int) error {
+	ctx := context.Background()
+	err := a.Client.Set(ctx, mfaPendingKey(token), username, time.Duration(expiration)*time.Second).Err()
+	if err != nil {
+		return fmt.Errorf("failed to save mfa pending token: %w", err)
+	}
+
+	return nil
+}
+
+// GetMFAPendingToken returns the username the token was issued for.
+// An empty username means the token is unknown or expired.
+func (a *AuthRepo) GetMFAPendingToken(token string) (string, error) {
+	ctx := context.Background()
+	username, err := a.Client.Get(ctx, mfaPendingKey(token)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get mfa pending token: %w", err)
+	}
+
+	return username, nil
+}
+
+// RecordMFAFailure counts a wrong code entered for the pending login and returns the number of
+// wrong codes so far, the current one included
+func (a *AuthRepo) RecordMFAFailure(token string, expiration int) (int64, error) {
+	ctx := context.Background()
+	key := mfaFailuresKey(token)
+
+	var count *redis.IntCmd
+	_, err := a.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, time.Duration(expiration)*time.Second)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to record mfa failure: %w", err)
+	}
+
+	return count.Val(), nil
+}
+
+func (a *AuthRepo) DeleteMFAPendingToken(token string) error {
+	ctx := context.Background()
+	err := a.Client.Del(ctx, mfaPendingKey(token), mfaFailuresKey(token)).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delete mfa pending token: %w", err)
+	}
+
+	return nil
+}
+
+// MarkTOTPCodeUsed records that the code of the given time step was accepted for the user.
+// It returns false when the code was used before, so a captured code can't be replayed
+// while it is still inside the validity window.
+func (a *AuthRepo) MarkTOTPCodeUsed(username string, counter int64, expiration int) (bool, error) {
+	ctx := context.Background()
+	key := fmt.Sprintf("totp_used:%s:%d", username, counter)
+	ok, err := a.Client.SetNX(ctx, key, "", time.Duration(expiration)*time.Second).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark totp code as used: %w", err)
+	}
+
+	return ok, nil
+}
+
+// MarkVerificationEmailSent records that a verification link was mailed to the user, for as long
+// as the link is valid. It returns false when an earlier link is still valid.
+func (a *AuthRepo) MarkVerificationEmailSent(username string, expiration int) (bool, error) {
+	ctx := context.Background()
+	ok, err := a.Client.SetNX(ctx, "verification_email_sent:"+username, "", time.Duration(expiration)*time.Second).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark verification email as sent: %w", err)
+	}
+
+	return ok, nil
+}
+
+// SaveWebAuthnSession keeps the challenge of a passkey ceremony until the browser answers it
+func (a *AuthRepo) SaveWebAuthnSession(id string, session []byte, expiration int) error {
+	ctx := context.Background()
+	err := a.Client.Set(ctx, webAuthnSessionKey(id), session, time.Duration(expiration)*time.Second).Err()
+	if err != nil {
+		return fmt.Errorf("failed to save webauthn session: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeWebAuthnSession returns the session and deletes it, so every challenge can only be answered once.
+// A nil session means it is unknown or expired.
+func (a *AuthRepo) ConsumeWebAuthnSession(id string) ([]byte, error) {
+	ctx := context.Background()
+	session, err := a.Client.GetDel(ctx, webAuthnSessionKey(id)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to consume webauthn session: %w", err)
+	}
+
+	return session, nil
+}
+
+// SaveAuthorizationCode keeps what the code stands for until the client redeems it
+func (a *AuthRepo) SaveAuthorizationCode(code string, authorizationCode entity.AuthorizationCode, expiration int) error {
+	ctx := context.Background()
+	value, err := json.Marshal(authorizationCode)
+	if err != nil {
+		return fmt.Errorf("failed to encode authorization code: %w", err)
+	}
+
+	err = a.Client.Set(ctx, authorizationCodeKey(code), value, time.Duration(expiration)*time.Second).Err()
+	if err != nil {
+		return fmt.Errorf("failed to save authorization code: %w", err)
+	}
+
+	return nil
+}
+
+// FindAuthorizationCode returns what the code stands for without redeeming it. A nil code means it is unknown or expired.
+func (a *AuthRepo) FindAuthorizationCode(code string) (*entity.AuthorizationCode, error) {
+	ctx := context.Background()
+	value, err := a.Client.Get(ctx, authorizationCodeKey(code)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find authorization code: %w", err)
+	}
+
+	var authorizationCode entity.AuthorizationCode
+	if err := json.Unmarshal(value, &authorizationCode); err != nil {
+		return nil, fmt.Errorf("failed to decode authorization code: %w", err)
+	}
+
+	return &authorizationCode, nil
+}
+
+// ConsumeAuthorizationCode returns what the code stands for and deletes it, so every code can only be redeemed once.
+// A nil code means it is unknown or expired.
+func (a *AuthRepo) ConsumeAuthorizationCode(code string) (*entity.AuthorizationCode, error) {
+	ctx := context.Background()
+	value, err := a.Client.GetDel(ctx, authorizationCodeKey(code)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to consume authorization code: %w", err)
+	}
+
+	var authorizationCode entity.AuthorizationCode
+	if err := json.Unmarshal(value, &authorizationCode); err != nil {
+		return nil, fmt.Errorf("failed to decode authorization code: %w", err)
+	}
+
+	return &authorizationCode, nil
+}
+
+// SaveOIDCAccessToken keeps what an access token issued to a client grants until it expires
+func (a *AuthRepo) SaveOIDCAccessToken(token string, accessToken entity.OIDCAccessToken, expiration int) error {
+	ctx := context.Background()
+	value, err := json.Marshal(accessToken)
+	if err != nil {
+		return fmt.Errorf("failed to encode oidc access token: %w", err)
+	}
+
+	err = a.Client.Set(ctx, oidcAccessTokenKey(token), value, time.Duration(expiration)*time.Second).Err()
+	if err != nil {
+		return fmt.Errorf("failed to save oidc access token: %w", err)
+	}
+
+	return nil
+}
+
+// FindOIDCAccessToken returns what an access token grants. A nil token means it is unknown or expired.
+func (a *AuthRepo) FindOIDCAccessToken(token string) (*entity.OIDCAccessToken, error) {
+	ctx := context.Background()
+	value, err := a.Client.Get(ctx, oidcAccessTokenKey(token)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find oidc access token: %w", err)
+	}
+
+	var accessToken entity.OIDCAccessToken
+	if err := json.Unmarshal(value, &accessToken); err != nil {
+		return nil, fmt.Errorf("failed to decode oidc access token: %w", err)
+	}
+
+	return &accessToken, nil
+}
+
+// DeleteOIDCAccessToken revokes an access token issued to a client
+func (a *AuthRepo) DeleteOIDCAccessToken(token string) error {
+	ctx := context.Background()
+	if err := a.Client.Del(ctx, oidcAccessTokenKey(token)).Err(); err != nil {
+		return fmt.Errorf("failed to delete oidc access token: %w", err)
+	}
+
+	return nil
+}
+
+// SaveFederationState keeps what the hub needs to verify the answer of an upstream provider until the browser comes back
+func (a *AuthRepo) SaveFederationState(state string, federationState entity.FederationState, expiration int) error {
+	ctx := context.Background()
+	value, err := json.Marshal(federationState)
+	if err != nil {
+		return fmt.Errorf("failed to encode federation state: %w", err)
+	}
+
+	err = a.Client.Set(ctx, federationStateKey(state), value, time.Duration(expiration)*time.Second).Err()
+	if err != nil {
+		return fmt.Errorf("failed to save federation state: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeFederationState returns the state and deletes it, so every answer can only be used once.
+// A nil state means it is unknown or expired.
+func (a *AuthRepo) ConsumeFederationState(state string) (*entity.FederationState, error) {
+	ctx := context.Background()
+	value, err := a.Client.GetDel(ctx, federationStateKey(state)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to consume federation state: %w", err)
+	}
+
+	var federationState entity.FederationState
+	if err := json.Unmarshal(value, &federationState); err != nil {
+		return nil, fmt.Errorf("failed to decode federation state: %w", err)
+	}
+
+	return &federationState, nil
+}
+
+func sessionKey(id string) string {
+	return "session:" + id
+}
+
+func parseSession(id string, fields map[string]string) *entity.Session {
+	createdAt, _ := strconv.ParseInt(fields["created_at"], 10, 64)
+	lastSeenAt, _ := strconv.ParseInt(fields["last_seen_at"], 10, 64)
+	rememberMe, _ := strconv.ParseBool(fields["remember_me"])
+
+	return &entity.Session{
+		ID:         id,
+		Username:   fields["username"],
+		UserAgent:  fields["user_agent"],
+		IP:         fields["ip"],
+		RememberMe: rememberMe,
+		CreatedAt:  time.Unix(createdAt, 0),
+		LastSeenAt: time.Unix(lastSeenAt, 0),
+	}
+}
+
+func loginFailuresKey(kind string, value string) string {
+	return "login_failures:" + kind + ":" + value
+}
+
+func loginDelayKey(kind string, value string) string {
+	return "login_delay:" + kind + ":" + value
+}
+
+func loginLockKey(kind string, value string) string {
+	return "login_lock:" + kind + ":" + value
+}
+
+func webAuthnSessionKey(id string) string {
+	return fmt.Sprintf("webauthn_session:%x", sha256.Sum256([]byte(id)))
+}
+
+func mfaPendingKey(token string) string {
+	return fmt.Sprintf("mfa_pending:%x", sha256.Sum256([]byte(token)))
+}
+
+func mfaFailuresKey(token string) string {
+	return fmt.Sprintf("mfa_failures:%x", sha256.Sum256([]byte(token)))
+}
+
+func resetPasswordKey(token string) string {
+	return fmt.Sprintf("reset_password:%x", sha256.Sum256([]byte(token)))
+}
+
+func authorizationCodeKey(code string) string {
+	return fmt.Sprintf("oidc_code:%x", sha256.Sum256([]byte(code)))
+}
+
+func oidcAccessTokenKey(token string) string {
+	return fmt.Sprintf("oidc_access_token:%x", sha256.Sum256([]byte(token)))
+}
+
+func federationStateKey(state string) string {
+	return fmt.Sprintf("federation_state:%x", sha256.Sum256([]byte(state)))
+}
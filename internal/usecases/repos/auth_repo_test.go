@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
 	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
 	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
 	redis_pkg "github.com/minhmannh2001/authconnecthub/pkg/redis"
@@ -205,6 +206,364 @@ func (suite *AuthRepoTestSuite) TestIsTokenBlacklisted_Blacklisted() {
 	suite.True(isBlacklisted)
 }
 
+func (suite *AuthRepoTestSuite) TestBlacklistUserTokens_Success() {
+	err := suite.authRepo.AddUserToken("tracked-user", "access-token", 60)
+	suite.Nil(err)
+	err = suite.authRepo.AddUserToken("tracked-user", "refresh-token", 120)
+	suite.Nil(err)
+	err = suite.authRepo.AddUserToken("tracked-user", "current-token", 120)
+	suite.Nil(err)
+
+	err = suite.authRepo.BlacklistUserTokens("tracked-user", 120, "current-token")
+	suite.Nil(err)
+
+	for token, expected := range map[string]bool{"access-token": true, "refresh-token": true, "current-token": false} {
+		isBlacklisted, err := suite.authRepo.IsTokenBlacklisted(token)
+		suite.Nil(err)
+		suite.Equal(expected, isBlacklisted, token)
+	}
+}
+
+func (suite *AuthRepoTestSuite) TestBlacklistUserTokens_NoTokens() {
+	err := suite.authRepo.BlacklistUserTokens("user-without-tokens", 120)
+	suite.Nil(err)
+}
+
+func (suite *AuthRepoTestSuite) TestRotateRefreshToken_Reuse() {
+	rotation := entity.RefreshTokenRotation{
+		AccessTokenJTI: "access-jti",
+		AccessToken:    "rotated-access-token",
+		RefreshToken:   "rotated-refresh-token",
+		RotatedAt:      time.Now().Truncate(time.Second),
+	}
+
+	previous, err := suite.authRepo.FindRefreshTokenRotation("refresh-jti")
+	suite.Nil(err)
+	suite.Nil(previous)
+
+	previous, err = suite.authRepo.RotateRefreshToken("refresh-jti", rotation, 60)
+	suite.Nil(err)
+	suite.Nil(previous)
+
+	previous, err = suite.authRepo.FindRefreshTokenRotation("refresh-jti")
+	suite.Nil(err)
+	suite.Equal("access-jti", previous.AccessTokenJTI)
+	suite.Equal(rotation.AccessToken, previous.AccessToken)
+
+	// the second exchange gets the pair of the first one
+	previous, err = suite.authRepo.RotateRefreshToken("refresh-jti", entity.RefreshTokenRotation{AccessToken: "other"}, 60)
+	suite.Nil(err)
+	suite.Equal(rotation.AccessToken, previous.AccessToken)
+	suite.Equal(rotation.RefreshToken, previous.RefreshToken)
+	suite.True(rotation.RotatedAt.Equal(previous.RotatedAt))
+}
+
+func (suite *AuthRepoTestSuite) TestRevokeTokenFamily_Success() {
+	err := suite.authRepo.AddFamilyToken("family-1", "family-access-token", 60)
+	suite.Nil(err)
+	err = suite.authRepo.AddFamilyToken("family-1", "family-refresh-token", 120)
+	suite.Nil(err)
+	err = suite.authRepo.AddFamilyToken("family-2", "other-family-token", 60)
+	suite.Nil(err)
+
+	err = suite.authRepo.RevokeTokenFamily("family-1", 120)
+	suite.Nil(err)
+
+	for _, token := range []string{"family-access-token", "family-refresh-token"} {
+		isBlacklisted, err := suite.authRepo.IsTokenBlacklisted(token)
+		suite.Nil(err)
+		suite.True(isBlacklisted)
+	}
+
+	isBlacklisted, err := suite.authRepo.IsTokenBlacklisted("other-family-token")
+	suite.Nil(err)
+	suite.False(isBlacklisted)
+}
+
+func (suite *AuthRepoTestSuite) TestConsumeResetPasswordToken_SingleUse() {
+	err := suite.authRepo.SaveResetPasswordToken("reset-token", "admin", 60)
+	suite.Nil(err)
+
+	username, err := suite.authRepo.ConsumeResetPasswordToken("reset-token")
+	suite.Nil(err)
+	suite.Equal("admin", username)
+
+	// second use of the same token
+	username, err = suite.authRepo.ConsumeResetPasswordToken("reset-token")
+	suite.Nil(err)
+	suite.Equal("", username)
+}
+
+func (suite *AuthRepoTestSuite) TestConsumeResetPasswordToken_NotFound() {
+	username, err := suite.authRepo.ConsumeResetPasswordToken("unknown-token")
+	suite.Nil(err)
+	suite.Equal("", username)
+}
+
+func (suite *AuthRepoTestSuite) TestMFAPendingToken_Lifecycle() {
+	err := suite.authRepo.SaveMFAPendingToken("pending-token", "admin", 60)
+	suite.Nil(err)
+
+	username, err := suite.authRepo.GetMFAPendingToken("pending-token")
+	suite.Nil(err)
+	suite.Equal("admin", username)
+
+	err = suite.authRepo.DeleteMFAPendingToken("pending-token")
+	suite.Nil(err)
+
+	username, err = suite.authRepo.GetMFAPendingToken("pending-token")
+	suite.Nil(err)
+	suite.Equal("", username)
+}
+
+func (suite *AuthRepoTestSuite) TestRecordMFAFailure() {
+	err := suite.authRepo.SaveMFAPendingToken("guessed-token", "admin", 60)
+	suite.Nil(err)
+
+	for i := int64(1); i <= 3; i++ {
+		count, err := suite.authRepo.RecordMFAFailure("guessed-token", 60)
+		suite.Nil(err)
+		suite.Equal(i, count)
+	}
+
+	// the failures go together with the pending login
+	err = suite.authRepo.DeleteMFAPendingToken("guessed-token")
+	suite.Nil(err)
+
+	count, err := suite.authRepo.RecordMFAFailure("guessed-token", 60)
+	suite.Nil(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *AuthRepoTestSuite) TestMarkTOTPCodeUsed_Replay() {
+	ok, err := suite.authRepo.MarkTOTPCodeUsed("admin", 42, 90)
+	suite.Nil(err)
+	suite.True(ok)
+
+	ok, err = suite.authRepo.MarkTOTPCodeUsed("admin", 42, 90)
+	suite.Nil(err)
+	suite.False(ok)
+
+	// the next time step is a different code
+	ok, err = suite.authRepo.MarkTOTPCodeUsed("admin", 43, 90)
+	suite.Nil(err)
+	suite.True(ok)
+}
+
+func (suite *AuthRepoTestSuite) TestMarkVerificationEmailSent() {
+	ok, err := suite.authRepo.MarkVerificationEmailSent("anna", 1)
+	suite.Nil(err)
+	suite.True(ok)
+
+	ok, err = suite.authRepo.MarkVerificationEmailSent("anna", 1)
+	suite.Nil(err)
+	suite.False(ok)
+
+	// a new link can be sent once the last one expired
+	time.Sleep(1100 * time.Millisecond)
+	ok, err = suite.authRepo.MarkVerificationEmailSent("anna", 1)
+	suite.Nil(err)
+	suite.True(ok)
+}
+
+func (suite *AuthRepoTestSuite) TestWebAuthnSession_SingleUse() {
+	err := suite.authRepo.SaveWebAuthnSession("session-id", []byte(`{"challenge":"abc"}`), 60)
+	suite.Nil(err)
+
+	session, err := suite.authRepo.ConsumeWebAuthnSession("session-id")
+	suite.Nil(err)
+	suite.Equal(`{"challenge":"abc"}`, string(session))
+
+	session, err = suite.authRepo.ConsumeWebAuthnSession("session-id")
+	suite.Nil(err)
+	suite.Nil(session)
+}
+
+func (suite *AuthRepoTestSuite) TestSessions_Lifecycle() {
+	hourAgo := time.Now().Add(-time.Hour)
+	for i, id := range []string{"session-1", "session-2", "session-3"} {
+		err := suite.authRepo.SaveSession(entity.Session{
+			ID:         id,
+			Username:   "admin",
+			CreatedAt:  hourAgo,
+			LastSeenAt: hourAgo.Add(time.Duration(i) * time.Minute),
+		}, 60)
+		suite.Nil(err)
+	}
+
+	err := suite.authRepo.TouchSession("session-1", "Mozilla/5.0", "203.0.113.7")
+	suite.Nil(err)
+
+	session, err := suite.authRepo.FindSession("session-1")
+	suite.Nil(err)
+	suite.Equal("admin", session.Username)
+	suite.Equal("Mozilla/5.0", session.UserAgent)
+	suite.Equal("203.0.113.7", session.IP)
+
+	// the most recently used session comes first
+	sessions, err := suite.authRepo.FindSessions("admin")
+	suite.Nil(err)
+	suite.Len(sessions, 3)
+	suite.Equal("session-1", sessions[0].ID)
+
+	err = suite.authRepo.DeleteSession("session-2", "admin")
+	suite.Nil(err)
+
+	session, err = suite.authRepo.FindSession("session-2")
+	suite.Nil(err)
+	suite.Nil(session)
+
+	// touching an ended session doesn't bring it back
+	err = suite.authRepo.TouchSession("session-2", "Mozilla/5.0", "203.0.113.7")
+	suite.Nil(err)
+
+	session, err = suite.authRepo.FindSession("session-2")
+	suite.Nil(err)
+	suite.Nil(session)
+
+	err = suite.authRepo.DeleteUserSessions("admin", "session-3")
+	suite.Nil(err)
+
+	sessions, err = suite.authRepo.FindSessions("admin")
+	suite.Nil(err)
+	suite.Len(sessions, 1)
+	suite.Equal("session-3", sessions[0].ID)
+}
+
+func (suite *AuthRepoTestSuite) TestLoginLocks_Lifecycle() {
+	for i := 1; i <= 3; i++ {
+		count, err := suite.authRepo.RecordLoginFailure("username", "admin", 60)
+		suite.Nil(err)
+		suite.Equal(int64(i), count)
+	}
+
+	// failures of other subjects are counted apart
+	count, err := suite.authRepo.RecordLoginFailure("ip", "203.0.113.7", 60)
+	suite.Nil(err)
+	suite.Equal(int64(1), count)
+
+	lock, err := suite.authRepo.FindLoginLock("username", "admin")
+	suite.Nil(err)
+	suite.Nil(lock)
+
+	err = suite.authRepo.LockLogin("username", "admin", 60)
+	suite.Nil(err)
+	err = suite.authRepo.LockLogin("ip", "2001:db8::1", 120)
+	suite.Nil(err)
+
+	lock, err = suite.authRepo.FindLoginLock("username", "admin")
+	suite.Nil(err)
+	suite.WithinDuration(time.Now().Add(time.Minute), lock.Until, 2*time.Second)
+
+	locks, err := suite.authRepo.FindLoginLocks()
+	suite.Nil(err)
+	suite.Len(locks, 2)
+	suite.Equal(entity.LoginLock{Kind: "username", Value: "admin", Until: lock.Until}, locks[0])
+	suite.Equal("2001:db8::1", locks[1].Value)
+
+	deleted, err := suite.authRepo.DeleteLoginLock("username", "admin")
+	suite.Nil(err)
+	suite.True(deleted)
+
+	deleted, err = suite.authRepo.DeleteLoginLock("username", "admin")
+	suite.Nil(err)
+	suite.False(deleted)
+
+	// the failures are forgotten with the lock
+	count, err = suite.authRepo.RecordLoginFailure("username", "admin", 60)
+	suite.Nil(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *AuthRepoTestSuite) TestLoginDelay() {
+	until, err := suite.authRepo.FindLoginDelay("ip", "203.0.113.7")
+	suite.Nil(err)
+	suite.True(until.IsZero())
+
+	err = suite.authRepo.DelayLogin("ip", "203.0.113.7", 500*time.Millisecond)
+	suite.Nil(err)
+
+	until, err = suite.authRepo.FindLoginDelay("ip", "203.0.113.7")
+	suite.Nil(err)
+	suite.WithinDuration(time.Now().Add(500*time.Millisecond), until, 100*time.Millisecond)
+
+	// the delay is lifted with the failures
+	err = suite.authRepo.ClearLoginFailures("ip", "203.0.113.7")
+	suite.Nil(err)
+	until, err = suite.authRepo.FindLoginDelay("ip", "203.0.113.7")
+	suite.Nil(err)
+	suite.True(until.IsZero())
+}
+
+func (suite *AuthRepoTestSuite) TestAuthorizationCode_SingleUse() {
+	authorizationCode := entity.AuthorizationCode{
+		ClientID:            "grafana",
+		RedirectURI:         "https://grafana.home/login/generic_oauth",
+		Username:            "admin",
+		Scope:               "openid email",
+		Nonce:               "nonce",
+		CodeChallenge:       "challenge",
+		CodeChallengeMethod: "S256",
+	}
+	err := suite.authRepo.SaveAuthorizationCode("the-code", authorizationCode, 60)
+	suite.Nil(err)
+
+	// finding the code doesn't redeem it
+	found, err := suite.authRepo.FindAuthorizationCode("the-code")
+	suite.Nil(err)
+	suite.Equal(&authorizationCode, found)
+
+	found, err = suite.authRepo.ConsumeAuthorizationCode("the-code")
+	suite.Nil(err)
+	suite.Equal(&authorizationCode, found)
+
+	found, err = suite.authRepo.ConsumeAuthorizationCode("the-code")
+	suite.Nil(err)
+	suite.Nil(found)
+
+	found, err = suite.authRepo.FindAuthorizationCode("the-code")
+	suite.Nil(err)
+	suite.Nil(found)
+}
+
+func (suite *AuthRepoTestSuite) TestOIDCAccessToken() {
+	accessToken := entity.OIDCAccessToken{ClientID: "grafana", Username: "admin", Scope: "openid"}
+	err := suite.authRepo.SaveOIDCAccessToken("access-token", accessToken, 60)
+	suite.Nil(err)
+
+	// the token can be used as long as it is valid
+	for i := 0; i < 2; i++ {
+		found, err := suite.authRepo.FindOIDCAccessToken("access-token")
+		suite.Nil(err)
+		suite.Equal(&accessToken, found)
+	}
+
+	found, err := suite.authRepo.FindOIDCAccessToken("unknown-token")
+	suite.Nil(err)
+	suite.Nil(found)
+
+	err = suite.authRepo.DeleteOIDCAccessToken("access-token")
+	suite.Nil(err)
+
+	found, err = suite.authRepo.FindOIDCAccessToken("access-token")
+	suite.Nil(err)
+	suite.Nil(found)
+}
+
+func (suite *AuthRepoTestSuite) TestFederationState_SingleUse() {
+	state := entity.FederationState{Provider: "keycloak", Nonce: "nonce", CodeVerifier: "verifier", RememberMe: true}
+	err := suite.authRepo.SaveFederationState("state", state, 60)
+	suite.Nil(err)
+
+	found, err := suite.authRepo.ConsumeFederationState("state")
+	suite.Nil(err)
+	suite.Equal(&state, found)
+
+	found, err = suite.authRepo.ConsumeFederationState("state")
+	suite.Nil(err)
+	suite.Nil(found)
+}
+
 func TestAuthRepoTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthRepoTestSuite))
 }
@@ -1,11 +1,54 @@
 package repos
 
-import "github.com/minhmannh2001/authconnecthub/internal/entity"
+import (
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+)
 
 type (
 	IAuthRepo interface {
 		BlacklistToken(string, int) error
 		IsTokenBlacklisted(string) (bool, error)
+		AddUserToken(string, string, int) error
+		BlacklistUserTokens(string, int, ...string) error
+		AddFamilyToken(string, string, int) error
+		RotateRefreshToken(string, entity.RefreshTokenRotation, int) (*entity.RefreshTokenRotation, error)
+		FindRefreshTokenRotation(string) (*entity.RefreshTokenRotation, error)
+		RevokeTokenFamily(string, int) error
+		SaveSession(entity.Session, int) error
+		ExtendSession(string, string, int) error
+		TouchSession(string, string, string) error
+		FindSession(string) (*entity.Session, error)
+		FindSessions(string) ([]entity.Session, error)
+		DeleteSession(string, string) error
+		DeleteUserSessions(string, ...string) error
+		RecordLoginFailure(string, string, int) (int64, error)
+		ClearLoginFailures(string, string) error
+		DelayLogin(string, string, time.Duration) error
+		FindLoginDelay(string, string) (time.Time, error)
+		LockLogin(string, string, int) error
+		FindLoginLock(string, string) (*entity.LoginLock, error)
+		FindLoginLocks() ([]entity.LoginLock, error)
+		DeleteLoginLock(string, string) (bool, error)
+		SaveResetPasswordToken(string, string, int) error
+		ConsumeResetPasswordToken(string) (string, error)
+		SaveMFAPendingToken(string, string, int) error
+		GetMFAPendingToken(string) (string, error)
+		RecordMFAFailure(string, int) (int64, error)
+		DeleteMFAPendingToken(string) error
+		MarkTOTPCodeUsed(string, int64, int) (bool, error)
+		MarkVerificationEmailSent(string, int) (bool, error)
+		SaveWebAuthnSession(string, []byte, int) error
+		ConsumeWebAuthnSession(string) ([]byte, error)
+		SaveAuthorizationCode(string, entity.AuthorizationCode, int) error
+		FindAuthorizationCode(string) (*entity.AuthorizationCode, error)
+		ConsumeAuthorizationCode(string) (*entity.AuthorizationCode, error)
+		SaveOIDCAccessToken(string, entity.OIDCAccessToken, int) error
+		FindOIDCAccessToken(string) (*entity.OIDCAccessToken, error)
+		DeleteOIDCAccessToken(string) error
+		SaveFederationState(string, entity.FederationState, int) error
+		ConsumeFederationState(string) (*entity.FederationState, error)
 	}
 
 	IUserRepo interface {
@@ -13,11 +56,69 @@ type (
 		RetrieveByID(uint) (entity.User, error)
 		Update(entity.User) (entity.User, error)
 		Delete(entity.User) (entity.User, error)
+		Restore(uint) (entity.User, error)
 		FindByUsernameOrEmail(string, string) (*entity.User, error)
+		FindUsers(entity.UserQuery) ([]entity.User, error)
 	}
 
 	IRoleRepo interface {
 		GetRoleIDByName(string) (uint, error)
+		ListRoles() ([]entity.Role, error)
+		FindRoleByName(string) (*entity.Role, error)
+		FindRolesByNames([]string) ([]entity.Role, error)
+		FindAncestorIDs(uint) ([]uint, error)
+		CreateRole(entity.Role) (entity.Role, error)
+		UpdateRole(entity.Role, string) (entity.Role, error)
+		DeleteRole(string) error
+		ListPermissions() ([]entity.Permission, error)
+		FindPermissionsByNames([]string) ([]entity.Permission, error)
+	}
+
+	IServiceAccountRepo interface {
+		CreateServiceAccount(entity.ServiceAccount) (entity.ServiceAccount, error)
+		FindServiceAccounts() ([]entity.ServiceAccount, error)
+		FindServiceAccountByClientID(string) (*entity.ServiceAccount, error)
+		UpdateServiceAccountUsage(uint) error
+		DeleteServiceAccount(string) (bool, error)
+	}
+
+	IRecoveryCodeRepo interface {
+		ReplaceRecoveryCodes(uint, []string) error
+		FindUnusedRecoveryCodes(uint) ([]entity.RecoveryCode, error)
+		MarkRecoveryCodeUsed(uint) (bool, error)
+		CountUnusedRecoveryCodes(uint) (int64, error)
+	}
+
+	IWebAuthnCredentialRepo interface {
+		CreateWebAuthnCredential(entity.WebAuthnCredential) (entity.WebAuthnCredential, error)
+		FindWebAuthnCredentials(uint) ([]entity.WebAuthnCredential, error)
+		FindWebAuthnCredentialByCredentialID([]byte) (*entity.WebAuthnCredential, error)
+		UpdateWebAuthnCredentialUsage(uint, uint32, bool) error
+		DeleteWebAuthnCredential(uint, uint) (bool, error)
+	}
+
+	ILinkedIdentityRepo interface {
+		CreateLinkedIdentity(entity.LinkedIdentity) (entity.LinkedIdentity, error)
+		FindLinkedIdentities(uint) ([]entity.LinkedIdentity, error)
+		FindLinkedIdentity(string, string) (*entity.LinkedIdentity, error)
+		UpdateLinkedIdentityUsage(uint) error
+		DeleteLinkedIdentity(uint, uint) (bool, error)
+	}
+
+	IApplicationRepo interface {
+		CreateApplication(entity.Application) (entity.Application, error)
+		FindApplications() ([]entity.Application, error)
+		FindApplicationByClientID(string) (*entity.Application, error)
+		UpdateApplication(entity.Application) (entity.Application, error)
+		DeleteApplication(string) (bool, error)
+		SaveApplicationGrant(entity.ApplicationGrant) (entity.ApplicationGrant, error)
+		FindApplicationGrants() ([]entity.ApplicationGrant, error)
+		FindApplicationGrantsByApplicationID(uint) ([]entity.ApplicationGrant, error)
+		DeleteApplicationGrant(uint, string, string) (bool, error)
+	}
+
+	IRateLimitRepo interface {
+		TakeToken(string, int, float64) (*entity.RateLimitResult, error)
 	}
 )
 
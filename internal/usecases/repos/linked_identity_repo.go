@@ -0,0 +1,73 @@
+package repos
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
+	"gorm.io/gorm"
+)
+
+type LinkedIdentityRepo struct {
+	*postgres.Postgres
+}
+
+func NewLinkedIdentityRepo(pg *postgres.Postgres) *LinkedIdentityRepo {
+	return &LinkedIdentityRepo{pg}
+}
+
+func (r *LinkedIdentityRepo) CreateLinkedIdentity(identity entity.LinkedIdentity) (entity.LinkedIdentity, error) {
+	if err := r.Conn.Create(&identity).Error; err != nil {
+		return entity.LinkedIdentity{}, fmt.Errorf("failed to create linked identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+func (r *LinkedIdentityRepo) FindLinkedIdentities(userID uint) ([]entity.LinkedIdentity, error) {
+	var identities []entity.LinkedIdentity
+	err := r.Conn.Where("user_id = ?", userID).Order("created_at").Find(&identities).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find linked identities: %w", err)
+	}
+
+	return identities, nil
+}
+
+// FindLinkedIdentity returns the identity together with its user.
+// A nil identity means it isn't linked to any user.
+func (r *LinkedIdentityRepo) FindLinkedIdentity(issuer string, subject string) (*entity.LinkedIdentity, error) {
+	var identity entity.LinkedIdentity
+	err := r.Conn.Preload("User").Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find linked identity: %w", err)
+	}
+
+	return &identity, nil
+}
+
+// UpdateLinkedIdentityUsage records a successful sign in with the identity
+func (r *LinkedIdentityRepo) UpdateLinkedIdentityUsage(id uint) error {
+	err := r.Conn.Model(&entity.LinkedIdentity{}).Where("id = ?", id).Update("last_used_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to update linked identity: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteLinkedIdentity removes the identity if it belongs to the user.
+// It returns false when there was nothing to delete.
+func (r *LinkedIdentityRepo) DeleteLinkedIdentity(userID uint, id uint) (bool, error) {
+	result := r.Conn.Unscoped().Where("id = ? AND user_id = ?", id, userID).Delete(&entity.LinkedIdentity{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete linked identity: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,114 @@
+package repos_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
+	"github.com/minhmannh2001/authconnecthub/tests/testhelpers"
+	"github.com/stretchr/testify/suite"
+)
+
+type LinkedIdentityRepoTestSuite struct {
+	suite.Suite
+	pgContainer        *testhelpers.PostgresContainer
+	pg                 *postgres.Postgres
+	linkedIdentityRepo *repos.LinkedIdentityRepo
+	ctx                context.Context
+}
+
+func (suite *LinkedIdentityRepoTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	pgContainer, err := testhelpers.CreatePostgresContainer(suite.ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pgContainer = pgContainer
+	host, err := pgContainer.ExtractHost(pgContainer.ConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	port, err := pgContainer.ExtractPort(pgContainer.ConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pg, err := postgres.New(&config.Config{
+		PG: config.PG{
+			Host:     host,
+			Port:     port,
+			Username: "postgres",
+			Password: "postgres",
+			Dbname:   "test-db",
+			Sslmode:  "disable",
+		},
+		Authen: config.Authen{
+			AdminUsername: "admin",
+			AdminPassword: "password",
+			AdminEmail:    "admin@localhost",
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pg = pg
+	suite.linkedIdentityRepo = repos.NewLinkedIdentityRepo(pg)
+}
+
+func (suite *LinkedIdentityRepoTestSuite) TearDownSuite() {
+	if err := suite.pgContainer.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating postgres container: %s", err)
+	}
+}
+
+func (suite *LinkedIdentityRepoTestSuite) TestLinkedIdentities_Lifecycle() {
+	var admin entity.User
+	err := suite.pg.Conn.Where("username = ?", "admin").First(&admin).Error
+	suite.Nil(err)
+
+	created, err := suite.linkedIdentityRepo.CreateLinkedIdentity(entity.LinkedIdentity{
+		UserID:   admin.ID,
+		Provider: "keycloak",
+		Issuer:   "https://sso.example.com",
+		Subject:  "u-42",
+		Email:    "admin@example.com",
+	})
+	suite.Nil(err)
+	suite.NotZero(created.ID)
+
+	// an identity can only be linked to one user
+	_, err = suite.linkedIdentityRepo.CreateLinkedIdentity(entity.LinkedIdentity{UserID: admin.ID, Provider: "keycloak", Issuer: "https://sso.example.com", Subject: "u-42"})
+	suite.NotNil(err)
+
+	identities, err := suite.linkedIdentityRepo.FindLinkedIdentities(admin.ID)
+	suite.Nil(err)
+	suite.Len(identities, 1)
+
+	err = suite.linkedIdentityRepo.UpdateLinkedIdentityUsage(created.ID)
+	suite.Nil(err)
+
+	found, err := suite.linkedIdentityRepo.FindLinkedIdentity("https://sso.example.com", "u-42")
+	suite.Nil(err)
+	suite.Equal("admin", found.User.Username)
+	suite.NotNil(found.LastUsedAt)
+
+	// only the owner can unlink an identity
+	deleted, err := suite.linkedIdentityRepo.DeleteLinkedIdentity(admin.ID+1, created.ID)
+	suite.Nil(err)
+	suite.False(deleted)
+
+	deleted, err = suite.linkedIdentityRepo.DeleteLinkedIdentity(admin.ID, created.ID)
+	suite.Nil(err)
+	suite.True(deleted)
+
+	found, err = suite.linkedIdentityRepo.FindLinkedIdentity("https://sso.example.com", "u-42")
+	suite.Nil(err)
+	suite.Nil(found)
+}
+
+func TestLinkedIdentityRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(LinkedIdentityRepoTestSuite))
+}
@@ -2,13 +2,54 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	entity "github.com/minhmannh2001/authconnecthub/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // IAuthRepo is an autogenerated mock type for the IAuthRepo type
 type IAuthRepo struct {
 	mock.Mock
 }
 
+// AddFamilyToken provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) AddFamilyToken(_a0 string, _a1 string, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddFamilyToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddUserToken provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) AddUserToken(_a0 string, _a1 string, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddUserToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // BlacklistToken provides a mock function with given fields: _a0, _a1
 func (_m *IAuthRepo) BlacklistToken(_a0 string, _a1 int) error {
 	ret := _m.Called(_a0, _a1)
@@ -27,25 +68,331 @@ func (_m *IAuthRepo) BlacklistToken(_a0 string, _a1 int) error {
 	return r0
 }
 
-// IsTokenBlacklisted provides a mock function with given fields: _a0
-func (_m *IAuthRepo) IsTokenBlacklisted(_a0 string) (bool, error) {
+// BlacklistUserTokens provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) BlacklistUserTokens(_a0 string, _a1 int, _a2 ...string) error {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BlacklistUserTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int, ...string) error); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClearLoginFailures provides a mock function with given fields: _a0, _a1
+func (_m *IAuthRepo) ClearLoginFailures(_a0 string, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClearLoginFailures")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConsumeAuthorizationCode provides a mock function with given fields: _a0
+func (_m *IAuthRepo) ConsumeAuthorizationCode(_a0 string) (*entity.AuthorizationCode, error) {
 	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
-		panic("no return value specified for IsTokenBlacklisted")
+		panic("no return value specified for ConsumeAuthorizationCode")
 	}
 
-	var r0 bool
+	var r0 *entity.AuthorizationCode
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+	if rf, ok := ret.Get(0).(func(string) (*entity.AuthorizationCode, error)); ok {
 		return rf(_a0)
 	}
-	if rf, ok := ret.Get(0).(func(string) bool); ok {
+	if rf, ok := ret.Get(0).(func(string) *entity.AuthorizationCode); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AuthorizationCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConsumeFederationState provides a mock function with given fields: _a0
+func (_m *IAuthRepo) ConsumeFederationState(_a0 string) (*entity.FederationState, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeFederationState")
+	}
+
+	var r0 *entity.FederationState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.FederationState, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.FederationState); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.FederationState)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConsumeResetPasswordToken provides a mock function with given fields: _a0
+func (_m *IAuthRepo) ConsumeResetPasswordToken(_a0 string) (string, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeResetPasswordToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConsumeWebAuthnSession provides a mock function with given fields: _a0
+func (_m *IAuthRepo) ConsumeWebAuthnSession(_a0 string) ([]byte, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeWebAuthnSession")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]byte, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
 		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DelayLogin provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) DelayLogin(_a0 string, _a1 string, _a2 time.Duration) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DelayLogin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Duration) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteLoginLock provides a mock function with given fields: _a0, _a1
+func (_m *IAuthRepo) DeleteLoginLock(_a0 string, _a1 string) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLoginLock")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (bool, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteMFAPendingToken provides a mock function with given fields: _a0
+func (_m *IAuthRepo) DeleteMFAPendingToken(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMFAPendingToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteOIDCAccessToken provides a mock function with given fields: _a0
+func (_m *IAuthRepo) DeleteOIDCAccessToken(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOIDCAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSession provides a mock function with given fields: _a0, _a1
+func (_m *IAuthRepo) DeleteSession(_a0 string, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUserSessions provides a mock function with given fields: _a0, _a1
+func (_m *IAuthRepo) DeleteUserSessions(_a0 string, _a1 ...string) error {
+	_va := make([]interface{}, len(_a1))
+	for _i := range _a1 {
+		_va[_i] = _a1[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, ...string) error); ok {
+		r0 = rf(_a0, _a1...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExtendSession provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) ExtendSession(_a0 string, _a1 string, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExtendSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAuthorizationCode provides a mock function with given fields: _a0
+func (_m *IAuthRepo) FindAuthorizationCode(_a0 string) (*entity.AuthorizationCode, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAuthorizationCode")
+	}
+
+	var r0 *entity.AuthorizationCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.AuthorizationCode, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.AuthorizationCode); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AuthorizationCode)
+		}
+	}
+
 	if rf, ok := ret.Get(1).(func(string) error); ok {
 		r1 = rf(_a0)
 	} else {
@@ -55,6 +402,592 @@ func (_m *IAuthRepo) IsTokenBlacklisted(_a0 string) (bool, error) {
 	return r0, r1
 }
 
+// FindLoginDelay provides a mock function with given fields: _a0, _a1
+func (_m *IAuthRepo) FindLoginDelay(_a0 string, _a1 string) (time.Time, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLoginDelay")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (time.Time, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) time.Time); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindLoginLock provides a mock function with given fields: _a0, _a1
+func (_m *IAuthRepo) FindLoginLock(_a0 string, _a1 string) (*entity.LoginLock, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLoginLock")
+	}
+
+	var r0 *entity.LoginLock
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*entity.LoginLock, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *entity.LoginLock); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.LoginLock)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindLoginLocks provides a mock function with given fields:
+func (_m *IAuthRepo) FindLoginLocks() ([]entity.LoginLock, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLoginLocks")
+	}
+
+	var r0 []entity.LoginLock
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.LoginLock, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.LoginLock); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.LoginLock)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindOIDCAccessToken provides a mock function with given fields: _a0
+func (_m *IAuthRepo) FindOIDCAccessToken(_a0 string) (*entity.OIDCAccessToken, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOIDCAccessToken")
+	}
+
+	var r0 *entity.OIDCAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.OIDCAccessToken, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.OIDCAccessToken); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.OIDCAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRefreshTokenRotation provides a mock function with given fields: _a0
+func (_m *IAuthRepo) FindRefreshTokenRotation(_a0 string) (*entity.RefreshTokenRotation, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRefreshTokenRotation")
+	}
+
+	var r0 *entity.RefreshTokenRotation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.RefreshTokenRotation, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.RefreshTokenRotation); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RefreshTokenRotation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindSession provides a mock function with given fields: _a0
+func (_m *IAuthRepo) FindSession(_a0 string) (*entity.Session, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSession")
+	}
+
+	var r0 *entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.Session, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.Session); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindSessions provides a mock function with given fields: _a0
+func (_m *IAuthRepo) FindSessions(_a0 string) ([]entity.Session, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSessions")
+	}
+
+	var r0 []entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]entity.Session, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []entity.Session); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMFAPendingToken provides a mock function with given fields: _a0
+func (_m *IAuthRepo) GetMFAPendingToken(_a0 string) (string, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMFAPendingToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsTokenBlacklisted provides a mock function with given fields: _a0
+func (_m *IAuthRepo) IsTokenBlacklisted(_a0 string) (bool, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTokenBlacklisted")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockLogin provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) LockLogin(_a0 string, _a1 string, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockLogin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkTOTPCodeUsed provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) MarkTOTPCodeUsed(_a0 string, _a1 int64, _a2 int) (bool, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkTOTPCodeUsed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int64, int) (bool, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(string, int64, int) bool); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int64, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkVerificationEmailSent provides a mock function with given fields: _a0, _a1
+func (_m *IAuthRepo) MarkVerificationEmailSent(_a0 string, _a1 int) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkVerificationEmailSent")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) (bool, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) bool); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordLoginFailure provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) RecordLoginFailure(_a0 string, _a1 string, _a2 int) (int64, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLoginFailure")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, int) (int64, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, int) int64); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordMFAFailure provides a mock function with given fields: _a0, _a1
+func (_m *IAuthRepo) RecordMFAFailure(_a0 string, _a1 int) (int64, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordMFAFailure")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) (int64, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) int64); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeTokenFamily provides a mock function with given fields: _a0, _a1
+func (_m *IAuthRepo) RevokeTokenFamily(_a0 string, _a1 int) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeTokenFamily")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RotateRefreshToken provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) RotateRefreshToken(_a0 string, _a1 entity.RefreshTokenRotation, _a2 int) (*entity.RefreshTokenRotation, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateRefreshToken")
+	}
+
+	var r0 *entity.RefreshTokenRotation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, entity.RefreshTokenRotation, int) (*entity.RefreshTokenRotation, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(string, entity.RefreshTokenRotation, int) *entity.RefreshTokenRotation); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RefreshTokenRotation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, entity.RefreshTokenRotation, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveAuthorizationCode provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) SaveAuthorizationCode(_a0 string, _a1 entity.AuthorizationCode, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAuthorizationCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, entity.AuthorizationCode, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveFederationState provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) SaveFederationState(_a0 string, _a1 entity.FederationState, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveFederationState")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, entity.FederationState, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveMFAPendingToken provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) SaveMFAPendingToken(_a0 string, _a1 string, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMFAPendingToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveOIDCAccessToken provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) SaveOIDCAccessToken(_a0 string, _a1 entity.OIDCAccessToken, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOIDCAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, entity.OIDCAccessToken, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveResetPasswordToken provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) SaveResetPasswordToken(_a0 string, _a1 string, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveResetPasswordToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveSession provides a mock function with given fields: _a0, _a1
+func (_m *IAuthRepo) SaveSession(_a0 entity.Session, _a1 int) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entity.Session, int) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveWebAuthnSession provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) SaveWebAuthnSession(_a0 string, _a1 []byte, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveWebAuthnSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []byte, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchSession provides a mock function with given fields: _a0, _a1, _a2
+func (_m *IAuthRepo) TouchSession(_a0 string, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewIAuthRepo creates a new instance of IAuthRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIAuthRepo(t interface {
@@ -2,13 +2,182 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	entity "github.com/minhmannh2001/authconnecthub/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // IRoleRepo is an autogenerated mock type for the IRoleRepo type
 type IRoleRepo struct {
 	mock.Mock
 }
 
+// CreateRole provides a mock function with given fields: _a0
+func (_m *IRoleRepo) CreateRole(_a0 entity.Role) (entity.Role, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRole")
+	}
+
+	var r0 entity.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.Role) (entity.Role, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(entity.Role) entity.Role); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(entity.Role)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.Role) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteRole provides a mock function with given fields: _a0
+func (_m *IRoleRepo) DeleteRole(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAncestorIDs provides a mock function with given fields: _a0
+func (_m *IRoleRepo) FindAncestorIDs(_a0 uint) ([]uint, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAncestorIDs")
+	}
+
+	var r0 []uint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]uint, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []uint); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindPermissionsByNames provides a mock function with given fields: _a0
+func (_m *IRoleRepo) FindPermissionsByNames(_a0 []string) ([]entity.Permission, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPermissionsByNames")
+	}
+
+	var r0 []entity.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string) ([]entity.Permission, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func([]string) []entity.Permission); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRoleByName provides a mock function with given fields: _a0
+func (_m *IRoleRepo) FindRoleByName(_a0 string) (*entity.Role, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRoleByName")
+	}
+
+	var r0 *entity.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.Role, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.Role); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRolesByNames provides a mock function with given fields: _a0
+func (_m *IRoleRepo) FindRolesByNames(_a0 []string) ([]entity.Role, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRolesByNames")
+	}
+
+	var r0 []entity.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string) ([]entity.Role, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func([]string) []entity.Role); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRoleIDByName provides a mock function with given fields: _a0
 func (_m *IRoleRepo) GetRoleIDByName(_a0 string) (uint, error) {
 	ret := _m.Called(_a0)
@@ -37,6 +206,94 @@ func (_m *IRoleRepo) GetRoleIDByName(_a0 string) (uint, error) {
 	return r0, r1
 }
 
+// ListPermissions provides a mock function with given fields:
+func (_m *IRoleRepo) ListPermissions() ([]entity.Permission, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPermissions")
+	}
+
+	var r0 []entity.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.Permission, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.Permission); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRoles provides a mock function with given fields:
+func (_m *IRoleRepo) ListRoles() ([]entity.Role, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRoles")
+	}
+
+	var r0 []entity.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]entity.Role, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []entity.Role); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRole provides a mock function with given fields: _a0, _a1
+func (_m *IRoleRepo) UpdateRole(_a0 entity.Role, _a1 string) (entity.Role, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRole")
+	}
+
+	var r0 entity.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.Role, string) (entity.Role, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(entity.Role, string) entity.Role); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(entity.Role)
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.Role, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewIRoleRepo creates a new instance of IRoleRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIRoleRepo(t interface {
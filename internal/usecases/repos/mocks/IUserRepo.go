@@ -98,6 +98,64 @@ func (_m *IUserRepo) FindByUsernameOrEmail(_a0 string, _a1 string) (*entity.User
 	return r0, r1
 }
 
+// FindUsers provides a mock function with given fields: _a0
+func (_m *IUserRepo) FindUsers(_a0 entity.UserQuery) ([]entity.User, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUsers")
+	}
+
+	var r0 []entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(entity.UserQuery) ([]entity.User, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(entity.UserQuery) []entity.User); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(entity.UserQuery) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: _a0
+func (_m *IUserRepo) Restore(_a0 uint) (entity.User, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (entity.User, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(uint) entity.User); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(entity.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RetrieveByID provides a mock function with given fields: _a0
 func (_m *IUserRepo) RetrieveByID(_a0 uint) (entity.User, error) {
 	ret := _m.Called(_a0)
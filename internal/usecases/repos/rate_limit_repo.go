@@ -0,0 +1,78 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	redis_pkg "github.com/minhmannh2001/authconnecthub/pkg/redis"
+	"github.com/redis/go-redis/v9"
+)
+
+type RateLimitRepo struct {
+	*redis_pkg.Redis
+}
+
+func NewRateLimitRepo(redis *redis_pkg.Redis) *RateLimitRepo {
+	return &RateLimitRepo{redis}
+}
+
+// takeTokenScript refills the bucket for the time passed since the last request and takes
+// a token out of it if there is one. The clock of redis is used, so every instance of the
+// app agrees on it. The bucket is dropped once it would be full again.
+var takeTokenScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) + tonumber(time[2]) / 1000000
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "updated_at")
+local tokens = tonumber(bucket[1]) or capacity
+local updatedAt = tonumber(bucket[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - updatedAt) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated_at", tostring(now))
+redis.call("EXPIRE", KEYS[1], math.ceil((capacity - tokens) / rate) + 1)
+return {allowed, tostring(tokens)}
+`)
+
+// TakeToken counts a request against the bucket with the given key. The bucket holds up to
+// capacity tokens and refills with rate tokens per second.
+func (r *RateLimitRepo) TakeToken(key string, capacity int, rate float64) (*entity.RateLimitResult, error) {
+	ctx := context.Background()
+	values, err := takeTokenScript.Run(ctx, r.Client, []string{"rate_limit:" + key}, capacity, rate).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+
+	allowed, _ := values[0].(int64)
+	tokens, err := strconv.ParseFloat(fmt.Sprint(values[1]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate limit bucket: %w", err)
+	}
+
+	result := &entity.RateLimitResult{
+		Allowed:   allowed == 1,
+		Limit:     capacity,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(capacity) - tokens) / rate),
+	}
+	if !result.Allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+
+	return result, nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,81 @@
+package repos_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+	redis_pkg "github.com/minhmannh2001/authconnecthub/pkg/redis"
+	"github.com/minhmannh2001/authconnecthub/tests/testhelpers"
+	"github.com/stretchr/testify/suite"
+)
+
+type RateLimitRepoTestSuite struct {
+	suite.Suite
+	redisContainer *testhelpers.RedisContainer
+	rateLimitRepo  *repos.RateLimitRepo
+	ctx            context.Context
+}
+
+func (suite *RateLimitRepoTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	redisContainer, err := testhelpers.CreateRedisContainer(suite.ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.redisContainer = redisContainer
+	host, err := redisContainer.ExtractHost(redisContainer.ConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	port, err := redisContainer.ExtractPort(redisContainer.ConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	redis, err := redis_pkg.New(&config.Config{
+		Redis: config.Redis{
+			Host:     host,
+			Port:     port,
+			Password: "",
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.rateLimitRepo = repos.NewRateLimitRepo(redis)
+}
+
+func (suite *RateLimitRepoTestSuite) TearDownSuite() {
+	if err := suite.redisContainer.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating redis container: %s", err)
+	}
+}
+
+func (suite *RateLimitRepoTestSuite) TestTakeToken_EmptiesBucket() {
+	for i := 2; i >= 0; i-- {
+		result, err := suite.rateLimitRepo.TakeToken("empties", 3, 0.1)
+		suite.Nil(err)
+		suite.True(result.Allowed)
+		suite.Equal(3, result.Limit)
+		suite.Equal(i, result.Remaining)
+	}
+
+	// the next token is back in about 10 seconds
+	result, err := suite.rateLimitRepo.TakeToken("empties", 3, 0.1)
+	suite.Nil(err)
+	suite.False(result.Allowed)
+	suite.Equal(0, result.Remaining)
+	suite.InDelta(10, result.RetryAfter.Seconds(), 1)
+	suite.InDelta(30, result.Reset.Seconds(), 1)
+
+	// other clients have their own bucket
+	result, err = suite.rateLimitRepo.TakeToken("other", 3, 0.1)
+	suite.Nil(err)
+	suite.True(result.Allowed)
+}
+
+func TestRateLimitRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitRepoTestSuite))
+}
@@ -0,0 +1,76 @@
+package repos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepo struct {
+	*postgres.Postgres
+}
+
+func NewRecoveryCodeRepo(pg *postgres.Postgres) *RecoveryCodeRepo {
+	return &RecoveryCodeRepo{pg}
+}
+
+// ReplaceRecoveryCodes drops every recovery code of the user and stores the given hashes instead
+func (r *RecoveryCodeRepo) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	err := r.Conn.Transaction(func(tx *gorm.DB) error {
+		// hard delete, old codes must never be usable again
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if len(codeHashes) == 0 {
+			return nil
+		}
+
+		codes := make([]entity.RecoveryCode, 0, len(codeHashes))
+		for _, codeHash := range codeHashes {
+			codes = append(codes, entity.RecoveryCode{UserID: userID, CodeHash: codeHash})
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RecoveryCodeRepo) FindUnusedRecoveryCodes(userID uint) ([]entity.RecoveryCode, error) {
+	var codes []entity.RecoveryCode
+	err := r.Conn.Where("user_id = ? AND used_at IS NULL", userID).Find(&codes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recovery codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+// MarkRecoveryCodeUsed records the use of the code. It returns false when the code
+// was used in the meantime, so two concurrent logins can't share one code.
+func (r *RecoveryCodeRepo) MarkRecoveryCodeUsed(id uint) (bool, error) {
+	result := r.Conn.Model(&entity.RecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark recovery code as used: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *RecoveryCodeRepo) CountUnusedRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	err := r.Conn.Model(&entity.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,111 @@
+package repos_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
+	"github.com/minhmannh2001/authconnecthub/tests/testhelpers"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecoveryCodeRepoTestSuite struct {
+	suite.Suite
+	pgContainer      *testhelpers.PostgresContainer
+	pg               *postgres.Postgres
+	recoveryCodeRepo *repos.RecoveryCodeRepo
+	ctx              context.Context
+}
+
+func (suite *RecoveryCodeRepoTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	pgContainer, err := testhelpers.CreatePostgresContainer(suite.ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pgContainer = pgContainer
+	host, err := pgContainer.ExtractHost(pgContainer.ConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	port, err := pgContainer.ExtractPort(pgContainer.ConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pg, err := postgres.New(&config.Config{
+		PG: config.PG{
+			Host:     host,
+			Port:     port,
+			Username: "postgres",
+			Password: "postgres",
+			Dbname:   "test-db",
+			Sslmode:  "disable",
+		},
+		Authen: config.Authen{
+			AdminUsername: "admin",
+			AdminPassword: "password",
+			AdminEmail:    "admin@localhost",
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pg = pg
+	suite.recoveryCodeRepo = repos.NewRecoveryCodeRepo(pg)
+}
+
+func (suite *RecoveryCodeRepoTestSuite) TearDownSuite() {
+	if err := suite.pgContainer.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating postgres container: %s", err)
+	}
+}
+
+func (suite *RecoveryCodeRepoTestSuite) TestRecoveryCodes_Lifecycle() {
+	var admin entity.User
+	err := suite.pg.Conn.Where("username = ?", "admin").First(&admin).Error
+	suite.Nil(err)
+
+	err = suite.recoveryCodeRepo.ReplaceRecoveryCodes(admin.ID, []string{"hash-1", "hash-2", "hash-3"})
+	suite.Nil(err)
+
+	codes, err := suite.recoveryCodeRepo.FindUnusedRecoveryCodes(admin.ID)
+	suite.Nil(err)
+	suite.Len(codes, 3)
+
+	used, err := suite.recoveryCodeRepo.MarkRecoveryCodeUsed(codes[0].ID)
+	suite.Nil(err)
+	suite.True(used)
+
+	// a code can only be used once
+	used, err = suite.recoveryCodeRepo.MarkRecoveryCodeUsed(codes[0].ID)
+	suite.Nil(err)
+	suite.False(used)
+
+	count, err := suite.recoveryCodeRepo.CountUnusedRecoveryCodes(admin.ID)
+	suite.Nil(err)
+	suite.Equal(int64(2), count)
+
+	// regenerating drops the old codes, used or not
+	err = suite.recoveryCodeRepo.ReplaceRecoveryCodes(admin.ID, []string{"hash-4"})
+	suite.Nil(err)
+
+	codes, err = suite.recoveryCodeRepo.FindUnusedRecoveryCodes(admin.ID)
+	suite.Nil(err)
+	suite.Len(codes, 1)
+	suite.Equal("hash-4", codes[0].CodeHash)
+
+	err = suite.recoveryCodeRepo.ReplaceRecoveryCodes(admin.ID, nil)
+	suite.Nil(err)
+
+	count, err = suite.recoveryCodeRepo.CountUnusedRecoveryCodes(admin.ID)
+	suite.Nil(err)
+	suite.Equal(int64(0), count)
+}
+
+func TestRecoveryCodeRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(RecoveryCodeRepoTestSuite))
+}
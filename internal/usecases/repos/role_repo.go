@@ -2,12 +2,24 @@ package repos
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/minhmannh2001/authconnecthub/internal/entity"
 	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// roleAncestorsQuery selects the id of a role together with the ids of its parents, their parents and
+// so on. UNION leaves out the ids seen already, so it ends even if the parents should form a cycle.
+const roleAncestorsQuery = `WITH RECURSIVE ancestors(id) AS (
+	SELECT CAST(? AS bigint)
+	UNION
+	SELECT role_parents.parent_id FROM role_parents JOIN ancestors ON role_parents.role_id = ancestors.id
+) SELECT id FROM ancestors`
+
 type RoleRepo struct {
 	*postgres.Postgres
 }
@@ -31,3 +43,220 @@ func (r *RoleRepo) GetRoleIDByName(name string) (uint, error) {
 	// Handle other errors
 	return 0, err
 }
+
+// ListRoles returns the roles ordered by name, with their own permissions and their parents
+func (r *RoleRepo) ListRoles() ([]entity.Role, error) {
+	var roles []entity.Role
+	err := r.Conn.Preload("Permissions", orderByName).Preload("Parents", orderByName).Order("name").Find(&roles).Error
+	return roles, err
+}
+
+// FindRoleByName returns the role with its own permissions and its parents
+func (r *RoleRepo) FindRoleByName(name string) (*entity.Role, error) {
+	var role entity.Role
+	err := r.Conn.Preload("Permissions", orderByName).Preload("Parents", orderByName).Where("name = ?", name).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &entity.RoleNotFoundError{Name: name}
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+// FindRolesByNames returns the roles which exist among the names
+func (r *RoleRepo) FindRolesByNames(names []string) ([]entity.Role, error) {
+	var roles []entity.Role
+	if len(names) == 0 {
+		return roles, nil
+	}
+	err := r.Conn.Where("name IN ?", names).Order("name").Find(&roles).Error
+	return roles, err
+}
+
+// FindAncestorIDs returns the id of the role and the ids of the roles it inherits from
+func (r *RoleRepo) FindAncestorIDs(id uint) ([]uint, error) {
+	var ids []uint
+	err := r.Conn.Raw(roleAncestorsQuery, id).Scan(&ids).Error
+	return ids, err
+}
+
+// CreateRole creates the role with its permissions and parents, which have to exist already
+func (r *RoleRepo) CreateRole(role entity.Role) (entity.Role, error) {
+	err := r.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := checkRoleNameFree(tx, role.Name); err != nil {
+			return err
+		}
+		return tx.Omit("Permissions.*", "Parents.*").Create(&role).Error
+	})
+	if err != nil {
+		return entity.Role{}, err
+	}
+	return role, nil
+}
+
+// UpdateRole saves the role and replaces its permissions and parents. A role which was renamed keeps
+// the grants and the allowed roles of the applications which named it.
+func (r *RoleRepo) UpdateRole(role entity.Role, oldName string) (entity.Role, error) {
+	if role.ID == 0 {
+		return entity.Role{}, errors.New("missing role id")
+	}
+
+	err := r.Conn.Transaction(func(tx *gorm.DB) error {
+		if role.Name != oldName {
+			if err := checkRoleNameFree(tx, role.Name); err != nil {
+				return err
+			}
+			if err := renameRoleInApplications(tx, oldName, role.Name); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Omit(clause.Associations).Save(&role).Error; err != nil {
+			return err
+		}
+
+		// clearing an association empties it on the role too
+		permissions, parents := role.Permissions, role.Parents
+		if err := tx.Model(&role).Association("Permissions").Clear(); err != nil {
+			return fmt.Errorf("failed to clear permissions: %w", err)
+		}
+		if err := tx.Model(&role).Association("Parents").Clear(); err != nil {
+			return fmt.Errorf("failed to clear parents: %w", err)
+		}
+		if len(permissions) > 0 {
+			if err := tx.Model(&role).Association("Permissions").Append(permissions); err != nil {
+				return fmt.Errorf("failed to add permissions: %w", err)
+			}
+		}
+		if len(parents) > 0 {
+			if err := tx.Model(&role).Association("Parents").Append(parents); err != nil {
+				return fmt.Errorf("failed to add parents: %w", err)
+			}
+		}
+		role.Permissions, role.Parents = permissions, parents
+		return nil
+	})
+	if err != nil {
+		return entity.Role{}, err
+	}
+	return role, nil
+}
+
+// DeleteRole deletes a role nobody has anymore. The roles which inherit from it don't anymore.
+func (r *RoleRepo) DeleteRole(name string) error {
+	return r.Conn.Transaction(func(tx *gorm.DB) error {
+		var role entity.Role
+		if err := tx.Where("name = ?", name).First(&role).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &entity.RoleNotFoundError{Name: name}
+			}
+			return err
+		}
+
+		// deleted users keep their role, they can be restored
+		var users int64
+		if err := tx.Unscoped().Model(&entity.User{}).Where("role_id = ?", role.ID).Count(&users).Error; err != nil {
+			return err
+		}
+		var grants int64
+		err := tx.Model(&entity.ApplicationGrant{}).
+			Where("subject_type = ? AND subject = ?", entity.GrantSubjectRole, name).
+			Count(&grants).Error
+		if err != nil {
+			return err
+		}
+		applications, err := findApplicationsAllowingRole(tx, name)
+		if err != nil {
+			return err
+		}
+		if users > 0 || grants > 0 || len(applications) > 0 {
+			return &entity.RoleInUseError{Name: name}
+		}
+
+		if err := tx.Model(&role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM role_parents WHERE role_id = ? OR parent_id = ?", role.ID, role.ID).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&role).Error
+	})
+}
+
+func (r *RoleRepo) ListPermissions() ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	err := r.Conn.Order("name").Find(&permissions).Error
+	return permissions, err
+}
+
+// FindPermissionsByNames returns the permissions which exist among the names
+func (r *RoleRepo) FindPermissionsByNames(names []string) ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	err := r.Conn.Where("name IN ?", names).Order("name").Find(&permissions).Error
+	return permissions, err
+}
+
+// effectivePermissions returns the permissions of the role together with the ones it inherits from its parents
+func effectivePermissions(db *gorm.DB, roleID uint) ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	err := db.Where("id IN (SELECT permission_id FROM role_permissions WHERE role_id IN ("+roleAncestorsQuery+"))", roleID).
+		Order("name").
+		Find(&permissions).Error
+	return permissions, err
+}
+
+func orderByName(db *gorm.DB) *gorm.DB {
+	return db.Order("name")
+}
+
+func checkRoleNameFree(tx *gorm.DB, name string) error {
+	var count int64
+	if err := tx.Model(&entity.Role{}).Unscoped().Where("name = ?", name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return &entity.RoleConflictError{Name: name}
+	}
+	return nil
+}
+
+// findApplicationsAllowingRole returns the applications whose allowed roles list the role
+func findApplicationsAllowingRole(tx *gorm.DB, name string) ([]entity.Application, error) {
+	var applications []entity.Application
+	err := tx.Where("' ' || allowed_roles || ' ' LIKE ?", "% "+escapeLike(name)+" %").Find(&applications).Error
+	return applications, err
+}
+
+func renameRoleInApplications(tx *gorm.DB, oldName string, newName string) error {
+	err := tx.Model(&entity.ApplicationGrant{}).
+		Where("subject_type = ? AND subject = ?", entity.GrantSubjectRole, oldName).
+		Update("subject", newName).Error
+	if err != nil {
+		return fmt.Errorf("failed to rename role in application grants: %w", err)
+	}
+
+	applications, err := findApplicationsAllowingRole(tx, oldName)
+	if err != nil {
+		return err
+	}
+	for _, application := range applications {
+		var allowedRoles []string
+		for _, role := range strings.Fields(application.AllowedRoles) {
+			if role == oldName {
+				role = newName
+			}
+			if !slices.Contains(allowedRoles, role) {
+				allowedRoles = append(allowedRoles, role)
+			}
+		}
+		err := tx.Model(&application).Update("allowed_roles", strings.Join(allowedRoles, " ")).Error
+		if err != nil {
+			return fmt.Errorf("failed to rename role in allowed roles: %w", err)
+		}
+	}
+	return nil
+}
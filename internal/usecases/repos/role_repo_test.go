@@ -86,6 +86,74 @@ func (suite *RoleRepoTestSuite) TestGetRoleIDByName_NotFound() {
 	suite.Equal(&entity.RoleNotFoundError{Name: name}, err)
 }
 
+func (suite *RoleRepoTestSuite) TestListRoles() {
+	roles, err := suite.roleRepo.ListRoles()
+
+	suite.Nil(err)
+	var names []string
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	suite.Equal([]string{"admin", "anonymous", "customer"}, names)
+}
+
+func (suite *RoleRepoTestSuite) TestRoleHierarchy() {
+	customer, err := suite.roleRepo.FindRoleByName("customer")
+	suite.Nil(err)
+	permissions, err := suite.roleRepo.FindPermissionsByNames([]string{"users:read"})
+	suite.Nil(err)
+
+	support, err := suite.roleRepo.CreateRole(entity.Role{
+		Name:        "repo-support",
+		Description: "Helps the customers",
+		Parents:     []*entity.Role{customer},
+		Permissions: permissions,
+	})
+	suite.Nil(err)
+	suite.NotZero(support.ID)
+
+	_, err = suite.roleRepo.CreateRole(entity.Role{Name: "repo-support"})
+	suite.Equal(&entity.RoleConflictError{Name: "repo-support"}, err)
+
+	lead, err := suite.roleRepo.CreateRole(entity.Role{Name: "repo-lead", Parents: []*entity.Role{&support}})
+	suite.Nil(err)
+
+	ancestorIDs, err := suite.roleRepo.FindAncestorIDs(lead.ID)
+	suite.Nil(err)
+	suite.ElementsMatch([]uint{lead.ID, support.ID, customer.ID}, ancestorIDs)
+
+	// the parent is renamed, the child still inherits from it
+	support.Name = "repo-helpdesk"
+	support.Parents = nil
+	_, err = suite.roleRepo.UpdateRole(support, "repo-support")
+	suite.Nil(err)
+
+	found, err := suite.roleRepo.FindRoleByName("repo-lead")
+	suite.Nil(err)
+	suite.Len(found.Parents, 1)
+	suite.Equal("repo-helpdesk", found.Parents[0].Name)
+
+	ancestorIDs, err = suite.roleRepo.FindAncestorIDs(lead.ID)
+	suite.Nil(err)
+	suite.ElementsMatch([]uint{lead.ID, support.ID}, ancestorIDs)
+
+	suite.Nil(suite.roleRepo.DeleteRole("repo-helpdesk"))
+	found, err = suite.roleRepo.FindRoleByName("repo-lead")
+	suite.Nil(err)
+	suite.Empty(found.Parents)
+	suite.Nil(suite.roleRepo.DeleteRole("repo-lead"))
+
+	_, err = suite.roleRepo.FindRoleByName("repo-helpdesk")
+	suite.Equal(&entity.RoleNotFoundError{Name: "repo-helpdesk"}, err)
+}
+
+func (suite *RoleRepoTestSuite) TestDeleteRole_InUse() {
+	// the admin account has the role
+	err := suite.roleRepo.DeleteRole("admin")
+
+	suite.Equal(&entity.RoleInUseError{Name: "admin"}, err)
+}
+
 func TestRoleRepoTestSuite(t *testing.T) {
 	suite.Run(t, new(RoleRepoTestSuite))
 }
@@ -0,0 +1,71 @@
+package repos
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
+	"gorm.io/gorm"
+)
+
+type ServiceAccountRepo struct {
+	*postgres.Postgres
+}
+
+func NewServiceAccountRepo(pg *postgres.Postgres) *ServiceAccountRepo {
+	return &ServiceAccountRepo{pg}
+}
+
+func (r *ServiceAccountRepo) CreateServiceAccount(serviceAccount entity.ServiceAccount) (entity.ServiceAccount, error) {
+	if err := r.Conn.Create(&serviceAccount).Error; err != nil {
+		return entity.ServiceAccount{}, fmt.Errorf("failed to create service account: %w", err)
+	}
+
+	return serviceAccount, nil
+}
+
+func (r *ServiceAccountRepo) FindServiceAccounts() ([]entity.ServiceAccount, error) {
+	var serviceAccounts []entity.ServiceAccount
+	err := r.Conn.Order("created_at").Find(&serviceAccounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find service accounts: %w", err)
+	}
+
+	return serviceAccounts, nil
+}
+
+// FindServiceAccountByClientID returns nil when no service account has the client id
+func (r *ServiceAccountRepo) FindServiceAccountByClientID(clientID string) (*entity.ServiceAccount, error) {
+	var serviceAccount entity.ServiceAccount
+	err := r.Conn.Where("client_id = ?", clientID).First(&serviceAccount).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find service account: %w", err)
+	}
+
+	return &serviceAccount, nil
+}
+
+// UpdateServiceAccountUsage records that the service account got a token
+func (r *ServiceAccountRepo) UpdateServiceAccountUsage(id uint) error {
+	err := r.Conn.Model(&entity.ServiceAccount{}).Where("id = ?", id).Update("last_used_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to update service account: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteServiceAccount returns false when there was nothing to delete
+func (r *ServiceAccountRepo) DeleteServiceAccount(clientID string) (bool, error) {
+	result := r.Conn.Unscoped().Where("client_id = ?", clientID).Delete(&entity.ServiceAccount{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete service account: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,108 @@
+package repos_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
+	"github.com/minhmannh2001/authconnecthub/tests/testhelpers"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServiceAccountRepoTestSuite struct {
+	suite.Suite
+	pgContainer        *testhelpers.PostgresContainer
+	pg                 *postgres.Postgres
+	serviceAccountRepo *repos.ServiceAccountRepo
+	ctx                context.Context
+}
+
+func (suite *ServiceAccountRepoTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	pgContainer, err := testhelpers.CreatePostgresContainer(suite.ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pgContainer = pgContainer
+	host, err := pgContainer.ExtractHost(pgContainer.ConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	port, err := pgContainer.ExtractPort(pgContainer.ConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pg, err := postgres.New(&config.Config{
+		PG: config.PG{
+			Host:     host,
+			Port:     port,
+			Username: "postgres",
+			Password: "postgres",
+			Dbname:   "test-db",
+			Sslmode:  "disable",
+		},
+		Authen: config.Authen{
+			AdminUsername: "admin",
+			AdminPassword: "password",
+			AdminEmail:    "admin@localhost",
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pg = pg
+	suite.serviceAccountRepo = repos.NewServiceAccountRepo(pg)
+}
+
+func (suite *ServiceAccountRepoTestSuite) TearDownSuite() {
+	if err := suite.pgContainer.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating postgres container: %s", err)
+	}
+}
+
+func (suite *ServiceAccountRepoTestSuite) TestServiceAccounts_Lifecycle() {
+	created, err := suite.serviceAccountRepo.CreateServiceAccount(entity.ServiceAccount{
+		ClientID:   "svc_backup",
+		SecretHash: "hash",
+		Name:       "Backup",
+		Scopes:     "login-locks:read",
+	})
+	suite.Nil(err)
+	suite.NotZero(created.ID)
+
+	// client ids are unique
+	_, err = suite.serviceAccountRepo.CreateServiceAccount(entity.ServiceAccount{ClientID: "svc_backup", SecretHash: "hash", Name: "Copy"})
+	suite.NotNil(err)
+
+	serviceAccounts, err := suite.serviceAccountRepo.FindServiceAccounts()
+	suite.Nil(err)
+	suite.Len(serviceAccounts, 1)
+
+	err = suite.serviceAccountRepo.UpdateServiceAccountUsage(created.ID)
+	suite.Nil(err)
+
+	found, err := suite.serviceAccountRepo.FindServiceAccountByClientID("svc_backup")
+	suite.Nil(err)
+	suite.Equal("Backup", found.Name)
+	suite.NotNil(found.LastUsedAt)
+
+	deleted, err := suite.serviceAccountRepo.DeleteServiceAccount("svc_backup")
+	suite.Nil(err)
+	suite.True(deleted)
+
+	deleted, err = suite.serviceAccountRepo.DeleteServiceAccount("svc_backup")
+	suite.Nil(err)
+	suite.False(deleted)
+
+	found, err = suite.serviceAccountRepo.FindServiceAccountByClientID("svc_backup")
+	suite.Nil(err)
+	suite.Nil(found)
+}
+
+func TestServiceAccountRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(ServiceAccountRepoTestSuite))
+}
@@ -2,13 +2,21 @@ package repos
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/minhmannh2001/authconnecthub/internal/entity"
 	"github.com/minhmannh2001/authconnecthub/internal/helper"
 	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// userSortColumns are the columns users can be sorted by
+var userSortColumns = []string{"username", "email", "created_at"}
+
 type UserRepo struct {
 	*postgres.Postgres
 }
@@ -18,9 +26,9 @@ func NewUserRepo(pg *postgres.Postgres) *UserRepo {
 }
 
 func (r *UserRepo) Create(u entity.User) (entity.User, error) {
-	// Check for existing user with same username or email
+	// Check for existing user with same username or email, the deleted users keep theirs until they are restored
 	var existingUser entity.User
-	err := r.Conn.Where("username = ?", u.Username).Or("email = ?", u.Email).First(&existingUser).Error
+	err := r.Conn.Unscoped().Where("username = ?", u.Username).Or("email = ?", u.Email).First(&existingUser).Error
 	if err == nil { // User already exists
 		return entity.User{}, &entity.ErrDuplicateUser{Username: u.Username, Email: u.Email}
 	}
@@ -33,16 +41,113 @@ func (r *UserRepo) Create(u entity.User) (entity.User, error) {
 	return u, nil
 }
 
-func (r *UserRepo) RetrieveByID(id uint) (entity.User, error) { // coverage-ignore
-	return entity.User{}, nil
+// RetrieveByID finds the user with its role, deleted users too
+func (r *UserRepo) RetrieveByID(id uint) (entity.User, error) {
+	var user entity.User
+	err := r.Conn.Unscoped().Preload("Role").First(&user, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.User{}, &entity.UserNotFoundError{}
+		}
+		return entity.User{}, err
+	}
+
+	return user, nil
 }
 
-func (r *UserRepo) Update(u entity.User) (entity.User, error) { // coverage-ignore
-	return entity.User{}, nil
+// FindUsers returns a page of the users the query selects, with their roles
+func (r *UserRepo) FindUsers(query entity.UserQuery) ([]entity.User, error) {
+	column, descending := strings.CutPrefix(query.Sort, "-")
+	if !slices.Contains(userSortColumns, column) {
+		return nil, fmt.Errorf("unsupported sort column %q", column)
+	}
+	direction, comparison := "ASC", ">"
+	if descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	db := r.Conn.Preload("Role")
+	switch query.Status {
+	case entity.UserStatusActive:
+		db = db.Where("disabled = ?", false)
+	case entity.UserStatusDisabled:
+		db = db.Where("disabled = ?", true)
+	case entity.UserStatusDeleted:
+		db = db.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+
+	if query.Search != "" {
+		pattern := "%" + escapeLike(query.Search) + "%"
+		db = db.Where("(username ILIKE ? OR email ILIKE ?)", pattern, pattern)
+	}
+	if query.Role != "" {
+		db = db.Where("role_id IN (?)", r.Conn.Model(&entity.Role{}).Select("id").Where("name = ?", query.Role))
+	}
+
+	if query.After != nil {
+		var value interface{} = query.After.Value
+		if column == "created_at" {
+			createdAt, err := time.Parse(time.RFC3339Nano, query.After.Value)
+			if err != nil {
+				return nil, &entity.InvalidCursorError{}
+			}
+			value = createdAt
+		}
+		db = db.Where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, comparison), value, value, query.After.ID)
+	}
+
+	var users []entity.User
+	err := db.Order(fmt.Sprintf("%s %s, id %s", column, direction, direction)).Limit(query.Limit).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
 }
 
-func (r *UserRepo) Delete(u entity.User) (entity.User, error) { // coverage-ignore
-	return entity.User{}, nil
+// escapeLike keeps the wildcards of a search from matching anything
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (r *UserRepo) Update(u entity.User) (entity.User, error) {
+	if u.ID == 0 {
+		return entity.User{}, errors.New("missing user id")
+	}
+
+	// the role is changed through the role id, a loaded role mustn't overwrite it
+	result := r.Conn.Omit(clause.Associations).Save(&u)
+	if err := result.Error; err != nil {
+		return entity.User{}, err
+	}
+
+	return u, nil
+}
+
+// Delete soft deletes the user, it can be restored
+func (r *UserRepo) Delete(u entity.User) (entity.User, error) {
+	result := r.Conn.Delete(&u)
+	if err := result.Error; err != nil {
+		return entity.User{}, err
+	}
+	if result.RowsAffected == 0 {
+		return entity.User{}, &entity.UserNotFoundError{}
+	}
+
+	return u, nil
+}
+
+// Restore brings a deleted user back
+func (r *UserRepo) Restore(id uint) (entity.User, error) {
+	result := r.Conn.Unscoped().Model(&entity.User{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	if err := result.Error; err != nil {
+		return entity.User{}, err
+	}
+	if result.RowsAffected == 0 {
+		return entity.User{}, &entity.UserNotFoundError{}
+	}
+
+	return r.RetrieveByID(id)
 }
 
 func (r *UserRepo) FindByUsernameOrEmail(username, email string) (*entity.User, error) {
@@ -52,12 +157,18 @@ func (r *UserRepo) FindByUsernameOrEmail(username, email string) (*entity.User,
 		email = helper.RandStringBytes(24)
 	}
 
-	err := r.Conn.Where("username = ? OR email = ?", username, email).First(&user).Error
+	err := r.Conn.Preload("Role").Where("username = ? OR email = ?", username, email).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, &entity.InvalidCredentialsError{} // User not found
 		}
 		return nil, err
 	}
+
+	// the user gets the permissions the role inherits from its parents too
+	user.Role.Permissions, err = effectivePermissions(r.Conn, user.RoleID)
+	if err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/minhmannh2001/authconnecthub/config"
 	"github.com/minhmannh2001/authconnecthub/internal/entity"
@@ -94,6 +95,14 @@ func (suite *UserRepoTestSuite) TestFindByUsernameOrEmail_ByUsernameSuccess() {
 	suite.Nil(err)
 	suite.Equal("admin", user.Username)
 	suite.Equal("admin@localhost", user.Email)
+	suite.Equal("admin", user.Role.Name)
+	// the admin role gets every default permission
+	var permissions []string
+	for _, permission := range user.Role.Permissions {
+		permissions = append(permissions, permission.Name)
+	}
+	suite.Contains(permissions, "application-grants:write")
+	suite.Contains(permissions, "login-locks:read")
 }
 
 func (suite *UserRepoTestSuite) TestFindByUsernameOrEmail_ByEmailSuccess() {
@@ -111,6 +120,96 @@ func (suite *UserRepoTestSuite) TestFindByUsernameOrEmail_NotFound() {
 	suite.Equal(&entity.InvalidCredentialsError{}, err)
 }
 
+func (suite *UserRepoTestSuite) TestUpdate_Success() {
+	user, err := suite.userRepo.FindByUsernameOrEmail("admin", "")
+	suite.Nil(err)
+
+	user.Password = "new-password-hash"
+	updatedUser, err := suite.userRepo.Update(*user)
+	suite.Nil(err)
+	suite.Equal("new-password-hash", updatedUser.Password)
+
+	user, err = suite.userRepo.FindByUsernameOrEmail("admin", "")
+	suite.Nil(err)
+	suite.Equal("new-password-hash", user.Password)
+}
+
+func (suite *UserRepoTestSuite) TestUpdate_MissingID() {
+	updatedUser, err := suite.userRepo.Update(entity.User{Username: "admin"})
+	suite.Equal(entity.User{}, updatedUser)
+	suite.EqualError(err, "missing user id")
+}
+
+func (suite *UserRepoTestSuite) TestFindUsers_Pages() {
+	for _, username := range []string{"pager-carl", "pager-anna", "pager-bob"} {
+		_, err := suite.userRepo.Create(entity.User{Username: username, Email: username + "@example.com", Disabled: username == "pager-bob"})
+		suite.Nil(err)
+	}
+
+	users, err := suite.userRepo.FindUsers(entity.UserQuery{Search: "PAGER-", Sort: "username", Limit: 2})
+	suite.Nil(err)
+	suite.Equal([]string{"pager-anna", "pager-bob"}, []string{users[0].Username, users[1].Username})
+	suite.NotZero(users[0].Role.ID)
+
+	users, err = suite.userRepo.FindUsers(entity.UserQuery{Search: "pager-", Sort: "username", Limit: 2, After: &entity.UserCursor{Value: users[1].Username, ID: users[1].ID}})
+	suite.Nil(err)
+	suite.Len(users, 1)
+	suite.Equal("pager-carl", users[0].Username)
+
+	users, err = suite.userRepo.FindUsers(entity.UserQuery{Search: "pager-", Status: entity.UserStatusDisabled, Sort: "-created_at", Limit: 10})
+	suite.Nil(err)
+	suite.Len(users, 1)
+	suite.Equal("pager-bob", users[0].Username)
+
+	after := &entity.UserCursor{Value: users[0].CreatedAt.Format(time.RFC3339Nano), ID: users[0].ID}
+	users, err = suite.userRepo.FindUsers(entity.UserQuery{Search: "pager-", Sort: "-created_at", Limit: 10, After: after})
+	suite.Nil(err)
+	suite.Len(users, 2)
+	suite.Equal([]string{"pager-anna", "pager-carl"}, []string{users[0].Username, users[1].Username})
+
+	// the wildcards of the search are taken literally
+	users, err = suite.userRepo.FindUsers(entity.UserQuery{Search: "pager%", Sort: "username", Limit: 10})
+	suite.Nil(err)
+	suite.Empty(users)
+}
+
+func (suite *UserRepoTestSuite) TestDeleteAndRestore() {
+	created, err := suite.userRepo.Create(entity.User{Username: "leaving", Email: "leaving@example.com"})
+	suite.Nil(err)
+
+	deleted, err := suite.userRepo.Delete(created)
+	suite.Nil(err)
+	suite.True(deleted.DeletedAt.Valid)
+
+	_, err = suite.userRepo.FindByUsernameOrEmail("leaving", "")
+	suite.Equal(&entity.InvalidCredentialsError{}, err)
+	// the username stays taken until the user is restored
+	_, err = suite.userRepo.Create(entity.User{Username: "leaving", Email: "other@example.com"})
+	suite.Equal(&entity.ErrDuplicateUser{Username: "leaving", Email: "other@example.com"}, err)
+
+	users, err := suite.userRepo.FindUsers(entity.UserQuery{Search: "leaving", Status: entity.UserStatusDeleted, Sort: "username", Limit: 10})
+	suite.Nil(err)
+	suite.Len(users, 1)
+
+	retrieved, err := suite.userRepo.RetrieveByID(created.ID)
+	suite.Nil(err)
+	suite.True(retrieved.DeletedAt.Valid)
+
+	restored, err := suite.userRepo.Restore(created.ID)
+	suite.Nil(err)
+	suite.False(restored.DeletedAt.Valid)
+
+	_, err = suite.userRepo.Restore(created.ID)
+	suite.Equal(&entity.UserNotFoundError{}, err)
+	_, err = suite.userRepo.FindByUsernameOrEmail("leaving", "")
+	suite.Nil(err)
+}
+
+func (suite *UserRepoTestSuite) TestRetrieveByID_NotFound() {
+	_, err := suite.userRepo.RetrieveByID(999999)
+	suite.Equal(&entity.UserNotFoundError{}, err)
+}
+
 func TestUserRepoTestSuite(t *testing.T) {
 	suite.Run(t, new(UserRepoTestSuite))
 }
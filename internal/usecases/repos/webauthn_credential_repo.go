@@ -0,0 +1,77 @@
+package repos
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
+	"gorm.io/gorm"
+)
+
+type WebAuthnCredentialRepo struct {
+	*postgres.Postgres
+}
+
+func NewWebAuthnCredentialRepo(pg *postgres.Postgres) *WebAuthnCredentialRepo {
+	return &WebAuthnCredentialRepo{pg}
+}
+
+func (r *WebAuthnCredentialRepo) CreateWebAuthnCredential(credential entity.WebAuthnCredential) (entity.WebAuthnCredential, error) {
+	if err := r.Conn.Create(&credential).Error; err != nil {
+		return entity.WebAuthnCredential{}, fmt.Errorf("failed to create webauthn credential: %w", err)
+	}
+
+	return credential, nil
+}
+
+func (r *WebAuthnCredentialRepo) FindWebAuthnCredentials(userID uint) ([]entity.WebAuthnCredential, error) {
+	var credentials []entity.WebAuthnCredential
+	err := r.Conn.Where("user_id = ?", userID).Order("created_at").Find(&credentials).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webauthn credentials: %w", err)
+	}
+
+	return credentials, nil
+}
+
+// FindWebAuthnCredentialByCredentialID returns the credential together with its user.
+// A nil credential means no passkey with this id was registered.
+func (r *WebAuthnCredentialRepo) FindWebAuthnCredentialByCredentialID(credentialID []byte) (*entity.WebAuthnCredential, error) {
+	var credential entity.WebAuthnCredential
+	err := r.Conn.Preload("User").Where("credential_id = ?", credentialID).First(&credential).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find webauthn credential: %w", err)
+	}
+
+	return &credential, nil
+}
+
+// UpdateWebAuthnCredentialUsage records a successful sign in with the credential
+func (r *WebAuthnCredentialRepo) UpdateWebAuthnCredentialUsage(id uint, signCount uint32, backupState bool) error {
+	err := r.Conn.Model(&entity.WebAuthnCredential{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"sign_count":   signCount,
+		"backup_state": backupState,
+		"last_used_at": time.Now(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update webauthn credential: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteWebAuthnCredential removes the credential if it belongs to the user.
+// It returns false when there was nothing to delete.
+func (r *WebAuthnCredentialRepo) DeleteWebAuthnCredential(userID uint, id uint) (bool, error) {
+	result := r.Conn.Unscoped().Where("id = ? AND user_id = ?", id, userID).Delete(&entity.WebAuthnCredential{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete webauthn credential: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,111 @@
+package repos_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+	"github.com/minhmannh2001/authconnecthub/pkg/postgres"
+	"github.com/minhmannh2001/authconnecthub/tests/testhelpers"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebAuthnCredentialRepoTestSuite struct {
+	suite.Suite
+	pgContainer            *testhelpers.PostgresContainer
+	pg                     *postgres.Postgres
+	webAuthnCredentialRepo *repos.WebAuthnCredentialRepo
+	ctx                    context.Context
+}
+
+func (suite *WebAuthnCredentialRepoTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	pgContainer, err := testhelpers.CreatePostgresContainer(suite.ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pgContainer = pgContainer
+	host, err := pgContainer.ExtractHost(pgContainer.ConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	port, err := pgContainer.ExtractPort(pgContainer.ConnectionString)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pg, err := postgres.New(&config.Config{
+		PG: config.PG{
+			Host:     host,
+			Port:     port,
+			Username: "postgres",
+			Password: "postgres",
+			Dbname:   "test-db",
+			Sslmode:  "disable",
+		},
+		Authen: config.Authen{
+			AdminUsername: "admin",
+			AdminPassword: "password",
+			AdminEmail:    "admin@localhost",
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pg = pg
+	suite.webAuthnCredentialRepo = repos.NewWebAuthnCredentialRepo(pg)
+}
+
+func (suite *WebAuthnCredentialRepoTestSuite) TearDownSuite() {
+	if err := suite.pgContainer.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating postgres container: %s", err)
+	}
+}
+
+func (suite *WebAuthnCredentialRepoTestSuite) TestWebAuthnCredentials_Lifecycle() {
+	var admin entity.User
+	err := suite.pg.Conn.Where("username = ?", "admin").First(&admin).Error
+	suite.Nil(err)
+
+	created, err := suite.webAuthnCredentialRepo.CreateWebAuthnCredential(entity.WebAuthnCredential{
+		UserID:       admin.ID,
+		Name:         "Laptop",
+		CredentialID: []byte("credential-id"),
+		PublicKey:    []byte("public-key"),
+	})
+	suite.Nil(err)
+	suite.NotZero(created.ID)
+
+	credentials, err := suite.webAuthnCredentialRepo.FindWebAuthnCredentials(admin.ID)
+	suite.Nil(err)
+	suite.Len(credentials, 1)
+
+	err = suite.webAuthnCredentialRepo.UpdateWebAuthnCredentialUsage(created.ID, 7, true)
+	suite.Nil(err)
+
+	found, err := suite.webAuthnCredentialRepo.FindWebAuthnCredentialByCredentialID([]byte("credential-id"))
+	suite.Nil(err)
+	suite.Equal("admin", found.User.Username)
+	suite.Equal(uint32(7), found.SignCount)
+	suite.True(found.BackupState)
+	suite.NotNil(found.LastUsedAt)
+
+	// only the owner can delete a passkey
+	deleted, err := suite.webAuthnCredentialRepo.DeleteWebAuthnCredential(admin.ID+1, created.ID)
+	suite.Nil(err)
+	suite.False(deleted)
+
+	deleted, err = suite.webAuthnCredentialRepo.DeleteWebAuthnCredential(admin.ID, created.ID)
+	suite.Nil(err)
+	suite.True(deleted)
+
+	found, err = suite.webAuthnCredentialRepo.FindWebAuthnCredentialByCredentialID([]byte("credential-id"))
+	suite.Nil(err)
+	suite.Nil(found)
+}
+
+func TestWebAuthnCredentialRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(WebAuthnCredentialRepoTestSuite))
+}
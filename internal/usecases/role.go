@@ -1,6 +1,19 @@
 package usecases
 
-import "github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+import (
+	"regexp"
+	"slices"
+
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+)
+
+// adminRoleName is the system role which has every permission
+const adminRoleName = "admin"
+
+// roleNamePattern keeps spaces out of role names, the allowed roles of applications are space separated
+var roleNamePattern = regexp.MustCompile(`^[a-z0-9._-]+$`)
 
 type RoleUseCase struct {
 	roleRepo repos.IRoleRepo
@@ -13,3 +26,242 @@ func NewRoleUseCase(rr repos.IRoleRepo) *RoleUseCase {
 func (uc *RoleUseCase) GetRoleIDByName(name string) (uint, error) {
 	return uc.roleRepo.GetRoleIDByName(name)
 }
+
+// ListRoles returns the roles ordered by name, with the permissions they inherit from their parents
+func (uc *RoleUseCase) ListRoles() ([]dto.AdminRole, error) {
+	roles, err := uc.roleRepo.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	rolesByID := make(map[uint]*entity.Role, len(roles))
+	for i := range roles {
+		rolesByID[roles[i].ID] = &roles[i]
+	}
+
+	adminRoles := make([]dto.AdminRole, 0, len(roles))
+	for _, role := range roles {
+		adminRoles = append(adminRoles, adminRole(role, rolesByID))
+	}
+	return adminRoles, nil
+}
+
+func (uc *RoleUseCase) ListPermissions() ([]dto.AdminPermission, error) {
+	permissions, err := uc.roleRepo.ListPermissions()
+	if err != nil {
+		return nil, err
+	}
+
+	adminPermissions := make([]dto.AdminPermission, 0, len(permissions))
+	for _, permission := range permissions {
+		adminPermissions = append(adminPermissions, dto.AdminPermission{Name: permission.Name, Description: permission.Description})
+	}
+	return adminPermissions, nil
+}
+
+// CreateRole creates a role which inherits the permissions of its parents
+func (uc *RoleUseCase) CreateRole(req dto.RoleCreateRequestBody) (*dto.AdminRole, error) {
+	if !roleNamePattern.MatchString(req.Name) {
+		return nil, &entity.InvalidRoleNameError{Name: req.Name}
+	}
+
+	parents, err := uc.findParents(req.Parents)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := uc.findPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := uc.roleRepo.CreateRole(entity.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Parents:     parents,
+		Permissions: permissions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.findAdminRole(role.Name)
+}
+
+// UpdateRole replaces the description, the parents and the permissions of a role and renames it when a
+// new name is given. System roles keep their names and the admin role keeps every permission.
+func (uc *RoleUseCase) UpdateRole(req dto.RoleUpdateRequestBody) (*dto.AdminRole, error) {
+	role, err := uc.roleRepo.FindRoleByName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	oldName := role.Name
+	if req.NewName != "" && req.NewName != role.Name {
+		if role.System {
+			return nil, &entity.SystemRoleError{Name: role.Name}
+		}
+		if !roleNamePattern.MatchString(req.NewName) {
+			return nil, &entity.InvalidRoleNameError{Name: req.NewName}
+		}
+		role.Name = req.NewName
+	}
+
+	parents, err := uc.findParents(req.Parents)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := uc.findPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldName == adminRoleName && (len(parents) > 0 || !samePermissions(role.Permissions, permissions)) {
+		return nil, &entity.SystemRoleError{Name: oldName}
+	}
+
+	// a parent which inherits from the role, or is the role, would make a cycle
+	for _, parent := range parents {
+		ancestorIDs, err := uc.roleRepo.FindAncestorIDs(parent.ID)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(ancestorIDs, role.ID) {
+			return nil, &entity.RoleCycleError{Name: oldName}
+		}
+	}
+
+	role.Description = req.Description
+	role.Parents = parents
+	role.Permissions = permissions
+
+	updated, err := uc.roleRepo.UpdateRole(*role, oldName)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.findAdminRole(updated.Name)
+}
+
+// DeleteRole deletes a role which isn't a system role and which nobody has anymore
+func (uc *RoleUseCase) DeleteRole(name string) error {
+	role, err := uc.roleRepo.FindRoleByName(name)
+	if err != nil {
+		return err
+	}
+	if role.System {
+		return &entity.SystemRoleError{Name: name}
+	}
+
+	return uc.roleRepo.DeleteRole(name)
+}
+
+func (uc *RoleUseCase) findParents(names []string) ([]*entity.Role, error) {
+	names = uniqueNames(names)
+	roles, err := uc.roleRepo.FindRolesByNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := make([]*entity.Role, 0, len(roles))
+	for _, name := range names {
+		i := slices.IndexFunc(roles, func(role entity.Role) bool { return role.Name == name })
+		if i < 0 {
+			return nil, &entity.RoleNotFoundError{Name: name}
+		}
+		parents = append(parents, &roles[i])
+	}
+	return parents, nil
+}
+
+func (uc *RoleUseCase) findPermissions(names []string) ([]entity.Permission, error) {
+	names = uniqueNames(names)
+	permissions, err := uc.roleRepo.FindPermissionsByNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if !slices.ContainsFunc(permissions, func(permission entity.Permission) bool { return permission.Name == name }) {
+			return nil, &entity.PermissionNotFoundError{Name: name}
+		}
+	}
+	return permissions, nil
+}
+
+func (uc *RoleUseCase) findAdminRole(name string) (*dto.AdminRole, error) {
+	roles, err := uc.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(roles, func(role dto.AdminRole) bool { return role.Name == name })
+	if i < 0 {
+		return nil, &entity.RoleNotFoundError{Name: name}
+	}
+	return &roles[i], nil
+}
+
+func adminRole(role entity.Role, rolesByID map[uint]*entity.Role) dto.AdminRole {
+	parents := make([]string, 0, len(role.Parents))
+	for _, parent := range role.Parents {
+		parents = append(parents, parent.Name)
+	}
+
+	permissions := permissionNames(role.Permissions)
+
+	// the parents are walked breadth first, the ones seen already are skipped in case of a cycle
+	inherited := []string{}
+	seen := map[uint]bool{role.ID: true}
+	queue := slices.Clone(role.Parents)
+	for len(queue) > 0 {
+		parent := rolesByID[queue[0].ID]
+		queue = queue[1:]
+		if parent == nil || seen[parent.ID] {
+			continue
+		}
+		seen[parent.ID] = true
+
+		for _, name := range permissionNames(parent.Permissions) {
+			if !slices.Contains(permissions, name) && !slices.Contains(inherited, name) {
+				inherited = append(inherited, name)
+			}
+		}
+		queue = append(queue, parent.Parents...)
+	}
+	slices.Sort(inherited)
+
+	return dto.AdminRole{
+		Name:                 role.Name,
+		Description:          role.Description,
+		System:               role.System,
+		PermissionsFixed:     role.Name == adminRoleName,
+		Parents:              parents,
+		Permissions:          permissions,
+		InheritedPermissions: inherited,
+	}
+}
+
+func permissionNames(permissions []entity.Permission) []string {
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		names = append(names, permission.Name)
+	}
+	return names
+}
+
+func samePermissions(a []entity.Permission, b []entity.Permission) bool {
+	namesA, namesB := permissionNames(a), permissionNames(b)
+	slices.Sort(namesA)
+	slices.Sort(namesB)
+	return slices.Equal(namesA, namesB)
+}
+
+func uniqueNames(names []string) []string {
+	var unique []string
+	for _, name := range names {
+		if name != "" && !slices.Contains(unique, name) {
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
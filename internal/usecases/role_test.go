@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
 	"github.com/minhmannh2001/authconnecthub/internal/entity"
 	"github.com/minhmannh2001/authconnecthub/internal/usecases"
 	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
@@ -70,3 +71,184 @@ func TestGetRoleIDByName_DatabaseError(t *testing.T) {
 
 	mockRoleRepo.AssertExpectations(t)
 }
+
+func TestListRoles(t *testing.T) {
+	mockRoleRepo := mocks.NewIRoleRepo(t)
+
+	admin := entity.Role{ID: 1, Name: "admin", System: true, Permissions: []entity.Permission{{Name: "users:read"}}}
+	customer := entity.Role{ID: 3, Name: "customer", System: true, Permissions: []entity.Permission{{Name: "apps:read"}}}
+	support := entity.Role{ID: 4, Name: "support", Parents: []*entity.Role{{ID: 3, Name: "customer"}}, Permissions: []entity.Permission{{Name: "users:read"}}}
+	mockRoleRepo.On("ListRoles").Return([]entity.Role{admin, customer, support}, nil)
+
+	uc := usecases.NewRoleUseCase(mockRoleRepo)
+
+	roles, err := uc.ListRoles()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []dto.AdminRole{
+		{Name: "admin", System: true, PermissionsFixed: true, Parents: []string{}, Permissions: []string{"users:read"}, InheritedPermissions: []string{}},
+		{Name: "customer", System: true, Parents: []string{}, Permissions: []string{"apps:read"}, InheritedPermissions: []string{}},
+		{Name: "support", Parents: []string{"customer"}, Permissions: []string{"users:read"}, InheritedPermissions: []string{"apps:read"}},
+	}, roles)
+}
+
+func TestCreateRole_InvalidName(t *testing.T) {
+	mockRoleRepo := mocks.NewIRoleRepo(t)
+	uc := usecases.NewRoleUseCase(mockRoleRepo)
+
+	role, err := uc.CreateRole(dto.RoleCreateRequestBody{Name: "help desk"})
+
+	assert.Nil(t, role)
+	assert.Equal(t, &entity.InvalidRoleNameError{Name: "help desk"}, err)
+}
+
+func TestCreateRole_PermissionNotFound(t *testing.T) {
+	mockRoleRepo := mocks.NewIRoleRepo(t)
+
+	mockRoleRepo.On("FindRolesByNames", []string(nil)).Return([]entity.Role{}, nil)
+	mockRoleRepo.On("FindPermissionsByNames", []string{"users:read", "users:fly"}).
+		Return([]entity.Permission{{Name: "users:read"}}, nil)
+
+	uc := usecases.NewRoleUseCase(mockRoleRepo)
+
+	role, err := uc.CreateRole(dto.RoleCreateRequestBody{Name: "support", Permissions: []string{"users:read", "users:fly"}})
+
+	assert.Nil(t, role)
+	assert.Equal(t, &entity.PermissionNotFoundError{Name: "users:fly"}, err)
+}
+
+func TestCreateRole_Success(t *testing.T) {
+	mockRoleRepo := mocks.NewIRoleRepo(t)
+
+	customer := entity.Role{ID: 3, Name: "customer", Permissions: []entity.Permission{{Name: "apps:read"}}}
+	readUsers := entity.Permission{Name: "users:read"}
+	mockRoleRepo.On("FindRolesByNames", []string{"customer"}).Return([]entity.Role{customer}, nil)
+	mockRoleRepo.On("FindPermissionsByNames", []string{"users:read"}).Return([]entity.Permission{readUsers}, nil)
+	mockRoleRepo.On("CreateRole", entity.Role{
+		Name:        "support",
+		Description: "Helps the customers",
+		Parents:     []*entity.Role{&customer},
+		Permissions: []entity.Permission{readUsers},
+	}).Return(entity.Role{ID: 4, Name: "support"}, nil)
+	mockRoleRepo.On("ListRoles").Return([]entity.Role{
+		customer,
+		{ID: 4, Name: "support", Description: "Helps the customers", Parents: []*entity.Role{&customer}, Permissions: []entity.Permission{readUsers}},
+	}, nil)
+
+	uc := usecases.NewRoleUseCase(mockRoleRepo)
+
+	role, err := uc.CreateRole(dto.RoleCreateRequestBody{
+		Name:        "support",
+		Description: "Helps the customers",
+		Parents:     []string{"customer", "customer"},
+		Permissions: []string{"users:read"},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, &dto.AdminRole{
+		Name:                 "support",
+		Description:          "Helps the customers",
+		Parents:              []string{"customer"},
+		Permissions:          []string{"users:read"},
+		InheritedPermissions: []string{"apps:read"},
+	}, role)
+}
+
+func TestUpdateRole_RenameSystemRole(t *testing.T) {
+	mockRoleRepo := mocks.NewIRoleRepo(t)
+
+	mockRoleRepo.On("FindRoleByName", "customer").Return(&entity.Role{ID: 3, Name: "customer", System: true}, nil)
+
+	uc := usecases.NewRoleUseCase(mockRoleRepo)
+
+	role, err := uc.UpdateRole(dto.RoleUpdateRequestBody{Name: "customer", NewName: "client"})
+
+	assert.Nil(t, role)
+	assert.Equal(t, &entity.SystemRoleError{Name: "customer"}, err)
+}
+
+func TestUpdateRole_AdminPermissionsFixed(t *testing.T) {
+	mockRoleRepo := mocks.NewIRoleRepo(t)
+
+	mockRoleRepo.On("FindRoleByName", "admin").Return(&entity.Role{
+		ID:          1,
+		Name:        "admin",
+		System:      true,
+		Permissions: []entity.Permission{{Name: "users:read"}, {Name: "users:write"}},
+	}, nil)
+	mockRoleRepo.On("FindRolesByNames", []string(nil)).Return([]entity.Role{}, nil)
+	mockRoleRepo.On("FindPermissionsByNames", []string{"users:read"}).Return([]entity.Permission{{Name: "users:read"}}, nil)
+
+	uc := usecases.NewRoleUseCase(mockRoleRepo)
+
+	role, err := uc.UpdateRole(dto.RoleUpdateRequestBody{Name: "admin", Permissions: []string{"users:read"}})
+
+	assert.Nil(t, role)
+	assert.Equal(t, &entity.SystemRoleError{Name: "admin"}, err)
+}
+
+func TestUpdateRole_Cycle(t *testing.T) {
+	mockRoleRepo := mocks.NewIRoleRepo(t)
+
+	mockRoleRepo.On("FindRoleByName", "support").Return(&entity.Role{ID: 4, Name: "support"}, nil)
+	mockRoleRepo.On("FindRolesByNames", []string{"lead"}).Return([]entity.Role{{ID: 5, Name: "lead"}}, nil)
+	mockRoleRepo.On("FindPermissionsByNames", []string(nil)).Return([]entity.Permission{}, nil)
+	// lead inherits from support already
+	mockRoleRepo.On("FindAncestorIDs", uint(5)).Return([]uint{5, 4, 3}, nil)
+
+	uc := usecases.NewRoleUseCase(mockRoleRepo)
+
+	role, err := uc.UpdateRole(dto.RoleUpdateRequestBody{Name: "support", Parents: []string{"lead"}})
+
+	assert.Nil(t, role)
+	assert.Equal(t, &entity.RoleCycleError{Name: "support"}, err)
+}
+
+func TestUpdateRole_Rename(t *testing.T) {
+	mockRoleRepo := mocks.NewIRoleRepo(t)
+
+	mockRoleRepo.On("FindRoleByName", "support").Return(&entity.Role{ID: 4, Name: "support"}, nil)
+	mockRoleRepo.On("FindRolesByNames", []string(nil)).Return([]entity.Role{}, nil)
+	mockRoleRepo.On("FindPermissionsByNames", []string(nil)).Return([]entity.Permission{}, nil)
+	mockRoleRepo.On("UpdateRole", entity.Role{
+		ID:          4,
+		Name:        "helpdesk",
+		Description: "Answers the tickets",
+		Parents:     []*entity.Role{},
+		Permissions: []entity.Permission{},
+	}, "support").Return(entity.Role{ID: 4, Name: "helpdesk"}, nil)
+	mockRoleRepo.On("ListRoles").Return([]entity.Role{{ID: 4, Name: "helpdesk", Description: "Answers the tickets"}}, nil)
+
+	uc := usecases.NewRoleUseCase(mockRoleRepo)
+
+	role, err := uc.UpdateRole(dto.RoleUpdateRequestBody{Name: "support", NewName: "helpdesk", Description: "Answers the tickets"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "helpdesk", role.Name)
+	assert.Equal(t, "Answers the tickets", role.Description)
+}
+
+func TestDeleteRole_SystemRole(t *testing.T) {
+	mockRoleRepo := mocks.NewIRoleRepo(t)
+
+	mockRoleRepo.On("FindRoleByName", "anonymous").Return(&entity.Role{ID: 2, Name: "anonymous", System: true}, nil)
+
+	uc := usecases.NewRoleUseCase(mockRoleRepo)
+
+	err := uc.DeleteRole("anonymous")
+
+	assert.Equal(t, &entity.SystemRoleError{Name: "anonymous"}, err)
+}
+
+func TestDeleteRole_InUse(t *testing.T) {
+	mockRoleRepo := mocks.NewIRoleRepo(t)
+
+	mockRoleRepo.On("FindRoleByName", "support").Return(&entity.Role{ID: 4, Name: "support"}, nil)
+	mockRoleRepo.On("DeleteRole", "support").Return(&entity.RoleInUseError{Name: "support"})
+
+	uc := usecases.NewRoleUseCase(mockRoleRepo)
+
+	err := uc.DeleteRole("support")
+
+	assert.Equal(t, &entity.RoleInUseError{Name: "support"}, err)
+}
@@ -0,0 +1,206 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// serviceAudience is the audience of the tokens of service accounts. ValidateToken rejects
+// it, so a service account can't pass for a user with the same name.
+const serviceAudience = "services"
+
+var scopePattern = regexp.MustCompile(`^[a-z0-9:._-]+$`)
+
+// CreateServiceAccount registers a service account with a random client id and secret.
+// The secret is returned once, only its hash is kept.
+func (au *AuthUseCase) CreateServiceAccount(req dto.ServiceAccountCreateRequestBody) (*dto.ServiceAccountCredentials, error) {
+	var scopes []string
+	for _, scope := range strings.Fields(req.Scopes) {
+		if !scopePattern.MatchString(scope) {
+			return nil, &entity.InvalidScopeError{Scope: scope}
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	clientID, err := generateClientID()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	secretHash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash client secret: %w", err)
+	}
+
+	serviceAccount, err := au.serviceAccountRepo.CreateServiceAccount(entity.ServiceAccount{
+		ClientID:   clientID,
+		SecretHash: string(secretHash),
+		Name:       req.Name,
+		Scopes:     strings.Join(scopes, " "),
+		TokenTTL:   req.TokenTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ServiceAccountCredentials{
+		ClientID:     serviceAccount.ClientID,
+		ClientSecret: secret,
+		Name:         serviceAccount.Name,
+		Scopes:       serviceAccount.Scopes,
+		TokenTTL:     serviceAccount.TokenTTL,
+	}, nil
+}
+
+func (au *AuthUseCase) ListServiceAccounts() ([]entity.ServiceAccount, error) {
+	return au.serviceAccountRepo.FindServiceAccounts()
+}
+
+// DeleteServiceAccount removes the service account. Its tokens stop working right away,
+// because ValidateServiceToken looks the account up.
+func (au *AuthUseCase) DeleteServiceAccount(clientID string) error {
+	deleted, err := au.serviceAccountRepo.DeleteServiceAccount(clientID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &entity.ServiceAccountNotFoundError{}
+	}
+
+	return nil
+}
+
+// IssueServiceToken implements the client credentials grant, see RFC 6749 section 4.4. The token holds
+// the requested scopes, or all scopes of the service account when none are requested.
+func (au *AuthUseCase) IssueServiceToken(req dto.TokenRequestBody, cfg *config.Config) (*dto.ServiceToken, error) {
+	if req.GrantType != "client_credentials" {
+		return nil, &entity.OAuthError{Code: entity.OAuthErrorUnsupportedGrantType, Description: "Only the client_credentials grant is supported."}
+	}
+
+	serviceAccount, err := au.authenticateServiceAccount(req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := strings.Fields(serviceAccount.Scopes)
+	if req.Scope != "" {
+		requested := strings.Fields(req.Scope)
+		for _, scope := range requested {
+			if !slices.Contains(scopes, scope) {
+				return nil, &entity.OAuthError{Code: entity.OAuthErrorInvalidScope, Description: fmt.Sprintf("The scope %q isn't granted to the client.", scope)}
+			}
+		}
+		scopes = requested
+	}
+	scope := strings.Join(scopes, " ")
+
+	ttl := serviceAccount.TokenTTL
+	if ttl <= 0 {
+		ttl = cfg.Authen.ServiceTokenTTL
+	}
+
+	accessToken, err := au.signToken(jwt.MapClaims{
+		"iss":       "AuthConnect Hub",
+		"sub":       serviceAccount.ClientID,
+		"client_id": serviceAccount.ClientID,
+		"scope":     scope,
+		"aud":       serviceAudience,
+		"token_use": tokenUseService,
+		"exp":       time.Now().Add(time.Second * time.Duration(ttl)).Unix(),
+		"nbf":       time.Now().Unix(),
+		"iat":       time.Now().Unix(),
+		"jti":       uuid.NewString(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := au.serviceAccountRepo.UpdateServiceAccountUsage(serviceAccount.ID); err != nil {
+		return nil, err
+	}
+
+	return &dto.ServiceToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   ttl,
+		Scope:       scope,
+	}, nil
+}
+
+func (au *AuthUseCase) authenticateServiceAccount(clientID string, clientSecret string) (*entity.ServiceAccount, error) {
+	serviceAccount, err := au.serviceAccountRepo.FindServiceAccountByClientID(clientID)
+	if err != nil {
+		return nil, err
+	}
+	if serviceAccount == nil || bcrypt.CompareHashAndPassword([]byte(serviceAccount.SecretHash), []byte(clientSecret)) != nil {
+		return nil, &entity.OAuthError{Code: entity.OAuthErrorInvalidClient, Description: "The client credentials are invalid."}
+	}
+
+	return serviceAccount, nil
+}
+
+// ValidateServiceToken checks a token issued by IssueServiceToken and returns what it grants
+func (au *AuthUseCase) ValidateServiceToken(jwtToken string) (*entity.ServiceAccountToken, error) {
+	token, err := au.parseToken(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+
+	tokenUse, _ := claims["token_use"].(string)
+	audience, _ := claims.GetAudience()
+	if tokenUse != tokenUseService || !slices.Contains(audience, serviceAudience) {
+		return nil, errors.New("token isn't meant for a service account")
+	}
+
+	clientID, ok := claims["client_id"].(string)
+	if !ok {
+		return nil, errors.New("missing 'client_id' claim in token")
+	}
+	scope, _ := claims["scope"].(string)
+
+	serviceAccount, err := au.serviceAccountRepo.FindServiceAccountByClientID(clientID)
+	if err != nil {
+		return nil, err
+	}
+	if serviceAccount == nil {
+		return nil, &entity.ServiceAccountNotFoundError{}
+	}
+
+	return &entity.ServiceAccountToken{
+		ClientID: clientID,
+		Scopes:   strings.Fields(scope),
+	}, nil
+}
+
+func generateClientID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate client id: %w", err)
+	}
+	return "svc_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,203 @@
+package usecases_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newServiceAccountConfig(t *testing.T) *config.Config {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	return &config.Config{Authen: config.Authen{JwtPrivateKey: privateKey, ServiceTokenTTL: 3600}}
+}
+
+func newServiceAccount(t *testing.T, secret string, scopes string, tokenTTL int) *entity.ServiceAccount {
+	secretHash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	serviceAccount := &entity.ServiceAccount{ClientID: "svc_backup", SecretHash: string(secretHash), Name: "Backup", Scopes: scopes, TokenTTL: tokenTTL}
+	serviceAccount.ID = 7
+	return serviceAccount
+}
+
+func TestAuthUseCase_CreateServiceAccount(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockServiceAccountRepo := repoMocks.NewIServiceAccountRepo(t)
+	var created entity.ServiceAccount
+	mockServiceAccountRepo.On("CreateServiceAccount", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(0).(entity.ServiceAccount)
+	}).Return(func(serviceAccount entity.ServiceAccount) (entity.ServiceAccount, error) {
+		return serviceAccount, nil
+	})
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, mockServiceAccountRepo, nil, nil, nil, nil, nil, nil, newServiceAccountConfig(t))
+
+	credentials, err := uc.CreateServiceAccount(dto.ServiceAccountCreateRequestBody{
+		Name:   "Backup",
+		Scopes: "login-locks:read  login-locks:write login-locks:read",
+	})
+
+	assert.NoError(t, err)
+	assert.Regexp(t, `^svc_[0-9a-f]{16}$`, credentials.ClientID)
+	assert.Equal(t, "login-locks:read login-locks:write", credentials.Scopes)
+	assert.Equal(t, credentials.ClientID, created.ClientID)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(created.SecretHash), []byte(credentials.ClientSecret)))
+}
+
+func TestAuthUseCase_CreateServiceAccount_InvalidScope(t *testing.T) {
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, nil, nil, newServiceAccountConfig(t))
+
+	_, err := uc.CreateServiceAccount(dto.ServiceAccountCreateRequestBody{Name: "Backup", Scopes: "login-locks:read Admin"})
+
+	assert.True(t, helper.IsErrOfType(err, &entity.InvalidScopeError{}))
+}
+
+func TestAuthUseCase_IssueServiceToken(t *testing.T) {
+	cfg := newServiceAccountConfig(t)
+	serviceAccount := newServiceAccount(t, "secret", "login-locks:read login-locks:write", 0)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockServiceAccountRepo := repoMocks.NewIServiceAccountRepo(t)
+	mockServiceAccountRepo.On("FindServiceAccountByClientID", "svc_backup").Return(serviceAccount, nil)
+	mockServiceAccountRepo.On("UpdateServiceAccountUsage", uint(7)).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, mockServiceAccountRepo, nil, nil, nil, nil, nil, nil, cfg)
+
+	token, err := uc.IssueServiceToken(dto.TokenRequestBody{
+		GrantType:    "client_credentials",
+		ClientID:     "svc_backup",
+		ClientSecret: "secret",
+		Scope:        "login-locks:read",
+	}, cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.Equal(t, 3600, token.ExpiresIn)
+	assert.Equal(t, "login-locks:read", token.Scope)
+
+	serviceAccountToken, err := uc.ValidateServiceToken(token.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.ServiceAccountToken{ClientID: "svc_backup", Scopes: []string{"login-locks:read"}}, serviceAccountToken)
+
+	// a service account can't pass for a user
+	_, err = uc.ValidateToken(token.AccessToken)
+	assert.Error(t, err)
+}
+
+func TestAuthUseCase_IssueServiceToken_OwnTTL(t *testing.T) {
+	cfg := newServiceAccountConfig(t)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockServiceAccountRepo := repoMocks.NewIServiceAccountRepo(t)
+	mockServiceAccountRepo.On("FindServiceAccountByClientID", "svc_backup").Return(newServiceAccount(t, "secret", "login-locks:read", 300), nil)
+	mockServiceAccountRepo.On("UpdateServiceAccountUsage", uint(7)).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, mockServiceAccountRepo, nil, nil, nil, nil, nil, nil, cfg)
+
+	token, err := uc.IssueServiceToken(dto.TokenRequestBody{GrantType: "client_credentials", ClientID: "svc_backup", ClientSecret: "secret"}, cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 300, token.ExpiresIn)
+	assert.Equal(t, "login-locks:read", token.Scope)
+}
+
+func TestAuthUseCase_IssueServiceToken_Rejected(t *testing.T) {
+	cases := []struct {
+		name         string
+		req          dto.TokenRequestBody
+		expectedCode string
+	}{
+		{
+			name:         "Unsupported grant",
+			req:          dto.TokenRequestBody{GrantType: "password", ClientID: "svc_backup", ClientSecret: "secret"},
+			expectedCode: entity.OAuthErrorUnsupportedGrantType,
+		},
+		{
+			name:         "Wrong secret",
+			req:          dto.TokenRequestBody{GrantType: "client_credentials", ClientID: "svc_backup", ClientSecret: "wrong"},
+			expectedCode: entity.OAuthErrorInvalidClient,
+		},
+		{
+			name:         "Unknown client",
+			req:          dto.TokenRequestBody{GrantType: "client_credentials", ClientID: "svc_unknown", ClientSecret: "secret"},
+			expectedCode: entity.OAuthErrorInvalidClient,
+		},
+		{
+			name:         "Scope not granted",
+			req:          dto.TokenRequestBody{GrantType: "client_credentials", ClientID: "svc_backup", ClientSecret: "secret", Scope: "login-locks:write"},
+			expectedCode: entity.OAuthErrorInvalidScope,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newServiceAccountConfig(t)
+
+			mockAuthRepo := repoMocks.NewIAuthRepo(t)
+			mockServiceAccountRepo := repoMocks.NewIServiceAccountRepo(t)
+			mockServiceAccountRepo.On("FindServiceAccountByClientID", "svc_backup").Return(newServiceAccount(t, "secret", "login-locks:read", 0), nil).Maybe()
+			mockServiceAccountRepo.On("FindServiceAccountByClientID", "svc_unknown").Return(nil, nil).Maybe()
+
+			uc := usecases.NewAuthUseCase(mockAuthRepo, mockServiceAccountRepo, nil, nil, nil, nil, nil, nil, cfg)
+
+			_, err := uc.IssueServiceToken(tc.req, cfg)
+
+			var oauthErr *entity.OAuthError
+			assert.ErrorAs(t, err, &oauthErr)
+			assert.Equal(t, tc.expectedCode, oauthErr.Code)
+		})
+	}
+}
+
+func TestAuthUseCase_ValidateServiceToken_DeletedAccount(t *testing.T) {
+	cfg := newServiceAccountConfig(t)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockServiceAccountRepo := repoMocks.NewIServiceAccountRepo(t)
+	mockServiceAccountRepo.On("FindServiceAccountByClientID", "svc_backup").Return(newServiceAccount(t, "secret", "login-locks:read", 0), nil).Once()
+	mockServiceAccountRepo.On("UpdateServiceAccountUsage", uint(7)).Return(nil)
+	mockServiceAccountRepo.On("FindServiceAccountByClientID", "svc_backup").Return(nil, nil).Once()
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, mockServiceAccountRepo, nil, nil, nil, nil, nil, nil, cfg)
+
+	token, err := uc.IssueServiceToken(dto.TokenRequestBody{GrantType: "client_credentials", ClientID: "svc_backup", ClientSecret: "secret"}, cfg)
+	assert.NoError(t, err)
+
+	_, err = uc.ValidateServiceToken(token.AccessToken)
+	assert.True(t, helper.IsErrOfType(err, &entity.ServiceAccountNotFoundError{}))
+}
+
+func TestAuthUseCase_ValidateServiceToken_UserToken(t *testing.T) {
+	cfg := newServiceAccountConfig(t)
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, nil, nil, cfg)
+
+	accessToken, err := uc.CreateAccessToken(entity.User{Username: "svc_backup"}, 600)
+	assert.NoError(t, err)
+
+	_, err = uc.ValidateServiceToken(accessToken)
+	assert.EqualError(t, err, "token isn't meant for a service account")
+}
+
+func TestAuthUseCase_DeleteServiceAccount_NotFound(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockServiceAccountRepo := repoMocks.NewIServiceAccountRepo(t)
+	mockServiceAccountRepo.On("DeleteServiceAccount", "svc_unknown").Return(false, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, mockServiceAccountRepo, nil, nil, nil, nil, nil, nil, newServiceAccountConfig(t))
+
+	err := uc.DeleteServiceAccount("svc_unknown")
+
+	assert.True(t, helper.IsErrOfType(err, &entity.ServiceAccountNotFoundError{}))
+}
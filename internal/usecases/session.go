@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+)
+
+// ListSessions returns the devices the user is logged in on, the most recently used first
+func (au *AuthUseCase) ListSessions(username string) ([]entity.Session, error) {
+	return au.authRepo.FindSessions(username)
+}
+
+// RevokeSession logs the user out on one device, its tokens stop working right away
+func (au *AuthUseCase) RevokeSession(username string, sid string, cfg *config.Config) error {
+	session, err := au.authRepo.FindSession(sid)
+	if err != nil {
+		return err
+	}
+	// sessions of other users are reported as missing, so their ids can't be probed
+	if session == nil || session.Username != username {
+		return &entity.SessionNotFoundError{}
+	}
+
+	return au.endSession(username, sid, cfg)
+}
+
+// LogoutEverywhere ends every session of the user, including the current one
+func (au *AuthUseCase) LogoutEverywhere(username string, cfg *config.Config) error {
+	return au.endUserSessions(username, cfg)
+}
+
+// TouchSession records the device the session of the access token is used from.
+// Tokens issued before sessions existed are ignored.
+func (au *AuthUseCase) TouchSession(accessToken string, userAgent string, ip string) error {
+	sid, _ := au.RetrieveFieldFromJwtToken(accessToken, "sid", false)
+	if sid, ok := sid.(string); ok {
+		return au.authRepo.TouchSession(sid, userAgent, ip)
+	}
+
+	return nil
+}
+
+func (au *AuthUseCase) saveSession(user entity.User, sid string, cfg *config.Config) error {
+	now := time.Now()
+	return au.authRepo.SaveSession(entity.Session{
+		ID:         sid,
+		Username:   user.Username,
+		RememberMe: user.RememberMe,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}, cfg.Authen.RefreshTokenTTL)
+}
+
+func (au *AuthUseCase) endSession(username string, sid string, cfg *config.Config) error {
+	if err := au.authRepo.RevokeTokenFamily(sid, cfg.Authen.RefreshTokenTTL); err != nil {
+		return err
+	}
+
+	return au.authRepo.DeleteSession(sid, username)
+}
+
+func (au *AuthUseCase) endUserSessions(username string, cfg *config.Config) error {
+	return endUserSessions(au.authRepo, username, cfg)
+}
+
+// endUserSessions is shared with the user usecase, which logs out the users it disables or deletes
+func endUserSessions(authRepo repos.IAuthRepo, username string, cfg *config.Config) error {
+	if err := authRepo.BlacklistUserTokens(username, cfg.Authen.RefreshTokenTTL); err != nil {
+		return err
+	}
+
+	return authRepo.DeleteUserSessions(username)
+}
@@ -0,0 +1,119 @@
+package usecases_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthUseCase_RevokeSession_Success(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("FindSession", "session-id").Return(&entity.Session{ID: "session-id", Username: "testuser"}, nil)
+	mockAuthRepo.On("RevokeTokenFamily", "session-id", 3600).Return(nil)
+	mockAuthRepo.On("DeleteSession", "session-id", "testuser").Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, mockConfig)
+
+	err := uc.RevokeSession("testuser", "session-id", mockConfig)
+
+	assert.NoError(t, err)
+}
+
+func TestAuthUseCase_RevokeSession_OtherUser(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("FindSession", "session-id").Return(&entity.Session{ID: "session-id", Username: "otheruser"}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, mockConfig)
+
+	err := uc.RevokeSession("testuser", "session-id", mockConfig)
+
+	assert.Equal(t, &entity.SessionNotFoundError{}, err)
+	mockAuthRepo.AssertNotCalled(t, "RevokeTokenFamily", mock.Anything, mock.Anything)
+}
+
+func TestAuthUseCase_RevokeSession_NotFound(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("FindSession", "session-id").Return(nil, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, mockConfig)
+
+	err := uc.RevokeSession("testuser", "session-id", mockConfig)
+
+	assert.Equal(t, &entity.SessionNotFoundError{}, err)
+}
+
+func TestAuthUseCase_LogoutEverywhere(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("BlacklistUserTokens", "testuser", 3600).Return(nil)
+	mockAuthRepo.On("DeleteUserSessions", "testuser").Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, nil, nil, mockConfig)
+
+	err := uc.LogoutEverywhere("testuser", mockConfig)
+
+	assert.NoError(t, err)
+}
+
+func TestAuthUseCase_TouchSession(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		RefreshTokenTTL: 3600,
+		AccessTokenTTL:  600,
+		JwtPrivateKey:   privateKey,
+	}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("AddUserToken", "testuser", mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("AddFamilyToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("SaveSession", mock.Anything, 3600).Return(nil)
+	mockUserUC := mocks.NewIUserUC(t)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser"}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	tokens, err := uc.GenerateTokens(entity.User{ID: 1, Username: "testuser"}, mockConfig)
+	assert.NoError(t, err)
+
+	sid, err := uc.RetrieveFieldFromJwtToken(tokens.AccessToken, "sid", true)
+	assert.NoError(t, err)
+
+	mockAuthRepo.On("TouchSession", sid, "Mozilla/5.0", "203.0.113.7").Return(nil)
+
+	err = uc.TouchSession(tokens.AccessToken, "Mozilla/5.0", "203.0.113.7")
+
+	assert.NoError(t, err)
+}
+
+func TestAuthUseCase_TouchSession_TokenWithoutSession(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	mockConfig := &config.Config{Authen: config.Authen{JwtPrivateKey: privateKey}}
+
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, nil, nil, mockConfig)
+
+	accessToken, err := uc.CreateAccessToken(entity.User{Username: "testuser"}, 600)
+	assert.NoError(t, err)
+
+	err = uc.TouchSession(accessToken, "Mozilla/5.0", "203.0.113.7")
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,223 @@
+package usecases
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"regexp"
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/pquerna/otp/hotp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// RFC 6238 defaults, supported by every authenticator app
+	totpPeriod = 30
+	// number of time steps before and after the current one a code is still accepted for
+	totpSkew   = 1
+	totpQRSize = 200
+	// wrong codes a pending login survives, the user has to enter the password again after that
+	maxMFAFailures = 5
+)
+
+var totpCodePattern = regexp.MustCompile(`^[0-9]{6}$`)
+
+// EnrollTOTP generates a new TOTP secret for the user. Two-factor authentication
+// only becomes active once the secret is confirmed with a first code.
+func (au *AuthUseCase) EnrollTOTP(username string, cfg *config.Config) (*dto.TOTPEnrollment, error) {
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, &entity.TOTPAlreadyEnabledError{}
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      cfg.App.Name,
+		AccountName: user.Email,
+		Period:      totpPeriod,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+
+	img, err := key.Image(totpQRSize, totpQRSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render totp qr code: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode totp qr code: %w", err)
+	}
+
+	user.TOTPSecret = key.Secret()
+	if _, err := au.userUseCase.Update(*user); err != nil {
+		return nil, err
+	}
+
+	return &dto.TOTPEnrollment{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// ConfirmTOTP turns two-factor authentication on once the user proved their app generates valid codes.
+// It returns the first batch of recovery codes.
+func (au *AuthUseCase) ConfirmTOTP(username string, code string) ([]string, error) {
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, &entity.TOTPAlreadyEnabledError{}
+	}
+
+	if user.TOTPSecret == "" {
+		return nil, &entity.TOTPNotEnrolledError{}
+	}
+
+	if err := au.validateTOTPCode(*user, code); err != nil {
+		return nil, err
+	}
+
+	user.TOTPEnabled = true
+	if _, err := au.userUseCase.Update(*user); err != nil {
+		return nil, err
+	}
+
+	return au.generateRecoveryCodes(*user)
+}
+
+// DisableTOTP turns two-factor authentication off, a valid code is required to do so
+func (au *AuthUseCase) DisableTOTP(username string, code string) error {
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return err
+	}
+
+	if !user.TOTPEnabled {
+		return &entity.TOTPNotEnrolledError{}
+	}
+
+	if err := au.validateTOTPCode(*user, code); err != nil {
+		return err
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	if _, err := au.userUseCase.Update(*user); err != nil {
+		return err
+	}
+
+	// recovery codes are bound to the second factor they replace
+	return au.recoveryCodeRepo.ReplaceRecoveryCodes(user.ID, nil)
+}
+
+// VerifyMFALogin finishes a login started with a correct password by checking the TOTP code.
+// Wrong codes count as failed logins of the user and the ip, like wrong passwords do.
+func (au *AuthUseCase) VerifyMFALogin(requestBody dto.MFALoginRequestBody, ip string, cfg *config.Config) (*dto.JwtTokens, error) {
+	username, err := au.authRepo.GetMFAPendingToken(requestBody.Token)
+	if err != nil {
+		return nil, err
+	}
+	if username == "" {
+		return nil, &entity.InvalidMFATokenError{}
+	}
+
+	subjects := loginSubjects(username, ip, cfg)
+	if err := au.checkLoginLocks(subjects); err != nil {
+		return nil, err
+	}
+
+	user, err := au.userUseCase.FindByUsernameOrEmail(username, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// anything else than a TOTP code is taken for a recovery code
+	if totpCodePattern.MatchString(requestBody.Code) {
+		err = au.validateTOTPCode(*user, requestBody.Code)
+	} else {
+		err = au.useRecoveryCode(*user, requestBody.Code)
+	}
+	if err != nil {
+		if helper.IsErrOfType(err, &entity.InvalidTOTPCodeError{}) {
+			return nil, au.mfaFailed(requestBody.Token, subjects, cfg)
+		}
+		return nil, err
+	}
+
+	// a pending login can only be finished once
+	if err := au.authRepo.DeleteMFAPendingToken(requestBody.Token); err != nil {
+		return nil, err
+	}
+	if err := au.authRepo.ClearLoginFailures(subjects[0].kind, subjects[0].value); err != nil {
+		return nil, err
+	}
+
+	if requestBody.RememberMe == "on" {
+		user.RememberMe = true
+	}
+
+	return au.GenerateTokens(*user, cfg)
+}
+
+// mfaFailed counts the wrong code for the pending login, which is dropped after too many of them,
+// and for the user and the ip, which are locked the same way as for wrong passwords
+func (au *AuthUseCase) mfaFailed(token string, subjects []loginSubject, cfg *config.Config) error {
+	failures, err := au.authRepo.RecordMFAFailure(token, cfg.Authen.MFAPendingTokenTTL)
+	if err != nil {
+		return err
+	}
+	if failures >= maxMFAFailures {
+		if err := au.authRepo.DeleteMFAPendingToken(token); err != nil {
+			return err
+		}
+	}
+
+	err = au.loginFailed(subjects, cfg)
+	if !helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+		return err
+	}
+	return &entity.InvalidTOTPCodeError{}
+}
+
+// validateTOTPCode accepts a code of the current time step or of the neighbouring ones.
+// Every accepted code is recorded, so it can't be used a second time.
+func (au *AuthUseCase) validateTOTPCode(user entity.User, code string) error {
+	counter := time.Now().Unix() / totpPeriod
+
+	for i := int64(-totpSkew); i <= totpSkew; i++ {
+		expected, err := hotp.GenerateCode(user.TOTPSecret, uint64(counter+i))
+		if err != nil {
+			return fmt.Errorf("failed to generate totp code: %w", err)
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) != 1 {
+			continue
+		}
+
+		fresh, err := au.authRepo.MarkTOTPCodeUsed(user.Username, counter+i, (2*totpSkew+1)*totpPeriod)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return &entity.InvalidTOTPCodeError{}
+		}
+		return nil
+	}
+
+	return &entity.InvalidTOTPCodeError{}
+}
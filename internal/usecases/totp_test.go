@@ -0,0 +1,337 @@
+package usecases_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
+	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases"
+	"github.com/minhmannh2001/authconnecthub/internal/usecases/mocks"
+	repoMocks "github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// base32 secret shared by the tests below
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+func currentTOTPCode(t *testing.T) string {
+	code, err := totp.GenerateCode(testTOTPSecret, time.Now())
+	assert.NoError(t, err)
+	return code
+}
+
+func TestAuthUseCase_Login_MFARequired(t *testing.T) {
+	requestBody := dto.LoginRequestBody{Username: "testuser", Password: "secret"}
+
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(requestBody.Password), bcrypt.DefaultCost)
+	assert.NoError(t, err)
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Password: string(encryptedPassword), TOTPEnabled: true, TOTPSecret: testTOTPSecret}, nil)
+
+	mockConfig := &config.Config{Authen: config.Authen{MFAPendingTokenTTL: 300}}
+	mockContext := newLoginContext(mockConfig)
+
+	var pendingToken string
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockLoginUnlocked(mockAuthRepo, "testuser")
+	mockAuthRepo.On("ClearLoginFailures", "username", "testuser").Return(nil)
+	mockAuthRepo.On("SaveMFAPendingToken", mock.Anything, "testuser", 300).Run(func(args mock.Arguments) {
+		pendingToken = args.String(0)
+	}).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	tokens, err := uc.Login(mockContext, requestBody)
+
+	assert.Nil(t, tokens)
+	var mfaRequiredErr *entity.MFARequiredError
+	assert.True(t, errors.As(err, &mfaRequiredErr))
+	assert.NotEmpty(t, pendingToken)
+	assert.Equal(t, pendingToken, mfaRequiredErr.Token)
+}
+
+func TestAuthUseCase_EnrollTOTP_Success(t *testing.T) {
+	mockConfig := &config.Config{App: config.App{Name: "AuthConnect Hub"}}
+
+	var savedSecret string
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", Email: "test@example.com"}, nil)
+	mockUserUC.On("Update", mock.MatchedBy(func(u entity.User) bool {
+		return u.TOTPSecret != "" && !u.TOTPEnabled
+	})).Run(func(args mock.Arguments) {
+		savedSecret = args.Get(0).(entity.User).TOTPSecret
+	}).Return(entity.User{}, nil)
+
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	enrollment, err := uc.EnrollTOTP("testuser", mockConfig)
+
+	assert.NoError(t, err)
+	assert.Equal(t, savedSecret, enrollment.Secret)
+	assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/"))
+	assert.Contains(t, enrollment.URI, "secret="+savedSecret)
+	assert.Contains(t, enrollment.URI, "test@example.com")
+	assert.True(t, strings.HasPrefix(enrollment.QRCode, "data:image/png;base64,"))
+}
+
+func TestAuthUseCase_EnrollTOTP_AlreadyEnabled(t *testing.T) {
+	mockConfig := &config.Config{App: config.App{Name: "AuthConnect Hub"}}
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPEnabled: true, TOTPSecret: testTOTPSecret}, nil)
+
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	enrollment, err := uc.EnrollTOTP("testuser", mockConfig)
+
+	assert.Nil(t, enrollment)
+	assert.Equal(t, &entity.TOTPAlreadyEnabledError{}, err)
+	mockUserUC.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestAuthUseCase_ConfirmTOTP_Success(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPSecret: testTOTPSecret}, nil)
+	mockUserUC.On("Update", mock.MatchedBy(func(u entity.User) bool {
+		return u.TOTPEnabled && u.TOTPSecret == testTOTPSecret
+	})).Return(entity.User{}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockRecoveryCodeRepo := repoMocks.NewIRecoveryCodeRepo(t)
+	mockAuthRepo.On("MarkTOTPCodeUsed", "testuser", mock.Anything, 90).Return(true, nil)
+	mockRecoveryCodeRepo.On("ReplaceRecoveryCodes", uint(1), mock.MatchedBy(func(hashes []string) bool {
+		return len(hashes) == 10
+	})).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, mockRecoveryCodeRepo, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	recoveryCodes, err := uc.ConfirmTOTP("testuser", currentTOTPCode(t))
+
+	assert.NoError(t, err)
+	assert.Len(t, recoveryCodes, 10)
+	mockUserUC.AssertExpectations(t)
+}
+
+func TestAuthUseCase_ConfirmTOTP_InvalidCode(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPSecret: testTOTPSecret}, nil)
+
+	// a wrong code is never recorded
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	code := "000000"
+	if code == currentTOTPCode(t) {
+		code = "111111"
+	}
+	recoveryCodes, err := uc.ConfirmTOTP("testuser", code)
+
+	assert.Nil(t, recoveryCodes)
+	assert.Equal(t, &entity.InvalidTOTPCodeError{}, err)
+	mockUserUC.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestAuthUseCase_ConfirmTOTP_ReplayedCode(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPSecret: testTOTPSecret}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("MarkTOTPCodeUsed", "testuser", mock.Anything, 90).Return(false, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	_, err := uc.ConfirmTOTP("testuser", currentTOTPCode(t))
+
+	assert.Equal(t, &entity.InvalidTOTPCodeError{}, err)
+	mockUserUC.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestAuthUseCase_ConfirmTOTP_NotEnrolled(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser"}, nil)
+
+	uc := usecases.NewAuthUseCase(repoMocks.NewIAuthRepo(t), nil, nil, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	_, err := uc.ConfirmTOTP("testuser", "123456")
+
+	assert.Equal(t, &entity.TOTPNotEnrolledError{}, err)
+}
+
+func TestAuthUseCase_DisableTOTP_Success(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPEnabled: true, TOTPSecret: testTOTPSecret}, nil)
+	mockUserUC.On("Update", mock.MatchedBy(func(u entity.User) bool {
+		return !u.TOTPEnabled && u.TOTPSecret == ""
+	})).Return(entity.User{}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockRecoveryCodeRepo := repoMocks.NewIRecoveryCodeRepo(t)
+	mockAuthRepo.On("MarkTOTPCodeUsed", "testuser", mock.Anything, 90).Return(true, nil)
+	mockRecoveryCodeRepo.On("ReplaceRecoveryCodes", uint(1), []string(nil)).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, mockRecoveryCodeRepo, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	err := uc.DisableTOTP("testuser", currentTOTPCode(t))
+
+	assert.NoError(t, err)
+	mockUserUC.AssertExpectations(t)
+}
+
+func TestAuthUseCase_VerifyMFALogin_Success(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		RefreshTokenTTL: 3600,
+		AccessTokenTTL:  600,
+		JwtPrivateKey:   privateKey,
+	}}
+
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPEnabled: true, TOTPSecret: testTOTPSecret}, nil)
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("GetMFAPendingToken", "pending-token").Return("testuser", nil)
+	mockAuthRepo.On("FindLoginLock", "username", "testuser").Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "username", "testuser").Return(time.Time{}, nil)
+	mockAuthRepo.On("MarkTOTPCodeUsed", "testuser", mock.Anything, 90).Return(true, nil)
+	mockAuthRepo.On("DeleteMFAPendingToken", "pending-token").Return(nil)
+	mockAuthRepo.On("ClearLoginFailures", "username", "testuser").Return(nil)
+	mockAuthRepo.On("AddUserToken", "testuser", mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("AddFamilyToken", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockAuthRepo.On("SaveSession", mock.Anything, 3600).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	tokens, err := uc.VerifyMFALogin(dto.MFALoginRequestBody{Token: "pending-token", Code: currentTOTPCode(t), RememberMe: "on"}, "", mockConfig)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+
+	rememberMe, err := uc.RetrieveFieldFromJwtToken(tokens.AccessToken, "remember_me", false)
+	assert.NoError(t, err)
+	assert.Equal(t, true, rememberMe)
+}
+
+func TestAuthUseCase_VerifyMFALogin_InvalidPendingToken(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("GetMFAPendingToken", "expired-token").Return("", nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, new(mocks.IUserUC), nil, &config.Config{})
+
+	tokens, err := uc.VerifyMFALogin(dto.MFALoginRequestBody{Token: "expired-token", Code: "123456"}, "", &config.Config{})
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.InvalidMFATokenError{}, err)
+}
+
+func TestAuthUseCase_VerifyMFALogin_InvalidCode(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPEnabled: true, TOTPSecret: testTOTPSecret}, nil)
+
+	// the pending login stays usable for another attempt
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("GetMFAPendingToken", "pending-token").Return("testuser", nil)
+	mockAuthRepo.On("FindLoginLock", "username", "testuser").Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "username", "testuser").Return(time.Time{}, nil)
+	mockAuthRepo.On("RecordMFAFailure", "pending-token", 0).Return(int64(1), nil)
+	mockAuthRepo.On("RecordLoginFailure", "username", "testuser", 0).Return(int64(1), nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, &config.Config{})
+
+	code := "000000"
+	if code == currentTOTPCode(t) {
+		code = "111111"
+	}
+	tokens, err := uc.VerifyMFALogin(dto.MFALoginRequestBody{Token: "pending-token", Code: code}, "", &config.Config{})
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.InvalidTOTPCodeError{}, err)
+}
+
+func TestAuthUseCase_VerifyMFALogin_TooManyWrongCodes(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPEnabled: true, TOTPSecret: testTOTPSecret}, nil)
+
+	mockConfig := &config.Config{Authen: config.Authen{MFAPendingTokenTTL: 300, LoginFailureWindow: 900}}
+
+	// the fifth wrong code drops the pending login, the password has to be entered again
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("GetMFAPendingToken", "pending-token").Return("testuser", nil)
+	mockAuthRepo.On("FindLoginLock", "username", "testuser").Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "username", "testuser").Return(time.Time{}, nil)
+	mockAuthRepo.On("FindLoginLock", "ip", "203.0.113.7").Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "ip", "203.0.113.7").Return(time.Time{}, nil)
+	mockAuthRepo.On("RecordMFAFailure", "pending-token", 300).Return(int64(5), nil)
+	mockAuthRepo.On("DeleteMFAPendingToken", "pending-token").Return(nil)
+	mockAuthRepo.On("RecordLoginFailure", "username", "testuser", 900).Return(int64(5), nil)
+	mockAuthRepo.On("RecordLoginFailure", "ip", "203.0.113.7", 900).Return(int64(5), nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	code := "000000"
+	if code == currentTOTPCode(t) {
+		code = "111111"
+	}
+	tokens, err := uc.VerifyMFALogin(dto.MFALoginRequestBody{Token: "pending-token", Code: code}, "203.0.113.7", mockConfig)
+
+	assert.Nil(t, tokens)
+	assert.Equal(t, &entity.InvalidTOTPCodeError{}, err)
+}
+
+func TestAuthUseCase_VerifyMFALogin_WrongCodeLocksUser(t *testing.T) {
+	mockUserUC := new(mocks.IUserUC)
+	mockUserUC.On("FindByUsernameOrEmail", "testuser", "").Return(&entity.User{ID: 1, Username: "testuser", TOTPEnabled: true, TOTPSecret: testTOTPSecret}, nil)
+
+	mockConfig := &config.Config{Authen: config.Authen{
+		MFAPendingTokenTTL:   300,
+		LoginFailureWindow:   900,
+		LoginMaxFailures:     3,
+		LoginLockoutDuration: 600,
+	}}
+
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("GetMFAPendingToken", "pending-token").Return("testuser", nil)
+	mockAuthRepo.On("FindLoginLock", "username", "testuser").Return(nil, nil)
+	mockAuthRepo.On("FindLoginDelay", "username", "testuser").Return(time.Time{}, nil)
+	mockAuthRepo.On("RecordMFAFailure", "pending-token", 300).Return(int64(1), nil)
+	mockAuthRepo.On("RecordLoginFailure", "username", "testuser", 900).Return(int64(3), nil)
+	mockAuthRepo.On("LockLogin", "username", "testuser", 600).Return(nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, mockUserUC, nil, mockConfig)
+
+	code := "000000"
+	if code == currentTOTPCode(t) {
+		code = "111111"
+	}
+	tokens, err := uc.VerifyMFALogin(dto.MFALoginRequestBody{Token: "pending-token", Code: code}, "", mockConfig)
+
+	assert.Nil(t, tokens)
+	assert.True(t, helper.IsErrOfType(err, &entity.LoginLockedError{}))
+}
+
+func TestAuthUseCase_VerifyMFALogin_Locked(t *testing.T) {
+	mockAuthRepo := repoMocks.NewIAuthRepo(t)
+	mockAuthRepo.On("GetMFAPendingToken", "pending-token").Return("testuser", nil)
+	mockAuthRepo.On("FindLoginLock", "username", "testuser").Return(&entity.LoginLock{Kind: "username", Value: "testuser"}, nil)
+
+	uc := usecases.NewAuthUseCase(mockAuthRepo, nil, nil, nil, nil, nil, new(mocks.IUserUC), nil, &config.Config{})
+
+	tokens, err := uc.VerifyMFALogin(dto.MFALoginRequestBody{Token: "pending-token", Code: "123456"}, "", &config.Config{})
+
+	assert.Nil(t, tokens)
+	assert.True(t, helper.IsErrOfType(err, &entity.LoginLockedError{}))
+}
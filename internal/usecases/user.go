@@ -1,16 +1,33 @@
 package usecases
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
 	"github.com/minhmannh2001/authconnecthub/internal/entity"
+	"github.com/minhmannh2001/authconnecthub/internal/helper"
 	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultUserPageSize = 20
+	defaultUserRole     = "customer"
 )
 
 type UserUseCase struct {
 	userRepo repos.IUserRepo
+	roleRepo repos.IRoleRepo
+	authRepo repos.IAuthRepo
 }
 
-func NewUserUseCase(ur repos.IUserRepo) *UserUseCase {
-	return &UserUseCase{userRepo: ur}
+func NewUserUseCase(ur repos.IUserRepo, rr repos.IRoleRepo, ar repos.IAuthRepo) *UserUseCase {
+	return &UserUseCase{userRepo: ur, roleRepo: rr, authRepo: ar}
 }
 
 func (uc *UserUseCase) Create(u entity.User) (entity.User, error) {
@@ -21,3 +38,256 @@ func (uc *UserUseCase) Create(u entity.User) (entity.User, error) {
 func (uc *UserUseCase) FindByUsernameOrEmail(username, email string) (*entity.User, error) {
 	return uc.userRepo.FindByUsernameOrEmail(username, email)
 }
+
+func (uc *UserUseCase) Update(u entity.User) (entity.User, error) {
+	return uc.userRepo.Update(u)
+}
+
+// userCursor is encoded into the next cursor of a page, the sort keeps a cursor from being used with another
+type userCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+// ListUsers returns a page of the users the query selects, sorted by username when no sort is given
+func (uc *UserUseCase) ListUsers(query dto.UserListQuery) (*dto.UserPage, error) {
+	userQuery := entity.UserQuery{
+		Search: query.Search,
+		Role:   query.Role,
+		Status: query.Status,
+		Sort:   query.Sort,
+		Limit:  query.Limit,
+	}
+	if userQuery.Sort == "" {
+		userQuery.Sort = "username"
+	}
+	if userQuery.Limit <= 0 {
+		userQuery.Limit = defaultUserPageSize
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeUserCursor(query.Cursor)
+		if err != nil || cursor.Sort != userQuery.Sort {
+			return nil, &entity.InvalidCursorError{}
+		}
+		userQuery.After = &entity.UserCursor{Value: cursor.Value, ID: cursor.ID}
+	}
+
+	// one more user tells if there is a next page
+	limit := userQuery.Limit
+	userQuery.Limit++
+	users, err := uc.userRepo.FindUsers(userQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.UserPage{Users: []dto.AdminUser{}}
+	if len(users) > limit {
+		users = users[:limit]
+		last := users[limit-1]
+		page.NextCursor, err = encodeUserCursor(userCursor{Sort: userQuery.Sort, Value: userSortValue(last, userQuery.Sort), ID: last.ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, user := range users {
+		page.Users = append(page.Users, adminUser(user))
+	}
+
+	return page, nil
+}
+
+// GetUser returns the user, deleted users too
+func (uc *UserUseCase) GetUser(id uint) (*dto.AdminUser, error) {
+	user, err := uc.userRepo.RetrieveByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := adminUser(user)
+	return &result, nil
+}
+
+// CreateUser creates a user on behalf of an administrator
+func (uc *UserUseCase) CreateUser(req dto.UserCreateRequestBody) (*dto.AdminUser, error) {
+	roleName := req.Role
+	if roleName == "" {
+		roleName = defaultUserRole
+	}
+	roleID, err := uc.roleRepo.GetRoleIDByName(roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := entity.User{
+		Username:      req.Username,
+		Email:         req.Email,
+		Password:      string(encryptedPassword),
+		RoleID:        roleID,
+		EmailVerified: req.EmailVerified,
+	}
+	if req.EmailVerified {
+		now := time.Now()
+		user.VerifiedAt = &now
+	}
+
+	created, err := uc.userRepo.Create(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetUser(created.ID)
+}
+
+// UpdateUser changes the email or the role of the user. Administrators can't change their own role.
+func (uc *UserUseCase) UpdateUser(req dto.UserUpdateRequestBody, admin string) (*dto.AdminUser, error) {
+	user, err := uc.findUndeletedUser(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Email != "" && req.Email != user.Email {
+		existing, err := uc.userRepo.FindByUsernameOrEmail("", req.Email)
+		if err == nil && existing.ID != user.ID {
+			return nil, &entity.ErrDuplicateUser{Email: req.Email}
+		}
+		if err != nil && !helper.IsErrOfType(err, &entity.InvalidCredentialsError{}) {
+			return nil, err
+		}
+
+		user.Email = req.Email
+		user.EmailVerified = false
+		user.VerifiedAt = nil
+	}
+
+	if req.Role != "" && req.Role != user.Role.Name {
+		if user.Username == admin {
+			return nil, &entity.OwnAccountError{}
+		}
+		roleID, err := uc.roleRepo.GetRoleIDByName(req.Role)
+		if err != nil {
+			return nil, err
+		}
+		user.RoleID = roleID
+	}
+
+	if _, err := uc.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return uc.GetUser(user.ID)
+}
+
+// SetUserDisabled disables or enables the user. Disabled users are logged out everywhere and can't log in again.
+func (uc *UserUseCase) SetUserDisabled(id uint, disabled bool, admin string, cfg *config.Config) (*dto.AdminUser, error) {
+	user, err := uc.findUndeletedUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Username == admin {
+		return nil, &entity.OwnAccountError{}
+	}
+
+	user.Disabled = disabled
+	if _, err := uc.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	if disabled {
+		if err := endUserSessions(uc.authRepo, user.Username, cfg); err != nil {
+			return nil, err
+		}
+	}
+	return uc.GetUser(user.ID)
+}
+
+// DeleteUser soft deletes the user and logs it out everywhere, RestoreUser brings it back
+func (uc *UserUseCase) DeleteUser(id uint, admin string, cfg *config.Config) (*dto.AdminUser, error) {
+	user, err := uc.findUndeletedUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Username == admin {
+		return nil, &entity.OwnAccountError{}
+	}
+
+	if _, err := uc.userRepo.Delete(user); err != nil {
+		return nil, err
+	}
+	if err := endUserSessions(uc.authRepo, user.Username, cfg); err != nil {
+		return nil, err
+	}
+	return uc.GetUser(user.ID)
+}
+
+func (uc *UserUseCase) RestoreUser(id uint) (*dto.AdminUser, error) {
+	user, err := uc.userRepo.Restore(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := adminUser(user)
+	return &result, nil
+}
+
+// findUndeletedUser finds a user which can be changed, deleted users have to be restored first
+func (uc *UserUseCase) findUndeletedUser(id uint) (entity.User, error) {
+	user, err := uc.userRepo.RetrieveByID(id)
+	if err != nil {
+		return entity.User{}, err
+	}
+	if user.DeletedAt.Valid {
+		return entity.User{}, &entity.UserNotFoundError{}
+	}
+
+	return user, nil
+}
+
+func adminUser(user entity.User) dto.AdminUser {
+	result := dto.AdminUser{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		Role:          user.Role.Name,
+		EmailVerified: user.EmailVerified,
+		TOTPEnabled:   user.TOTPEnabled,
+		Disabled:      user.Disabled,
+		CreatedAt:     user.CreatedAt,
+	}
+	if user.DeletedAt.Valid {
+		result.DeletedAt = &user.DeletedAt.Time
+	}
+	return result
+}
+
+func userSortValue(user entity.User, sort string) string {
+	switch strings.TrimPrefix(sort, "-") {
+	case "email":
+		return user.Email
+	case "created_at":
+		return user.CreatedAt.Format(time.RFC3339Nano)
+	}
+	return user.Username
+}
+
+func encodeUserCursor(cursor userCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeUserCursor(s string) (userCursor, error) {
+	var cursor userCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
@@ -2,12 +2,17 @@ package usecases_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/internal/dto"
 	"github.com/minhmannh2001/authconnecthub/internal/entity"
 	"github.com/minhmannh2001/authconnecthub/internal/usecases"
 	"github.com/minhmannh2001/authconnecthub/internal/usecases/repos/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 func TestUserUseCase_Create_Success(t *testing.T) {
@@ -19,7 +24,7 @@ func TestUserUseCase_Create_Success(t *testing.T) {
 	mockRepo.On("Create", mock.Anything).Return(user, nil)
 
 	// Create the use case with the mock repository
-	uc := usecases.NewUserUseCase(mockRepo)
+	uc := usecases.NewUserUseCase(mockRepo, nil, nil)
 
 	// Call Create and assert the result
 	createdUser, err := uc.Create(user)
@@ -36,7 +41,7 @@ func TestUserUseCase_Create_DuplicateUser(t *testing.T) {
 	mockRepo.On("Create", mock.Anything).Return(entity.User{}, &entity.ErrDuplicateUser{})
 
 	// Create the use case
-	uc := usecases.NewUserUseCase(mockRepo)
+	uc := usecases.NewUserUseCase(mockRepo, nil, nil)
 
 	// Create a user
 	user := entity.User{Username: "testuser", Email: "test@example.com"}
@@ -54,7 +59,7 @@ func TestUserUseCase_FindByUsernameOrEmail_Success(t *testing.T) {
 	mockRepo.On("FindByUsernameOrEmail", "testuser", "test@example.com").Return(&user, nil)
 
 	// Create the use case
-	uc := usecases.NewUserUseCase(mockRepo)
+	uc := usecases.NewUserUseCase(mockRepo, nil, nil)
 
 	// Call FindByUsernameOrEmail and assert the result
 	foundUser, err := uc.FindByUsernameOrEmail("testuser", "test@example.com")
@@ -68,10 +73,190 @@ func TestUserUseCase_FindByUsernameOrEmail_NotFound(t *testing.T) {
 	mockRepo.On("FindByUsernameOrEmail", "testuser", "test@example.com").Return(nil, &entity.InvalidCredentialsError{})
 
 	// Create the use case
-	uc := usecases.NewUserUseCase(mockRepo)
+	uc := usecases.NewUserUseCase(mockRepo, nil, nil)
 
 	// Call FindByUsernameOrEmail and assert the error
 	_, err := uc.FindByUsernameOrEmail("testuser", "test@example.com")
 	assert.Error(t, err)
 	assert.IsType(t, &entity.InvalidCredentialsError{}, err)
 }
+
+func TestUserUseCase_Update_Success(t *testing.T) {
+	// Create a mock repository
+	mockRepo := new(mocks.IUserRepo)
+	user := entity.User{ID: 1, Username: "testuser", Email: "test@example.com"}
+	mockRepo.On("Update", user).Return(user, nil)
+
+	// Create the use case
+	uc := usecases.NewUserUseCase(mockRepo, nil, nil)
+
+	// Call Update and assert the result
+	updatedUser, err := uc.Update(user)
+	assert.NoError(t, err)
+	assert.Equal(t, user, updatedUser)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_ListUsers_Pages(t *testing.T) {
+	mockRepo := mocks.NewIUserRepo(t)
+	uc := usecases.NewUserUseCase(mockRepo, nil, nil)
+
+	// one more user than asked for tells there is a next page
+	mockRepo.On("FindUsers", entity.UserQuery{Search: "an", Status: entity.UserStatusActive, Sort: "-email", Limit: 3}).Return([]entity.User{
+		{ID: 4, Username: "anna", Email: "anna@example.com", Role: entity.Role{Name: "customer"}},
+		{ID: 9, Username: "dan", Email: "dan@example.com", Disabled: false},
+	}, nil).Once()
+	page, err := uc.ListUsers(dto.UserListQuery{Search: "an", Status: entity.UserStatusActive, Sort: "-email", Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, page.Users, 2)
+	assert.Equal(t, "customer", page.Users[0].Role)
+	assert.Empty(t, page.NextCursor)
+
+	mockRepo.On("FindUsers", entity.UserQuery{Sort: "username", Limit: 2}).Return([]entity.User{
+		{ID: 4, Username: "anna"},
+		{ID: 9, Username: "dan"},
+	}, nil).Once()
+	page, err = uc.ListUsers(dto.UserListQuery{Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, page.Users, 1)
+	assert.NotEmpty(t, page.NextCursor)
+
+	// the next page starts after the last user of the page before
+	mockRepo.On("FindUsers", entity.UserQuery{Sort: "username", Limit: 2, After: &entity.UserCursor{Value: "anna", ID: 4}}).Return([]entity.User{
+		{ID: 9, Username: "dan"},
+	}, nil).Once()
+	page, err = uc.ListUsers(dto.UserListQuery{Limit: 1, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, "dan", page.Users[0].Username)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestUserUseCase_ListUsers_InvalidCursor(t *testing.T) {
+	mockRepo := mocks.NewIUserRepo(t)
+	uc := usecases.NewUserUseCase(mockRepo, nil, nil)
+
+	mockRepo.On("FindUsers", mock.Anything).Return([]entity.User{{ID: 4, Username: "anna"}, {ID: 9, Username: "dan"}}, nil).Once()
+	page, err := uc.ListUsers(dto.UserListQuery{Limit: 1})
+	assert.NoError(t, err)
+
+	// a cursor can't be used with another sort
+	for _, query := range []dto.UserListQuery{{Cursor: "not-a-cursor"}, {Cursor: page.NextCursor, Sort: "email"}} {
+		_, err = uc.ListUsers(query)
+		assert.Equal(t, &entity.InvalidCursorError{}, err)
+	}
+}
+
+func TestUserUseCase_CreateUser(t *testing.T) {
+	mockRepo := mocks.NewIUserRepo(t)
+	mockRoleRepo := mocks.NewIRoleRepo(t)
+	uc := usecases.NewUserUseCase(mockRepo, mockRoleRepo, nil)
+
+	var created entity.User
+	mockRoleRepo.On("GetRoleIDByName", "customer").Return(uint(3), nil)
+	mockRepo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(0).(entity.User)
+	}).Return(entity.User{ID: 7}, nil)
+	mockRepo.On("RetrieveByID", uint(7)).Return(entity.User{ID: 7, Username: "anna", Role: entity.Role{Name: "customer"}}, nil)
+
+	user, err := uc.CreateUser(dto.UserCreateRequestBody{Username: "anna", Email: "anna@example.com", Password: "password123", EmailVerified: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "customer", user.Role)
+	assert.Equal(t, uint(3), created.RoleID)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(created.Password), []byte("password123")))
+	assert.True(t, created.EmailVerified)
+	assert.NotNil(t, created.VerifiedAt)
+}
+
+func TestUserUseCase_UpdateUser(t *testing.T) {
+	mockRepo := mocks.NewIUserRepo(t)
+	mockRoleRepo := mocks.NewIRoleRepo(t)
+	uc := usecases.NewUserUseCase(mockRepo, mockRoleRepo, nil)
+
+	anna := entity.User{ID: 7, Username: "anna", Email: "anna@example.com", EmailVerified: true, RoleID: 3, Role: entity.Role{Name: "customer"}}
+	mockRepo.On("RetrieveByID", uint(7)).Return(anna, nil)
+	mockRepo.On("FindByUsernameOrEmail", "", "dan@example.com").Return(&entity.User{ID: 9, Username: "dan"}, nil)
+	mockRepo.On("FindByUsernameOrEmail", "", "anna@home.example").Return(nil, &entity.InvalidCredentialsError{})
+	mockRoleRepo.On("GetRoleIDByName", "admin").Return(uint(1), nil)
+
+	_, err := uc.UpdateUser(dto.UserUpdateRequestBody{ID: 7, Email: "dan@example.com"}, "root")
+	assert.Equal(t, &entity.ErrDuplicateUser{Email: "dan@example.com"}, err)
+
+	// administrators can't take their own role away
+	_, err = uc.UpdateUser(dto.UserUpdateRequestBody{ID: 7, Role: "admin"}, "anna")
+	assert.Equal(t, &entity.OwnAccountError{}, err)
+
+	var updated entity.User
+	mockRepo.On("Update", mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(0).(entity.User)
+	}).Return(anna, nil)
+	_, err = uc.UpdateUser(dto.UserUpdateRequestBody{ID: 7, Email: "anna@home.example", Role: "admin"}, "root")
+	assert.NoError(t, err)
+	assert.Equal(t, "anna@home.example", updated.Email)
+	assert.False(t, updated.EmailVerified)
+	assert.Equal(t, uint(1), updated.RoleID)
+}
+
+func TestUserUseCase_SetUserDisabled(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+	mockRepo := mocks.NewIUserRepo(t)
+	mockAuthRepo := mocks.NewIAuthRepo(t)
+	uc := usecases.NewUserUseCase(mockRepo, nil, mockAuthRepo)
+
+	mockRepo.On("RetrieveByID", uint(7)).Return(entity.User{ID: 7, Username: "anna"}, nil)
+	mockRepo.On("Update", entity.User{ID: 7, Username: "anna", Disabled: true}).Return(entity.User{ID: 7, Username: "anna", Disabled: true}, nil)
+	mockAuthRepo.On("BlacklistUserTokens", "anna", 3600).Return(nil)
+	mockAuthRepo.On("DeleteUserSessions", "anna").Return(nil)
+
+	_, err := uc.SetUserDisabled(7, true, "anna", mockConfig)
+	assert.Equal(t, &entity.OwnAccountError{}, err)
+
+	_, err = uc.SetUserDisabled(7, true, "root", mockConfig)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_SetUserDisabled_Enable(t *testing.T) {
+	mockRepo := mocks.NewIUserRepo(t)
+	mockAuthRepo := mocks.NewIAuthRepo(t)
+	uc := usecases.NewUserUseCase(mockRepo, nil, mockAuthRepo)
+
+	mockRepo.On("RetrieveByID", uint(7)).Return(entity.User{ID: 7, Username: "anna", Disabled: true}, nil)
+	mockRepo.On("Update", entity.User{ID: 7, Username: "anna"}).Return(entity.User{ID: 7, Username: "anna"}, nil)
+
+	_, err := uc.SetUserDisabled(7, false, "root", &config.Config{})
+
+	assert.NoError(t, err)
+	mockAuthRepo.AssertNotCalled(t, "DeleteUserSessions", mock.Anything)
+}
+
+func TestUserUseCase_DeleteUser(t *testing.T) {
+	mockConfig := &config.Config{Authen: config.Authen{RefreshTokenTTL: 3600}}
+	mockRepo := mocks.NewIUserRepo(t)
+	mockAuthRepo := mocks.NewIAuthRepo(t)
+	uc := usecases.NewUserUseCase(mockRepo, nil, mockAuthRepo)
+
+	anna := entity.User{ID: 7, Username: "anna"}
+	mockRepo.On("RetrieveByID", uint(7)).Return(anna, nil)
+	mockRepo.On("Delete", anna).Return(anna, nil)
+	mockAuthRepo.On("BlacklistUserTokens", "anna", 3600).Return(nil)
+	mockAuthRepo.On("DeleteUserSessions", "anna").Return(nil)
+
+	_, err := uc.DeleteUser(7, "root", mockConfig)
+
+	assert.NoError(t, err)
+}
+
+func TestUserUseCase_DeleteUser_AlreadyDeleted(t *testing.T) {
+	mockRepo := mocks.NewIUserRepo(t)
+	uc := usecases.NewUserUseCase(mockRepo, nil, nil)
+
+	deleted := entity.User{ID: 7, Username: "anna"}
+	deleted.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	mockRepo.On("RetrieveByID", uint(7)).Return(deleted, nil)
+
+	_, err := uc.DeleteUser(7, "root", &config.Config{})
+
+	assert.Equal(t, &entity.UserNotFoundError{}, err)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
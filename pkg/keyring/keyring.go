@@ -0,0 +1,339 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// A key is used to sign while it is active. The next key is published ahead of time,
+// so relying parties already know it when it becomes active. Retired keys no longer
+// sign but still verify the tokens they signed until those expire.
+const (
+	StatusActive  = "active"
+	StatusNext    = "next"
+	StatusRetired = "retired"
+)
+
+// The supported signing algorithms, named as in the alg header of the tokens
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// Key is a signing key identified by the kid header of the tokens it signs
+type Key struct {
+	ID         string
+	Status     string
+	Algorithm  string
+	PrivateKey crypto.Signer
+}
+
+// Keyring holds the signing keys, exactly one of them is active
+type Keyring struct {
+	keys   []Key
+	active Key
+}
+
+// JSONWebKey is the public part of a key as published in the JWKS, see RFC 7517 and RFC 8037
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is the document served at /.well-known/jwks.json
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// New returns a keyring of the given keys
+func New(keys ...Key) (*Keyring, error) {
+	k := &Keyring{}
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("key without id")
+		}
+		if seen[key.ID] {
+			return nil, fmt.Errorf("duplicate key id: %s", key.ID)
+		}
+		seen[key.ID] = true
+
+		if key.PrivateKey == nil {
+			return nil, fmt.Errorf("key %s has no private key", key.ID)
+		}
+		if err := checkAlgorithm(key.Algorithm, key.PrivateKey); err != nil {
+			return nil, fmt.Errorf("key %s: %w", key.ID, err)
+		}
+
+		switch key.Status {
+		case StatusActive:
+			if k.active.ID != "" {
+				return nil, fmt.Errorf("keys %s and %s are both active", k.active.ID, key.ID)
+			}
+			k.active = key
+		case StatusNext, StatusRetired:
+		default:
+			return nil, fmt.Errorf("key %s has unknown status: %s", key.ID, key.Status)
+		}
+
+		k.keys = append(k.keys, key)
+	}
+
+	if k.active.ID == "" {
+		return nil, errors.New("no active key")
+	}
+
+	return k, nil
+}
+
+// FromPrivateKey returns a keyring of a single active key, its id is the thumbprint of the key
+// and its algorithm the usual one of the key type
+func FromPrivateKey(privateKey crypto.Signer) *Keyring {
+	key := Key{
+		ID:         Thumbprint(privateKey.Public()),
+		Status:     StatusActive,
+		Algorithm:  AlgorithmFor(privateKey),
+		PrivateKey: privateKey,
+	}
+	return &Keyring{keys: []Key{key}, active: key}
+}
+
+// Active returns the key new tokens are signed with
+func (k *Keyring) Active() Key {
+	return k.active
+}
+
+// Find returns the key with the given id, whatever its status
+func (k *Keyring) Find(id string) (Key, bool) {
+	for _, key := range k.keys {
+		if key.ID == id {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// Algorithms returns the algorithms of the keys, tokens signed with any other algorithm are rejected
+func (k *Keyring) Algorithms() []string {
+	var algorithms []string
+	seen := make(map[string]bool, len(k.keys))
+	for _, key := range k.keys {
+		if !seen[key.Algorithm] {
+			seen[key.Algorithm] = true
+			algorithms = append(algorithms, key.Algorithm)
+		}
+	}
+	return algorithms
+}
+
+// JSONWebKeySet returns the public keys of every key in the keyring
+func (k *Keyring) JSONWebKeySet() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(k.keys))}
+	for _, key := range k.keys {
+		jwk := publicJSONWebKey(key.PrivateKey.Public())
+		jwk.Kid = key.ID
+		jwk.Use = "sig"
+		jwk.Alg = key.Algorithm
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// AlgorithmFor returns the usual algorithm of a key type, or an empty string for unsupported keys
+func AlgorithmFor(privateKey crypto.Signer) string {
+	switch privateKey := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return AlgorithmRS256
+	case *ecdsa.PrivateKey:
+		if privateKey.Curve == elliptic.P256() {
+			return AlgorithmES256
+		}
+	case ed25519.PrivateKey:
+		return AlgorithmEdDSA
+	}
+	return ""
+}
+
+func checkAlgorithm(algorithm string, privateKey crypto.Signer) error {
+	switch algorithm {
+	case AlgorithmRS256, AlgorithmES256, AlgorithmEdDSA:
+	default:
+		return fmt.Errorf("unsupported algorithm: %s", algorithm)
+	}
+
+	if AlgorithmFor(privateKey) != algorithm {
+		return fmt.Errorf("a %T can't sign %s", privateKey, algorithm)
+	}
+	return nil
+}
+
+// ParsePrivateKey reads a PEM encoded PKCS#1 RSA, SEC 1 EC or PKCS#8 private key
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := privateKey.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key: %T", privateKey)
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block: %s", block.Type)
+	}
+}
+
+// Thumbprint identifies a public key by the hash of its required members, see RFC 7638
+func Thumbprint(publicKey crypto.PublicKey) string {
+	jwk := publicJSONWebKey(publicKey)
+
+	// the members have to be in lexicographic order
+	var members []byte
+	switch jwk.Kty {
+	case "RSA":
+		members, _ = json.Marshal(struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N})
+	case "EC":
+		members, _ = json.Marshal(struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y})
+	default:
+		members, _ = json.Marshal(struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X})
+	}
+
+	sum := sha256.Sum256(members)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func publicJSONWebKey(publicKey crypto.PublicKey) JSONWebKey {
+	switch publicKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		return JSONWebKey{
+			Kty: "RSA",
+			N:   encode(publicKey.N.Bytes()),
+			E:   encode(big.NewInt(int64(publicKey.E)).Bytes()),
+		}
+	case *ecdsa.PublicKey:
+		// the coordinates are padded to the size of the curve
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		return JSONWebKey{
+			Kty: "EC",
+			Crv: publicKey.Curve.Params().Name,
+			X:   encode(publicKey.X.FillBytes(make([]byte, size))),
+			Y:   encode(publicKey.Y.FillBytes(make([]byte, size))),
+		}
+	case ed25519.PublicKey:
+		return JSONWebKey{Kty: "OKP", Crv: "Ed25519", X: encode(publicKey)}
+	}
+	return JSONWebKey{}
+}
+
+// PublicKey reads the public key of a JSON Web Key, such as one of the JWKS of another issuer
+func (k JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA key")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		publicKey := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, errors.New("invalid EC key")
+		}
+		return publicKey, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON Web Key: %w", err)
+	}
+	return b, nil
+}
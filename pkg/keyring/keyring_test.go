@@ -0,0 +1,275 @@
+package keyring_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/pkg/keyring"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	return privateKey
+}
+
+func TestNew(t *testing.T) {
+	active, next, retired := generateKey(t), generateKey(t), generateKey(t)
+
+	k, err := keyring.New(
+		keyring.Key{ID: "2026-09", Status: keyring.StatusRetired, Algorithm: keyring.AlgorithmRS256, PrivateKey: retired},
+		keyring.Key{ID: "2026-10", Status: keyring.StatusActive, Algorithm: keyring.AlgorithmRS256, PrivateKey: active},
+		keyring.Key{ID: "2026-11", Status: keyring.StatusNext, Algorithm: keyring.AlgorithmRS256, PrivateKey: next},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-10", k.Active().ID)
+
+	key, found := k.Find("2026-09")
+	assert.True(t, found)
+	assert.Equal(t, retired, key.PrivateKey)
+
+	_, found = k.Find("2026-08")
+	assert.False(t, found)
+}
+
+func TestNew_Invalid(t *testing.T) {
+	privateKey := generateKey(t)
+
+	tests := []struct {
+		name string
+		keys []keyring.Key
+		err  string
+	}{
+		{"no keys", nil, "no active key"},
+		{"no active key", []keyring.Key{{ID: "a", Status: keyring.StatusNext, Algorithm: keyring.AlgorithmRS256, PrivateKey: privateKey}}, "no active key"},
+		{"two active keys", []keyring.Key{
+			{ID: "a", Status: keyring.StatusActive, Algorithm: keyring.AlgorithmRS256, PrivateKey: privateKey},
+			{ID: "b", Status: keyring.StatusActive, Algorithm: keyring.AlgorithmRS256, PrivateKey: privateKey},
+		}, "keys a and b are both active"},
+		{"duplicate id", []keyring.Key{
+			{ID: "a", Status: keyring.StatusActive, Algorithm: keyring.AlgorithmRS256, PrivateKey: privateKey},
+			{ID: "a", Status: keyring.StatusRetired, Algorithm: keyring.AlgorithmRS256, PrivateKey: privateKey},
+		}, "duplicate key id: a"},
+		{"missing id", []keyring.Key{{Status: keyring.StatusActive, Algorithm: keyring.AlgorithmRS256, PrivateKey: privateKey}}, "key without id"},
+		{"missing private key", []keyring.Key{{ID: "a", Status: keyring.StatusActive}}, "key a has no private key"},
+		{"unknown status", []keyring.Key{{ID: "a", Status: "disabled", Algorithm: keyring.AlgorithmRS256, PrivateKey: privateKey}}, "key a has unknown status: disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := keyring.New(tt.keys...)
+
+			assert.Nil(t, k)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestFromPrivateKey(t *testing.T) {
+	privateKey := generateKey(t)
+
+	k := keyring.FromPrivateKey(privateKey)
+
+	assert.Equal(t, keyring.Thumbprint(&privateKey.PublicKey), k.Active().ID)
+	assert.Equal(t, keyring.StatusActive, k.Active().Status)
+}
+
+func TestThumbprint(t *testing.T) {
+	// the example key of RFC 7638 section 3.1
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	assert.NoError(t, err)
+	publicKey := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", keyring.Thumbprint(publicKey))
+}
+
+func TestJSONWebKeySet(t *testing.T) {
+	active, retired := generateKey(t), generateKey(t)
+	k, err := keyring.New(
+		keyring.Key{ID: "new", Status: keyring.StatusActive, Algorithm: keyring.AlgorithmRS256, PrivateKey: active},
+		keyring.Key{ID: "old", Status: keyring.StatusRetired, Algorithm: keyring.AlgorithmRS256, PrivateKey: retired},
+	)
+	assert.NoError(t, err)
+
+	set := k.JSONWebKeySet()
+
+	assert.Len(t, set.Keys, 2)
+	assert.Equal(t, "new", set.Keys[0].Kid)
+	assert.Equal(t, "old", set.Keys[1].Kid)
+	for _, key := range set.Keys {
+		assert.Equal(t, "RSA", key.Kty)
+		assert.Equal(t, "RS256", key.Alg)
+		assert.Equal(t, "sig", key.Use)
+		assert.Equal(t, "AQAB", key.E)
+	}
+}
+
+func TestNew_AlgorithmOfKey(t *testing.T) {
+	rsaKey := generateKey(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		algorithm  string
+		privateKey crypto.Signer
+		err        string
+	}{
+		{"rsa", keyring.AlgorithmRS256, rsaKey, ""},
+		{"ecdsa", keyring.AlgorithmES256, ecKey, ""},
+		{"ed25519", keyring.AlgorithmEdDSA, edKey, ""},
+		{"rsa key for ES256", keyring.AlgorithmES256, rsaKey, "key a: a *rsa.PrivateKey can't sign ES256"},
+		{"P-384 key for ES256", keyring.AlgorithmES256, p384Key, "key a: a *ecdsa.PrivateKey can't sign ES256"},
+		{"ed25519 key for RS256", keyring.AlgorithmRS256, edKey, "key a: a ed25519.PrivateKey can't sign RS256"},
+		{"no algorithm", "", rsaKey, "key a: unsupported algorithm: "},
+		{"symmetric algorithm", "HS256", rsaKey, "key a: unsupported algorithm: HS256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := keyring.New(keyring.Key{ID: "a", Status: keyring.StatusActive, Algorithm: tt.algorithm, PrivateKey: tt.privateKey})
+
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	rsaKey := generateKey(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	sec1, err := x509.MarshalECPrivateKey(ecKey)
+	assert.NoError(t, err)
+	pkcs8RSA, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	assert.NoError(t, err)
+	pkcs8EC, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	assert.NoError(t, err)
+	pkcs8Ed, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		block    *pem.Block
+		expected crypto.Signer
+	}{
+		{"PKCS#1 RSA", &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}, rsaKey},
+		{"SEC 1 EC", &pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}, ecKey},
+		{"PKCS#8 RSA", &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8RSA}, rsaKey},
+		{"PKCS#8 EC", &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8EC}, ecKey},
+		{"PKCS#8 Ed25519", &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Ed}, edKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey, err := keyring.ParsePrivateKey(pem.EncodeToMemory(tt.block))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.Public(), privateKey.Public())
+		})
+	}
+}
+
+func TestParsePrivateKey_Invalid(t *testing.T) {
+	_, err := keyring.ParsePrivateKey([]byte("not a key"))
+	assert.EqualError(t, err, "no PEM data found")
+
+	_, err = keyring.ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte{1}}))
+	assert.EqualError(t, err, "unsupported PEM block: PUBLIC KEY")
+}
+
+func TestThumbprint_Ed25519(t *testing.T) {
+	// the example key of RFC 8037 appendix A.3
+	x, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", keyring.Thumbprint(ed25519.PublicKey(x)))
+}
+
+func TestJSONWebKeySet_EllipticCurves(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	edPublicKey, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	k, err := keyring.New(
+		keyring.Key{ID: "ec", Status: keyring.StatusActive, Algorithm: keyring.AlgorithmES256, PrivateKey: ecKey},
+		keyring.Key{ID: "ed", Status: keyring.StatusNext, Algorithm: keyring.AlgorithmEdDSA, PrivateKey: edKey},
+	)
+	assert.NoError(t, err)
+
+	set := k.JSONWebKeySet()
+
+	assert.Equal(t, []string{keyring.AlgorithmES256, keyring.AlgorithmEdDSA}, k.Algorithms())
+	assert.Equal(t, "EC", set.Keys[0].Kty)
+	assert.Equal(t, "P-256", set.Keys[0].Crv)
+	assert.Equal(t, "ES256", set.Keys[0].Alg)
+	// the coordinates are always 32 bytes long
+	assert.Len(t, set.Keys[0].X, 43)
+	assert.Len(t, set.Keys[0].Y, 43)
+	assert.Empty(t, set.Keys[0].N)
+	assert.Equal(t, keyring.JSONWebKey{
+		Kty: "OKP",
+		Kid: "ed",
+		Use: "sig",
+		Alg: "EdDSA",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(edPublicKey),
+	}, set.Keys[1])
+}
+
+func TestJSONWebKey_PublicKey(t *testing.T) {
+	rsaKey := generateKey(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	k, err := keyring.New(
+		keyring.Key{ID: "rsa", Status: keyring.StatusActive, Algorithm: keyring.AlgorithmRS256, PrivateKey: rsaKey},
+		keyring.Key{ID: "ec", Status: keyring.StatusNext, Algorithm: keyring.AlgorithmES256, PrivateKey: ecKey},
+		keyring.Key{ID: "ed", Status: keyring.StatusNext, Algorithm: keyring.AlgorithmEdDSA, PrivateKey: edKey},
+	)
+	assert.NoError(t, err)
+
+	// the keys of a published set read back as the public keys they were made of
+	for i, want := range []crypto.PublicKey{rsaKey.Public(), ecKey.Public(), edKey.Public()} {
+		publicKey, err := k.JSONWebKeySet().Keys[i].PublicKey()
+		assert.NoError(t, err)
+		assert.True(t, publicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(want))
+	}
+}
+
+func TestJSONWebKey_PublicKey_Invalid(t *testing.T) {
+	for _, jwk := range []keyring.JSONWebKey{
+		{Kty: "oct"},
+		{Kty: "RSA", N: "not base64!", E: "AQAB"},
+		{Kty: "EC", Crv: "P-256", X: "AQ", Y: "AQ"},
+		{Kty: "EC", Crv: "secp256k1", X: "AQ", Y: "AQ"},
+		{Kty: "OKP", Crv: "Ed25519", X: "AQ"},
+	} {
+		_, err := jwk.PublicKey()
+		assert.Error(t, err, jwk.Kty)
+	}
+}
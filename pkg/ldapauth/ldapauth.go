@@ -0,0 +1,204 @@
+// Package ldapauth checks passwords against an LDAP directory such as OpenLDAP or lldap
+package ldapauth
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ErrInvalidCredentials is returned when the username is unknown, ambiguous or the password is wrong
+var ErrInvalidCredentials = errors.New("ldapauth: invalid credentials")
+
+// Config describes where the users and groups live in the directory. {username} in the user filter
+// is replaced by the username, {dn} and {username} in the group filter by the user found.
+type Config struct {
+	URL               string
+	StartTLS          bool
+	BindDN            string
+	BindPassword      string
+	BaseDN            string
+	UserFilter        string
+	IDAttribute       string
+	UsernameAttribute string
+	EmailAttribute    string
+	GroupBaseDN       string
+	GroupFilter       string
+	Timeout           time.Duration
+}
+
+// Entry is a user of the directory. ID is the value of the id attribute, such as entryUUID,
+// it falls back to the DN which changes when the user is renamed.
+type Entry struct {
+	ID       string
+	DN       string
+	Username string
+	Email    string
+	Groups   []string
+}
+
+type Directory struct {
+	config Config
+}
+
+// New creates the client of the directory. It doesn't connect until the first password is checked.
+func New(config Config) *Directory {
+	if config.UserFilter == "" {
+		config.UserFilter = "(&(objectClass=person)(uid={username}))"
+	}
+	if config.IDAttribute == "" {
+		config.IDAttribute = "entryUUID"
+	}
+	if config.UsernameAttribute == "" {
+		config.UsernameAttribute = "uid"
+	}
+	if config.EmailAttribute == "" {
+		config.EmailAttribute = "mail"
+	}
+	if config.GroupBaseDN == "" {
+		config.GroupBaseDN = config.BaseDN
+	}
+	if config.GroupFilter == "" {
+		config.GroupFilter = "(member={dn})"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	return &Directory{config: config}
+}
+
+// Authenticate looks the user up with the service account and binds as the user to check the password.
+// The groups are looked up with the service account again, the user may not be allowed to read them.
+func (d *Directory) Authenticate(username string, password string) (*Entry, error) {
+	// a simple bind without a password is an anonymous bind, which most directories let through
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := d.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := d.bindServiceAccount(conn); err != nil {
+		return nil, err
+	}
+
+	entry, err := d.findUser(conn, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to bind as user: %w", err)
+	}
+
+	if err := d.bindServiceAccount(conn); err != nil {
+		return nil, err
+	}
+
+	entry.Groups, err = d.findGroups(conn, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (d *Directory) dial() (*ldap.Conn, error) {
+	dialer := &net.Dialer{Timeout: d.config.Timeout}
+	conn, err := ldap.DialURL(d.config.URL, ldap.DialWithDialer(dialer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to directory: %w", err)
+	}
+	conn.SetTimeout(d.config.Timeout)
+
+	if d.config.StartTLS {
+		u, err := url.Parse(d.config.URL)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("invalid directory url: %w", err)
+		}
+		if err := conn.StartTLS(&tls.Config{ServerName: u.Hostname()}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	return conn, nil
+}
+
+func (d *Directory) bindServiceAccount(conn *ldap.Conn) error {
+	if d.config.BindDN == "" {
+		return nil
+	}
+
+	if err := conn.Bind(d.config.BindDN, d.config.BindPassword); err != nil {
+		return fmt.Errorf("failed to bind as service account: %w", err)
+	}
+	return nil
+}
+
+func (d *Directory) findUser(conn *ldap.Conn, username string) (*Entry, error) {
+	filter := strings.ReplaceAll(d.config.UserFilter, "{username}", ldap.EscapeFilter(username))
+
+	// two results are enough to tell the username is ambiguous
+	result, err := conn.Search(ldap.NewSearchRequest(
+		d.config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false, filter,
+		[]string{d.config.IDAttribute, d.config.UsernameAttribute, d.config.EmailAttribute}, nil,
+	))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("failed to search user: %w", err)
+	}
+	if result == nil || len(result.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+
+	found := result.Entries[0]
+	entry := &Entry{
+		ID:       found.GetAttributeValue(d.config.IDAttribute),
+		DN:       found.DN,
+		Username: found.GetAttributeValue(d.config.UsernameAttribute),
+		Email:    found.GetAttributeValue(d.config.EmailAttribute),
+	}
+	if entry.ID == "" {
+		entry.ID = entry.DN
+	}
+	if entry.Username == "" {
+		entry.Username = username
+	}
+
+	return entry, nil
+}
+
+func (d *Directory) findGroups(conn *ldap.Conn, entry *Entry) ([]string, error) {
+	filter := strings.NewReplacer(
+		"{dn}", ldap.EscapeFilter(entry.DN),
+		"{username}", ldap.EscapeFilter(entry.Username),
+	).Replace(d.config.GroupFilter)
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		d.config.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, filter,
+		[]string{"dn"}, nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search groups: %w", err)
+	}
+
+	groups := make([]string, 0, len(result.Entries))
+	for _, group := range result.Entries {
+		groups = append(groups, group.DN)
+	}
+	return groups, nil
+}
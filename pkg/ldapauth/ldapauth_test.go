@@ -0,0 +1,114 @@
+package ldapauth_test
+
+import (
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/pkg/ldapauth"
+	"github.com/minhmannh2001/authconnecthub/pkg/ldapauth/ldaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	baseDN    = "dc=home,dc=example"
+	serviceDN = "cn=hub,ou=services,dc=home,dc=example"
+	annaDN    = "uid=anna,ou=people,dc=home,dc=example"
+)
+
+func newDirectory(t *testing.T) (*ldaptest.Server, *ldapauth.Directory) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: serviceDN, Password: "service-secret"},
+		ldaptest.Entry{DN: annaDN, Password: "anna-secret", Attributes: map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"anna"},
+			"mail":        {"anna@home.example"},
+			"entryUUID":   {"5f0c1d2e-0000-4000-8000-000000000001"},
+		}},
+		ldaptest.Entry{DN: "cn=family,ou=groups,dc=home,dc=example", Attributes: map[string][]string{
+			"member": {annaDN},
+		}},
+		ldaptest.Entry{DN: "cn=admins,ou=groups,dc=home,dc=example", Attributes: map[string][]string{
+			"member": {"uid=bob,ou=people,dc=home,dc=example"},
+		}},
+	)
+
+	directory := ldapauth.New(ldapauth.Config{
+		URL:          server.URL,
+		BindDN:       serviceDN,
+		BindPassword: "service-secret",
+		BaseDN:       baseDN,
+		GroupBaseDN:  "ou=groups," + baseDN,
+	})
+
+	return server, directory
+}
+
+func TestDirectory_Authenticate(t *testing.T) {
+	_, directory := newDirectory(t)
+
+	entry, err := directory.Authenticate("anna", "anna-secret")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ldapauth.Entry{
+		ID:       "5f0c1d2e-0000-4000-8000-000000000001",
+		DN:       annaDN,
+		Username: "anna",
+		Email:    "anna@home.example",
+		Groups:   []string{"cn=family,ou=groups,dc=home,dc=example"},
+	}, entry)
+}
+
+func TestDirectory_Authenticate_Rejected(t *testing.T) {
+	cases := []struct {
+		name     string
+		username string
+		password string
+	}{
+		{name: "Wrong password", username: "anna", password: "wrong"},
+		{name: "Unknown user", username: "carol", password: "anna-secret"},
+		{name: "Empty password", username: "anna", password: ""},
+		{name: "Filter injection", username: "*", password: "anna-secret"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, directory := newDirectory(t)
+
+			entry, err := directory.Authenticate(tc.username, tc.password)
+
+			assert.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+			assert.Nil(t, entry)
+		})
+	}
+}
+
+func TestDirectory_Authenticate_Ambiguous(t *testing.T) {
+	server, directory := newDirectory(t)
+	server.Add(ldaptest.Entry{DN: "uid=anna,ou=guests,dc=home,dc=example", Password: "anna-secret", Attributes: map[string][]string{
+		"objectClass": {"person"},
+		"uid":         {"anna"},
+	}})
+
+	_, err := directory.Authenticate("anna", "anna-secret")
+
+	assert.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+}
+
+func TestDirectory_Authenticate_WrongServicePassword(t *testing.T) {
+	server, _ := newDirectory(t)
+	directory := ldapauth.New(ldapauth.Config{URL: server.URL, BindDN: serviceDN, BindPassword: "wrong", BaseDN: baseDN})
+
+	_, err := directory.Authenticate("anna", "anna-secret")
+
+	assert.ErrorContains(t, err, "failed to bind as service account")
+	assert.NotErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+}
+
+func TestDirectory_Authenticate_Unreachable(t *testing.T) {
+	server, _ := newDirectory(t)
+	server.Close()
+	directory := ldapauth.New(ldapauth.Config{URL: server.URL, BindDN: serviceDN, BindPassword: "service-secret", BaseDN: baseDN})
+
+	_, err := directory.Authenticate("anna", "anna-secret")
+
+	assert.ErrorContains(t, err, "failed to connect to directory")
+}
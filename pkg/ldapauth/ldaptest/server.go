@@ -0,0 +1,358 @@
+// Package ldaptest runs an LDAP directory in the tests of its clients. It speaks just enough
+// of the protocol for simple binds and searches.
+package ldaptest
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+)
+
+// Application tags and result codes of RFC 4511
+const (
+	bindRequest      = 0
+	bindResponse     = 1
+	unbindRequest    = 2
+	searchRequest    = 3
+	searchResultItem = 4
+	searchResultDone = 5
+	extendedResponse = 24
+
+	resultSuccess                 = 0
+	resultProtocolError           = 2
+	resultSizeLimitExceeded       = 4
+	resultNoSuchObject            = 32
+	resultInvalidCredentials      = 49
+	resultInsufficientAccessRight = 50
+)
+
+// Entry is an object of the directory. A password lets it be bound to.
+type Entry struct {
+	DN         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// Server is a directory holding the entries the test gives it. Like most directories, a bind without
+// a password is an anonymous bind, and anonymous clients can't search.
+type Server struct {
+	URL string
+
+	listener net.Listener
+	mutex    sync.Mutex
+	entries  []Entry
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewServer starts the directory, it is closed when the test ends
+func NewServer(t *testing.T, entries ...Entry) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		URL:      "ldap://" + listener.Addr().String(),
+		listener: listener,
+		entries:  entries,
+		conns:    map[net.Conn]struct{}{},
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Add puts another entry into the directory
+func (s *Server) Add(entry Entry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.entries = append(s.entries, entry)
+}
+
+// Close stops the directory and drops the clients still connected
+func (s *Server) Close() {
+	_ = s.listener.Close()
+
+	s.mutex.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mutex.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mutex.Lock()
+		s.conns[conn] = struct{}{}
+		s.mutex.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.conns, conn)
+		s.mutex.Unlock()
+		_ = conn.Close()
+	}()
+
+	boundDN := ""
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		messageID, _ := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+
+		switch op.Tag {
+		case bindRequest:
+			var code int
+			code, boundDN = s.bind(op)
+			if !s.write(conn, messageID, result(bindResponse, code)) {
+				return
+			}
+		case searchRequest:
+			if !s.search(conn, messageID, op, boundDN) {
+				return
+			}
+		case unbindRequest:
+			return
+		default:
+			// extended operations such as StartTLS aren't supported
+			if !s.write(conn, messageID, result(extendedResponse, resultProtocolError)) {
+				return
+			}
+		}
+	}
+}
+
+// bind returns the result code and the DN the connection is bound to, which is empty when anonymous
+func (s *Server) bind(op *ber.Packet) (int, string) {
+	if len(op.Children) < 3 || op.Children[2].Tag != 0 {
+		return resultProtocolError, ""
+	}
+
+	dn := primitiveString(op.Children[1])
+	password := primitiveString(op.Children[2])
+	if password == "" {
+		return resultSuccess, ""
+	}
+
+	entry := s.find(dn)
+	if entry == nil || entry.Password == "" || entry.Password != password {
+		return resultInvalidCredentials, ""
+	}
+	return resultSuccess, dn
+}
+
+func (s *Server) search(conn net.Conn, messageID int64, op *ber.Packet, boundDN string) bool {
+	if len(op.Children) < 8 {
+		return s.write(conn, messageID, result(searchResultDone, resultProtocolError))
+	}
+	if boundDN == "" {
+		return s.write(conn, messageID, result(searchResultDone, resultInsufficientAccessRight))
+	}
+
+	baseDN := normalizeDN(primitiveString(op.Children[0]))
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+
+	var attributes []string
+	for _, attribute := range op.Children[7].Children {
+		attributes = append(attributes, primitiveString(attribute))
+	}
+
+	s.mutex.Lock()
+	entries := append([]Entry(nil), s.entries...)
+	s.mutex.Unlock()
+
+	if s.find(baseDN) == nil && !hasEntriesBelow(entries, baseDN) {
+		return s.write(conn, messageID, result(searchResultDone, resultNoSuchObject))
+	}
+
+	sent := int64(0)
+	for _, entry := range entries {
+		if !inScope(normalizeDN(entry.DN), baseDN, scope) || !matches(entry, filter) {
+			continue
+		}
+		if sizeLimit > 0 && sent == sizeLimit {
+			return s.write(conn, messageID, result(searchResultDone, resultSizeLimitExceeded))
+		}
+		if !s.write(conn, messageID, searchEntry(entry, attributes)) {
+			return false
+		}
+		sent++
+	}
+
+	return s.write(conn, messageID, result(searchResultDone, resultSuccess))
+}
+
+func (s *Server) find(dn string) *Entry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.entries {
+		if normalizeDN(s.entries[i].DN) == normalizeDN(dn) {
+			return &s.entries[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) write(conn net.Conn, messageID int64, op *ber.Packet) bool {
+	packet := ber.NewSequence("LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	packet.AppendChild(op)
+
+	_, err := conn.Write(packet.Bytes())
+	return err == nil
+}
+
+func result(tag ber.Tag, code int) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return op
+}
+
+func searchEntry(entry Entry, requested []string) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, searchResultItem, nil, "Search Result Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "DN"))
+
+	attributes := ber.NewSequence("Attributes")
+	for name, values := range entry.Attributes {
+		if !isRequested(name, requested) {
+			continue
+		}
+
+		attribute := ber.NewSequence("Attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+		attribute.AppendChild(set)
+		attributes.AppendChild(attribute)
+	}
+	op.AppendChild(attributes)
+
+	return op
+}
+
+func isRequested(name string, requested []string) bool {
+	if len(requested) == 0 {
+		return true
+	}
+	for _, r := range requested {
+		if r == "*" || strings.EqualFold(r, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches evaluates the filters the clients of the hub send, the others never match
+func matches(entry Entry, filter *ber.Packet) bool {
+	switch filter.Tag {
+	case 0: // and
+		for _, child := range filter.Children {
+			if !matches(entry, child) {
+				return false
+			}
+		}
+		return true
+	case 1: // or
+		for _, child := range filter.Children {
+			if matches(entry, child) {
+				return true
+			}
+		}
+		return false
+	case 2: // not
+		return len(filter.Children) == 1 && !matches(entry, filter.Children[0])
+	case 3: // equality match
+		if len(filter.Children) != 2 {
+			return false
+		}
+		name := primitiveString(filter.Children[0])
+		value := primitiveString(filter.Children[1])
+		for _, v := range attributeValues(entry, name) {
+			if strings.EqualFold(v, value) || (strings.Contains(v, "=") && normalizeDN(v) == normalizeDN(value)) {
+				return true
+			}
+		}
+		return false
+	case 7: // present
+		return len(attributeValues(entry, primitiveString(filter))) > 0
+	}
+	return false
+}
+
+func attributeValues(entry Entry, name string) []string {
+	for attribute, values := range entry.Attributes {
+		if strings.EqualFold(attribute, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func inScope(dn string, baseDN string, scope int64) bool {
+	switch scope {
+	case 0: // base object
+		return dn == baseDN
+	case 1: // single level
+		_, parent, _ := strings.Cut(dn, ",")
+		return parent == baseDN
+	default: // whole subtree
+		return dn == baseDN || strings.HasSuffix(dn, ","+baseDN)
+	}
+}
+
+func hasEntriesBelow(entries []Entry, baseDN string) bool {
+	for _, entry := range entries {
+		if inScope(normalizeDN(entry.DN), baseDN, 2) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeDN(dn string) string {
+	parts := strings.Split(dn, ",")
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ",")
+}
+
+func primitiveString(p *ber.Packet) string {
+	if s, ok := p.Value.(string); ok {
+		return s
+	}
+	return p.Data.String()
+}
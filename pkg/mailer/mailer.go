@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+)
+
+const (
+	DriverFile   = "file"
+	DriverStdout = "stdout"
+)
+
+// Message is a plain text email
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails through the configured driver
+type Mailer interface {
+	Send(Message) error
+}
+
+// New returns the mailer matching the configured driver
+func New(cfg *config.Config) (Mailer, error) {
+	switch cfg.Mail.Driver {
+	case DriverFile:
+		return NewFileMailer(cfg.Mail.Dir, cfg.Mail.From)
+	case DriverStdout:
+		return NewStdoutMailer(os.Stdout, cfg.Mail.From), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s", cfg.Mail.Driver)
+	}
+}
+
+func (m Message) String() string {
+	return fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s", m.From, m.To, m.Subject, m.Body)
+}
+
+// mockery --dir=./pkg/mailer --output=./pkg/mailer/mocks --outpkg=mocks --all
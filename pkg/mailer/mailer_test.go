@@ -0,0 +1,78 @@
+package mailer_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minhmannh2001/authconnecthub/config"
+	"github.com/minhmannh2001/authconnecthub/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_FileDriver(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mails")
+
+	m, err := mailer.New(&config.Config{Mail: config.Mail{Driver: mailer.DriverFile, Dir: dir}})
+
+	assert.NoError(t, err)
+	assert.IsType(t, &mailer.FileMailer{}, m)
+	assert.DirExists(t, dir)
+}
+
+func TestNew_StdoutDriver(t *testing.T) {
+	m, err := mailer.New(&config.Config{Mail: config.Mail{Driver: mailer.DriverStdout}})
+
+	assert.NoError(t, err)
+	assert.IsType(t, &mailer.StdoutMailer{}, m)
+}
+
+func TestNew_UnsupportedDriver(t *testing.T) {
+	m, err := mailer.New(&config.Config{Mail: config.Mail{Driver: "pigeon"}})
+
+	assert.Nil(t, m)
+	assert.EqualError(t, err, "unsupported mail driver: pigeon")
+}
+
+func TestNewFileMailer_MissingDir(t *testing.T) {
+	m, err := mailer.NewFileMailer("", "")
+
+	assert.Nil(t, m)
+	assert.Error(t, err)
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := t.TempDir()
+	m, err := mailer.NewFileMailer(dir, "hub@localhost")
+	assert.NoError(t, err)
+
+	err = m.Send(mailer.Message{To: "user@localhost", Subject: "Hello", Body: "World"})
+	assert.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), ".eml"))
+
+	content, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "From: hub@localhost")
+	assert.Contains(t, string(content), "To: user@localhost")
+	assert.Contains(t, string(content), "Subject: Hello")
+	assert.True(t, strings.HasSuffix(string(content), "\r\n\r\nWorld"))
+}
+
+func TestStdoutMailer_Send(t *testing.T) {
+	var buf bytes.Buffer
+	m := mailer.NewStdoutMailer(&buf, "hub@localhost")
+
+	err := m.Send(mailer.Message{To: "user@localhost", Subject: "Hello", Body: "World"})
+	assert.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "From: hub@localhost")
+	assert.Contains(t, buf.String(), "To: user@localhost")
+	assert.Contains(t, buf.String(), "Subject: Hello")
+	assert.Contains(t, buf.String(), "\r\n\r\nWorld")
+}
@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// StdoutMailer prints every message instead of delivering it, handy for local development
+type StdoutMailer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+func NewStdoutMailer(w io.Writer, from string) *StdoutMailer {
+	return &StdoutMailer{w: w, from: from}
+}
+
+func (m *StdoutMailer) Send(msg Message) error {
+	if msg.From == "" {
+		msg.From = m.from
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.w, "----- mail -----\r\n%s\r\n----------------\r\n", msg.String())
+	if err != nil {
+		return fmt.Errorf("failed to print mail: %w", err)
+	}
+
+	return nil
+}
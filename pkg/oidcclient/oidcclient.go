@@ -0,0 +1,348 @@
+// Package oidcclient signs users in with an upstream OpenID Connect provider using the
+// authorization code flow with PKCE, see OpenID Connect Core section 3.1.
+package oidcclient
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/minhmannh2001/authconnecthub/pkg/keyring"
+)
+
+// algorithms are the signing algorithms of ID tokens which are accepted
+var algorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Provider is an upstream OpenID Connect provider. Its metadata and keys are fetched the first
+// time they are needed, the keys again when a token is signed with a key they don't have.
+type Provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+	httpClient   *http.Client
+
+	mutex    sync.Mutex
+	metadata *metadata
+	keys     map[string]crypto.PublicKey
+}
+
+// Identity is who the provider says signed in
+type Identity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce"`
+	AuthorizedParty   string `json:"azp"`
+	Email             string `json:"email"`
+	EmailVerified     any    `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+// New returns the provider of the issuer. The scopes default to openid, email and profile.
+func New(issuer string, clientID string, clientSecret string, redirectURL string, scopes []string) *Provider {
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Provider{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		scopes:       scopes,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AuthCodeURL returns the url of the authorization endpoint the browser is sent to
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	m, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.clientID},
+		"redirect_uri":          {p.redirectURL},
+		"scope":                 {strings.Join(p.scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(m.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return m.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange redeems the code returned to the redirect url and verifies the ID token it is redeemed for.
+// The email is asked from the userinfo endpoint when the ID token doesn't have it.
+func (p *Provider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*Identity, error) {
+	m, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.redirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+
+	var tokens tokenResponse
+	if err := p.do(req, &tokens); err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("token response has no id token")
+	}
+
+	c, err := p.verifyIDToken(ctx, tokens.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Issuer:            c.Issuer,
+		Subject:           c.Subject,
+		Email:             c.Email,
+		EmailVerified:     isTrue(c.EmailVerified),
+		Name:              c.Name,
+		PreferredUsername: c.PreferredUsername,
+	}
+
+	if identity.Email == "" && m.UserinfoEndpoint != "" && tokens.AccessToken != "" {
+		if err := p.userInfo(ctx, m.UserinfoEndpoint, tokens.AccessToken, identity); err != nil {
+			return nil, err
+		}
+	}
+
+	return identity, nil
+}
+
+func (p *Provider) verifyIDToken(ctx context.Context, idToken string, nonce string) (*claims, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(idToken, &c, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods(algorithms),
+		jwt.WithIssuer(p.issuer),
+		jwt.WithAudience(p.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if c.Subject == "" {
+		return nil, errors.New("invalid id token: missing subject")
+	}
+	if c.Nonce != nonce {
+		return nil, errors.New("invalid id token: nonce mismatch")
+	}
+	// a token for several audiences has to name who it was issued to
+	if (len(c.Audience) > 1 || c.AuthorizedParty != "") && c.AuthorizedParty != p.clientID {
+		return nil, errors.New("invalid id token: issued to another party")
+	}
+
+	return &c, nil
+}
+
+func (p *Provider) userInfo(ctx context.Context, endpoint string, accessToken string, identity *Identity) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	var info struct {
+		Subject           string `json:"sub"`
+		Email             string `json:"email"`
+		EmailVerified     any    `json:"email_verified"`
+		Name              string `json:"name"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	if err := p.do(req, &info); err != nil {
+		return fmt.Errorf("userinfo request failed: %w", err)
+	}
+
+	// the answer could be about someone else, see OpenID Connect Core section 5.3.2
+	if info.Subject != identity.Subject {
+		return errors.New("userinfo subject doesn't match the id token")
+	}
+
+	identity.Email = info.Email
+	identity.EmailVerified = isTrue(info.EmailVerified)
+	if identity.Name == "" {
+		identity.Name = info.Name
+	}
+	if identity.PreferredUsername == "" {
+		identity.PreferredUsername = info.PreferredUsername
+	}
+	return nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var m metadata
+	if err := p.do(req, &m); err != nil {
+		return nil, fmt.Errorf("discovery failed: %w", err)
+	}
+	// a provider answering for another issuer could hand out anyone's identity, see OpenID Connect Discovery section 4.3
+	if strings.TrimSuffix(m.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("discovery failed: issuer %q doesn't match", m.Issuer)
+	}
+	if m.AuthorizationEndpoint == "" || m.TokenEndpoint == "" || m.JwksURI == "" {
+		return nil, errors.New("discovery failed: missing endpoints")
+	}
+
+	p.metadata = &m
+	return p.metadata, nil
+}
+
+// key returns the public key with the kid, the keys are fetched again when it is unknown.
+// A token without a kid can only be verified when the provider has a single key.
+func (p *Provider) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if key := findKey(p.keys, kid); key != nil {
+		return key, nil
+	}
+
+	keys, err := p.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+
+	if key := findKey(p.keys, kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (p *Provider) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.metadata.JwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set keyring.JSONWebKeySet
+	if err := p.do(req, &set); err != nil {
+		return nil, fmt.Errorf("jwks request failed: %w", err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// keys of unsupported types may be published alongside, they are of no use here
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (p *Provider) do(req *http.Request, v any) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+func findKey(keys map[string]crypto.PublicKey, kid string) crypto.PublicKey {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+	return keys[kid]
+}
+
+// isTrue reads email_verified, which some providers send as a string
+func isTrue(v any) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package oidcclient_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/minhmannh2001/authconnecthub/pkg/oidcclient"
+	"github.com/minhmannh2001/authconnecthub/pkg/oidcclient/oidctest"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	redirectURL  = "http://localhost:8080/v1/auth/federation/callback"
+	codeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+func codeChallenge() string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	provider := oidcclient.New(issuer.URL, "hub", "hub-secret", redirectURL, nil)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", codeChallenge())
+
+	assert.NoError(t, err)
+	u, err := url.Parse(authURL)
+	assert.NoError(t, err)
+	assert.Equal(t, issuer.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"hub"},
+		"redirect_uri":          {redirectURL},
+		"scope":                 {"openid email profile"},
+		"state":                 {"state-1"},
+		"nonce":                 {"nonce-1"},
+		"code_challenge":        {codeChallenge()},
+		"code_challenge_method": {"S256"},
+	}, u.Query())
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	provider := oidcclient.New(issuer.URL, "hub", "hub-secret", redirectURL, nil)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", codeChallenge())
+	assert.NoError(t, err)
+	code, state, err := issuer.Login(authURL, jwt.MapClaims{"sub": "u-42", "email": "anna@example.com", "email_verified": "true", "preferred_username": "anna"})
+	assert.NoError(t, err)
+	assert.Equal(t, "state-1", state)
+
+	identity, err := provider.Exchange(context.Background(), code, codeVerifier, "nonce-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &oidcclient.Identity{
+		Issuer:            issuer.URL,
+		Subject:           "u-42",
+		Email:             "anna@example.com",
+		EmailVerified:     true,
+		PreferredUsername: "anna",
+	}, identity)
+
+	// every code can only be redeemed once
+	_, err = provider.Exchange(context.Background(), code, codeVerifier, "nonce-1")
+	assert.ErrorContains(t, err, "invalid_grant")
+}
+
+func TestProvider_Exchange_UserInfo(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	issuer.UserInfo = map[string]interface{}{"sub": "u-42", "email": "anna@example.com", "email_verified": true, "name": "Anna"}
+	provider := oidcclient.New(issuer.URL, "hub", "hub-secret", redirectURL, nil)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", codeChallenge())
+	assert.NoError(t, err)
+	code, _, err := issuer.Login(authURL, jwt.MapClaims{"sub": "u-42"})
+	assert.NoError(t, err)
+
+	identity, err := provider.Exchange(context.Background(), code, codeVerifier, "nonce-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "anna@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, "Anna", identity.Name)
+}
+
+func TestProvider_Exchange_Rejected(t *testing.T) {
+	cases := []struct {
+		name          string
+		claims        jwt.MapClaims
+		codeVerifier  string
+		nonce         string
+		expectedError string
+	}{
+		{
+			name:          "Wrong code verifier",
+			claims:        jwt.MapClaims{"sub": "u-42"},
+			codeVerifier:  "another-verifier",
+			nonce:         "nonce-1",
+			expectedError: "invalid_grant",
+		},
+		{
+			name:          "Wrong nonce",
+			claims:        jwt.MapClaims{"sub": "u-42"},
+			codeVerifier:  codeVerifier,
+			nonce:         "nonce-2",
+			expectedError: "nonce mismatch",
+		},
+		{
+			name:          "Another issuer",
+			claims:        jwt.MapClaims{"sub": "u-42", "iss": "https://evil.example.com"},
+			codeVerifier:  codeVerifier,
+			nonce:         "nonce-1",
+			expectedError: "invalid id token",
+		},
+		{
+			name:          "Another audience",
+			claims:        jwt.MapClaims{"sub": "u-42", "aud": "someone-else"},
+			codeVerifier:  codeVerifier,
+			nonce:         "nonce-1",
+			expectedError: "invalid id token",
+		},
+		{
+			name:          "Another authorized party",
+			claims:        jwt.MapClaims{"sub": "u-42", "aud": []string{"hub", "someone-else"}, "azp": "someone-else"},
+			codeVerifier:  codeVerifier,
+			nonce:         "nonce-1",
+			expectedError: "issued to another party",
+		},
+		{
+			name:          "Expired",
+			claims:        jwt.MapClaims{"sub": "u-42", "exp": 1},
+			codeVerifier:  codeVerifier,
+			nonce:         "nonce-1",
+			expectedError: "invalid id token",
+		},
+		{
+			name:          "Missing subject",
+			claims:        jwt.MapClaims{"email": "anna@example.com"},
+			codeVerifier:  codeVerifier,
+			nonce:         "nonce-1",
+			expectedError: "missing subject",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+			provider := oidcclient.New(issuer.URL, "hub", "hub-secret", redirectURL, nil)
+
+			authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", codeChallenge())
+			assert.NoError(t, err)
+			code, _, err := issuer.Login(authURL, tc.claims)
+			assert.NoError(t, err)
+
+			_, err = provider.Exchange(context.Background(), code, tc.codeVerifier, tc.nonce)
+
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestProvider_Exchange_WrongSecret(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	provider := oidcclient.New(issuer.URL, "hub", "wrong", redirectURL, nil)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", codeChallenge())
+	assert.NoError(t, err)
+	code, _, err := issuer.Login(authURL, jwt.MapClaims{"sub": "u-42"})
+	assert.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), code, codeVerifier, "nonce-1")
+
+	assert.ErrorContains(t, err, "invalid_client")
+}
+
+func TestProvider_Discovery_IssuerMismatch(t *testing.T) {
+	issuer := oidctest.NewIssuer(t, "hub", "hub-secret")
+	// the same server under another name, its metadata names 127.0.0.1 as the issuer
+	provider := oidcclient.New(strings.Replace(issuer.URL, "127.0.0.1", "localhost", 1), "hub", "hub-secret", redirectURL, nil)
+
+	_, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", codeChallenge())
+
+	assert.ErrorContains(t, err, "doesn't match")
+}
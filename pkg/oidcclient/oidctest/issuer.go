@@ -0,0 +1,184 @@
+// Package oidctest runs an OpenID Connect provider in the tests of its relying parties
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/minhmannh2001/authconnecthub/pkg/keyring"
+)
+
+// Issuer is an OpenID Connect provider which signs in whoever the test says. Claims are
+// added to the ID token, UserInfo is added to the subject at the userinfo endpoint.
+type Issuer struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+	UserInfo     map[string]interface{}
+
+	keys     *keyring.Keyring
+	mutex    sync.Mutex
+	grants   map[string]grant
+	subjects map[string]interface{}
+}
+
+type grant struct {
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	claims        jwt.MapClaims
+}
+
+// NewIssuer starts the provider, it is closed when the test ends
+func NewIssuer(t *testing.T, clientID string, clientSecret string) *Issuer {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := keyring.New(keyring.Key{ID: "mock-1", Status: keyring.StatusActive, Algorithm: keyring.AlgorithmRS256, PrivateKey: privateKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i := &Issuer{ClientID: clientID, ClientSecret: clientSecret, keys: keys, grants: map[string]grant{}, subjects: map[string]interface{}{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", i.discovery)
+	mux.HandleFunc("/jwks", i.jwks)
+	mux.HandleFunc("/token", i.token)
+	mux.HandleFunc("/userinfo", i.userInfo)
+	i.Server = httptest.NewServer(mux)
+	t.Cleanup(i.Close)
+
+	return i
+}
+
+// Login stands in for the user signing in at the authorization url, the ID token will carry the claims.
+// It returns the code and state the browser would be redirected back with.
+func (i *Issuer) Login(authURL string, claims jwt.MapClaims) (string, string, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	query := u.Query()
+	if query.Get("client_id") != i.ClientID || query.Get("code_challenge_method") != "S256" {
+		return "", "", errors.New("invalid authorization request")
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	code := hex.EncodeToString(b)
+
+	i.mutex.Lock()
+	i.grants[code] = grant{
+		redirectURI:   query.Get("redirect_uri"),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		claims:        claims,
+	}
+	i.mutex.Unlock()
+
+	return code, query.Get("state"), nil
+}
+
+func (i *Issuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 i.URL,
+		"authorization_endpoint": i.URL + "/authorize",
+		"token_endpoint":         i.URL + "/token",
+		"userinfo_endpoint":      i.URL + "/userinfo",
+		"jwks_uri":               i.URL + "/jwks",
+	})
+}
+
+func (i *Issuer) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, i.keys.JSONWebKeySet())
+}
+
+func (i *Issuer) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, _ := r.BasicAuth()
+	if clientID != i.ClientID || clientSecret != i.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	code := r.PostFormValue("code")
+	i.mutex.Lock()
+	g, ok := i.grants[code]
+	delete(i.grants, code)
+	i.mutex.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !ok || g.redirectURI != r.PostFormValue("redirect_uri") || base64.RawURLEncoding.EncodeToString(sum[:]) != g.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   i.URL,
+		"aud":   i.ClientID,
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": g.nonce,
+	}
+	for name, value := range g.claims {
+		claims[name] = value
+	}
+
+	key := i.keys.Active()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+	idToken, err := token.SignedString(key.PrivateKey)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	accessToken := "access-" + code
+	i.mutex.Lock()
+	i.subjects[accessToken] = claims["sub"]
+	i.mutex.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func (i *Issuer) userInfo(w http.ResponseWriter, r *http.Request) {
+	i.mutex.Lock()
+	subject, ok := i.subjects[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	i.mutex.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	info := map[string]interface{}{"sub": subject}
+	for name, value := range i.UserInfo {
+		info[name] = value
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
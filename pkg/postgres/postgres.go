@@ -59,14 +59,26 @@ func New(cfg *config.Config, opts ...Option) (*Postgres, error) {
 	}
 
 	// migration
+	pg.Conn.AutoMigrate(&entity.Permission{})
 	pg.Conn.AutoMigrate(&entity.Role{})
 	pg.Conn.AutoMigrate(&entity.User{})
+	pg.Conn.AutoMigrate(&entity.RecoveryCode{})
+	pg.Conn.AutoMigrate(&entity.WebAuthnCredential{})
+	pg.Conn.AutoMigrate(&entity.ServiceAccount{})
+	pg.Conn.AutoMigrate(&entity.LinkedIdentity{})
+	pg.Conn.AutoMigrate(&entity.Application{})
+	pg.Conn.AutoMigrate(&entity.ApplicationGrant{})
 
 	err = pg.createDefaultRoles(cfg)
 	if err != nil {
 		return nil, err
 	}
 
+	err = pg.createDefaultPermissions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	err = pg.CreateAdminUser(cfg)
 	if err != nil {
 		return nil, err
@@ -79,15 +91,15 @@ func New(cfg *config.Config, opts ...Option) (*Postgres, error) {
 
 func (p *Postgres) createDefaultRoles(cfg *config.Config) error {
 	var roles = []entity.Role{
-		{Name: "admin", Description: "Administrator role"},
-		{Name: "customer", Description: "Authenticated customer role"},
-		{Name: "anonymous", Description: "Unauthenticated customer role"},
+		{Name: "admin", Description: "Administrator role", System: true},
+		{Name: "customer", Description: "Authenticated customer role", System: true},
+		{Name: "anonymous", Description: "Unauthenticated customer role", System: true},
 	}
 
-	// Upsert roles
+	// Upsert roles, administrators may have changed the description since
 	result := p.Conn.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "name"}},                   // Assuming uniqueness on Name
-		DoUpdates: clause.AssignmentColumns([]string{"description"}), // Update only Description
+		Columns:   []clause.Column{{Name: "name"}},              // Assuming uniqueness on Name
+		DoUpdates: clause.AssignmentColumns([]string{"system"}), // Update only System
 	}).Create(&roles)
 	if err := result.Error; err != nil {
 		return fmt.Errorf("error upserting roles: %w", err)
@@ -97,6 +109,50 @@ func (p *Postgres) createDefaultRoles(cfg *config.Config) error {
 	return nil
 }
 
+// createDefaultPermissions upserts the permissions the operations of the swagger file ask for.
+// The admin role gets all of them, the other roles none.
+func (p *Postgres) createDefaultPermissions(cfg *config.Config) error {
+	var permissions = []entity.Permission{
+		{Name: "login-locks:read", Description: "List the locked usernames and ips"},
+		{Name: "login-locks:write", Description: "Clear login locks"},
+		{Name: "service-accounts:read", Description: "List service accounts"},
+		{Name: "service-accounts:write", Description: "Create and delete service accounts"},
+		{Name: "applications:read", Description: "List applications"},
+		{Name: "applications:write", Description: "Register, update and delete applications"},
+		{Name: "application-grants:read", Description: "Show the grants of the applications"},
+		{Name: "application-grants:write", Description: "Grant and revoke access to applications"},
+		{Name: "users:read", Description: "List the users"},
+		{Name: "users:write", Description: "Create, update, disable, delete and restore users"},
+		{Name: "roles:read", Description: "List the roles and permissions"},
+		{Name: "roles:write", Description: "Create, update and delete roles"},
+	}
+
+	result := p.Conn.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"description"}),
+	}).Create(&permissions)
+	if err := result.Error; err != nil {
+		return fmt.Errorf("error upserting permissions: %w", err)
+	}
+
+	var adminRole entity.Role
+	if err := p.Conn.Where("name = ?", "admin").First(&adminRole).Error; err != nil {
+		return fmt.Errorf("error finding admin role: %w", err)
+	}
+
+	// the ids of the permissions which existed already aren't returned by the upsert
+	var allPermissions []entity.Permission
+	if err := p.Conn.Find(&allPermissions).Error; err != nil {
+		return fmt.Errorf("error finding permissions: %w", err)
+	}
+	if err := p.Conn.Model(&adminRole).Association("Permissions").Append(allPermissions); err != nil {
+		return fmt.Errorf("error granting permissions to admin role: %w", err)
+	}
+
+	log.Println("Created default permissions")
+	return nil
+}
+
 func (p *Postgres) CreateAdminUser(cfg *config.Config) error {
 	encryptedPassword, err := bcrypt.GenerateFromPassword(
 		[]byte(cfg.Authen.AdminPassword),
@@ -107,11 +163,14 @@ func (p *Postgres) CreateAdminUser(cfg *config.Config) error {
 		return fmt.Errorf("error hashing password: %w", err)
 	}
 
+	verifiedAt := time.Now()
 	var user = []entity.User{{
-		Username: cfg.Authen.AdminUsername,
-		Email:    cfg.Authen.AdminEmail,
-		Password: string(encryptedPassword),
-		RoleID:   1,
+		Username:      cfg.Authen.AdminUsername,
+		Email:         cfg.Authen.AdminEmail,
+		Password:      string(encryptedPassword),
+		RoleID:        1,
+		EmailVerified: true,
+		VerifiedAt:    &verifiedAt,
 	}}
 
 	// Fetch role ID for "admin" role
@@ -127,7 +186,7 @@ func (p *Postgres) CreateAdminUser(cfg *config.Config) error {
 	// Upsert user
 	result = p.Conn.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "username"}}, // Assuming uniqueness on Username
-		DoUpdates: clause.AssignmentColumns([]string{"email", "password", "role_id"}),
+		DoUpdates: clause.AssignmentColumns([]string{"email", "password", "role_id", "email_verified"}),
 	}).Create(&user)
 	if err := result.Error; err != nil {
 		return fmt.Errorf("error upserting user: %w", err)